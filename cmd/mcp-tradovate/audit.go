@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/logging"
+)
+
+// AuditRecord is a single line of the audit log describing one tool call.
+type AuditRecord struct {
+	Time      time.Time              `json:"time"`             // When the call completed
+	RequestID string                 `json:"requestId"`        // MCP request ID of the call
+	Method    string                 `json:"method"`           // Method or tool that was invoked
+	Params    map[string]interface{} `json:"params,omitempty"` // Parameters with secrets redacted
+	Error     string                 `json:"error,omitempty"`  // Error message if the call failed
+}
+
+var (
+	auditMu   sync.Mutex
+	auditFile *os.File
+)
+
+// openAuditLog starts appending audit records to the file at path.
+func openAuditLog(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if auditFile != nil {
+		auditFile.Close()
+	}
+	auditFile = f
+	return nil
+}
+
+// auditRequest appends an audit record for req if an audit log is open.
+func auditRequest(ctx context.Context, req Request, errMsg string) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if auditFile == nil {
+		return
+	}
+
+	record := AuditRecord{
+		Time:      time.Now().UTC(),
+		RequestID: logging.RequestID(ctx),
+		Method:    req.Method,
+		Error:     errMsg,
+	}
+	if len(req.Params) > 0 {
+		var params map[string]interface{}
+		if err := json.Unmarshal(req.Params, &params); err == nil {
+			record.Params = redactParams(params)
+		}
+	}
+
+	if err := json.NewEncoder(auditFile).Encode(record); err != nil {
+		slog.ErrorContext(ctx, "error writing audit record", "error", err)
+	}
+}
+
+// redactParams returns a copy of params with credential-like values replaced.
+func redactParams(params map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		if logging.IsSecretKey(key) {
+			redacted[key] = "[REDACTED]"
+			continue
+		}
+		redacted[key] = value
+	}
+	return redacted
+}
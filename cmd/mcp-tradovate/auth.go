@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// loadAuthTokens collects the bearer tokens accepted by network transports
+// from the -auth-token flag value and the optional tokens file. The file
+// holds one token per line; blank lines and lines starting with '#' are ignored.
+func loadAuthTokens(token, tokensFile string) ([]string, error) {
+	var tokens []string
+	if token != "" {
+		tokens = append(tokens, token)
+	}
+
+	if tokensFile == "" {
+		return tokens, nil
+	}
+
+	f, err := os.Open(tokensFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tokens file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		tokens = append(tokens, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tokens file: %w", err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no tokens found in %s", tokensFile)
+	}
+
+	return tokens, nil
+}
+
+// requireBearerToken rejects requests that do not carry one of tokens in
+// their Authorization header with a 401 and a structured MCP error.
+// When tokens is empty every request is allowed through.
+func requireBearerToken(tokens []string, next http.Handler) http.Handler {
+	if len(tokens) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !validBearerToken(r.Header.Get("Authorization"), tokens) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp-tradovate"`)
+			writeHTTPResponse(w, http.StatusUnauthorized, newErrorResponse("", 401, "Unauthorized: missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validBearerToken reports whether header is a Bearer credential matching
+// one of tokens. Comparisons are constant time.
+func validBearerToken(header string, tokens []string) bool {
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return false
+	}
+	presented := []byte(strings.TrimSpace(header[len(prefix):]))
+
+	valid := false
+	for _, token := range tokens {
+		if subtle.ConstantTimeCompare(presented, []byte(token)) == 1 {
+			valid = true
+		}
+	}
+	return valid
+}
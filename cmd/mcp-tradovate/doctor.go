@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+)
+
+// maxClockSkew is the largest clock difference doctor accepts silently.
+const maxClockSkew = 5 * time.Second
+
+// Doctor check outcomes.
+const (
+	checkOK   = "ok"
+	checkWarn = "warn"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+// doctorCheck is the outcome of one diagnostic check.
+type doctorCheck struct {
+	Name   string // What was checked
+	Status string // One of checkOK, checkWarn, checkFail or checkSkip
+	Detail string // What was found
+	Remedy string // How to fix a warning or failure
+}
+
+// doctorOptions configures the hosts and clients doctor checks against.
+type doctorOptions struct {
+	restURL       string
+	marketDataURL string
+	httpClient    *http.Client
+	authenticate  func(ctx context.Context) error
+	now           func() time.Time
+}
+
+// runDoctor implements the doctor subcommand. It returns an error if any
+// check failed.
+func runDoctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	timeout := fs.Duration("timeout", 10*time.Second, "Timeout for each network check")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	demo := client.NewTradovateClient()
+	if err := demo.SetEnvironment(client.EnvironmentDemo.Name); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	checks := diagnose(ctx, doctorOptions{
+		restURL:       client.EnvironmentDemo.BaseURL,
+		marketDataURL: strings.Replace(client.EnvironmentDemo.MarketDataURL, "wss://", "https://", 1),
+		httpClient:    &http.Client{Timeout: *timeout},
+		authenticate: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, *timeout)
+			defer cancel()
+			_, err := demo.Authenticate(ctx)
+			return err
+		},
+		now: time.Now,
+	})
+
+	if failed := printDoctorReport(os.Stdout, checks); failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// diagnose runs every doctor check in order.
+func diagnose(ctx context.Context, opts doctorOptions) []doctorCheck {
+	env := checkEnvVars()
+	checks := []doctorCheck{env}
+
+	rest, serverDate := checkReachable(ctx, opts.httpClient, "REST API reachable", opts.restURL)
+	checks = append(checks, rest)
+	md, _ := checkReachable(ctx, opts.httpClient, "Market data host reachable", opts.marketDataURL)
+	checks = append(checks, md)
+	checks = append(checks, checkClockSkew(serverDate, opts.now()))
+
+	switch {
+	case env.Status == checkFail:
+		checks = append(checks, doctorCheck{Name: "Demo authentication", Status: checkSkip, Detail: "credentials are incomplete"})
+	case rest.Status == checkFail:
+		checks = append(checks, doctorCheck{Name: "Demo authentication", Status: checkSkip, Detail: "REST API is unreachable"})
+	default:
+		checks = append(checks, checkAuthentication(ctx, opts.authenticate))
+	}
+	return checks
+}
+
+// checkEnvVars verifies that every credential environment variable is set.
+func checkEnvVars() doctorCheck {
+	var missing []string
+	for _, key := range requiredEnvVars {
+		if strings.TrimSpace(os.Getenv(key)) == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return doctorCheck{
+			Name:   "Environment variables",
+			Status: checkFail,
+			Detail: "missing " + strings.Join(missing, ", "),
+			Remedy: "Set the missing variables in your .env file or MCP host configuration. App ID, CID and secret come from the API key created under Application Settings > API Access in Tradovate.",
+		}
+	}
+	return doctorCheck{Name: "Environment variables", Status: checkOK, Detail: "all credentials set"}
+}
+
+// checkReachable issues a request to url and reports whether any HTTP
+// response came back. It also returns the server's Date header, if any.
+func checkReachable(ctx context.Context, httpClient *http.Client, name, url string) (doctorCheck, time.Time) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return doctorCheck{Name: name, Status: checkFail, Detail: err.Error(), Remedy: "Check the configured URL."}, time.Time{}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return doctorCheck{
+			Name:   name,
+			Status: checkFail,
+			Detail: err.Error(),
+			Remedy: "Check your internet connection, DNS, proxy (HTTPS_PROXY) and firewall rules for outbound HTTPS to " + req.URL.Host + ".",
+		}, time.Time{}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	serverDate, _ := http.ParseTime(resp.Header.Get("Date"))
+	return doctorCheck{Name: name, Status: checkOK, Detail: fmt.Sprintf("%s responded with HTTP %d", req.URL.Host, resp.StatusCode)}, serverDate
+}
+
+// checkClockSkew compares the local clock with the server's Date header.
+func checkClockSkew(serverDate, now time.Time) doctorCheck {
+	if serverDate.IsZero() {
+		return doctorCheck{Name: "Clock skew", Status: checkSkip, Detail: "server time unavailable"}
+	}
+
+	skew := now.Sub(serverDate).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		return doctorCheck{
+			Name:   "Clock skew",
+			Status: checkWarn,
+			Detail: fmt.Sprintf("local clock differs from Tradovate by %s", skew),
+			Remedy: "Enable NTP time synchronisation; token expiry and order timestamps depend on an accurate clock.",
+		}
+	}
+	return doctorCheck{Name: "Clock skew", Status: checkOK, Detail: fmt.Sprintf("within %s", maxClockSkew)}
+}
+
+// checkAuthentication attempts to authenticate with the demo environment.
+func checkAuthentication(ctx context.Context, authenticate func(context.Context) error) doctorCheck {
+	if err := authenticate(ctx); err != nil {
+		return doctorCheck{
+			Name:   "Demo authentication",
+			Status: checkFail,
+			Detail: err.Error(),
+			Remedy: "Verify TRADOVATE_USERNAME and TRADOVATE_PASSWORD by logging in to the Tradovate web trader, and that TRADOVATE_CID and TRADOVATE_SEC belong to an API key with API access enabled.",
+		}
+	}
+	return doctorCheck{Name: "Demo authentication", Status: checkOK, Detail: "access token issued"}
+}
+
+// printDoctorReport writes a human readable report of checks to w and
+// returns the number of failed checks.
+func printDoctorReport(w io.Writer, checks []doctorCheck) int {
+	failed := 0
+	for _, check := range checks {
+		fmt.Fprintf(w, "[%s] %s: %s\n", strings.ToUpper(check.Status), check.Name, check.Detail)
+		if check.Remedy != "" {
+			fmt.Fprintf(w, "       -> %s\n", check.Remedy)
+		}
+		if check.Status == checkFail {
+			failed++
+		}
+	}
+	return failed
+}
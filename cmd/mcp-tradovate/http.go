@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// maxRequestBytes bounds the size of a single HTTP request body.
+const maxRequestBytes = 1 << 20
+
+// serveHTTP serves MCP requests over HTTP on addr. Each POST carries a single
+// JSON request and receives a single JSON response. When tlsConfig is non-nil
+// the listener is wrapped in TLS and plaintext connections are rejected.
+// When tokens is non-empty every request must present one of them as a
+// bearer token.
+func serveHTTP(addr string, tlsConfig *tls.Config, tokens []string) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           requireBearerToken(tokens, newHTTPHandler()),
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-serverLifecycle.Done()
+		// Shutdown waits for in-flight requests, including the one that
+		// asked for the shutdown, to finish writing their responses.
+		if err := server.Shutdown(context.Background()); err != nil {
+			slog.Error("error shutting down HTTP server", "error", err)
+		}
+	}()
+
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	<-stopped
+	return nil
+}
+
+// newHTTPHandler returns the http.Handler implementing the MCP HTTP transport.
+func newHTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeHTTPResponse(w, http.StatusMethodNotAllowed, newErrorResponse("", 405, "Method not allowed"))
+			return
+		}
+
+		var req Request
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+			writeHTTPResponse(w, http.StatusBadRequest, newErrorResponse(req.ID, 400, fmt.Sprintf("Invalid request: %v", err)))
+			return
+		}
+
+		writeHTTPResponse(w, http.StatusOK, handleRequest(r.Context(), req))
+	})
+	return mux
+}
+
+func writeHTTPResponse(w http.ResponseWriter, status int, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("error encoding response", "error", err)
+	}
+}
+
+// isLoopbackAddr reports whether addr only binds to a loopback interface.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -5,12 +5,26 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/config"
+	"github.com/0xjmp/mcp-tradovate/internal/handlers"
+	"github.com/0xjmp/mcp-tradovate/internal/logging"
+	"github.com/0xjmp/mcp-tradovate/internal/ratelimit"
 )
 
 // Request represents an incoming MCP request
@@ -27,57 +41,395 @@ type Response struct {
 	Error  *Error      `json:"error,omitempty"`
 }
 
+// Notification is a message sent to the client without a request, such as
+// an update from a market data subscription.
+type Notification struct {
+	Method string      `json:"method"`
+	Params interface{} `json:"params,omitempty"`
+}
+
+// Tool describes a tool in the reply to tools/list.
+type Tool struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	InputSchema *handlers.Schema `json:"inputSchema"`
+}
+
 // Error represents an MCP error
 type Error struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
 }
 
-var tradovateClient client.TradovateClientInterface
+var (
+	tradovateClient client.TradovateClientInterface
+	toolHandlers    handlers.Handlers
+	rateLimiter     = ratelimit.New()
+	exports         = handlers.NewExports()
+)
 
 func init() {
 	tradovateClient = client.NewTradovateClient()
+	toolHandlers = handlers.NewHandlers(tradovateClient, handlers.WithExports(exports))
 }
 
 func main() {
-	// Initialize scanner for STDIN
-	scanner := bufio.NewScanner(os.Stdin)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "support-bundle":
+			if err := runSupportBundle(os.Args[2:]); err != nil {
+				log.Fatalf("Error creating support bundle: %v", err)
+			}
+			return
+		case "doctor":
+			if err := runDoctor(os.Args[2:]); err != nil {
+				log.Fatalf("Doctor: %v", err)
+			}
+			return
+		}
+	}
+
+	fs := flag.NewFlagSet("mcp-tradovate", flag.ExitOnError)
+	transport := fs.String("transport", "stdio", "Transport to serve MCP requests on: stdio, http, tcp or unix")
+	addr := fs.String("addr", defaultAddr(), "Listen address for network transports, or socket path for the unix transport")
+	tlsCert := fs.String("tls-cert", "", "Path to the PEM encoded TLS certificate for network transports")
+	tlsKey := fs.String("tls-key", "", "Path to the PEM encoded TLS private key for network transports")
+	tlsClientCA := fs.String("tls-client-ca", "", "Path to a PEM encoded CA bundle; when set, clients must present a certificate signed by it (mTLS)")
+	allowPlaintext := fs.Bool("allow-plaintext", false, "Allow network transports to listen on non-loopback addresses without TLS")
+	authToken := fs.String("auth-token", os.Getenv("MCP_AUTH_TOKEN"), "Bearer token required on network transport requests")
+	authTokensFile := fs.String("auth-tokens-file", "", "Path to a file of accepted bearer tokens, one per line")
+	expiryWarningDays := fs.Int("expiry-warning-days", 5, "Warn about positions and orders on contracts expiring within this many days")
+	auditLogPath := fs.String("audit-log", "", "Path to append JSON audit records of every tool call to")
+	logFile := fs.String("log-file", "", "Path to append logs to instead of stderr")
+	logRequests := fs.Bool("log-requests", false, "Log the method, endpoint, status and latency of every Tradovate API call")
+	environment := fs.String("env", defaultEnvironment(), "Tradovate environment to trade in: live, demo or replay")
+	tokenCache := fs.String("token-cache", defaultTokenCachePath(), "Path to persist Tradovate tokens to between restarts; empty to disable")
+	deviceID := fs.String("device-id", os.Getenv("TRADOVATE_DEVICE_ID"), "Device ID to authenticate with; by default one is generated and kept next to the token cache")
+	logoutOnExit := fs.Bool("logout-on-exit", false, "End the Tradovate session on shutdown instead of keeping it for the next start; always done when the token cache is disabled")
+	journalPath := fs.String("journal", defaultJournalPath(), "Path to keep the trade journal's notes, tags and strategies in; empty to keep them in memory only")
+	configPath := fs.String("config", os.Getenv("MCP_CONFIG"), "Path to a JSON configuration file, reloaded on SIGHUP")
+	fs.Parse(os.Args[1:])
+
+	var logOutput io.Writer = os.Stderr
+	if *logFile != "" {
+		f, err := os.OpenFile(*logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			log.Fatalf("Error opening log file: %v", err)
+		}
+		defer f.Close()
+		logOutput = f
+	}
+	slog.SetDefault(slog.New(logging.NewHandler(slog.NewTextHandler(logOutput, &slog.HandlerOptions{Level: &logLevel}))))
+
+	if *configPath != "" {
+		store, err := config.NewStore(*configPath)
+		if err != nil {
+			log.Fatalf("Error loading configuration: %v", err)
+		}
+		configStore = store
+		applyConfig(store.Current())
+		watchConfigReload(store)
+	}
+
+	if *auditLogPath != "" {
+		if err := openAuditLog(*auditLogPath); err != nil {
+			log.Fatalf("Error opening audit log: %v", err)
+		}
+	}
+
+	if c, ok := tradovateClient.(*client.TradovateClient); ok {
+		if err := c.SetEnvironment(*environment); err != nil {
+			log.Fatalf("Error selecting environment: %v", err)
+		}
+		slog.Info("using tradovate environment", "env", c.Environment().Name, "baseUrl", c.Environment().BaseURL)
+		transport := configStore.Current().Transport
+		if err := c.SetTransportOptions(client.TransportOptions{
+			MaxIdleConnsPerHost: transport.MaxIdleConnsPerHost,
+			IdleConnTimeout:     transport.IdleConnTimeout.Duration,
+			DialTimeout:         transport.DialTimeout.Duration,
+			TLSHandshakeTimeout: transport.TLSHandshakeTimeout.Duration,
+			ProxyURL:            transport.Proxy,
+		}); err != nil {
+			log.Fatalf("Error configuring transport: %v", err)
+		}
+		if *logRequests {
+			c.SetHooks(client.LoggingHooks(slog.Default()))
+		}
+		serverLifecycle.onShutdown(func() { c.Close() })
+
+		switch {
+		case *deviceID != "":
+			c.SetDeviceID(*deviceID)
+		case *tokenCache != "":
+			id, err := client.LoadDeviceID(client.DeviceIDPath(*tokenCache))
+			if err != nil {
+				slog.Warn("using a temporary device ID", "error", err)
+			} else {
+				c.SetDeviceID(id)
+			}
+		}
+
+		if *tokenCache != "" {
+			c.SetTokenCachePath(*tokenCache)
+			if loaded, err := c.LoadTokenCache(); err != nil {
+				slog.Warn("ignoring unreadable token cache", "path", *tokenCache, "error", err)
+			} else if loaded {
+				slog.Info("reusing cached tradovate token", "path", *tokenCache)
+			}
+		}
+		if *logoutOnExit || *tokenCache == "" {
+			serverLifecycle.onShutdown(func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if err := c.Logout(ctx); err != nil {
+					slog.Warn("failed to end tradovate session", "error", err)
+				}
+			})
+		}
+	}
+
+	journal, err := handlers.OpenJournal(*journalPath)
+	if err != nil {
+		log.Fatalf("Error opening trade journal: %v", err)
+	}
+	toolHandlers = handlers.NewHandlers(tradovateClient, handlers.WithExpiryWarningDays(*expiryWarningDays), handlers.WithConfig(configStore),
+		handlers.WithExports(exports), handlers.WithJournal(journal), handlers.WithTransport(*transport))
+
+	switch *transport {
+	case "stdio":
+		serveStdio(os.Stdin, os.Stdout)
+	case "http":
+		tlsConfig, err := newTLSConfig(*tlsCert, *tlsKey, *tlsClientCA)
+		if err != nil {
+			log.Fatalf("Error configuring TLS: %v", err)
+		}
+		if tlsConfig == nil && !*allowPlaintext && !isLoopbackAddr(*addr) {
+			log.Fatalf("Refusing to serve %s in plaintext on %s; configure -tls-cert/-tls-key or pass -allow-plaintext", *transport, *addr)
+		}
+		tokens, err := loadAuthTokens(*authToken, *authTokensFile)
+		if err != nil {
+			log.Fatalf("Error loading auth tokens: %v", err)
+		}
+		if len(tokens) == 0 && !isLoopbackAddr(*addr) {
+			log.Fatalf("Refusing to serve %s on %s without authentication; configure -auth-token or -auth-tokens-file", *transport, *addr)
+		}
+		if err := serveHTTP(*addr, tlsConfig, tokens); err != nil {
+			log.Fatalf("Error serving HTTP: %v", err)
+		}
+	case "tcp":
+		tlsConfig, err := newTLSConfig(*tlsCert, *tlsKey, *tlsClientCA)
+		if err != nil {
+			log.Fatalf("Error configuring TLS: %v", err)
+		}
+		if !isLoopbackAddr(*addr) && (tlsConfig == nil || tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert) {
+			log.Fatalf("Refusing to serve %s on %s without client certificates; configure -tls-cert/-tls-key/-tls-client-ca", *transport, *addr)
+		}
+		if err := serveSocket("tcp", *addr, tlsConfig); err != nil {
+			log.Fatalf("Error serving TCP: %v", err)
+		}
+	case "unix":
+		if err := serveSocket("unix", *addr, nil); err != nil {
+			log.Fatalf("Error serving Unix socket: %v", err)
+		}
+	default:
+		log.Fatalf("Unknown transport: %s", *transport)
+	}
+
+	serverLifecycle.finish()
+}
+
+// defaultAddr returns the listen address for network transports, honouring
+// the PORT environment variable used by hosted deployments.
+func defaultAddr() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return ":" + port
+	}
+	return "127.0.0.1:8080"
+}
+
+// defaultEnvironment returns the Tradovate environment named by the
+// TRADOVATE_ENV environment variable, defaulting to live.
+func defaultEnvironment() string {
+	if env := os.Getenv("TRADOVATE_ENV"); env != "" {
+		return env
+	}
+	return client.EnvironmentLive.Name
+}
+
+// defaultTokenCachePath returns the token cache location, honouring the
+// TRADOVATE_TOKEN_CACHE environment variable.
+func defaultTokenCachePath() string {
+	if path, ok := os.LookupEnv("TRADOVATE_TOKEN_CACHE"); ok {
+		return path
+	}
+	return client.DefaultTokenCachePath()
+}
+
+// defaultJournalPath returns the trade journal location, honouring the
+// TRADOVATE_JOURNAL environment variable.
+func defaultJournalPath() string {
+	if path, ok := os.LookupEnv("TRADOVATE_JOURNAL"); ok {
+		return path
+	}
+	return handlers.DefaultJournalPath()
+}
+
+// serveStdio reads newline-delimited requests from r and writes one response
+// per request to w until r is exhausted.
+func serveStdio(r io.Reader, w io.Writer) {
+	if err := serveStream(context.Background(), r, w); err != nil {
+		log.Fatalf("Error reading standard input: %v", err)
+	}
+}
+
+// serveStream serves newline-delimited requests read from r, writing one
+// response per request to w, until r is exhausted or ctx is cancelled.
+// Notifications, such as market data updates, are written to w between
+// responses; subscriptions opened on the stream end with it.
+func serveStream(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxRequestBytes)
+	out := &responseWriter{w: w}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ctx = handlers.WithNotifier(ctx, func(method string, params interface{}) {
+		out.encode(Notification{Method: method, Params: params})
+	})
 
 	// Process incoming requests
 	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return nil
+		}
 		line := scanner.Text()
 
 		// Parse request
 		var req Request
 		if err := json.Unmarshal([]byte(line), &req); err != nil {
-			sendError(req.ID, 400, fmt.Sprintf("Invalid request: %v", err))
+			out.write(newErrorResponse(req.ID, 400, fmt.Sprintf("Invalid request: %v", err)))
 			continue
 		}
 
-		// Handle request
-		switch req.Method {
-		case "ping":
-			sendResponse(req.ID, "pong")
-		case "authenticate":
-			handleAuthenticate(req.ID)
-		default:
-			sendError(req.ID, 404, fmt.Sprintf("Unknown method: %s", req.Method))
+		out.write(handleRequest(ctx, req))
+		if serverLifecycle.isStopping() {
+			return nil
 		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		log.Fatalf("Error reading standard input: %v", err)
+	return scanner.Err()
+}
+
+// handleRequest dispatches a single request to the matching method and
+// returns the response to send back to the caller. The request ID is attached
+// to ctx so that client calls, log lines, and audit records can be correlated.
+func handleRequest(ctx context.Context, req Request) Response {
+	ctx = logging.WithRequestID(ctx, req.ID)
+	start := time.Now()
+
+	if !serverLifecycle.begin() {
+		return newErrorResponse(req.ID, 503, "Server is shutting down")
 	}
+	defer serverLifecycle.end()
+
+	resp := dispatch(ctx, req)
+
+	if req.Method != "ping" {
+		var errMsg string
+		if resp.Error != nil {
+			errMsg = resp.Error.Message
+			slog.WarnContext(ctx, "request failed", "method", req.Method, "code", resp.Error.Code, "error", errMsg, "duration", time.Since(start))
+		} else {
+			slog.InfoContext(ctx, "request completed", "method", req.Method, "duration", time.Since(start))
+		}
+		auditRequest(ctx, req, errMsg)
+	}
+
+	return resp
+}
+
+// dispatch routes req to the built-in method or tool handler it names.
+// A panic while handling req is recovered and reported as an internal error
+// so that one faulty handler cannot take down the server.
+func dispatch(ctx context.Context, req Request) (resp Response) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.ErrorContext(ctx, "panic while handling request", "method", req.Method, "panic", r, "stack", string(debug.Stack()))
+			resp = newErrorResponse(req.ID, 500, fmt.Sprintf("Internal error while handling %s", req.Method))
+		}
+	}()
+
+	if req.Method != "ping" && req.Method != "shutdown" {
+		if limit, ok := configStore.Current().RateLimits.For(req.Method); ok {
+			if allowed, wait := rateLimiter.Allow(req.Method, limit.Limit, limit.Window.Duration); !allowed {
+				return newRateLimitedResponse(req.ID, req.Method, wait)
+			}
+		}
+	}
+
+	switch req.Method {
+	case "ping":
+		return newResponse(req.ID, "pong")
+	case "tools/list":
+		return newResponse(req.ID, map[string][]Tool{"tools": listTools()})
+	case "resources/list":
+		return newResponse(req.ID, map[string][]handlers.Resource{"resources": exports.List()})
+	case "resources/read":
+		return handleReadResource(req)
+	case "authenticate":
+		return handleAuthenticate(ctx, req.ID)
+	case "shutdown":
+		slog.InfoContext(ctx, "shutdown requested")
+		serverLifecycle.requestShutdown()
+		return newResponse(req.ID, map[string]interface{}{"status": "shutting down"})
+	}
+
+	handler, ok := toolHandlers[req.Method]
+	if !ok {
+		return newErrorResponse(req.ID, 404, fmt.Sprintf("Unknown method: %s", req.Method))
+	}
+
+	var params map[string]interface{}
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return newErrorResponse(req.ID, 400, fmt.Sprintf("Invalid params: %v", err))
+		}
+	}
+
+	page, err := handlers.ExtractPageRequest(params)
+	if err != nil {
+		return newErrorResponse(req.ID, 400, fmt.Sprintf("Invalid params: %v", err))
+	}
+
+	if timeout := configStore.Current().RequestTimeout.Duration; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result, err := handler.Handler(ctx, params)
+	if err != nil {
+		return newToolErrorResponse(req.ID, err)
+	}
+	return newResponse(req.ID, handlers.Paginate(result, page))
 }
 
-func handleAuthenticate(reqID string) {
-	authResp, err := tradovateClient.Authenticate()
+func handleAuthenticate(ctx context.Context, reqID string) Response {
+	authResp, err := tradovateClient.Authenticate(ctx)
+	var challenge *client.ChallengeError
+	if errors.As(err, &challenge) {
+		return newChallengeResponse(reqID, challenge)
+	}
+	var conflict *client.SessionConflictError
+	if errors.As(err, &conflict) {
+		return newErrorResponse(reqID, 409, fmt.Sprintf("Authentication failed: %v", err))
+	}
 	if err != nil {
-		sendError(reqID, 401, fmt.Sprintf("Authentication failed: %v", err))
-		return
+		return newErrorResponse(reqID, 401, fmt.Sprintf("Authentication failed: %v", err))
 	}
 
-	sendResponse(reqID, map[string]interface{}{
+	return newResponse(reqID, map[string]interface{}{
 		"status":         "authenticated",
 		"token":          authResp.AccessToken,
 		"mdToken":        authResp.MdAccessToken,
@@ -87,21 +439,50 @@ func handleAuthenticate(reqID string) {
 	})
 }
 
-func sendResponse(id string, result interface{}) {
-	resp := Response{
+// handleReadResource returns the contents of the resource named by the uri
+// parameter of req.
+func handleReadResource(req Request) Response {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return newErrorResponse(req.ID, 400, fmt.Sprintf("Invalid params: %v", err))
+		}
+	}
+	if params.URI == "" {
+		return newErrorResponse(req.ID, 400, "Invalid params: missing required field: uri")
+	}
+	contents, err := exports.Read(params.URI)
+	if err != nil {
+		return newErrorResponse(req.ID, 404, err.Error())
+	}
+	return newResponse(req.ID, map[string][]handlers.ResourceContents{"contents": {*contents}})
+}
+
+// listTools describes the tool handlers, by name, with the schemas their
+// parameters are validated against.
+func listTools() []Tool {
+	tools := make([]Tool, 0, len(toolHandlers))
+	for name, handler := range toolHandlers {
+		tools = append(tools, Tool{Name: name, Description: handler.Description, InputSchema: handler.InputSchema()})
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+func newResponse(id string, result interface{}) Response {
+	return Response{
 		ID:     id,
 		Result: result,
 	}
-	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
-		log.Printf("Error encoding response: %v", err)
-	}
 }
 
-func sendError(id string, code int, message string) {
+func newErrorResponse(id string, code int, message string) Response {
 	if code == 0 {
 		code = 500 // Default to internal server error for zero code
 	}
-	resp := Response{
+	return Response{
 		ID:     id,
 		Result: nil,
 		Error: &Error{
@@ -109,7 +490,107 @@ func sendError(id string, code int, message string) {
 			Message: message,
 		},
 	}
-	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
-		log.Printf("Error encoding error response: %v", err)
+}
+
+// RateLimitData is the error data returned when a call is rate limited.
+type RateLimitData struct {
+	Tool         string `json:"tool"`         // Tool whose rate limit was exceeded
+	RetryAfterMs int64  `json:"retryAfterMs"` // Milliseconds to wait before retrying
+}
+
+func newRateLimitedResponse(id, tool string, retryAfter time.Duration) Response {
+	retryAfter = retryAfter.Round(time.Millisecond)
+	resp := newErrorResponse(id, 429, fmt.Sprintf("Rate limited: retry %s after %s", tool, retryAfter))
+	resp.Error.Data = RateLimitData{Tool: tool, RetryAfterMs: retryAfter.Milliseconds()}
+	return resp
+}
+
+// ChallengeData is the error data returned when Tradovate needs the user to
+// act before it will authenticate the server.
+type ChallengeData struct {
+	ActionRequired string `json:"actionRequired"` // Kind of challenge, e.g. "captcha"
+	Instructions   string `json:"instructions"`   // What the user needs to do
+	Resume         string `json:"resume"`         // Method to call once they have
+}
+
+func newChallengeResponse(id string, challenge *client.ChallengeError) Response {
+	resp := newErrorResponse(id, 401, fmt.Sprintf("Action required: %v", challenge))
+	resp.Error.Data = ChallengeData{ActionRequired: challenge.Kind, Instructions: challenge.Message, Resume: "authenticate"}
+	return resp
+}
+
+// APIErrorData is the error data returned when Tradovate rejects a call.
+type APIErrorData struct {
+	Endpoint  string `json:"endpoint"`            // Tradovate endpoint that was called
+	Status    int    `json:"status"`              // Status Tradovate responded with
+	ErrorText string `json:"errorText,omitempty"` // Tradovate's description of the error
+	ErrorCode string `json:"errorCode,omitempty"` // Tradovate's code for the error
+	Retryable bool   `json:"retryable"`           // Whether the call may succeed if retried later
+}
+
+// newToolErrorResponse reports an error returned by a tool handler. Missing
+// or invalid parameters are reported with 400. When Tradovate rejected the
+// call, the response carries a matching code and the details of the
+// rejection.
+func newToolErrorResponse(id string, err error) Response {
+	var paramErr *handlers.ParamError
+	if errors.As(err, &paramErr) {
+		return newErrorResponse(id, 400, err.Error())
+	}
+	var challenge *client.ChallengeError
+	if errors.As(err, &challenge) {
+		return newChallengeResponse(id, challenge)
+	}
+	var conflict *client.SessionConflictError
+	if errors.As(err, &conflict) {
+		return newErrorResponse(id, 409, err.Error())
+	}
+	var entitlementErr *client.EntitlementError
+	if errors.As(err, &entitlementErr) {
+		return newErrorResponse(id, 403, err.Error())
+	}
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		return newErrorResponse(id, 500, err.Error())
+	}
+
+	resp := newErrorResponse(id, apiErrorCode(apiErr), err.Error())
+	resp.Error.Data = APIErrorData{
+		Endpoint:  apiErr.Endpoint,
+		Status:    apiErr.StatusCode,
+		ErrorText: apiErr.ErrorText,
+		ErrorCode: apiErr.ErrorCode,
+		Retryable: apiErr.Retryable(),
+	}
+	return resp
+}
+
+// apiErrorCode maps a Tradovate error status to the code reported to the
+// caller. Client errors are passed through; server errors become 502, since
+// they are Tradovate's failure rather than this server's.
+func apiErrorCode(apiErr *client.APIError) int {
+	if apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
+		return apiErr.StatusCode
+	}
+	return 502
+}
+
+// responseWriter serializes responses onto a shared stream so that
+// concurrent writers never interleave partial JSON lines.
+type responseWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (rw *responseWriter) write(resp Response) {
+	rw.encode(resp)
+}
+
+// encode writes v as one JSON line.
+func (rw *responseWriter) encode(v interface{}) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	if err := json.NewEncoder(rw.w).Encode(v); err != nil {
+		slog.Error("error encoding response", "error", err)
 	}
 }
@@ -0,0 +1,904 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/config"
+	"github.com/0xjmp/mcp-tradovate/internal/handlers"
+	"github.com/0xjmp/mcp-tradovate/internal/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleRequest(t *testing.T) {
+	resp := handleRequest(context.Background(), Request{ID: "1", Method: "ping"})
+	assert.Equal(t, "1", resp.ID)
+	assert.Equal(t, "pong", resp.Result)
+	assert.Nil(t, resp.Error)
+
+	resp = handleRequest(context.Background(), Request{ID: "2", Method: "unknown"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, 404, resp.Error.Code)
+
+	resp = handleRequest(context.Background(), Request{ID: "3", Method: "getMarketData", Params: json.RawMessage(`[1,2]`)})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, 400, resp.Error.Code)
+}
+
+func TestServeStdio(t *testing.T) {
+	in := strings.NewReader("{\"id\":\"1\",\"method\":\"ping\"}\nnot json\n")
+	var out bytes.Buffer
+
+	serveStdio(in, &out)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var first, second Response
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, "pong", first.Result)
+	require.NotNil(t, second.Error)
+	assert.Equal(t, 400, second.Error.Code)
+}
+
+func TestHTTPHandler(t *testing.T) {
+	server := httptest.NewServer(newHTTPHandler())
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"id":"1","method":"ping"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "pong", body.Result)
+
+	getResp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	getResp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, getResp.StatusCode)
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	assert.True(t, isLoopbackAddr("127.0.0.1:8080"))
+	assert.True(t, isLoopbackAddr("localhost:8080"))
+	assert.True(t, isLoopbackAddr("[::1]:8080"))
+	assert.False(t, isLoopbackAddr(":8080"))
+	assert.False(t, isLoopbackAddr("0.0.0.0:8080"))
+	assert.False(t, isLoopbackAddr("invalid"))
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+	pki := newTestPKI(t, dir)
+
+	config, err := newTLSConfig("", "", "")
+	assert.NoError(t, err)
+	assert.Nil(t, config)
+
+	_, err = newTLSConfig(pki.serverCert, "", "")
+	assert.Error(t, err)
+
+	_, err = newTLSConfig("", "", pki.caCert)
+	assert.Error(t, err)
+
+	_, err = newTLSConfig(filepath.Join(dir, "missing.pem"), pki.serverKey, "")
+	assert.Error(t, err)
+
+	_, err = newTLSConfig(pki.serverCert, pki.serverKey, pki.serverKey)
+	assert.Error(t, err)
+
+	config, err = newTLSConfig(pki.serverCert, pki.serverKey, "")
+	require.NoError(t, err)
+	assert.Equal(t, tls.NoClientCert, config.ClientAuth)
+
+	config, err = newTLSConfig(pki.serverCert, pki.serverKey, pki.caCert)
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, config.ClientAuth)
+}
+
+func TestHTTPMutualTLS(t *testing.T) {
+	pki := newTestPKI(t, t.TempDir())
+
+	config, err := newTLSConfig(pki.serverCert, pki.serverKey, pki.caCert)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(newHTTPHandler())
+	server.TLS = config
+	server.StartTLS()
+	defer server.Close()
+
+	// Without a client certificate the handshake must fail.
+	anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pki.pool}}}
+	_, err = anonymous.Post(server.URL, "application/json", strings.NewReader(`{"id":"1","method":"ping"}`))
+	assert.Error(t, err)
+
+	clientCert, err := tls.LoadX509KeyPair(pki.clientCert, pki.clientKey)
+	require.NoError(t, err)
+	authenticated := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      pki.pool,
+		Certificates: []tls.Certificate{clientCert},
+	}}}
+	resp, err := authenticated.Post(server.URL, "application/json", strings.NewReader(`{"id":"1","method":"ping"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "pong", body.Result)
+}
+
+// testPKI holds the file paths of a throwaway CA, server, and client certificate.
+type testPKI struct {
+	caCert     string
+	serverCert string
+	serverKey  string
+	clientCert string
+	clientKey  string
+	pool       *x509.CertPool
+}
+
+func newTestPKI(t *testing.T, dir string) testPKI {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage) ([]byte, *ecdsa.PrivateKey) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "localhost"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			DNSNames:     []string{"localhost"},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		return der, key
+	}
+
+	writePEM := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+		return path
+	}
+	writeKey := func(name string, key *ecdsa.PrivateKey) string {
+		der, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		return writePEM(name, "EC PRIVATE KEY", der)
+	}
+
+	serverDER, serverKey := issue(2, x509.ExtKeyUsageServerAuth)
+	clientDER, clientKey := issue(3, x509.ExtKeyUsageClientAuth)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+
+	return testPKI{
+		caCert:     writePEM("ca.pem", "CERTIFICATE", caDER),
+		serverCert: writePEM("server.pem", "CERTIFICATE", serverDER),
+		serverKey:  writeKey("server-key.pem", serverKey),
+		clientCert: writePEM("client.pem", "CERTIFICATE", clientDER),
+		clientKey:  writeKey("client-key.pem", clientKey),
+		pool:       pool,
+	}
+}
+
+func TestLoadAuthTokens(t *testing.T) {
+	tokens, err := loadAuthTokens("", "")
+	assert.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	path := filepath.Join(t.TempDir(), "tokens")
+	require.NoError(t, os.WriteFile(path, []byte("# agents\nalpha\n\n  beta  \n"), 0600))
+
+	tokens, err = loadAuthTokens("flag-token", path)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"flag-token", "alpha", "beta"}, tokens)
+
+	empty := filepath.Join(t.TempDir(), "empty")
+	require.NoError(t, os.WriteFile(empty, []byte("# nothing\n"), 0600))
+	_, err = loadAuthTokens("", empty)
+	assert.Error(t, err)
+
+	_, err = loadAuthTokens("", filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestRequireBearerToken(t *testing.T) {
+	server := httptest.NewServer(requireBearerToken([]string{"secret"}, newHTTPHandler()))
+	defer server.Close()
+
+	post := func(authorization string) (*http.Response, Response) {
+		req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"id":"1","method":"ping"}`))
+		require.NoError(t, err)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var body Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return resp, body
+	}
+
+	for _, header := range []string{"", "Bearer wrong", "Basic secret", "Bearer "} {
+		resp, body := post(header)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, header)
+		assert.Equal(t, `Bearer realm="mcp-tradovate"`, resp.Header.Get("WWW-Authenticate"))
+		require.NotNil(t, body.Error)
+		assert.Equal(t, 401, body.Error.Code)
+	}
+
+	resp, body := post("Bearer secret")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "pong", body.Result)
+}
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	require.NoError(t, openAuditLog(path))
+	defer func() {
+		auditMu.Lock()
+		auditFile.Close()
+		auditFile = nil
+		auditMu.Unlock()
+	}()
+
+	handleRequest(context.Background(), Request{ID: "ping-1", Method: "ping"})
+	handleRequest(context.Background(), Request{ID: "req-7", Method: "unknownTool", Params: json.RawMessage(`{"orderId":1,"password":"hunter2"}`)})
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+
+	var record AuditRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "req-7", record.RequestID)
+	assert.Equal(t, "unknownTool", record.Method)
+	assert.Equal(t, "[REDACTED]", record.Params["password"])
+	assert.Equal(t, float64(1), record.Params["orderId"])
+	assert.Contains(t, record.Error, "Unknown method")
+}
+
+func TestRedactText(t *testing.T) {
+	assert.Equal(t, "Authorization: Bearer [REDACTED]", redactText("Authorization: Bearer abc.def-123"))
+	assert.Equal(t, "password=[REDACTED] user=bob", redactText("password=hunter2 user=bob"))
+	assert.Equal(t, `{"p-ticket":"[REDACTED]","accountId":1,"mdAccessToken":"[REDACTED]"}`,
+		redactText(`{"p-ticket":"t-1","accountId":1,"mdAccessToken":"xyz"}`))
+	assert.Equal(t, "security=ES", redactText("security=ES"))
+}
+
+func TestWriteSupportBundle(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "server.log")
+	auditPath := filepath.Join(dir, "audit.log")
+	require.NoError(t, os.WriteFile(logPath, []byte("line one\nAuthorization: Bearer abc.def-123\npassword=hunter2 user=bob\n"), 0600))
+	require.NoError(t, os.WriteFile(auditPath, []byte(`{"method":"placeOrder","params":{"accessToken":"xyz"}}`+"\n"), 0600))
+
+	t.Setenv("TRADOVATE_USERNAME", "bob")
+	t.Setenv("TRADOVATE_PASSWORD", "hunter2")
+	t.Setenv("MCP_AUTH_TOKEN", "agent-token")
+
+	var buf bytes.Buffer
+	require.NoError(t, writeSupportBundle(&buf, supportBundleOptions{
+		logFile:   logPath,
+		auditLog:  auditPath,
+		tailLines: 2,
+	}))
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range reader.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		files[f.Name] = string(data)
+	}
+
+	assert.Contains(t, files, "version.json")
+	assert.Contains(t, files, "environment.json")
+	assert.Contains(t, files["config.json"], `"TRADOVATE_USERNAME": "bob"`)
+	assert.NotContains(t, files["config.json"], "hunter2")
+	assert.NotContains(t, files["config.json"], "agent-token")
+
+	assert.NotContains(t, files["logs.txt"], "line one")
+	assert.NotContains(t, files["logs.txt"], "abc.def-123")
+	assert.NotContains(t, files["logs.txt"], "hunter2")
+	assert.Contains(t, files["logs.txt"], "user=bob")
+	assert.NotContains(t, files["audit.jsonl"], "xyz")
+}
+
+func TestWriteSupportBundleMissingLog(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeSupportBundle(&buf, supportBundleOptions{
+		logFile:   filepath.Join(t.TempDir(), "missing.log"),
+		tailLines: 10,
+	}))
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, reader.File, 4)
+}
+
+func TestHandleRequestToolsList(t *testing.T) {
+	resp := handleRequest(context.Background(), Request{ID: "1", Method: "tools/list"})
+	require.Nil(t, resp.Error)
+	tools := resp.Result.(map[string][]Tool)["tools"]
+	require.Len(t, tools, len(toolHandlers))
+	assert.True(t, sort.SliceIsSorted(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name }))
+
+	byName := make(map[string]Tool, len(tools))
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+	placeOrder := byName["placeOrder"].InputSchema
+	assert.Equal(t, []string{"accountId", "orderType", "quantity", "timeInForce"}, placeOrder.Required)
+	assert.Equal(t, "integer", placeOrder.Properties["quantity"].Type)
+	assert.Contains(t, placeOrder.Properties, "symbol", "a symbol may be given instead of contractId")
+	assert.Equal(t, []string{"Market", "Limit", "Stop", "StopLimit", "MIT"}, placeOrder.Properties["orderType"].Enum)
+	assert.NotEmpty(t, placeOrder.AllOf)
+
+	// Tools that do not declare their parameters take any object.
+	data, err := json.Marshal(byName["getAccounts"].InputSchema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "object", "properties": {}}`, string(data))
+}
+
+func TestHandleRequestRecoversFromPanic(t *testing.T) {
+	toolHandlers["panics"] = handlers.Handler{
+		Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			panic("faulty handler")
+		},
+	}
+	defer delete(toolHandlers, "panics")
+
+	resp := handleRequest(context.Background(), Request{ID: "1", Method: "panics", Params: json.RawMessage(`{}`)})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, "1", resp.ID)
+	assert.Equal(t, 500, resp.Error.Code)
+	assert.Contains(t, resp.Error.Message, "Internal error while handling panics")
+
+	in := strings.NewReader("{\"id\":\"1\",\"method\":\"panics\"}\n{\"id\":\"2\",\"method\":\"ping\"}\n")
+	var out bytes.Buffer
+	serveStdio(in, &out)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 2)
+	var second Response
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, "pong", second.Result)
+}
+
+func TestReloadConfig(t *testing.T) {
+	defer func() {
+		configStore = nil
+		logLevel.Set(slog.LevelInfo)
+	}()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"logLevel": "warn"}`), 0600))
+	store, err := config.NewStore(path)
+	require.NoError(t, err)
+	configStore = store
+	applyConfig(store.Current())
+	assert.Equal(t, slog.LevelWarn, logLevel.Level())
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"logLevel": "debug", "requestTimeout": "1ms"}`), 0600))
+	reloadConfig(store)
+	assert.Equal(t, slog.LevelDebug, logLevel.Level())
+
+	// The reloaded timeout bounds subsequent tool calls.
+	toolHandlers = handlers.Handlers{
+		"slow": {Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}},
+	}
+	defer func() { toolHandlers = handlers.NewHandlers(tradovateClient, handlers.WithExports(exports)) }()
+	resp := handleRequest(context.Background(), Request{ID: "1", Method: "slow"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, context.DeadlineExceeded.Error(), resp.Error.Message)
+
+	// An invalid file leaves the previous configuration active.
+	require.NoError(t, os.WriteFile(path, []byte(`{"logLevel": "loud"}`), 0600))
+	reloadConfig(store)
+	assert.Equal(t, slog.LevelDebug, logLevel.Level())
+}
+
+func TestSocketServerSessions(t *testing.T) {
+	dir, err := os.MkdirTemp("", "mcp")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "mcp.sock")
+
+	for _, tc := range []struct{ network, addr string }{
+		{"unix", path},
+		{"tcp", "127.0.0.1:0"},
+	} {
+		t.Run(tc.network, func(t *testing.T) {
+			server, err := newSocketServer(tc.network, tc.addr, nil)
+			require.NoError(t, err)
+			if tc.network == "unix" {
+				info, err := os.Stat(path)
+				require.NoError(t, err)
+				assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+			}
+			done := make(chan error, 1)
+			go func() { done <- server.serve() }()
+
+			// Two sessions are served concurrently over the same listener.
+			first, err := net.Dial(tc.network, server.listener.Addr().String())
+			require.NoError(t, err)
+			defer first.Close()
+			second, err := net.Dial(tc.network, server.listener.Addr().String())
+			require.NoError(t, err)
+			defer second.Close()
+
+			for i, conn := range []net.Conn{second, first} {
+				_, err := fmt.Fprintf(conn, "{\"id\":\"%d\",\"method\":\"ping\"}\n", i)
+				require.NoError(t, err)
+
+				var resp Response
+				require.NoError(t, json.NewDecoder(conn).Decode(&resp))
+				assert.Equal(t, fmt.Sprint(i), resp.ID)
+				assert.Equal(t, "pong", resp.Result)
+			}
+
+			require.NoError(t, server.close())
+			require.NoError(t, <-done)
+		})
+	}
+
+	// A regular file at the socket path is never removed.
+	regular := filepath.Join(dir, "regular")
+	require.NoError(t, os.WriteFile(regular, nil, 0600))
+	_, err = newSocketServer("unix", regular, nil)
+	assert.ErrorContains(t, err, "not a socket")
+}
+
+func TestHandleRequestPagination(t *testing.T) {
+	bars := make([]int, 150)
+	toolHandlers = handlers.Handlers{
+		"bars": {Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			assert.NotContains(t, params, "limit")
+			return bars, nil
+		}},
+	}
+	defer func() { toolHandlers = handlers.NewHandlers(tradovateClient, handlers.WithExports(exports)) }()
+
+	resp := handleRequest(context.Background(), Request{ID: "1", Method: "bars", Params: json.RawMessage(`{"limit": 40}`)})
+	require.Nil(t, resp.Error)
+	page, ok := resp.Result.(handlers.Page)
+	require.True(t, ok)
+	assert.Equal(t, 150, page.TotalCount)
+	assert.Len(t, page.Items, 40)
+	assert.NotEmpty(t, page.NextCursor)
+
+	resp = handleRequest(context.Background(), Request{ID: "2", Method: "bars", Params: json.RawMessage(`{"limit": -1}`)})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, 400, resp.Error.Code)
+}
+
+func TestShutdown(t *testing.T) {
+	defer func() { serverLifecycle = newLifecycle() }()
+
+	in := strings.NewReader("{\"id\":\"1\",\"method\":\"shutdown\"}\n{\"id\":\"2\",\"method\":\"ping\"}\n")
+	var out bytes.Buffer
+	serveStdio(in, &out)
+
+	// Requests after shutdown are not read.
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 1)
+	var resp Response
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &resp))
+	assert.Equal(t, map[string]interface{}{"status": "shutting down"}, resp.Result)
+
+	select {
+	case <-serverLifecycle.Done():
+	default:
+		t.Fatal("shutdown was not signalled")
+	}
+
+	// Requests arriving on other sessions are rejected.
+	rejected := handleRequest(context.Background(), Request{ID: "3", Method: "ping"})
+	require.NotNil(t, rejected.Error)
+	assert.Equal(t, 503, rejected.Error.Code)
+
+	var order []string
+	serverLifecycle.onShutdown(func() { order = append(order, "first") })
+	serverLifecycle.onShutdown(func() { order = append(order, "second") })
+	serverLifecycle.finish()
+	assert.Equal(t, []string{"second", "first"}, order)
+}
+
+func TestShutdownWaitsForInFlightRequests(t *testing.T) {
+	defer func() {
+		serverLifecycle = newLifecycle()
+		toolHandlers = handlers.NewHandlers(tradovateClient, handlers.WithExports(exports))
+	}()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	toolHandlers = handlers.Handlers{
+		"slow": {Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			close(started)
+			<-release
+			return "done", nil
+		}},
+	}
+
+	result := make(chan Response, 1)
+	go func() { result <- handleRequest(context.Background(), Request{ID: "1", Method: "slow"}) }()
+	<-started
+
+	handleRequest(context.Background(), Request{ID: "2", Method: "shutdown"})
+	finished := make(chan struct{})
+	go func() {
+		serverLifecycle.finish()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		t.Fatal("finish returned before the in-flight request completed")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	assert.Equal(t, "done", (<-result).Result)
+	<-finished
+}
+
+func TestSocketServerShutdown(t *testing.T) {
+	defer func() { serverLifecycle = newLifecycle() }()
+
+	server, err := newSocketServer("tcp", "127.0.0.1:0", nil)
+	require.NoError(t, err)
+	done := make(chan error, 1)
+	go func() { done <- server.serve() }()
+	go func() {
+		<-serverLifecycle.Done()
+		server.close()
+	}()
+
+	idle, err := net.Dial("tcp", server.listener.Addr().String())
+	require.NoError(t, err)
+	defer idle.Close()
+	conn, err := net.Dial("tcp", server.listener.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = io.WriteString(conn, "{\"id\":\"1\",\"method\":\"shutdown\"}\n")
+	require.NoError(t, err)
+	var resp Response
+	require.NoError(t, json.NewDecoder(conn).Decode(&resp))
+	assert.Nil(t, resp.Error)
+
+	// The idle session is ended and the server stops.
+	require.NoError(t, <-done)
+	_, err = idle.Read(make([]byte, 1))
+	assert.Error(t, err)
+}
+
+func TestHandleRequestRateLimit(t *testing.T) {
+	defer func() {
+		configStore = nil
+		rateLimiter = ratelimit.New()
+	}()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"rateLimits": {
+		"default": {"limit": 60, "window": "1m"},
+		"tools": {"getFills": {"limit": 1, "window": "1h"}}
+	}}`), 0600))
+	store, err := config.NewStore(path)
+	require.NoError(t, err)
+	configStore = store
+
+	resp := handleRequest(context.Background(), Request{ID: "1", Method: "getFills", Params: json.RawMessage(`{}`)})
+	require.NotNil(t, resp.Error)
+	assert.NotEqual(t, 429, resp.Error.Code)
+
+	resp = handleRequest(context.Background(), Request{ID: "2", Method: "getFills", Params: json.RawMessage(`{}`)})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, 429, resp.Error.Code)
+	assert.Contains(t, resp.Error.Message, "Rate limited: retry getFills after")
+	data, ok := resp.Error.Data.(RateLimitData)
+	require.True(t, ok)
+	assert.Equal(t, "getFills", data.Tool)
+	assert.InDelta(t, time.Hour.Milliseconds(), data.RetryAfterMs, 1000)
+
+	// Other tools fall back to the default limit, and ping is never limited.
+	resp = handleRequest(context.Background(), Request{ID: "3", Method: "unknownTool"})
+	assert.Equal(t, 404, resp.Error.Code)
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, handleRequest(context.Background(), Request{ID: "4", Method: "ping"}).Error)
+	}
+}
+
+func TestHandleRequestSearchContracts(t *testing.T) {
+	var suggestLimit string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/product/list":
+			w.Write([]byte(`[{"id": 2, "name": "MNQ", "description": "Micro E-mini Nasdaq-100"}]`))
+		case "/contract/suggest":
+			suggestLimit = r.URL.Query().Get("l")
+			w.Write([]byte(`[{"id": 21, "name": "MNQZ98", "contractMaturityId": 201}, {"id": 22, "name": "MNQH99", "contractMaturityId": 202}]`))
+		case "/contractMaturity/item":
+			dates := map[string]string{"201": "2098-12-19T14:30:00Z", "202": "2099-03-20T13:30:00Z"}
+			id := r.URL.Query().Get("id")
+			fmt.Fprintf(w, `{"id": %s, "productId": 2, "expirationDate": %q}`, id, dates[id])
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c := client.NewTradovateClient()
+	c.SetBaseURL(server.URL)
+	previous := toolHandlers
+	toolHandlers = handlers.NewHandlers(c)
+	defer func() { toolHandlers = previous }()
+
+	// maxResults reaches the search, while limit pages its results.
+	resp := handleRequest(context.Background(), Request{ID: "1", Method: "searchContracts", Params: json.RawMessage(`{"text": "MNQ", "maxResults": 3, "limit": 1}`)})
+	require.Nil(t, resp.Error)
+	assert.Equal(t, "3", suggestLimit)
+	page, ok := resp.Result.(handlers.Page)
+	require.True(t, ok)
+	assert.Equal(t, 2, page.TotalCount)
+	assert.Len(t, page.Items, 1)
+	assert.NotEmpty(t, page.NextCursor)
+
+	resp = handleRequest(context.Background(), Request{ID: "2", Method: "searchContracts", Params: json.RawMessage(`{"text": "MNQ"}`)})
+	require.Nil(t, resp.Error)
+	assert.Equal(t, "10", suggestLimit)
+
+	resp = handleRequest(context.Background(), Request{ID: "3", Method: "searchContracts", Params: json.RawMessage(`{"text": "MNQ", "maxResults": 0}`)})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, 400, resp.Error.Code)
+	assert.Equal(t, "invalid maxResults", resp.Error.Message)
+}
+
+func TestHandleRequestChallenge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"p-ticket": "ticket-1", "p-time": 0, "p-captcha": true})
+	}))
+	defer server.Close()
+
+	c := client.NewTradovateClient()
+	c.SetBaseURL(server.URL)
+	previous := tradovateClient
+	tradovateClient = c
+	defer func() { tradovateClient = previous }()
+
+	resp := handleRequest(context.Background(), Request{ID: "1", Method: "authenticate"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, 401, resp.Error.Code)
+	assert.Contains(t, resp.Error.Message, "Action required: tradovate requires a captcha")
+	data, ok := resp.Error.Data.(ChallengeData)
+	require.True(t, ok)
+	assert.Equal(t, ChallengeData{
+		ActionRequired: client.ChallengeCaptcha,
+		Instructions:   "log in to the Tradovate web trader from this network, then try again",
+		Resume:         "authenticate",
+	}, data)
+}
+
+func TestHandleRequestSessionConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"errorText": "Session already exists for this user"})
+	}))
+	defer server.Close()
+
+	c := client.NewTradovateClient()
+	c.SetBaseURL(server.URL)
+	previous := tradovateClient
+	tradovateClient = c
+	defer func() { tradovateClient = previous }()
+
+	resp := handleRequest(context.Background(), Request{ID: "1", Method: "authenticate"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, 409, resp.Error.Code)
+	assert.Contains(t, resp.Error.Message, "another tradovate session is already open")
+}
+
+func TestHandleRequestAPIError(t *testing.T) {
+	status := http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"errorText": "Order not found"})
+	}))
+	defer server.Close()
+
+	c := client.NewTradovateClient()
+	c.SetBaseURL(server.URL)
+	c.SetRetryPolicy(client.RetryPolicy{MaxAttempts: 1})
+	previous := toolHandlers
+	toolHandlers = handlers.NewHandlers(c)
+	defer func() { toolHandlers = previous }()
+
+	resp := handleRequest(context.Background(), Request{ID: "1", Method: "getFills", Params: json.RawMessage(`{"orderId": 42}`)})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, 404, resp.Error.Code)
+	assert.Equal(t, APIErrorData{Endpoint: "/fill/list/42", Status: 404, ErrorText: "Order not found"}, resp.Error.Data)
+
+	// Tradovate's own failures are reported as a bad gateway.
+	status = http.StatusServiceUnavailable
+	resp = handleRequest(context.Background(), Request{ID: "2", Method: "getFills", Params: json.RawMessage(`{"orderId": 42}`)})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, 502, resp.Error.Code)
+	data, ok := resp.Error.Data.(APIErrorData)
+	require.True(t, ok)
+	assert.True(t, data.Retryable)
+
+	// Bad parameters are the caller's fault, not a server failure.
+	for _, params := range []string{`{}`, `{"orderId": "forty-two"}`} {
+		resp = handleRequest(context.Background(), Request{ID: "3", Method: "getFills", Params: json.RawMessage(params)})
+		require.NotNil(t, resp.Error)
+		assert.Equal(t, 400, resp.Error.Code, params)
+		assert.Nil(t, resp.Error.Data)
+	}
+	resp = handleRequest(context.Background(), Request{ID: "4", Method: "placeOrder", Params: json.RawMessage(`{"accountId": 1, "contractId": 1234, "orderType": "Limit", "quantity": 1, "timeInForce": "Day"}`)})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, 400, resp.Error.Code)
+	assert.Equal(t, "price is required when orderType is Limit", resp.Error.Message)
+}
+
+func TestHandleRequestResources(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"contractId": 1234, "timestamp": 1710864000, "open": 5100, "high": 5102.25, "low": 5099.5, "close": 5101, "volume": 42}]`))
+	}))
+	defer server.Close()
+
+	c := client.NewTradovateClient()
+	c.SetBaseURL(server.URL)
+	previous := toolHandlers
+	toolHandlers = handlers.NewHandlers(c, handlers.WithExports(exports))
+	defer func() { toolHandlers = previous }()
+
+	resp := handleRequest(context.Background(), Request{ID: "1", Method: "exportHistoricalData", Params: json.RawMessage(
+		`{"contractId": 1234, "startTime": "2024-03-19T00:00:00Z", "endTime": "2024-03-20T00:00:00Z", "interval": "1h"}`)})
+	require.Nil(t, resp.Error)
+	export := resp.Result.(handlers.HistoricalExport)
+	assert.Equal(t, 1, export.Rows)
+
+	resp = handleRequest(context.Background(), Request{ID: "2", Method: "resources/list"})
+	require.Nil(t, resp.Error)
+	resources := resp.Result.(map[string][]handlers.Resource)["resources"]
+	require.NotEmpty(t, resources)
+	assert.Equal(t, export.URI, resources[len(resources)-1].URI)
+
+	resp = handleRequest(context.Background(), Request{ID: "3", Method: "resources/read", Params: json.RawMessage(`{"uri": "` + export.URI + `"}`)})
+	require.Nil(t, resp.Error)
+	contents := resp.Result.(map[string][]handlers.ResourceContents)["contents"]
+	require.Len(t, contents, 1)
+	assert.Equal(t, "text/csv", contents[0].MimeType)
+	assert.Equal(t, "time,contractId,open,high,low,close,volume\n2024-03-19T16:00:00Z,1234,5100,5102.25,5099.5,5101,42\n", contents[0].Text)
+
+	resp = handleRequest(context.Background(), Request{ID: "4", Method: "resources/read", Params: json.RawMessage(`{"uri": "tradovate://exports/0"}`)})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, 404, resp.Error.Code)
+
+	resp = handleRequest(context.Background(), Request{ID: "5", Method: "resources/read"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, 400, resp.Error.Code)
+}
+
+func TestDiagnose(t *testing.T) {
+	serverTime := time.Date(2024, 12, 17, 12, 0, 0, 0, time.UTC)
+	rest := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer rest.Close()
+	md := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	md.Close() // unreachable
+
+	for _, key := range requiredEnvVars {
+		t.Setenv(key, "value")
+	}
+
+	authErr := errors.New("authentication failed: Incorrect username or password")
+	checks := diagnose(context.Background(), doctorOptions{
+		restURL:       rest.URL,
+		marketDataURL: md.URL,
+		httpClient:    rest.Client(),
+		authenticate:  func(ctx context.Context) error { return authErr },
+		now:           func() time.Time { return serverTime.Add(30 * time.Second) },
+	})
+
+	statuses := map[string]string{}
+	for _, check := range checks {
+		statuses[check.Name] = check.Status
+	}
+	assert.Equal(t, map[string]string{
+		"Environment variables":      checkOK,
+		"REST API reachable":         checkOK,
+		"Market data host reachable": checkFail,
+		"Clock skew":                 checkWarn,
+		"Demo authentication":        checkFail,
+	}, statuses)
+
+	var out bytes.Buffer
+	assert.Equal(t, 2, printDoctorReport(&out, checks))
+	assert.Contains(t, out.String(), "[WARN] Clock skew: local clock differs from Tradovate by 30s")
+	assert.Contains(t, out.String(), "Incorrect username or password")
+	assert.Contains(t, out.String(), "-> Verify TRADOVATE_USERNAME")
+}
+
+func TestDiagnoseMissingEnvVars(t *testing.T) {
+	for _, key := range requiredEnvVars {
+		t.Setenv(key, "")
+	}
+	t.Setenv("TRADOVATE_USERNAME", "trader")
+
+	called := false
+	checks := diagnose(context.Background(), doctorOptions{
+		restURL:       "http://127.0.0.1:0",
+		marketDataURL: "http://127.0.0.1:0",
+		httpClient:    http.DefaultClient,
+		authenticate:  func(ctx context.Context) error { called = true; return nil },
+		now:           time.Now,
+	})
+
+	assert.False(t, called)
+	assert.Equal(t, checkFail, checks[0].Status)
+	assert.NotContains(t, checks[0].Detail, "TRADOVATE_USERNAME")
+	assert.Contains(t, checks[0].Detail, "TRADOVATE_PASSWORD")
+	assert.Equal(t, checkSkip, checks[len(checks)-1].Status)
+}
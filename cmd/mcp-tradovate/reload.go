@@ -0,0 +1,58 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/config"
+)
+
+var (
+	configStore *config.Store
+	logLevel    slog.LevelVar
+)
+
+// applyConfig puts the process-wide settings of cfg into effect. Settings read
+// per call, such as risk limits and timeouts, are picked up from configStore.
+func applyConfig(cfg *config.Config) {
+	logLevel.Set(cfg.Level())
+	if c, ok := tradovateClient.(*client.TradovateClient); ok {
+		c.SetCompression(!cfg.DisableCompression)
+		c.SetEntitlementCheck(cfg.VerifyEntitlements)
+		if policy, err := client.ParseTickPolicy(cfg.TickPrices); err == nil {
+			c.SetTickPolicy(policy)
+		}
+		c.SetOperationTimeouts(client.OperationTimeouts{
+			Auth:       cfg.Timeouts.Auth.Duration,
+			MarketData: cfg.Timeouts.MarketData.Duration,
+			Historical: cfg.Timeouts.Historical.Duration,
+			Trading:    cfg.Timeouts.Trading.Duration,
+		})
+	}
+}
+
+// watchConfigReload reloads the configuration file whenever the process
+// receives SIGHUP. A file that fails to load leaves the previous
+// configuration in place.
+func watchConfigReload(store *config.Store) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			reloadConfig(store)
+		}
+	}()
+}
+
+func reloadConfig(store *config.Store) {
+	cfg, err := store.Reload()
+	if err != nil {
+		slog.Error("configuration reload failed; keeping previous configuration", "error", err)
+		return
+	}
+	applyConfig(cfg)
+	slog.Info("configuration reloaded", "logLevel", cfg.Level(), "requestTimeout", cfg.RequestTimeout.Duration)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// lifecycle tracks in-flight requests and coordinates a clean shutdown of
+// the server once one has been requested through the shutdown method.
+type lifecycle struct {
+	mu       sync.Mutex
+	idle     *sync.Cond
+	active   int
+	stopping bool
+	done     chan struct{}
+	hooks    []func()
+}
+
+// serverLifecycle is the lifecycle of the running server.
+var serverLifecycle = newLifecycle()
+
+func newLifecycle() *lifecycle {
+	l := &lifecycle{done: make(chan struct{})}
+	l.idle = sync.NewCond(&l.mu)
+	return l
+}
+
+// begin records the start of a request. It returns false once shutdown has
+// been requested, in which case the request must be rejected.
+func (l *lifecycle) begin() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopping {
+		return false
+	}
+	l.active++
+	return true
+}
+
+// end records the completion of a request started with begin.
+func (l *lifecycle) end() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	if l.active == 0 {
+		l.idle.Broadcast()
+	}
+}
+
+// requestShutdown stops new requests from being accepted and signals the
+// transports to stop. It is safe to call more than once.
+func (l *lifecycle) requestShutdown() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopping {
+		return
+	}
+	l.stopping = true
+	close(l.done)
+}
+
+// Done returns a channel that is closed once shutdown has been requested.
+func (l *lifecycle) Done() <-chan struct{} {
+	return l.done
+}
+
+// isStopping reports whether shutdown has been requested.
+func (l *lifecycle) isStopping() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stopping
+}
+
+// onShutdown registers fn to run after in-flight requests have drained, for
+// example to cancel streaming subscriptions.
+func (l *lifecycle) onShutdown(fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, fn)
+}
+
+// finish waits for in-flight requests to complete and runs the registered
+// shutdown hooks in reverse order of registration.
+func (l *lifecycle) finish() {
+	l.mu.Lock()
+	for l.active > 0 {
+		l.idle.Wait()
+	}
+	hooks := l.hooks
+	l.hooks = nil
+	l.mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i]()
+	}
+	slog.Info("server stopped")
+}
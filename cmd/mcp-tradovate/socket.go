@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/logging"
+)
+
+// socketServer serves the newline-delimited MCP protocol to any number of
+// concurrent clients connected over a TCP or Unix socket. Each connection is
+// its own session; all sessions share the process's Tradovate client.
+type socketServer struct {
+	listener net.Listener
+	closing  atomic.Bool
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+}
+
+// serveSocket listens on network ("tcp" or "unix") at addr and serves
+// sessions until the listener fails or shutdown is requested. When tlsConfig is non-nil TCP
+// connections are wrapped in TLS.
+func serveSocket(network, addr string, tlsConfig *tls.Config) error {
+	server, err := newSocketServer(network, addr, tlsConfig)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-serverLifecycle.Done()
+		server.close()
+	}()
+	return server.serve()
+}
+
+func newSocketServer(network, addr string, tlsConfig *tls.Config) (*socketServer, error) {
+	if network == "unix" {
+		if err := removeStaleSocket(addr); err != nil {
+			return nil, err
+		}
+	}
+
+	listener, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	if network == "unix" {
+		// Only the owning user may connect; the socket grants full trading access.
+		if err := os.Chmod(addr, 0600); err != nil {
+			listener.Close()
+			return nil, fmt.Errorf("failed to restrict socket permissions: %w", err)
+		}
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	return &socketServer{
+		listener: listener,
+		conns:    make(map[net.Conn]struct{}),
+	}, nil
+}
+
+// removeStaleSocket deletes a socket file left behind by a previous run so
+// that the address can be reused. Any other kind of file is left alone.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", path, err)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("refusing to replace %s: not a socket", path)
+	}
+	return os.Remove(path)
+}
+
+// serve accepts connections until the listener is closed, serving each on
+// its own goroutine. After close it returns once every session has ended.
+func (s *socketServer) serve() error {
+	slog.Info("serving MCP sessions", "network", s.listener.Addr().Network(), "addr", s.listener.Addr().String())
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if s.closing.Load() {
+				s.wg.Wait()
+				return nil
+			}
+			return fmt.Errorf("failed to accept connection: %w", err)
+		}
+
+		s.mu.Lock()
+		if s.closing.Load() {
+			s.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.serveSession(conn)
+	}
+}
+
+// serveSession serves requests on conn until the client disconnects.
+func (s *socketServer) serveSession(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	ctx := logging.WithSessionID(context.Background(), newSessionID())
+	slog.InfoContext(ctx, "session opened", "remoteAddr", conn.RemoteAddr().String())
+	if err := serveStream(ctx, conn, conn); err != nil && !s.closing.Load() {
+		slog.WarnContext(ctx, "session ended with error", "error", err)
+	}
+	slog.InfoContext(ctx, "session closed")
+}
+
+// close stops accepting connections and ends every session once its
+// in-flight request has been answered, waiting for them to finish.
+func (s *socketServer) close() error {
+	s.mu.Lock()
+	s.closing.Store(true)
+	err := s.listener.Close()
+
+	// Expiring the read deadline unblocks sessions waiting for their next
+	// request without interrupting a response that is being written.
+	for conn := range s.conns {
+		conn.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// newSessionID returns a random identifier for a client session.
+func newSessionID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/logging"
+)
+
+// supportBundleTailLines is the number of trailing log and audit lines
+// included in a support bundle.
+const supportBundleTailLines = 500
+
+// secretEnvVars lists environment variables whose values never leave the host.
+var secretEnvVars = map[string]bool{
+	"TRADOVATE_PASSWORD": true,
+	"TRADOVATE_SEC":      true,
+	"TRADOVATE_CID":      true,
+	"MCP_AUTH_TOKEN":     true,
+}
+
+// requiredEnvVars lists the environment variables needed to authenticate.
+var requiredEnvVars = []string{
+	"TRADOVATE_USERNAME",
+	"TRADOVATE_PASSWORD",
+	"TRADOVATE_APP_ID",
+	"TRADOVATE_APP_VERSION",
+	"TRADOVATE_CID",
+	"TRADOVATE_SEC",
+}
+
+// bearerPattern matches bearer tokens embedded in free-form log text.
+var bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+
+// keyValuePattern matches key/value pairs, such as password=x or
+// "accessToken":"x", in free-form log text. Submatch 2 is the key.
+var keyValuePattern = regexp.MustCompile(`("?([A-Za-z][A-Za-z0-9_-]*)"?\s*[:=]\s*"?)[^"\s,}]+`)
+
+// supportBundleOptions configures what goes into a support bundle.
+type supportBundleOptions struct {
+	logFile   string // Server log file to include, if any
+	auditLog  string // Audit log to include, if any
+	tailLines int    // Number of trailing lines to keep from each log
+}
+
+// runSupportBundle implements the support-bundle subcommand.
+func runSupportBundle(args []string) error {
+	fs := flag.NewFlagSet("support-bundle", flag.ContinueOnError)
+	output := fs.String("o", fmt.Sprintf("mcp-tradovate-support-%s.zip", time.Now().UTC().Format("20060102-150405")), "Path of the archive to write")
+	logFile := fs.String("log-file", "", "Server log file to include")
+	auditLog := fs.String("audit-log", "", "Audit log to include")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(*output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer f.Close()
+
+	if err := writeSupportBundle(f, supportBundleOptions{
+		logFile:   *logFile,
+		auditLog:  *auditLog,
+		tailLines: supportBundleTailLines,
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Support bundle written to %s\n", *output)
+	return f.Close()
+}
+
+// writeSupportBundle writes a zip archive of redacted diagnostics to w.
+func writeSupportBundle(w io.Writer, opts supportBundleOptions) error {
+	archive := zip.NewWriter(w)
+
+	if err := writeBundleJSON(archive, "version.json", buildInfo()); err != nil {
+		return err
+	}
+	if err := writeBundleJSON(archive, "config.json", redactedConfig()); err != nil {
+		return err
+	}
+	if err := writeBundleJSON(archive, "environment.json", environmentDiagnostics()); err != nil {
+		return err
+	}
+	if opts.logFile != "" {
+		if err := writeBundleTail(archive, "logs.txt", opts.logFile, opts.tailLines); err != nil {
+			return err
+		}
+	}
+	if opts.auditLog != "" {
+		if err := writeBundleTail(archive, "audit.jsonl", opts.auditLog, opts.tailLines); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finalize bundle: %w", err)
+	}
+	return nil
+}
+
+func writeBundleJSON(archive *zip.Writer, name string, value interface{}) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeBundleTail adds the redacted last n lines of the file at path. A
+// missing file is recorded in the entry rather than failing the bundle.
+func writeBundleTail(archive *zip.Writer, name, path string, n int) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+
+	lines, err := tailFile(path, n)
+	if err != nil {
+		_, err = fmt.Fprintf(entry, "unavailable: %v\n", err)
+		return err
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(entry, redactText(line)); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// tailFile returns the last n lines of the file at path.
+func tailFile(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// redactText strips bearer tokens and credential-like key/value pairs from s.
+func redactText(s string) string {
+	s = bearerPattern.ReplaceAllString(s, "${1}[REDACTED]")
+	return keyValuePattern.ReplaceAllStringFunc(s, func(pair string) string {
+		match := keyValuePattern.FindStringSubmatch(pair)
+		if !logging.IsSecretKey(match[2]) {
+			return pair
+		}
+		return match[1] + "[REDACTED]"
+	})
+}
+
+// buildInfo describes the running binary.
+func buildInfo() map[string]interface{} {
+	info := map[string]interface{}{
+		"goVersion": runtime.Version(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info["path"] = bi.Path
+		info["version"] = bi.Main.Version
+		settings := map[string]string{}
+		for _, setting := range bi.Settings {
+			if strings.HasPrefix(setting.Key, "vcs.") || setting.Key == "GOOS" || setting.Key == "GOARCH" {
+				settings[setting.Key] = setting.Value
+			}
+		}
+		info["settings"] = settings
+	}
+	return info
+}
+
+// redactedConfig reports the server's environment configuration with
+// secrets replaced by whether they are set.
+func redactedConfig() map[string]string {
+	config := map[string]string{}
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, "TRADOVATE_") && !strings.HasPrefix(key, "MCP_") && key != "PORT" {
+			continue
+		}
+		if secretEnvVars[key] || logging.IsSecretKey(key) {
+			value = "[REDACTED]"
+		}
+		config[key] = value
+	}
+	return config
+}
+
+// environmentDiagnostics captures host details useful for triage.
+func environmentDiagnostics() map[string]interface{} {
+	now := time.Now()
+	zone, offset := now.Zone()
+
+	var missing []string
+	for _, key := range requiredEnvVars {
+		if os.Getenv(key) == "" {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+
+	return map[string]interface{}{
+		"os":              runtime.GOOS,
+		"arch":            runtime.GOARCH,
+		"numCPU":          runtime.NumCPU(),
+		"time":            now.UTC().Format(time.RFC3339),
+		"timezone":        zone,
+		"utcOffsetSecs":   offset,
+		"missingEnvVars":  missing,
+		"proxyConfigured": os.Getenv("HTTPS_PROXY") != "" || os.Getenv("https_proxy") != "",
+	}
+}
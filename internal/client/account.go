@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// ResolveAccount finds one of the user's accounts by its numeric ID or its
+// name, matched case-insensitively. Accounts that are no longer active are
+// refused. The client keeps no selection: every caller that shares it may
+// target a different account.
+func (c *TradovateClient) ResolveAccount(ctx context.Context, account string) (*models.Account, error) {
+	account = strings.TrimSpace(account)
+	if account == "" {
+		return nil, fmt.Errorf("account ID or name is required")
+	}
+
+	accounts, err := c.GetAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing accounts: %w", err)
+	}
+
+	id, err := strconv.Atoi(account)
+	isID := err == nil
+	for i := range accounts {
+		if (isID && accounts[i].ID == id) || strings.EqualFold(accounts[i].Name, account) {
+			if !accounts[i].Active {
+				return nil, fmt.Errorf("account %s is not active", accounts[i].Name)
+			}
+			return &accounts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no account matches %q", account)
+}
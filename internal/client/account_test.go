@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/account/list", r.URL.Path)
+		json.NewEncoder(w).Encode([]models.Account{
+			{ID: 101, Name: "DEMO101", Active: true},
+			{ID: 202, Name: "DEMO202", Active: true},
+			{ID: 303, Name: "CLOSED303", Active: false},
+		})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	account, err := client.ResolveAccount(context.Background(), "demo202")
+	require.NoError(t, err)
+	assert.Equal(t, 202, account.ID)
+
+	account, err = client.ResolveAccount(context.Background(), "101")
+	require.NoError(t, err)
+	assert.Equal(t, "DEMO101", account.Name)
+
+	_, err = client.ResolveAccount(context.Background(), "999")
+	assert.EqualError(t, err, `no account matches "999"`)
+	_, err = client.ResolveAccount(context.Background(), "CLOSED303")
+	assert.EqualError(t, err, "account CLOSED303 is not active")
+	_, err = client.ResolveAccount(context.Background(), " ")
+	assert.EqualError(t, err, "account ID or name is required")
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// GetAccountAlerts retrieves the notices raised for the user, newest first:
+// margin calls, trade desk messages and system notices from the broker, and
+// the user's own alerts that have triggered.
+// Parameters:
+// - accountID: Only return broker notices about this account, along with those about the user; 0 for every account
+// - unhandledOnly: Skip alerts that have been read or dealt with
+func (c *TradovateClient) GetAccountAlerts(ctx context.Context, accountID int, unhandledOnly bool) ([]models.AccountAlert, error) {
+	var brokerAlerts []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := c.getList(ctx, "/adminAlert/list", "broker alerts", &brokerAlerts); err != nil {
+		return nil, err
+	}
+	names := make(map[int]string, len(brokerAlerts))
+	for _, alert := range brokerAlerts {
+		names[alert.ID] = alert.Name
+	}
+
+	var brokerSignals []struct {
+		ID                 int    `json:"id"`
+		Timestamp          string `json:"timestamp"`
+		AdminAlertID       int    `json:"adminAlertId"`
+		RelatedToAccountID int    `json:"relatedToAccountId"`
+		Text               string `json:"text"`
+		Completed          bool   `json:"completed"`
+	}
+	if err := c.getList(ctx, "/adminAlertSignal/list", "broker alert signals", &brokerSignals); err != nil {
+		return nil, err
+	}
+
+	var userSignals []struct {
+		ID        int    `json:"id"`
+		Timestamp string `json:"timestamp"`
+		Text      string `json:"text"`
+		IsRead    bool   `json:"isRead"`
+	}
+	if err := c.getList(ctx, "/alertSignal/list", "alert signals", &userSignals); err != nil {
+		return nil, err
+	}
+
+	alerts := []models.AccountAlert{}
+	for _, signal := range brokerSignals {
+		if accountID != 0 && signal.RelatedToAccountID != 0 && signal.RelatedToAccountID != accountID {
+			continue
+		}
+		text := signal.Text
+		if text == "" {
+			text = names[signal.AdminAlertID]
+		}
+		alerts = append(alerts, models.AccountAlert{
+			ID:        signal.ID,
+			Source:    "broker",
+			AccountID: signal.RelatedToAccountID,
+			Timestamp: signal.Timestamp,
+			Text:      text,
+			Handled:   signal.Completed,
+		})
+	}
+	for _, signal := range userSignals {
+		alerts = append(alerts, models.AccountAlert{
+			ID:        signal.ID,
+			Source:    "user",
+			Timestamp: signal.Timestamp,
+			Text:      signal.Text,
+			Handled:   signal.IsRead,
+		})
+	}
+
+	if unhandledOnly {
+		unhandled := alerts[:0]
+		for _, alert := range alerts {
+			if !alert.Handled {
+				unhandled = append(unhandled, alert)
+			}
+		}
+		alerts = unhandled
+	}
+	// Timestamps are RFC 3339 in UTC, so they sort as strings.
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].Timestamp > alerts[j].Timestamp })
+	return alerts, nil
+}
+
+// getList decodes the entities returned by a list endpoint into v, naming
+// them what in errors.
+func (c *TradovateClient) getList(ctx context.Context, endpoint, what string, v interface{}) error {
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error decoding %s: %w", what, err)
+	}
+	return nil
+}
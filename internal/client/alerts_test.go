@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountAlerts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/adminAlert/list":
+			json.NewEncoder(w).Encode([]map[string]interface{}{{"id": 3, "name": "MarginCall"}})
+		case "/adminAlertSignal/list":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 10, "timestamp": "2024-06-03T14:00:00Z", "adminAlertId": 3, "relatedToAccountId": 12345},
+				{"id": 11, "timestamp": "2024-06-03T15:00:00Z", "adminAlertId": 3, "relatedToAccountId": 999},
+				{"id": 12, "timestamp": "2024-06-01T09:00:00Z", "adminAlertId": 4, "text": "Trading halted in CL", "completed": true},
+			})
+		case "/alertSignal/list":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": 20, "timestamp": "2024-06-03T14:30:00Z", "text": "ESM4 crossed 5300", "isRead": false},
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	alerts, err := client.GetAccountAlerts(context.Background(), 12345, false)
+	require.NoError(t, err)
+	assert.Equal(t, []models.AccountAlert{
+		{ID: 20, Source: "user", Timestamp: "2024-06-03T14:30:00Z", Text: "ESM4 crossed 5300"},
+		{ID: 10, Source: "broker", AccountID: 12345, Timestamp: "2024-06-03T14:00:00Z", Text: "MarginCall"},
+		{ID: 12, Source: "broker", Timestamp: "2024-06-01T09:00:00Z", Text: "Trading halted in CL", Handled: true},
+	}, alerts)
+
+	alerts, err = client.GetAccountAlerts(context.Background(), 0, true)
+	require.NoError(t, err)
+	var ids []int
+	for _, alert := range alerts {
+		ids = append(ids, alert.ID)
+	}
+	assert.Equal(t, []int{11, 20, 10}, ids)
+}
+
+func TestGetAccountAlertsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not json"))
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	_, err := client.GetAccountAlerts(context.Background(), 0, false)
+	assert.ErrorContains(t, err, "error decoding broker alerts")
+}
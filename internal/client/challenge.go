@@ -0,0 +1,42 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kinds of challenge Tradovate may answer an authentication attempt with.
+const (
+	ChallengeCaptcha      = "captcha"      // A captcha must be solved in the web trader
+	ChallengeVerification = "verification" // The login must be confirmed, e.g. a new device by email
+)
+
+// captchaInstructions tells the user how to clear a captcha challenge, which
+// the API itself offers no way to solve.
+const captchaInstructions = "log in to the Tradovate web trader from this network, then try again"
+
+// ChallengeError reports that Tradovate needs the user to act before it will
+// issue a token. Once they have, calling Authenticate again completes the
+// login, resuming with Ticket if Tradovate issued one.
+type ChallengeError struct {
+	Kind    string // ChallengeCaptcha or ChallengeVerification
+	Message string // What the user needs to do
+	Ticket  string // Penalty ticket the next attempt resumes with, if any
+}
+
+func (e *ChallengeError) Error() string {
+	if e.Kind == ChallengeCaptcha {
+		return fmt.Sprintf("tradovate requires a captcha: %s", e.Message)
+	}
+	return fmt.Sprintf("tradovate requires %s: %s", e.Kind, e.Message)
+}
+
+// verificationChallenge returns the challenge described by an
+// authentication errorText, or nil if it is an ordinary failure.
+func verificationChallenge(errorText string) *ChallengeError {
+	text := strings.ToLower(errorText)
+	if !strings.Contains(text, "verif") && !strings.Contains(text, "confirm") {
+		return nil
+	}
+	return &ChallengeError{Kind: ChallengeVerification, Message: errorText}
+}
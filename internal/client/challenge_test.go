@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticateCaptchaChallenge(t *testing.T) {
+	solved := false
+	var tickets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AuthRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		tickets = append(tickets, req.PenaltyTicket)
+		if !solved {
+			json.NewEncoder(w).Encode(map[string]interface{}{"p-ticket": "ticket-1", "p-time": 0, "p-captcha": true})
+			return
+		}
+		json.NewEncoder(w).Encode(AuthResponse{AccessToken: "test-token"})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	_, err := client.Authenticate(context.Background())
+	var challenge *ChallengeError
+	require.True(t, errors.As(err, &challenge))
+	assert.Equal(t, ChallengeCaptcha, challenge.Kind)
+	assert.Equal(t, "ticket-1", challenge.Ticket)
+	assert.Equal(t, captchaInstructions, challenge.Message)
+
+	// Once the user has solved it, authenticating again resumes with the ticket.
+	solved = true
+	resp, err := client.Authenticate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "test-token", resp.AccessToken)
+
+	_, err = client.Authenticate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "ticket-1", ""}, tickets)
+}
+
+func TestAuthenticateVerificationChallenge(t *testing.T) {
+	errorText := "Please verify this device using the link sent to your email"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(AuthResponse{ErrorText: errorText})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	_, err := client.Authenticate(context.Background())
+	var challenge *ChallengeError
+	require.True(t, errors.As(err, &challenge))
+	assert.Equal(t, ChallengeVerification, challenge.Kind)
+	assert.Equal(t, errorText, challenge.Message)
+	assert.EqualError(t, err, "authentication failed: tradovate requires verification: "+errorText)
+
+	assert.Nil(t, verificationChallenge("Incorrect username or password"))
+}
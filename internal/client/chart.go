@@ -0,0 +1,217 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// maxUnclaimedChartPackets bounds how many packets are held for a chart
+// whose md/getChart response has not been handled yet.
+const maxUnclaimedChartPackets = 64
+
+// ChartOptions describes the bars a chart subscription delivers.
+type ChartOptions struct {
+	UnderlyingType  string // "MinuteBar" (default), "Tick", "DailyBar" or "Custom"
+	ElementSize     int    // Size of each bar in ElementSizeUnit; defaults to 1
+	ElementSizeUnit string // "UnderlyingUnits" (default), "Volume", "Range", "Renko", "MomentumRange", "PointAndFigure" or "OFARange"
+	HistoricalBars  int    // Completed bars to deliver before live updates; defaults to 1
+}
+
+// chartPacket is one entry of a "chart" event.
+type chartPacket struct {
+	ID   int               `json:"id"`             // Subscription ID from md/getChart
+	EOH  bool              `json:"eoh,omitempty"`  // Marks the end of historical bars
+	Bars []models.ChartBar `json:"bars,omitempty"` // New or updated bars
+}
+
+// chartSeries turns the raw bar updates of one chart into completed and
+// in-progress bars. Its bars are only handled from the stream's read
+// goroutine; the subscription fields are guarded by the stream's mutex.
+type chartSeries struct {
+	contractID int
+	onBar      func(models.ChartBar)
+	last       *models.ChartBar // Newest bar seen, which may still be updating
+
+	request      map[string]interface{} // md/getChart body, resent on reconnect
+	historicalID int                    // Subscription IDs assigned by the last md/getChart
+	realtimeID   int
+	cancelled    bool // Whether the subscription was cancelled
+}
+
+// SubscribeChart streams OHLCV bars for contractID, calling onBar until the
+// subscription is cancelled. Each bar is delivered as in-progress every time
+// it updates and once more with Complete set when the next bar opens, so
+// callers never have to build candles from raw quotes. onBar is called from
+// the stream's read goroutine and must not block.
+func (c *TradovateClient) SubscribeChart(ctx context.Context, contractID int, opts ChartOptions, onBar func(models.ChartBar)) (*Subscription, error) {
+	if opts.UnderlyingType == "" {
+		opts.UnderlyingType = "MinuteBar"
+	}
+	if opts.ElementSize == 0 {
+		opts.ElementSize = 1
+	}
+	if opts.ElementSizeUnit == "" {
+		opts.ElementSizeUnit = "UnderlyingUnits"
+	}
+	if opts.HistoricalBars == 0 {
+		opts.HistoricalBars = 1
+	}
+	if opts.ElementSize < 0 || opts.HistoricalBars < 0 {
+		return nil, fmt.Errorf("invalid chart options: element size and historical bars must be positive")
+	}
+
+	if err := c.checkEntitlement(ctx, contractID, FeedHistorical); err != nil {
+		return nil, err
+	}
+
+	stream, err := c.marketDataStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{
+		"symbol": contractID,
+		"chartDescription": map[string]interface{}{
+			"underlyingType":  opts.UnderlyingType,
+			"elementSize":     opts.ElementSize,
+			"elementSizeUnit": opts.ElementSizeUnit,
+			"withHistogram":   false,
+		},
+		"timeRange": map[string]interface{}{"asMuchAsElements": opts.HistoricalBars},
+	}
+
+	series := &chartSeries{contractID: contractID, onBar: onBar, request: body}
+	if err := stream.requestChart(ctx, stream.live.current(), series); err != nil {
+		return nil, fmt.Errorf("error subscribing to chart for contract %d: %w", contractID, err)
+	}
+
+	return &Subscription{unsubscribe: func(ctx context.Context) error {
+		stream.mu.Lock()
+		series.cancelled = true
+		delete(stream.charts, series.historicalID)
+		delete(stream.charts, series.realtimeID)
+		realtimeID := series.realtimeID
+		stream.mu.Unlock()
+		if _, err := stream.live.current().request(ctx, "md/cancelChart", "", map[string]interface{}{"subscriptionId": realtimeID}); err != nil {
+			return fmt.Errorf("error cancelling chart for contract %d: %w", contractID, err)
+		}
+		return nil
+	}}, nil
+}
+
+// requestChart asks for the bars of series on socket and routes them to it
+// under the subscription IDs Tradovate assigns, replacing any it had.
+func (s *marketDataStream) requestChart(ctx context.Context, socket *tradovateSocket, series *chartSeries) error {
+	s.beginChart()
+	data, err := socket.request(ctx, "md/getChart", "", series.request)
+	if err != nil {
+		s.endChart(nil, 0, 0)
+		return err
+	}
+	var ids struct {
+		HistoricalID int `json:"historicalId"`
+		RealtimeID   int `json:"realtimeId"`
+	}
+	if err := json.Unmarshal(data, &ids); err != nil {
+		s.endChart(nil, 0, 0)
+		return fmt.Errorf("error decoding chart subscription: %w", err)
+	}
+	s.endChart(series, ids.HistoricalID, ids.RealtimeID)
+	return nil
+}
+
+// beginChart starts holding chart packets until endChart is called.
+func (s *marketDataStream) beginChart() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pendingCharts++
+}
+
+// endChart registers series under its subscription IDs, replaying any
+// packets that arrived first. A nil series abandons the request, and a
+// cancelled one is not registered.
+func (s *marketDataStream) endChart(series *chartSeries, historicalID, realtimeID int) {
+	s.mu.Lock()
+	var held []chartPacket
+	if series != nil && !series.cancelled {
+		delete(s.charts, series.historicalID)
+		delete(s.charts, series.realtimeID)
+		series.historicalID, series.realtimeID = historicalID, realtimeID
+		for _, id := range []int{historicalID, realtimeID} {
+			s.charts[id] = series
+			held = append(held, s.unclaimed[id]...)
+			delete(s.unclaimed, id)
+		}
+	}
+	s.pendingCharts--
+	if s.pendingCharts == 0 {
+		s.unclaimed = make(map[int][]chartPacket)
+	}
+	s.mu.Unlock()
+
+	for _, packet := range held {
+		series.handle(packet)
+	}
+}
+
+// handleChartEvent routes the packets of a "chart" event to their series.
+func (s *marketDataStream) handleChartEvent(data json.RawMessage) {
+	var payload struct {
+		Charts []chartPacket `json:"charts"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		slog.Warn("ignoring malformed chart event", "error", err)
+		return
+	}
+
+	for _, packet := range payload.Charts {
+		s.mu.Lock()
+		series, ok := s.charts[packet.ID]
+		if !ok && s.pendingCharts > 0 && len(s.unclaimed[packet.ID]) < maxUnclaimedChartPackets {
+			s.unclaimed[packet.ID] = append(s.unclaimed[packet.ID], packet)
+		}
+		s.mu.Unlock()
+		if ok {
+			series.handle(packet)
+		}
+	}
+}
+
+// handle delivers the bars of packet. A bar is reported complete once a
+// newer bar arrives; the newest bar is reported in progress.
+func (cs *chartSeries) handle(packet chartPacket) {
+	if len(packet.Bars) == 0 {
+		return
+	}
+
+	for i := range packet.Bars {
+		bar := packet.Bars[i]
+		bar.ContractID = cs.contractID
+		switch {
+		case cs.last == nil || barTime(bar).Equal(barTime(*cs.last)):
+			cs.last = &bar
+		case barTime(bar).After(barTime(*cs.last)):
+			complete := *cs.last
+			complete.Complete = true
+			cs.onBar(complete)
+			cs.last = &bar
+		default:
+			// Older bars, such as history arriving after live updates, are final.
+			bar.Complete = true
+			cs.onBar(bar)
+		}
+	}
+
+	cs.onBar(*cs.last)
+}
+
+// barTime parses a bar's timestamp, returning the zero time if it is invalid.
+func barTime(bar models.ChartBar) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, bar.Timestamp)
+	return t
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeChart(t *testing.T) {
+	server := newFakeSocketServer(t, func(req socketRequest) (int, interface{}) {
+		if req.Endpoint == "md/getChart" {
+			return 200, map[string]int{"historicalId": 11, "realtimeId": 12}
+		}
+		return 200, map[string]interface{}{}
+	})
+	client := newMarketDataTestClient(server)
+	defer client.Close()
+
+	bars := make(chan models.ChartBar, 10)
+	sub, err := client.SubscribeChart(context.Background(), 1234, ChartOptions{ElementSize: 5, HistoricalBars: 2}, func(b models.ChartBar) {
+		bars <- b
+	})
+	require.NoError(t, err)
+
+	reqs := server.received()
+	require.Equal(t, "md/getChart", reqs[1].Endpoint)
+	assert.JSONEq(t, `{
+		"symbol": 1234,
+		"chartDescription": {"underlyingType": "MinuteBar", "elementSize": 5, "elementSizeUnit": "UnderlyingUnits", "withHistogram": false},
+		"timeRange": {"asMuchAsElements": 2}
+	}`, reqs[1].Body)
+
+	bar := func(ts string, close float64) map[string]interface{} {
+		return map[string]interface{}{"timestamp": ts, "open": 5100, "high": 5102, "low": 5099, "close": close, "upVolume": 10, "downVolume": 8}
+	}
+	server.push("chart", map[string]interface{}{"charts": []map[string]interface{}{
+		{"id": 11, "bars": []interface{}{bar("2024-03-15T13:30:00.000Z", 5101), bar("2024-03-15T13:35:00.000Z", 5100.5)}},
+		{"id": 11, "eoh": true},
+	}})
+	server.push("chart", map[string]interface{}{"charts": []map[string]interface{}{
+		{"id": 12, "bars": []interface{}{bar("2024-03-15T13:35:00.000Z", 5101.75)}},
+	}})
+	server.push("chart", map[string]interface{}{"charts": []map[string]interface{}{
+		{"id": 12, "bars": []interface{}{bar("2024-03-15T13:40:00.000Z", 5102)}},
+	}})
+
+	var got []models.ChartBar
+	for len(got) < 5 {
+		select {
+		case b := <-bars:
+			got = append(got, b)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d of 5 bars", len(got))
+		}
+	}
+
+	type summary struct {
+		Timestamp string
+		Close     float64
+		Complete  bool
+	}
+	var summaries []summary
+	for _, b := range got {
+		assert.Equal(t, 1234, b.ContractID)
+		summaries = append(summaries, summary{b.Timestamp, b.Close, b.Complete})
+	}
+	assert.Equal(t, []summary{
+		{"2024-03-15T13:30:00.000Z", 5101, true},
+		{"2024-03-15T13:35:00.000Z", 5100.5, false},
+		{"2024-03-15T13:35:00.000Z", 5101.75, false},
+		{"2024-03-15T13:35:00.000Z", 5101.75, true},
+		{"2024-03-15T13:40:00.000Z", 5102, false},
+	}, summaries)
+
+	require.NoError(t, sub.Unsubscribe(context.Background()))
+	reqs = server.received()
+	assert.Equal(t, "md/cancelChart", reqs[2].Endpoint)
+	assert.JSONEq(t, `{"subscriptionId": 12}`, reqs[2].Body)
+}
+
+func TestChartPacketsBeforeRegistration(t *testing.T) {
+	stream := &marketDataStream{charts: make(map[int]*chartSeries), unclaimed: make(map[int][]chartPacket)}
+	var got []models.ChartBar
+
+	stream.beginChart()
+	stream.handleChartEvent([]byte(`{"charts":[{"id":11,"bars":[{"timestamp":"2024-03-15T13:30:00Z","close":5101}]},{"id":99,"bars":[{"timestamp":"2024-03-15T13:30:00Z"}]}]}`))
+	stream.endChart(&chartSeries{contractID: 1234, onBar: func(b models.ChartBar) { got = append(got, b) }}, 11, 12)
+
+	require.Len(t, got, 1)
+	assert.Equal(t, 5101.0, got[0].Close)
+	assert.Empty(t, stream.unclaimed, "packets for other charts are dropped")
+}
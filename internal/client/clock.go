@@ -0,0 +1,72 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// MaxClockSkew is how far the local clock may drift from Tradovate's before
+// the client warns about it. Token expiry is judged by Tradovate's clock
+// either way.
+const MaxClockSkew = 5 * time.Second
+
+// dateResolution is the precision of the HTTP Date header. Smaller skews
+// cannot be told apart from rounding.
+const dateResolution = time.Second
+
+// GetServerTime returns Tradovate's current time, taken from the Date
+// header of a lightweight request, and updates the client's estimate of
+// local clock skew.
+func (c *TradovateClient) GetServerTime(ctx context.Context) (time.Time, error) {
+	// sendRequest observes the Date header of every response, whatever its
+	// status.
+	resp, err := c.sendRequest(ctx, "GET", "/auth/me", nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	resp.Body.Close()
+
+	if _, err := http.ParseTime(resp.Header.Get("Date")); err != nil {
+		return time.Time{}, fmt.Errorf("tradovate response has no valid Date header")
+	}
+	return c.serverNow(), nil
+}
+
+// ClockSkew returns how far Tradovate's clock is ahead of the local one, as
+// last observed; negative if it is behind.
+func (c *TradovateClient) ClockSkew() time.Duration {
+	return time.Duration(c.clockSkew.Load())
+}
+
+// serverNow returns the current time by Tradovate's clock.
+func (c *TradovateClient) serverNow() time.Time {
+	return time.Now().Add(c.ClockSkew())
+}
+
+// observeServerDate updates the clock skew estimate from the Date header of
+// a response to a request sent at sent, warning once if it exceeds
+// MaxClockSkew.
+func (c *TradovateClient) observeServerDate(ctx context.Context, resp *http.Response, sent time.Time) {
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+
+	// The header is truncated to the second, so the server's time was most
+	// likely half a second later, and it was read around the midpoint of
+	// the round trip.
+	received := time.Now()
+	local := sent.Add(received.Sub(sent) / 2)
+	skew := date.Add(dateResolution / 2).Sub(local)
+	if skew > -dateResolution && skew < dateResolution {
+		skew = 0
+	}
+	c.clockSkew.Store(int64(skew))
+
+	if (skew > MaxClockSkew || skew < -MaxClockSkew) && c.skewWarned.CompareAndSwap(false, true) {
+		slog.WarnContext(ctx, "local clock differs from tradovate's; timestamps may be off and tokens are timed by tradovate's clock", "skew", skew.Round(time.Second))
+	}
+}
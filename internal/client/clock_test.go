@@ -0,0 +1,62 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetServerTime(t *testing.T) {
+	offset := 30 * time.Second
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/me", r.URL.Path)
+		w.Header().Set("Date", time.Now().Add(offset).UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(previous)
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	serverTime, err := client.GetServerTime(context.Background())
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(offset), serverTime, 2*time.Second)
+	assert.InDelta(t, offset.Seconds(), client.ClockSkew().Seconds(), 2)
+	assert.Contains(t, logs.String(), "local clock differs from tradovate's")
+
+	// Token expiry is judged by Tradovate's clock.
+	client.accessToken = "test-token"
+	client.tokenExpiry = time.Now().Add(20 * time.Second)
+	assert.False(t, client.IsAuthenticated())
+
+	// Skews within the header's one second resolution are ignored.
+	offset = 0
+	_, err = client.GetServerTime(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, client.ClockSkew())
+}
+
+func TestGetServerTimeWithoutDate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header()["Date"] = nil
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	_, err := client.GetServerTime(context.Background())
+	assert.EqualError(t, err, "tradovate response has no valid Date header")
+}
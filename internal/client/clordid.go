@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// clientOrderTTL is how long an order placed with a client order ID is
+// remembered, so a retry returns it without asking Tradovate.
+const clientOrderTTL = 24 * time.Hour
+
+// clientOrders remembers the orders placed with a client order ID, and the
+// IDs whose placement is in progress.
+type clientOrders struct {
+	mu       sync.Mutex
+	placed   map[string]clientOrder
+	inFlight map[string]bool
+}
+
+type clientOrder struct {
+	order    models.Order
+	placedAt time.Time
+}
+
+// begin claims id for a placement. It returns the order already placed with
+// id, if this client remembers one, or an error if another placement with
+// id has not finished.
+func (o *clientOrders) begin(id string) (*models.Order, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if placed, ok := o.placed[id]; ok && time.Since(placed.placedAt) < clientOrderTTL {
+		order := placed.order
+		return &order, nil
+	}
+	if o.inFlight[id] {
+		return nil, fmt.Errorf("an order with client order ID %q is already being placed", id)
+	}
+	if o.inFlight == nil {
+		o.inFlight = make(map[string]bool)
+	}
+	o.inFlight[id] = true
+	return nil, nil
+}
+
+// finish releases id, remembering the order placed with it, if any.
+func (o *clientOrders) finish(id string, placed *models.Order) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.inFlight, id)
+	if placed == nil {
+		return
+	}
+	now := time.Now()
+	for other, entry := range o.placed {
+		if now.Sub(entry.placedAt) >= clientOrderTTL {
+			delete(o.placed, other)
+		}
+	}
+	if o.placed == nil {
+		o.placed = make(map[string]clientOrder)
+	}
+	o.placed[id] = clientOrder{order: *placed, placedAt: now}
+}
+
+// placeOnce places an order carrying a client order ID unless one with the
+// same ID was already placed, in which case that order is returned instead.
+// A placement that failed ambiguously, say with a timeout after the request
+// was sent, may still have reached Tradovate, so before sending, the
+// commands Tradovate received are searched for the ID. If that search fails
+// the order is not sent, since it could be a duplicate.
+func (c *TradovateClient) placeOnce(ctx context.Context, order models.Order) (*models.Order, error) {
+	id := order.ClientOrderID
+	existing, err := c.clientOrders.begin(id)
+	if err != nil || existing != nil {
+		return existing, err
+	}
+
+	var placed *models.Order
+	defer func() { c.clientOrders.finish(id, placed) }()
+
+	if placed, err = c.findClientOrder(ctx, id); err != nil {
+		return nil, fmt.Errorf("error checking for an existing order with client order ID %q: %w", id, err)
+	}
+	if placed != nil {
+		slog.InfoContext(ctx, "order with client order ID already placed; not sending it again", "clOrdId", id, "orderId", placed.ID)
+		return placed, nil
+	}
+
+	if placed, err = c.placeOrder(ctx, order); err != nil {
+		return nil, err
+	}
+	if placed.ClientOrderID == "" {
+		placed.ClientOrderID = id
+	}
+	return placed, nil
+}
+
+// findClientOrder returns the order Tradovate placed for the client order
+// ID id, or nil if it has received no command carrying it.
+func (c *TradovateClient) findClientOrder(ctx context.Context, id string) (*models.Order, error) {
+	resp, err := c.doRequest(ctx, "GET", "/command/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var commands []struct {
+		OrderID     int    `json:"orderId"`
+		ClOrdID     string `json:"clOrdId"`
+		CommandType string `json:"commandType"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&commands); err != nil {
+		return nil, fmt.Errorf("error decoding commands: %w", err)
+	}
+
+	for _, command := range commands {
+		if command.ClOrdID != id || command.CommandType != "New" {
+			continue
+		}
+		resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/order/item?id=%d", command.OrderID), nil)
+		if err != nil {
+			return nil, err
+		}
+		var order models.Order
+		err = json.NewDecoder(resp.Body).Decode(&order)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding order: %w", err)
+		}
+		order.ClientOrderID = id
+		return &order, nil
+	}
+	return nil, nil
+}
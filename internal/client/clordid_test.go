@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceOrderClientOrderID(t *testing.T) {
+	var placed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/command/list":
+			json.NewEncoder(w).Encode([]map[string]interface{}{{"id": 1, "orderId": 40, "clOrdId": "other", "commandType": "New"}})
+		case "/order/placeOrder":
+			var order models.Order
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&order))
+			placed = append(placed, order.ClientOrderID)
+			order.ID = 41
+			json.NewEncoder(w).Encode(order)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	order := models.Order{AccountID: 1, ContractID: 1234, OrderType: "Market", Quantity: 1, ClientOrderID: "entry-1"}
+	first, err := client.PlaceOrder(context.Background(), order)
+	require.NoError(t, err)
+	assert.Equal(t, 41, first.ID)
+	assert.Equal(t, "entry-1", first.ClientOrderID)
+
+	// A retry returns the order already placed without sending it again.
+	again, err := client.PlaceOrder(context.Background(), order)
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+	assert.Equal(t, []string{"entry-1"}, placed)
+}
+
+func TestPlaceOrderClientOrderIDAfterAmbiguousFailure(t *testing.T) {
+	var placeCalls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/command/list":
+			// The first attempt reached Tradovate even though its response
+			// was lost.
+			if placeCalls == 0 {
+				json.NewEncoder(w).Encode([]interface{}{})
+				return
+			}
+			json.NewEncoder(w).Encode([]map[string]interface{}{{"id": 1, "orderId": 41, "clOrdId": "entry-1", "commandType": "New"}})
+		case "/order/placeOrder":
+			placeCalls++
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case "/order/item":
+			assert.Equal(t, "41", r.URL.Query().Get("id"))
+			json.NewEncoder(w).Encode(models.Order{ID: 41, Status: "Working"})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	order := models.Order{AccountID: 1, ContractID: 1234, OrderType: "Market", Quantity: 1, ClientOrderID: "entry-1"}
+	_, err := client.PlaceOrder(context.Background(), order)
+	require.Error(t, err)
+
+	existing, err := client.PlaceOrder(context.Background(), order)
+	require.NoError(t, err)
+	assert.Equal(t, &models.Order{ID: 41, Status: "Working", ClientOrderID: "entry-1"}, existing)
+	assert.Equal(t, 1, placeCalls)
+}
+
+func TestPlaceOrderClientOrderIDLookupFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/order/placeOrder" {
+			t.Error("order sent without checking for a duplicate")
+		}
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	_, err := client.PlaceOrder(context.Background(), models.Order{AccountID: 1, ContractID: 1234, OrderType: "Market", Quantity: 1, ClientOrderID: "entry-1"})
+	assert.ErrorContains(t, err, `error checking for an existing order with client order ID "entry-1"`)
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// GetCommandHistory retrieves the commands sent on an order, or on any of
+// an account's orders, oldest first. Each command carries the reports of its
+// outcome, and rejected ones the reason they were rejected.
+// Parameters:
+// - orderID: Only return commands sent on this order; 0 to use accountID instead
+// - accountID: Only return commands sent on this account's orders
+func (c *TradovateClient) GetCommandHistory(ctx context.Context, orderID, accountID int) ([]models.Command, error) {
+	if orderID == 0 && accountID == 0 {
+		return nil, fmt.Errorf("an order or account is required")
+	}
+
+	var commands []models.Command
+	reports := make(map[int][]models.CommandReport)
+	if orderID != 0 {
+		if err := c.getList(ctx, fmt.Sprintf("/command/deps?masterid=%d", orderID), "commands", &commands); err != nil {
+			return nil, err
+		}
+		for _, command := range commands {
+			var commandReports []models.CommandReport
+			if err := c.getList(ctx, fmt.Sprintf("/commandReport/deps?masterid=%d", command.ID), "command reports", &commandReports); err != nil {
+				return nil, err
+			}
+			reports[command.ID] = commandReports
+		}
+	} else {
+		orders, err := c.GetOrders(ctx, accountID, "")
+		if err != nil {
+			return nil, fmt.Errorf("error listing orders of account %d: %w", accountID, err)
+		}
+		accountOrders := make(map[int]bool, len(orders))
+		for _, order := range orders {
+			accountOrders[order.ID] = true
+		}
+
+		var all []models.Command
+		if err := c.getList(ctx, "/command/list", "commands", &all); err != nil {
+			return nil, err
+		}
+		for _, command := range all {
+			if accountOrders[command.OrderID] {
+				commands = append(commands, command)
+			}
+		}
+
+		var allReports []models.CommandReport
+		if err := c.getList(ctx, "/commandReport/list", "command reports", &allReports); err != nil {
+			return nil, err
+		}
+		for _, report := range allReports {
+			reports[report.CommandID] = append(reports[report.CommandID], report)
+		}
+	}
+
+	history := make([]models.Command, 0, len(commands))
+	for _, command := range commands {
+		command.Reports = reports[command.ID]
+		if command.Reports == nil {
+			command.Reports = []models.CommandReport{}
+		}
+		sort.SliceStable(command.Reports, func(i, j int) bool { return command.Reports[i].Timestamp < command.Reports[j].Timestamp })
+		for _, report := range command.Reports {
+			command.CommandStatus = report.CommandStatus
+			if report.Rejected() {
+				command.Rejected = true
+				command.RejectReason = report.RejectReason
+				command.Text = report.Text
+			}
+		}
+		history = append(history, command)
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Timestamp < history[j].Timestamp })
+	return history, nil
+}
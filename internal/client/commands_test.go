@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCommandServer serves a placed order (41) whose modification was
+// rejected, and an order (42) of another account.
+func newCommandServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path + "?" + r.URL.RawQuery {
+		case "/command/deps?masterid=41":
+			w.Write([]byte(`[
+				{"id": 2, "orderId": 41, "timestamp": "2024-03-15T13:31:00Z", "commandType": "Modify"},
+				{"id": 1, "orderId": 41, "timestamp": "2024-03-15T13:30:00Z", "clOrdId": "entry-1", "commandType": "New"}
+			]`))
+		case "/command/list?":
+			w.Write([]byte(`[
+				{"id": 1, "orderId": 41, "timestamp": "2024-03-15T13:30:00Z", "clOrdId": "entry-1", "commandType": "New"},
+				{"id": 2, "orderId": 41, "timestamp": "2024-03-15T13:31:00Z", "commandType": "Modify"},
+				{"id": 3, "orderId": 42, "timestamp": "2024-03-15T13:32:00Z", "commandType": "New"}
+			]`))
+		case "/commandReport/deps?masterid=1":
+			w.Write([]byte(`[{"id": 10, "commandId": 1, "timestamp": "2024-03-15T13:30:00Z", "commandStatus": "RiskPassed"}]`))
+		case "/commandReport/deps?masterid=2":
+			w.Write([]byte(`[{"id": 11, "commandId": 2, "timestamp": "2024-03-15T13:31:00Z", "commandStatus": "RiskRejected", "rejectReason": "RiskCheck", "text": "Exceeds position limit"}]`))
+		case "/commandReport/list?":
+			w.Write([]byte(`[
+				{"id": 10, "commandId": 1, "timestamp": "2024-03-15T13:30:00Z", "commandStatus": "RiskPassed"},
+				{"id": 11, "commandId": 2, "timestamp": "2024-03-15T13:31:00Z", "commandStatus": "RiskRejected", "rejectReason": "RiskCheck", "text": "Exceeds position limit"},
+				{"id": 12, "commandId": 3, "timestamp": "2024-03-15T13:32:00Z", "commandStatus": "RiskPassed"}
+			]`))
+		case "/order/deps?masterid=12345":
+			w.Write([]byte(`[{"id": 41, "accountId": 12345}]`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL)
+		}
+	}))
+}
+
+func TestGetCommandHistory(t *testing.T) {
+	server := newCommandServer(t)
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	want := []models.Command{
+		{ID: 1, OrderID: 41, Timestamp: "2024-03-15T13:30:00Z", ClOrdID: "entry-1", CommandType: "New", CommandStatus: "RiskPassed",
+			Reports: []models.CommandReport{{ID: 10, CommandID: 1, Timestamp: "2024-03-15T13:30:00Z", CommandStatus: "RiskPassed"}}},
+		{ID: 2, OrderID: 41, Timestamp: "2024-03-15T13:31:00Z", CommandType: "Modify", CommandStatus: "RiskRejected",
+			Rejected: true, RejectReason: "RiskCheck", Text: "Exceeds position limit",
+			Reports: []models.CommandReport{{ID: 11, CommandID: 2, Timestamp: "2024-03-15T13:31:00Z", CommandStatus: "RiskRejected", RejectReason: "RiskCheck", Text: "Exceeds position limit"}}},
+	}
+
+	history, err := client.GetCommandHistory(context.Background(), 41, 0)
+	require.NoError(t, err)
+	assert.Equal(t, want, history)
+
+	history, err = client.GetCommandHistory(context.Background(), 0, 12345)
+	require.NoError(t, err)
+	assert.Equal(t, want, history, "commands on other accounts' orders are left out")
+
+	_, err = client.GetCommandHistory(context.Background(), 0, 0)
+	assert.EqualError(t, err, "an order or account is required")
+}
@@ -0,0 +1,59 @@
+package client
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SetCompression enables or disables gzip compression of API responses.
+// Compression is on by default; it mostly helps contract lists and long
+// historical ranges. It is safe to call while requests are in flight.
+func (c *TradovateClient) SetCompression(enabled bool) {
+	c.noCompression.Store(!enabled)
+}
+
+// acceptGzip asks for a gzip-compressed response, or an uncompressed one if
+// compression is disabled. Setting the header explicitly turns off net/http's
+// transparent compression, so responses are decompressed by
+// decompressResponse.
+func (c *TradovateClient) acceptGzip(req *http.Request) {
+	if c.noCompression.Load() {
+		req.Header.Set("Accept-Encoding", "identity")
+		return
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+}
+
+// decompressResponse replaces a gzip-encoded response body with its
+// decompressed contents.
+func decompressResponse(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return fmt.Errorf("error decompressing response: %w", err)
+	}
+	resp.Body = &gzipBody{Reader: zr, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipBody reads a decompressed response body and closes the underlying
+// connection's body with it.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
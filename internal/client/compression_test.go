@@ -0,0 +1,59 @@
+package client
+
+import (
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression(t *testing.T) {
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		if acceptEncoding != "gzip" {
+			w.Write([]byte(`[{"id": 2, "name": "NQZ4"}]`))
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write([]byte(`[{"id": 1, "name": "ESZ4"}]`))
+		zw.Close()
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	contracts, err := client.GetContracts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "gzip", acceptEncoding)
+	assert.Equal(t, []models.Contract{{ID: 1, Name: "ESZ4"}}, contracts)
+
+	client.SetCompression(false)
+	contracts, err = client.GetContracts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "identity", acceptEncoding)
+	assert.Equal(t, []models.Contract{{ID: 2, Name: "NQZ4"}}, contracts)
+}
+
+func TestCompressionCorruptBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write([]byte("not gzip"))
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+
+	_, err := client.GetContracts(context.Background())
+	assert.ErrorContains(t, err, "error decompressing response")
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests exercise the client from many goroutines at once and are most
+// useful under go test -race.
+
+func TestConcurrentRequestsShareTokenRenewal(t *testing.T) {
+	var renewals atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/renewAccessToken":
+			renewals.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			json.NewEncoder(w).Encode(AuthResponse{AccessToken: "renewed-token", ExpirationTime: time.Now().Add(time.Hour).Format(time.RFC3339)})
+		case "/account/list":
+			json.NewEncoder(w).Encode([]models.Account{{ID: 1}})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.setTokens(&AuthResponse{AccessToken: "expiring-token", ExpirationTime: time.Now().Add(time.Minute).Format(time.RFC3339)})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetAccounts(context.Background())
+			assert.NoError(t, err)
+			assert.True(t, client.IsAuthenticated())
+		}()
+	}
+	// Reconfiguring while requests are in flight is safe.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		client.SetBaseURL(server.URL)
+		client.SetTickPolicy(TickSnap)
+		client.SetEntitlementCheck(false)
+	}()
+	wg.Wait()
+
+	assert.Equal(t, int32(1), renewals.Load())
+	assert.Equal(t, "renewed-token", client.GetAccessToken())
+}
+
+func TestConcurrentRequestsShareReauthentication(t *testing.T) {
+	var logins atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/accessTokenRequest":
+			logins.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			json.NewEncoder(w).Encode(AuthResponse{AccessToken: "new-token"})
+		case "/account/list":
+			if r.Header.Get("Authorization") != "Bearer new-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode([]models.Account{{ID: 1}})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.setTokens(&AuthResponse{AccessToken: "revoked-token"})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			accounts, err := client.GetAccounts(context.Background())
+			if assert.NoError(t, err) {
+				assert.Len(t, accounts, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, "new-token", client.GetAccessToken())
+	assert.Equal(t, int32(1), logins.Load())
+}
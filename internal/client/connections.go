@@ -0,0 +1,35 @@
+package client
+
+import "github.com/0xjmp/mcp-tradovate/internal/models"
+
+// ConnectionStatus reports the environment the client trades in and the
+// state of its WebSockets: the market data stream, the user sync stream and,
+// in the replay environment, the replay session. It is answered locally.
+func (c *TradovateClient) ConnectionStatus() models.ConnectionStatus {
+	env := c.Environment()
+	status := models.ConnectionStatus{Environment: env.Name}
+
+	c.socketMu.Lock()
+	md, user, replay := c.md, c.user, c.replay
+	c.socketMu.Unlock()
+
+	marketData := models.SocketStatus{Name: "marketData"}
+	if md != nil {
+		marketData.Connected = md.live.connected()
+		md.mu.Lock()
+		marketData.Subscriptions = len(md.subscribers) + len(md.charts)
+		md.mu.Unlock()
+	}
+	userSync := models.SocketStatus{Name: "userSync"}
+	if user != nil {
+		userSync.Connected = user.live.connected()
+		user.mu.Lock()
+		userSync.Subscriptions = len(user.subscribers)
+		user.mu.Unlock()
+	}
+	status.Sockets = []models.SocketStatus{marketData, userSync}
+	if env.Replay {
+		status.Sockets = append(status.Sockets, models.SocketStatus{Name: "replay", Connected: replay != nil && socketOpen(replay)})
+	}
+	return status
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionStatus(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+	client := newMarketDataTestClient(server)
+	defer client.Close()
+
+	assert.Equal(t, models.ConnectionStatus{
+		Environment: "live",
+		Sockets: []models.SocketStatus{
+			{Name: "marketData"},
+			{Name: "userSync"},
+		},
+	}, client.ConnectionStatus())
+
+	for _, contractID := range []int{1234, 5678, 1234} {
+		_, err := client.SubscribeQuote(context.Background(), contractID, func(models.Quote) {})
+		require.NoError(t, err)
+	}
+	status := client.ConnectionStatus()
+	assert.Equal(t, models.SocketStatus{Name: "marketData", Connected: true, Subscriptions: 2}, status.Sockets[0])
+
+	client.Close()
+	assert.False(t, client.ConnectionStatus().Sockets[0].Connected)
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// uuidPattern matches a UUID in its canonical textual form.
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// SetDeviceID sets the device ID sent when requesting access tokens.
+// Tradovate expects it to stay the same across sessions of one
+// installation, so callers should persist it, e.g. with LoadDeviceID.
+func (c *TradovateClient) SetDeviceID(id string) {
+	c.deviceID = id
+}
+
+// DeviceID returns the device ID sent when requesting access tokens.
+func (c *TradovateClient) DeviceID() string {
+	return c.deviceID
+}
+
+// DeviceIDPath returns where the device ID is persisted for a token cache
+// at tokenCachePath: a device-id file in the same directory.
+func DeviceIDPath(tokenCachePath string) string {
+	return filepath.Join(filepath.Dir(tokenCachePath), "device-id")
+}
+
+// LoadDeviceID returns the device ID stored at path, generating and storing
+// a new one if there is none yet.
+func LoadDeviceID(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		id := strings.TrimSpace(string(data))
+		if uuidPattern.MatchString(id) {
+			return id, nil
+		}
+		return "", fmt.Errorf("invalid device ID in %s", path)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to read device ID: %w", err)
+	}
+
+	id, err := newDeviceID()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("failed to create device ID directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(id+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to write device ID: %w", err)
+	}
+	return id, nil
+}
+
+// newDeviceID returns a random (version 4) UUID.
+func newDeviceID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate device ID: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
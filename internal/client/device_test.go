@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDeviceID(t *testing.T) {
+	path := DeviceIDPath(filepath.Join(t.TempDir(), "cache", "token.json"))
+	assert.Equal(t, "device-id", filepath.Base(path))
+
+	id, err := LoadDeviceID(path)
+	require.NoError(t, err)
+	assert.Regexp(t, uuidPattern, id)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	again, err := LoadDeviceID(path)
+	require.NoError(t, err)
+	assert.Equal(t, id, again, "the device ID is stable across restarts")
+
+	require.NoError(t, os.WriteFile(path, []byte("not-a-uuid"), 0600))
+	_, err = LoadDeviceID(path)
+	assert.ErrorContains(t, err, "invalid device ID")
+}
+
+func TestAuthenticateSendsDeviceID(t *testing.T) {
+	var deviceIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var authReq AuthRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&authReq))
+		deviceIDs = append(deviceIDs, authReq.DeviceID)
+		json.NewEncoder(w).Encode(AuthResponse{AccessToken: "test-token"})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	generated := client.DeviceID()
+	assert.Regexp(t, uuidPattern, generated)
+
+	_, err := client.Authenticate(context.Background())
+	require.NoError(t, err)
+
+	client.SetDeviceID("3f2c8a4e-1b7d-4c9e-8f0a-6d5b4e3c2a1f")
+	_, err = client.Authenticate(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{generated, "3f2c8a4e-1b7d-4c9e-8f0a-6d5b4e3c2a1f"}, deviceIDs)
+}
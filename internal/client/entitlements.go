@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// entitlementCacheTTL is how long the user's market data entitlements are
+// reused before the plugins are fetched again.
+const entitlementCacheTTL = 15 * time.Minute
+
+// Market data feeds named in EntitlementError.
+const (
+	FeedTopOfBook     = "top-of-book"
+	FeedDepthOfMarket = "depth-of-market"
+	FeedHistorical    = "historical"
+)
+
+// cmeGroupExchanges are the exchanges covered by a CME Group bundle.
+var cmeGroupExchanges = []string{"CME", "CBOT", "NYMEX", "COMEX"}
+
+// EntitlementError is returned instead of calling a market data endpoint
+// the user has no active subscription for.
+type EntitlementError struct {
+	Exchange string // Exchange the contract is listed on, e.g. CME
+	Feed     string // Feed that was requested, e.g. FeedTopOfBook
+}
+
+func (e *EntitlementError) Error() string {
+	return fmt.Sprintf("not entitled to %s %s data: subscribe to %s market data in Tradovate", e.Exchange, e.Feed, e.Exchange)
+}
+
+// entitlements caches the exchanges the user has market data for, and the
+// exchange of each contract checked.
+type entitlements struct {
+	mu        sync.Mutex
+	exchanges map[string]bool // Upper-case exchange names; nil until fetched
+	fetched   time.Time
+	contracts map[int]string // Exchange of each contract looked up
+}
+
+// SetEntitlementCheck enables or disables verifying the user's market data
+// entitlements, from their plugins, before market data calls. It is off by
+// default. It is safe to call while requests are in flight.
+func (c *TradovateClient) SetEntitlementCheck(enabled bool) {
+	c.checkEntitlements.Store(enabled)
+}
+
+// checkEntitlement returns an EntitlementError if the user has no active
+// subscription to the exchange contractID is listed on. Failures to look
+// the entitlements up are logged and do not block the call; Tradovate
+// still enforces them.
+func (c *TradovateClient) checkEntitlement(ctx context.Context, contractID int, feed string) error {
+	if !c.checkEntitlements.Load() {
+		return nil
+	}
+
+	exchange, err := c.contractExchange(ctx, contractID)
+	if err != nil {
+		slog.WarnContext(ctx, "skipping market data entitlement check", "contractId", contractID, "error", err)
+		return nil
+	}
+	entitled, err := c.entitledExchanges(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "skipping market data entitlement check", "contractId", contractID, "error", err)
+		return nil
+	}
+	if exchange == "" || entitled[strings.ToUpper(exchange)] {
+		return nil
+	}
+	return &EntitlementError{Exchange: exchange, Feed: feed}
+}
+
+// contractExchange returns the exchange contractID is listed on.
+func (c *TradovateClient) contractExchange(ctx context.Context, contractID int) (string, error) {
+	c.entitlements.mu.Lock()
+	exchange, ok := c.entitlements.contracts[contractID]
+	c.entitlements.mu.Unlock()
+	if ok {
+		return exchange, nil
+	}
+
+	contract, err := c.GetContract(ctx, contractID)
+	if err != nil {
+		return "", err
+	}
+
+	c.entitlements.mu.Lock()
+	defer c.entitlements.mu.Unlock()
+	if c.entitlements.contracts == nil {
+		c.entitlements.contracts = make(map[int]string)
+	}
+	c.entitlements.contracts[contractID] = contract.Exchange
+	return contract.Exchange, nil
+}
+
+// entitledExchanges returns the exchanges the user's active plugins grant
+// market data for, fetching the plugins if the cached set has expired.
+func (c *TradovateClient) entitledExchanges(ctx context.Context) (map[string]bool, error) {
+	c.entitlements.mu.Lock()
+	if c.entitlements.exchanges != nil && time.Since(c.entitlements.fetched) < entitlementCacheTTL {
+		exchanges := c.entitlements.exchanges
+		c.entitlements.mu.Unlock()
+		return exchanges, nil
+	}
+	c.entitlements.mu.Unlock()
+
+	plugins, err := c.GetUserPlugins(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	exchanges := make(map[string]bool)
+	for _, plugin := range plugins {
+		if !plugin.Active(now) {
+			continue
+		}
+		for _, exchange := range pluginExchanges(plugin.PluginName) {
+			exchanges[exchange] = true
+		}
+	}
+
+	c.entitlements.mu.Lock()
+	defer c.entitlements.mu.Unlock()
+	c.entitlements.exchanges = exchanges
+	c.entitlements.fetched = now
+	return exchanges, nil
+}
+
+// pluginExchanges returns the exchanges a market data plugin covers, judged
+// by its name: CME_Bundle covers CME, and a CME Group bundle covers all four
+// CME Group exchanges.
+func pluginExchanges(name string) []string {
+	name = strings.ToUpper(name)
+	if strings.HasPrefix(name, "CME_GROUP") || strings.HasPrefix(name, "CMEGROUP") {
+		return cmeGroupExchanges
+	}
+	exchange, _, _ := strings.Cut(name, "_")
+	return []string{exchange}
+}
+
+// resetEntitlements forgets the cached entitlements, e.g. when the user
+// changes.
+func (c *TradovateClient) resetEntitlements() {
+	c.entitlements.mu.Lock()
+	defer c.entitlements.mu.Unlock()
+	c.entitlements.exchanges = nil
+	c.entitlements.contracts = nil
+}
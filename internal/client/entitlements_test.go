@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckEntitlement(t *testing.T) {
+	pluginCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/userPlugin/deps":
+			pluginCalls++
+			json.NewEncoder(w).Encode([]models.UserPlugin{
+				{PluginName: "CME_Bundle", Approval: true},
+				{PluginName: "NYMEX_Bundle", Approval: false},
+			})
+		case "/contract/item":
+			exchange := map[string]string{"1": "CME", "2": "NYMEX"}[r.URL.Query().Get("id")]
+			json.NewEncoder(w).Encode(models.Contract{ID: 1, Exchange: exchange})
+		case "/md/getQuote/1":
+			json.NewEncoder(w).Encode(models.MarketData{ContractID: 1})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+	client.userID = 7
+
+	// The check is off by default.
+	require.NoError(t, client.checkEntitlement(context.Background(), 2, FeedTopOfBook))
+	assert.Zero(t, pluginCalls)
+
+	client.SetEntitlementCheck(true)
+	_, err := client.GetMarketData(context.Background(), 1)
+	require.NoError(t, err)
+
+	_, err = client.GetMarketData(context.Background(), 2)
+	var entitlementErr *EntitlementError
+	require.True(t, errors.As(err, &entitlementErr))
+	assert.EqualError(t, err, "not entitled to NYMEX top-of-book data: subscribe to NYMEX market data in Tradovate")
+	assert.Equal(t, 1, pluginCalls, "entitlements are cached")
+
+	client.resetEntitlements()
+	_, err = client.GetMarketData(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, pluginCalls)
+}
+
+func TestCheckEntitlementLookupFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	client.SetEntitlementCheck(true)
+
+	assert.NoError(t, client.checkEntitlement(context.Background(), 1, FeedTopOfBook), "failed lookups do not block market data")
+}
+
+func TestPluginExchanges(t *testing.T) {
+	assert.Equal(t, []string{"CME"}, pluginExchanges("CME_Bundle"))
+	assert.Equal(t, []string{"CBOT"}, pluginExchanges("cbot_bundle_depth"))
+	assert.Equal(t, []string{"CME", "CBOT", "NYMEX", "COMEX"}, pluginExchanges("CME_Group_Bundle"))
+	assert.Equal(t, []string{"EUREX"}, pluginExchanges("EUREX"))
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Environment describes the hosts of one Tradovate environment.
+type Environment struct {
+	Name          string // Environment name, e.g. "demo"
+	BaseURL       string // REST API base URL
+	WebSocketURL  string // User data WebSocket URL
+	MarketDataURL string // Market data WebSocket URL
+	Replay        bool   // Whether API requests are served by a Market Replay session
+}
+
+// Tradovate environments selectable with SetEnvironment.
+var (
+	EnvironmentLive = Environment{
+		Name:          "live",
+		BaseURL:       "https://live.tradovate.com/v1",
+		WebSocketURL:  "wss://live.tradovateapi.com/v1/websocket",
+		MarketDataURL: "wss://md.tradovateapi.com/v1/websocket",
+	}
+	EnvironmentDemo = Environment{
+		Name:          "demo",
+		BaseURL:       "https://demo.tradovateapi.com/v1",
+		WebSocketURL:  "wss://demo.tradovateapi.com/v1/websocket",
+		MarketDataURL: "wss://md-demo.tradovateapi.com/v1/websocket",
+	}
+	// EnvironmentReplay replays historical sessions. It authenticates with
+	// live credentials, then serves every API request and market data over
+	// the replay WebSocket once the replay clock has been initialized.
+	EnvironmentReplay = Environment{
+		Name:          "replay",
+		BaseURL:       EnvironmentLive.BaseURL,
+		WebSocketURL:  "wss://replay.tradovateapi.com/v1/websocket",
+		MarketDataURL: "wss://replay.tradovateapi.com/v1/websocket",
+		Replay:        true,
+	}
+)
+
+// environments indexes the selectable environments by name.
+var environments = map[string]Environment{
+	EnvironmentLive.Name:   EnvironmentLive,
+	EnvironmentDemo.Name:   EnvironmentDemo,
+	EnvironmentReplay.Name: EnvironmentReplay,
+}
+
+// LookupEnvironment returns the environment with the given name.
+func LookupEnvironment(name string) (Environment, error) {
+	env, ok := environments[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		names := make([]string, 0, len(environments))
+		for n := range environments {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Environment{}, fmt.Errorf("unknown environment %q: must be one of %s", name, strings.Join(names, ", "))
+	}
+	return env, nil
+}
+
+// SetEnvironment points the client at the named environment ("live",
+// "demo" or "replay"). Any tokens from a previous environment are discarded, since they
+// are not valid elsewhere.
+func (c *TradovateClient) SetEnvironment(name string) error {
+	env, err := LookupEnvironment(name)
+	if err != nil {
+		return err
+	}
+	if env.Name != c.Environment().Name {
+		c.closeReplaySocket()
+		c.closeMarketDataStream()
+		c.closeUserSyncStream()
+		c.clearTokens(true)
+		c.resetEntitlements()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.env = env
+	c.baseURL = env.BaseURL
+	return nil
+}
+
+// Environment returns the environment the client is configured for.
+func (c *TradovateClient) Environment() Environment {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.env
+}
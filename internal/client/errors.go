@@ -0,0 +1,50 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is returned when Tradovate answers a request with an error
+// status. Use errors.As to inspect it.
+type APIError struct {
+	Method     string // HTTP method of the request; empty for WebSocket requests
+	Endpoint   string // Endpoint the request was sent to
+	StatusCode int    // HTTP (or WebSocket response) status
+	ErrorText  string // Tradovate's errorText, if it sent one
+	ErrorCode  string // Tradovate's errorCode, if it sent one
+}
+
+func (e *APIError) Error() string {
+	if e.ErrorText == "" {
+		return fmt.Sprintf("status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.ErrorText)
+}
+
+// Retryable reports whether the same request may succeed if sent again
+// later: Tradovate was rate limiting or temporarily unavailable.
+func (e *APIError) Retryable() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// newAPIError builds the error for an error response with body data,
+// taking errorText and errorCode from it if it is a Tradovate error object.
+func newAPIError(method, endpoint string, status int, data []byte) *APIError {
+	apiErr := &APIError{Method: method, Endpoint: endpoint, StatusCode: status}
+	var body struct {
+		ErrorText string          `json:"errorText"`
+		ErrorCode json.RawMessage `json:"errorCode"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		apiErr.ErrorText = body.ErrorText
+		apiErr.ErrorCode = strings.Trim(string(body.ErrorCode), `"`)
+	}
+	return apiErr
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError(t *testing.T) {
+	status := http.StatusNotFound
+	body := `{"errorText":"Order not found","errorCode":"NotFound"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+
+	_, err := client.GetFills(context.Background(), 42)
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, &APIError{
+		Method:     http.MethodGet,
+		Endpoint:   "/fill/list/42",
+		StatusCode: http.StatusNotFound,
+		ErrorText:  "Order not found",
+		ErrorCode:  "NotFound",
+	}, apiErr)
+	assert.False(t, apiErr.Retryable())
+	assert.ErrorContains(t, err, "status 404: Order not found")
+
+	status, body = http.StatusServiceUnavailable, "upstream unavailable"
+	_, err = client.GetFills(context.Background(), 42)
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Empty(t, apiErr.ErrorText, "bodies that are not error objects are not used as errorText")
+	assert.True(t, apiErr.Retryable())
+	assert.ErrorContains(t, err, "status 503")
+}
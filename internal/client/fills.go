@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// GetAccountFills retrieves the fills of an account's orders between start
+// and end, oldest first, each with the symbol of its contract. Tradovate
+// keeps the fills of the current trading day.
+// Parameters:
+// - accountID: The account whose fills to return
+// - start: Only return fills at or after this time; zero for the start of the trading day, 17:00 Chicago time
+// - end: Only return fills before this time; zero for no limit
+func (c *TradovateClient) GetAccountFills(ctx context.Context, accountID int, start, end time.Time) ([]models.Fill, error) {
+	if start.IsZero() {
+		start = tradingDayStart(c.serverNow())
+	}
+
+	orders, err := c.GetOrders(ctx, accountID, "")
+	if err != nil {
+		return nil, fmt.Errorf("error listing orders of account %d: %w", accountID, err)
+	}
+	accountOrders := make(map[int]models.Order, len(orders))
+	for _, order := range orders {
+		accountOrders[order.ID] = order
+	}
+
+	var all []models.Fill
+	if err := c.getList(ctx, "/fill/list", "fills", &all); err != nil {
+		return nil, err
+	}
+
+	fills := []models.Fill{}
+	symbols := make(map[int]string)
+	for _, fill := range all {
+		order, ok := accountOrders[fill.OrderID]
+		if !ok {
+			continue
+		}
+		at := time.UnixMilli(fill.Timestamp)
+		if at.Before(start) || (!end.IsZero() && !at.Before(end)) {
+			continue
+		}
+		if fill.ContractID == 0 {
+			fill.ContractID = order.ContractID
+		}
+		if fill.Action == "" {
+			fill.Action = order.Side
+		}
+		symbol, ok := symbols[fill.ContractID]
+		if !ok {
+			contract, err := c.GetContract(ctx, fill.ContractID)
+			if err != nil {
+				return nil, fmt.Errorf("error looking up contract %d: %w", fill.ContractID, err)
+			}
+			symbol = contract.Name
+			symbols[fill.ContractID] = symbol
+		}
+		fill.Symbol = symbol
+		fills = append(fills, fill)
+	}
+	sort.SliceStable(fills, func(i, j int) bool { return fills[i].Timestamp < fills[j].Timestamp })
+	return fills, nil
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountFills(t *testing.T) {
+	contractLookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path + "?" + r.URL.RawQuery {
+		case "/order/deps?masterid=12345":
+			w.Write([]byte(`[
+				{"id": 41, "accountId": 12345, "contractId": 1234, "side": "Buy"},
+				{"id": 43, "accountId": 12345, "contractId": 1234, "side": "Sell"}
+			]`))
+		case "/fill/list?":
+			w.Write([]byte(`[
+				{"id": 503, "orderId": 43, "price": 5110, "quantity": 2, "timestamp": 1710513000000},
+				{"id": 501, "orderId": 41, "contractId": 1234, "action": "Buy", "price": 5100.25, "quantity": 1, "timestamp": 1710509400000},
+				{"id": 502, "orderId": 41, "price": 5100.5, "quantity": 1, "timestamp": 1710509400488},
+				{"id": 504, "orderId": 42, "contractId": 5678, "price": 18000, "quantity": 1, "timestamp": 1710509400000}
+			]`))
+		case "/contract/item?id=1234":
+			contractLookups++
+			w.Write([]byte(`{"id": 1234, "name": "MESM4"}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	start := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	fills, err := client.GetAccountFills(context.Background(), 12345, start, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Fill{
+		{ID: 501, OrderID: 41, ContractID: 1234, Symbol: "MESM4", Action: "Buy", Price: 5100.25, Quantity: 1, Timestamp: 1710509400000},
+		{ID: 502, OrderID: 41, ContractID: 1234, Symbol: "MESM4", Action: "Buy", Price: 5100.5, Quantity: 1, Timestamp: 1710509400488},
+		{ID: 503, OrderID: 43, ContractID: 1234, Symbol: "MESM4", Action: "Sell", Price: 5110, Quantity: 2, Timestamp: 1710513000000},
+	}, fills)
+	assert.Equal(t, 1, contractLookups)
+
+	end := time.Date(2024, 3, 15, 14, 0, 0, 0, time.UTC)
+	fills, err = client.GetAccountFills(context.Background(), 12345, start.Add(13*time.Hour+30*time.Minute), end)
+	require.NoError(t, err)
+	require.Len(t, fills, 2)
+	assert.Equal(t, 501, fills[0].ID)
+	assert.Equal(t, 502, fills[1].ID)
+
+	// Without a start, fills from before today are left out.
+	fills, err = client.GetAccountFills(context.Background(), 12345, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, fills)
+}
@@ -0,0 +1,210 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FixtureMode selects whether a FixtureTransport records or replays.
+type FixtureMode int
+
+const (
+	// FixtureReplay answers requests from the fixture file without touching
+	// the network.
+	FixtureReplay FixtureMode = iota
+	// FixtureRecord sends requests to Tradovate and records the exchanges,
+	// which Save writes to the fixture file.
+	FixtureRecord
+)
+
+// Interaction is one recorded request and the response Tradovate gave it.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the part of a request a replayed response is matched
+// on. Credentials in the body are redacted when recording.
+type RecordedRequest struct {
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"` // Path and query
+	Body     string `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response as recorded, with its body decompressed.
+type RecordedResponse struct {
+	StatusCode int               `json:"status"`
+	Header     map[string]string `json:"header,omitempty"`
+	Body       string            `json:"body"`
+}
+
+// FixtureTransport is an http.RoundTripper that records real Tradovate
+// responses to a fixture file and replays them, so client behaviour against
+// rare responses such as penalties or odd error bodies can be tested
+// deterministically. Install it with SetTransport.
+//
+// When replaying, each request is answered by the first unused interaction
+// with the same method and endpoint, so a fixture can script a sequence of
+// different answers to the same call.
+type FixtureTransport struct {
+	path string
+	mode FixtureMode
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewFixtureTransport returns a transport for the fixture file at path. In
+// FixtureReplay mode the file is loaded; in FixtureRecord mode requests are
+// sent with next, or the default transport if next is nil.
+func NewFixtureTransport(path string, mode FixtureMode, next http.RoundTripper) (*FixtureTransport, error) {
+	t := &FixtureTransport{path: path, mode: mode, next: next}
+	if mode == FixtureRecord {
+		if t.next == nil {
+			t.next = defaultTransport()
+		}
+		return t, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	if err := json.Unmarshal(data, &t.interactions); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	t.used = make([]bool, len(t.interactions))
+	return t, nil
+}
+
+// RoundTrip records or replays req.
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if t.mode == FixtureRecord {
+		return t.record(req, recorded)
+	}
+	return t.replay(req, recorded)
+}
+
+// Unused returns the interactions that have not been replayed, so tests can
+// check that every scripted response was requested.
+func (t *FixtureTransport) Unused() []Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range t.interactions {
+		if !t.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// Save writes the recorded interactions to the fixture file.
+func (t *FixtureTransport) Save() error {
+	t.mu.Lock()
+	data, err := json.MarshalIndent(t.interactions, "", "  ")
+	t.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(t.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+func (t *FixtureTransport) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := decompressResponse(resp); err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	header := make(map[string]string)
+	for _, key := range []string{"Content-Type", "Retry-After"} {
+		if value := resp.Header.Get(key); value != "" {
+			header[key] = value
+		}
+	}
+
+	t.mu.Lock()
+	t.interactions = append(t.interactions, Interaction{
+		Request:  recorded,
+		Response: RecordedResponse{StatusCode: resp.StatusCode, Header: header, Body: string(body)},
+	})
+	t.used = append(t.used, true)
+	t.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (t *FixtureTransport) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i, interaction := range t.interactions {
+		if t.used[i] || interaction.Request.Method != recorded.Method || interaction.Request.Endpoint != recorded.Endpoint {
+			continue
+		}
+		t.used[i] = true
+
+		header := make(http.Header)
+		for key, value := range interaction.Response.Header {
+			header.Set(key, value)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded response for %s %s in %s", recorded.Method, recorded.Endpoint, t.path)
+}
+
+// recordRequest returns the recorded form of req, restoring its body so it
+// can still be sent.
+func recordRequest(req *http.Request) (RecordedRequest, error) {
+	recorded := RecordedRequest{Method: req.Method, Endpoint: req.URL.RequestURI()}
+	if req.Body == nil || req.Body == http.NoBody {
+		return recorded, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return recorded, fmt.Errorf("error reading request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	recorded.Body = string(redactBody(body))
+	return recorded, nil
+}
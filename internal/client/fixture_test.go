@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFixtureClient returns a client that replays the named fixture from
+// testdata/fixtures.
+func newFixtureClient(t *testing.T, name string) (*TradovateClient, *FixtureTransport) {
+	t.Helper()
+	transport, err := NewFixtureTransport(filepath.Join("testdata", "fixtures", name), FixtureReplay, nil)
+	require.NoError(t, err)
+
+	client := NewTradovateClient()
+	client.SetBaseURL("https://fixture.tradovate.test/v1")
+	client.SetTransport(transport)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	client.accessToken = "test-token"
+	return client, transport
+}
+
+func TestFixturePenalizedPartialFills(t *testing.T) {
+	client, transport := newFixtureClient(t, "penalized_partial_fills.json")
+
+	fills, err := client.GetFills(context.Background(), 42)
+	require.NoError(t, err)
+	assert.Equal(t, []models.Fill{
+		{ID: 501, OrderID: 42, Price: 5100.25, Quantity: 1, Timestamp: 1710509400000},
+		{ID: 502, OrderID: 42, Price: 5100.5, Quantity: 2, Timestamp: 1710509400488},
+	}, fills)
+	assert.Empty(t, transport.Unused())
+
+	_, err = client.GetFills(context.Background(), 42)
+	assert.ErrorContains(t, err, "no recorded response for GET /v1/fill/list/42")
+}
+
+func TestFixtureGatewayErrorBody(t *testing.T) {
+	client, _ := newFixtureClient(t, "gateway_error.json")
+
+	_, err := client.GetOrder(context.Background(), 7)
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadGateway, apiErr.StatusCode)
+	assert.Empty(t, apiErr.ErrorText)
+	assert.True(t, apiErr.Retryable())
+}
+
+func TestFixtureRecord(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/auth/accessTokenRequest" {
+			w.Write([]byte(`{"accessToken":"test-token"}`))
+			return
+		}
+		w.Write([]byte(`[{"id":501,"orderId":42,"price":5100.25,"quantity":1}]`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "recorded.json")
+	recorder, err := NewFixtureTransport(path, FixtureRecord, nil)
+	require.NoError(t, err)
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.SetTransport(recorder)
+	t.Setenv("TRADOVATE_USERNAME", "testuser")
+	t.Setenv("TRADOVATE_PASSWORD", "hunter2")
+	_, err = client.Authenticate(context.Background())
+	require.NoError(t, err)
+	recorded, err := client.GetFills(context.Background(), 42)
+	require.NoError(t, err)
+	require.NoError(t, recorder.Save())
+
+	replayer, err := NewFixtureTransport(path, FixtureReplay, nil)
+	require.NoError(t, err)
+	require.Len(t, replayer.interactions, 2)
+	assert.NotContains(t, replayer.interactions[0].Request.Body, "hunter2", "credentials are not written to fixtures")
+	assert.Equal(t, "/fill/list/42", replayer.interactions[1].Request.Endpoint)
+
+	client.SetTransport(replayer)
+	_, err = client.Authenticate(context.Background())
+	require.NoError(t, err)
+	replayed, err := client.GetFills(context.Background(), 42)
+	require.NoError(t, err)
+	assert.Equal(t, recorded, replayed)
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// ResolveFrontMonth maps a product root symbol such as "ES" to the contract
+// currently most actively traded. The nearest unexpired maturity is the
+// front month until the next one trades more volume, at which point the
+// market has rolled and the next contract is returned instead. If volume
+// cannot be fetched, for example without a market data subscription, the
+// nearest maturity is used.
+func (c *TradovateClient) ResolveFrontMonth(ctx context.Context, productSymbol string) (*models.FrontMonth, error) {
+	productSymbol = strings.TrimSpace(productSymbol)
+	if productSymbol == "" {
+		return nil, fmt.Errorf("product symbol is required")
+	}
+
+	products, err := c.GetProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing products: %w", err)
+	}
+	var product *models.Product
+	for i := range products {
+		if strings.EqualFold(products[i].Name, productSymbol) {
+			product = &products[i]
+			break
+		}
+	}
+	if product == nil {
+		return nil, fmt.Errorf("unknown product %q", productSymbol)
+	}
+
+	maturities, err := c.GetContractMaturities(ctx, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error listing maturities of %s: %w", product.Name, err)
+	}
+	maturities = unexpiredMaturities(maturities, c.serverNow())
+	if len(maturities) == 0 {
+		return nil, fmt.Errorf("no unexpired contracts listed for %s", product.Name)
+	}
+
+	front, err := c.maturityContract(ctx, maturities[0].ID)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.FrontMonth{
+		Product:        product.Name,
+		Contract:       *front,
+		ExpirationDate: maturities[0].ExpirationDate,
+	}
+	if len(maturities) == 1 {
+		return result, nil
+	}
+
+	next, err := c.maturityContract(ctx, maturities[1].ID)
+	if err != nil {
+		return nil, err
+	}
+	result.NextContract = next
+
+	volumes, err := c.GetMarketDataBatch(ctx, []int{front.ID, next.ID})
+	if err != nil || volumes[0].Data == nil || volumes[1].Data == nil {
+		slog.WarnContext(ctx, "resolving front month without volume", "product", product.Name, "error", err)
+		return result, nil
+	}
+	result.Volume = volumes[0].Data.Volume
+	result.NextVolume = volumes[1].Data.Volume
+	if result.NextVolume > result.Volume {
+		result.Rolled = true
+		result.Contract, result.NextContract = *next, nil
+		result.ExpirationDate = maturities[1].ExpirationDate
+		result.Volume, result.NextVolume = result.NextVolume, 0
+		if len(maturities) > 2 {
+			if following, err := c.maturityContract(ctx, maturities[2].ID); err == nil {
+				result.NextContract = following
+			}
+		}
+	}
+	return result, nil
+}
+
+// unexpiredMaturities returns the maturities that have not expired by now,
+// nearest expiration first.
+func unexpiredMaturities(maturities []models.ContractMaturity, now time.Time) []models.ContractMaturity {
+	var open []models.ContractMaturity
+	for _, m := range maturities {
+		if expires, err := time.Parse(time.RFC3339, m.ExpirationDate); err == nil && !expires.After(now) {
+			continue
+		}
+		open = append(open, m)
+	}
+	sort.SliceStable(open, func(i, j int) bool {
+		if open[i].ExpirationMonth != open[j].ExpirationMonth {
+			return open[i].ExpirationMonth < open[j].ExpirationMonth
+		}
+		return open[i].ExpirationDate < open[j].ExpirationDate
+	})
+	return open
+}
+
+// maturityContract retrieves the outright contract listed for a maturity.
+func (c *TradovateClient) maturityContract(ctx context.Context, maturityID int) (*models.Contract, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/contract/deps?masterid=%d", maturityID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var contracts []models.Contract
+	if err := json.NewDecoder(resp.Body).Decode(&contracts); err != nil {
+		return nil, fmt.Errorf("error decoding contracts: %w", err)
+	}
+	if len(contracts) == 0 {
+		return nil, fmt.Errorf("no contract listed for maturity %d", maturityID)
+	}
+	return &contracts[0], nil
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFrontMonthServer serves ES maturities for March, June and September
+// 2099 plus one that has expired, with the given volume per contract.
+func newFrontMonthServer(t *testing.T, volumes map[int]int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/product/list":
+			w.Write([]byte(`[{"id": 1, "name": "NQ"}, {"id": 2, "name": "ES"}]`))
+		case "/contractMaturity/deps":
+			assert.Equal(t, "2", r.URL.Query().Get("masterid"))
+			w.Write([]byte(`[
+				{"id": 12, "productId": 2, "expirationMonth": 209906, "expirationDate": "2099-06-19T13:30:00Z"},
+				{"id": 10, "productId": 2, "expirationMonth": 202012, "expirationDate": "2020-12-18T14:30:00Z"},
+				{"id": 13, "productId": 2, "expirationMonth": 209909, "expirationDate": "2099-09-18T13:30:00Z"},
+				{"id": 11, "productId": 2, "expirationMonth": 209903, "expirationDate": "2099-03-20T13:30:00Z"}
+			]`))
+		case "/contract/deps":
+			names := map[string]string{"11": "ESH99", "12": "ESM99", "13": "ESU99"}
+			id := r.URL.Query().Get("masterid")
+			fmt.Fprintf(w, `[{"id": 1%s, "name": %q, "contractMaturityId": %s}]`, id, names[id], id)
+		default:
+			var contractID int
+			if _, err := fmt.Sscanf(r.URL.Path, "/md/getQuote/%d", &contractID); err != nil {
+				t.Errorf("unexpected request to %s", r.URL.Path)
+			}
+			volume, ok := volumes[contractID]
+			if !ok {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprintf(w, `{"contractId": %d, "volume": %d}`, contractID, volume)
+		}
+	}))
+}
+
+func TestResolveFrontMonth(t *testing.T) {
+	tests := []struct {
+		name     string
+		volumes  map[int]int
+		contract string
+		next     string
+		rolled   bool
+	}{
+		{"nearest maturity leads", map[int]int{111: 1200000, 112: 40000}, "ESH99", "ESM99", false},
+		{"volume has rolled", map[int]int{111: 300000, 112: 1100000}, "ESM99", "ESU99", true},
+		{"volume unavailable", nil, "ESH99", "ESM99", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFrontMonthServer(t, tt.volumes)
+			defer server.Close()
+
+			client := NewTradovateClient()
+			client.SetBaseURL(server.URL)
+			client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+
+			front, err := client.ResolveFrontMonth(context.Background(), "es")
+			require.NoError(t, err)
+			assert.Equal(t, "ES", front.Product)
+			assert.Equal(t, tt.contract, front.Contract.Name)
+			require.NotNil(t, front.NextContract)
+			assert.Equal(t, tt.next, front.NextContract.Name)
+			assert.Equal(t, tt.rolled, front.Rolled)
+		})
+	}
+}
+
+func TestResolveFrontMonthErrors(t *testing.T) {
+	server := newFrontMonthServer(t, nil)
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	_, err := client.ResolveFrontMonth(context.Background(), " ")
+	assert.EqualError(t, err, "product symbol is required")
+
+	_, err = client.ResolveFrontMonth(context.Background(), "ZZ")
+	assert.EqualError(t, err, `unknown product "ZZ"`)
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// IntervalTick requests every trade rather than bars. Each tick is returned
+// as a HistoricalData whose prices are all the trade price and whose volume
+// is the trade size.
+const IntervalTick = "tick"
+
+const (
+	// maxHistoricalBars bounds how many bars a single historical request
+	// asks for; longer ranges are split into several requests.
+	maxHistoricalBars = 5000
+	// tickWindow is the time range of each request for tick data.
+	tickWindow = 5 * time.Minute
+	// maxHistoricalRequests bounds how many requests one call may be split
+	// into, so a long range at a fine resolution fails fast instead of
+	// hammering the API.
+	maxHistoricalRequests = 200
+)
+
+// historicalWindow is the time range of one historical data request.
+type historicalWindow struct {
+	start, end time.Time
+}
+
+// parseInterval returns the duration of a bar interval such as "1s", "15s",
+// "5m", "1h" or "1d", or zero and false if it is not of that form.
+func parseInterval(interval string) (time.Duration, bool) {
+	if len(interval) < 2 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	units := map[byte]time.Duration{'s': time.Second, 'm': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour}
+	unit, ok := units[interval[len(interval)-1]]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// historicalWindows splits [start, end] into the ranges requested for
+// interval. Timestamps have a resolution of one second, so consecutive
+// windows do not overlap and no bar or tick is returned twice.
+func historicalWindows(start, end time.Time, interval string) ([]historicalWindow, error) {
+	var size time.Duration
+	if strings.EqualFold(interval, IntervalTick) {
+		size = tickWindow
+	} else if d, ok := parseInterval(interval); ok {
+		size = d * maxHistoricalBars
+	} else {
+		// Intervals the client does not understand are passed through as is.
+		return []historicalWindow{{start, end}}, nil
+	}
+
+	n := int(end.Sub(start)/size) + 1
+	if n > maxHistoricalRequests {
+		return nil, fmt.Errorf("time range too long for %s data: it would take %d requests; narrow the range or use a coarser interval", interval, n)
+	}
+
+	windows := make([]historicalWindow, 0, n)
+	for from := start; !from.After(end); from = from.Add(size) {
+		to := from.Add(size - time.Second)
+		if to.After(end) {
+			to = end
+		}
+		windows = append(windows, historicalWindow{from, to})
+	}
+	return windows, nil
+}
+
+// getHistoricalWindow fetches the historical data of one window.
+func (c *TradovateClient) getHistoricalWindow(ctx context.Context, contractID int, window historicalWindow, interval string) ([]models.HistoricalData, error) {
+	params := map[string]interface{}{
+		"contractId": contractID,
+		"startTime":  window.start.Unix(),
+		"endTime":    window.end.Unix(),
+		"interval":   interval,
+	}
+
+	resp, err := c.doRequest(ctx, "GET", "/md/historical", params)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var data []models.HistoricalData
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("error decoding historical data: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInterval(t *testing.T) {
+	for interval, want := range map[string]time.Duration{"1s": time.Second, "15s": 15 * time.Second, "5m": 5 * time.Minute, "1h": time.Hour, "1d": 24 * time.Hour} {
+		d, ok := parseInterval(interval)
+		assert.True(t, ok, interval)
+		assert.Equal(t, want, d, interval)
+	}
+	for _, interval := range []string{"", "m", "0s", "-1m", "1w", "tick"} {
+		_, ok := parseInterval(interval)
+		assert.False(t, ok, interval)
+	}
+}
+
+func TestHistoricalWindows(t *testing.T) {
+	start := time.Date(2024, 3, 15, 13, 30, 0, 0, time.UTC)
+
+	windows, err := historicalWindows(start, start.Add(24*time.Hour), "1h")
+	require.NoError(t, err)
+	assert.Equal(t, []historicalWindow{{start, start.Add(24 * time.Hour)}}, windows)
+
+	windows, err = historicalWindows(start, start.Add(12*time.Minute), IntervalTick)
+	require.NoError(t, err)
+	assert.Equal(t, []historicalWindow{
+		{start, start.Add(5*time.Minute - time.Second)},
+		{start.Add(5 * time.Minute), start.Add(10*time.Minute - time.Second)},
+		{start.Add(10 * time.Minute), start.Add(12 * time.Minute)},
+	}, windows)
+
+	windows, err = historicalWindows(start, start.Add(time.Hour), "weekly")
+	require.NoError(t, err)
+	assert.Len(t, windows, 1, "unknown intervals are passed through")
+
+	_, err = historicalWindows(start, start.Add(30*24*time.Hour), IntervalTick)
+	assert.ErrorContains(t, err, "time range too long for tick data")
+}
+
+func TestGetHistoricalDataChunked(t *testing.T) {
+	start := time.Date(2024, 3, 15, 13, 30, 0, 0, time.UTC)
+	var ranges [][2]int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params struct {
+			StartTime int64  `json:"startTime"`
+			EndTime   int64  `json:"endTime"`
+			Interval  string `json:"interval"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+		assert.Equal(t, "1s", params.Interval)
+		ranges = append(ranges, [2]int64{params.StartTime, params.EndTime})
+		json.NewEncoder(w).Encode([]models.HistoricalData{{ContractID: 1234, Timestamp: params.StartTime}})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	data, err := client.GetHistoricalData(context.Background(), 1234, start, start.Add(3*time.Hour), "1s")
+	require.NoError(t, err)
+
+	s := start.Unix()
+	assert.Equal(t, [][2]int64{{s, s + 4999}, {s + 5000, s + 9999}, {s + 10000, s + 10800}}, ranges)
+	require.Len(t, data, 3)
+	assert.Equal(t, s+5000, data[1].Timestamp)
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/logging"
+)
+
+// redacted replaces secrets in the requests passed to hooks.
+const redacted = "[REDACTED]"
+
+// RequestInfo describes a request sent to Tradovate. Authorization headers
+// and credential fields in the body are redacted.
+type RequestInfo struct {
+	Method   string      // HTTP method
+	Endpoint string      // Path and query, relative to the API base URL
+	Header   http.Header // Request headers
+	Body     []byte      // JSON request body, if any
+}
+
+// ResponseInfo describes the outcome of a request sent to Tradovate.
+type ResponseInfo struct {
+	Request    RequestInfo   // The request the response answers
+	StatusCode int           // HTTP status; zero if no response was received
+	Duration   time.Duration // Time until the response headers arrived
+	Err        error         // Why no response was received, if it was not
+}
+
+// Hooks observe the HTTP requests the client sends, e.g. to debug API
+// traffic. Either may be nil. They are called synchronously on the
+// request's goroutine and must not block.
+type Hooks struct {
+	OnRequest  func(ctx context.Context, req RequestInfo)
+	OnResponse func(ctx context.Context, resp ResponseInfo)
+}
+
+// SetHooks replaces the client's request hooks. The zero Hooks disables
+// them.
+func (c *TradovateClient) SetHooks(hooks Hooks) {
+	c.hooks.Store(&hooks)
+}
+
+// LoggingHooks returns hooks that log the method, endpoint, status and
+// latency of every request to logger.
+func LoggingHooks(logger *slog.Logger) Hooks {
+	return Hooks{
+		OnResponse: func(ctx context.Context, resp ResponseInfo) {
+			if resp.Err != nil {
+				logger.WarnContext(ctx, "tradovate api call failed", "method", resp.Request.Method, "endpoint", resp.Request.Endpoint, "duration", resp.Duration, "error", resp.Err)
+				return
+			}
+			logger.InfoContext(ctx, "tradovate api call", "method", resp.Request.Method, "endpoint", resp.Request.Endpoint, "status", resp.StatusCode, "duration", resp.Duration)
+		},
+	}
+}
+
+// observe calls the OnRequest hook for req and returns the function to
+// call with its outcome, which calls the OnResponse hook.
+func (c *TradovateClient) observe(ctx context.Context, req *http.Request, endpoint string, data []byte) func(*http.Response, error) {
+	hooks := c.hooks.Load()
+	if hooks == nil || (hooks.OnRequest == nil && hooks.OnResponse == nil) {
+		return func(*http.Response, error) {}
+	}
+
+	info := RequestInfo{
+		Method:   req.Method,
+		Endpoint: endpoint,
+		Header:   redactHeader(req.Header),
+		Body:     redactBody(data),
+	}
+	if hooks.OnRequest != nil {
+		hooks.OnRequest(ctx, info)
+	}
+
+	start := time.Now()
+	return func(resp *http.Response, err error) {
+		if hooks.OnResponse == nil {
+			return
+		}
+		result := ResponseInfo{Request: info, Duration: time.Since(start), Err: err}
+		if resp != nil {
+			result.StatusCode = resp.StatusCode
+		}
+		hooks.OnResponse(ctx, result)
+	}
+}
+
+// redactHeader returns a copy of header with credentials redacted.
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	if header.Get("Authorization") != "" {
+		header.Set("Authorization", redacted)
+	}
+	return header
+}
+
+// redactBody returns a copy of a JSON body with the values of credential
+// fields redacted. Bodies that are not JSON are redacted entirely.
+func redactBody(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return []byte(redacted)
+	}
+	out, err := json.Marshal(redactValue(body))
+	if err != nil {
+		return nil
+	}
+	return out
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if logging.IsSecretKey(key) {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+	}
+	return v
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHooks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/accessTokenRequest" {
+			json.NewEncoder(w).Encode(AuthResponse{AccessToken: "test-token"})
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	var requests []RequestInfo
+	var responses []ResponseInfo
+	client.SetHooks(Hooks{
+		OnRequest:  func(ctx context.Context, req RequestInfo) { requests = append(requests, req) },
+		OnResponse: func(ctx context.Context, resp ResponseInfo) { responses = append(responses, resp) },
+	})
+
+	t.Setenv("TRADOVATE_USERNAME", "testuser")
+	t.Setenv("TRADOVATE_PASSWORD", "hunter2")
+	t.Setenv("TRADOVATE_SEC", "client-secret")
+	_, err := client.Authenticate(context.Background())
+	require.NoError(t, err)
+	_, err = client.GetFills(context.Background(), 42)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 2)
+	auth := requests[0]
+	assert.Equal(t, "/auth/accessTokenRequest", auth.Endpoint)
+	assert.Contains(t, string(auth.Body), `"name":"testuser"`)
+	assert.Contains(t, string(auth.Body), `"password":"[REDACTED]"`)
+	assert.Contains(t, string(auth.Body), `"sec":"[REDACTED]"`)
+	assert.NotContains(t, string(auth.Body), "hunter2")
+	assert.NotContains(t, string(auth.Body), "client-secret")
+
+	assert.Equal(t, http.MethodGet, requests[1].Method)
+	assert.Equal(t, "/fill/list/42", requests[1].Endpoint)
+	assert.Equal(t, "[REDACTED]", requests[1].Header.Get("Authorization"))
+
+	require.Len(t, responses, 2)
+	assert.Equal(t, http.StatusOK, responses[1].StatusCode)
+	assert.Equal(t, requests[1], responses[1].Request)
+	assert.NoError(t, responses[1].Err)
+}
+
+func TestLoggingHooks(t *testing.T) {
+	var buf bytes.Buffer
+	hooks := LoggingHooks(slog.New(slog.NewTextHandler(&buf, nil)))
+	hooks.OnResponse(context.Background(), ResponseInfo{
+		Request:    RequestInfo{Method: http.MethodGet, Endpoint: "/fill/list/42"},
+		StatusCode: http.StatusOK,
+	})
+	assert.Contains(t, buf.String(), "method=GET endpoint=/fill/list/42 status=200")
+}
+
+func TestRedactBody(t *testing.T) {
+	assert.JSONEq(t,
+		`{"accountId":1,"nested":{"accessToken":"[REDACTED]"},"p-ticket":"[REDACTED]","orders":[{"cid":"[REDACTED]"}]}`,
+		string(redactBody([]byte(`{"accountId":1,"nested":{"accessToken":"abc"},"p-ticket":"t","orders":[{"cid":"x"}]}`))))
+	assert.Equal(t, "[REDACTED]", string(redactBody([]byte("not json"))))
+	assert.Nil(t, redactBody(nil))
+}
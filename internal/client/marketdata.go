@@ -0,0 +1,327 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// Subscription is an active market data subscription.
+type Subscription struct {
+	once        sync.Once
+	err         error
+	unsubscribe func(ctx context.Context) error
+}
+
+// Unsubscribe stops delivering updates. It is safe to call more than once.
+func (s *Subscription) Unsubscribe(ctx context.Context) error {
+	s.once.Do(func() {
+		s.err = s.unsubscribe(ctx)
+	})
+	return s.err
+}
+
+// mdKey identifies the stream of one kind of market data for a contract.
+type mdKey struct {
+	kind       string // "quote", "dom" or "histogram"
+	contractID int
+}
+
+// mdEndpoints are the endpoints that start and stop each kind of market
+// data stream.
+var mdEndpoints = map[string]struct{ subscribe, unsubscribe string }{
+	"quote":     {"md/subscribeQuote", "md/unsubscribeQuote"},
+	"dom":       {"md/subscribeDOM", "md/unsubscribeDOM"},
+	"histogram": {"md/subscribeHistogram", "md/unsubscribeHistogram"},
+}
+
+// marketDataStream multiplexes market data subscriptions over a single md
+// WebSocket. Several subscribers may share one subscription to a contract;
+// Tradovate is only asked to subscribe and unsubscribe on the first and last.
+// If the WebSocket drops, it is reconnected and every subscription restored.
+type marketDataStream struct {
+	live liveSocket
+
+	mu          sync.Mutex
+	nextID      int
+	subscribers map[mdKey]map[int]func(json.RawMessage)
+
+	// Charts are addressed by the subscription IDs md/getChart returns, so
+	// they are routed separately. Packets that arrive while a getChart
+	// response is still being handled are held in unclaimed until the chart
+	// is registered.
+	charts        map[int]*chartSeries
+	pendingCharts int
+	unclaimed     map[int][]chartPacket
+}
+
+// SubscribeQuote streams real-time quotes for contractID from the market data
+// WebSocket, calling onQuote for every update until the subscription is
+// cancelled. onQuote is called from the stream's read goroutine and must not
+// block.
+func (c *TradovateClient) SubscribeQuote(ctx context.Context, contractID int, onQuote func(models.Quote)) (*Subscription, error) {
+	return c.subscribeMarketData(ctx, mdKey{kind: "quote", contractID: contractID}, func(data json.RawMessage) {
+		var quote models.Quote
+		if err := json.Unmarshal(data, &quote); err != nil {
+			slog.Warn("ignoring malformed quote", "contractId", contractID, "error", err)
+			return
+		}
+		onQuote(quote)
+	})
+}
+
+// SubscribeDOM streams depth-of-market updates for contractID, calling onDOM
+// with the full bid and ask ladders on every change until the subscription
+// is cancelled. onDOM is called from the stream's read goroutine and must
+// not block.
+func (c *TradovateClient) SubscribeDOM(ctx context.Context, contractID int, onDOM func(models.DOM)) (*Subscription, error) {
+	return c.subscribeMarketData(ctx, mdKey{kind: "dom", contractID: contractID}, func(data json.RawMessage) {
+		var dom models.DOM
+		if err := json.Unmarshal(data, &dom); err != nil {
+			slog.Warn("ignoring malformed DOM update", "contractId", contractID, "error", err)
+			return
+		}
+		onDOM(dom)
+	})
+}
+
+// SubscribeHistogram streams the volume profile of contractID for the
+// current session, calling onProfile with the full profile every time it
+// changes until the subscription is cancelled. onProfile is called from the
+// stream's read goroutine and must not block.
+func (c *TradovateClient) SubscribeHistogram(ctx context.Context, contractID int, onProfile func(models.VolumeProfile)) (*Subscription, error) {
+	// Tradovate sends the full histogram when Refresh is set and only the
+	// changed levels otherwise, so each subscriber accumulates its own copy.
+	profile := models.VolumeProfile{ContractID: contractID, Items: make(map[int]float64)}
+	return c.subscribeMarketData(ctx, mdKey{kind: "histogram", contractID: contractID}, func(data json.RawMessage) {
+		var update struct {
+			models.VolumeProfile
+			Refresh bool `json:"refresh"`
+		}
+		if err := json.Unmarshal(data, &update); err != nil {
+			slog.Warn("ignoring malformed histogram", "contractId", contractID, "error", err)
+			return
+		}
+
+		if update.Refresh || update.Base != profile.Base {
+			profile.Items = make(map[int]float64, len(update.Items))
+		}
+		profile.Timestamp = update.Timestamp
+		profile.Base = update.Base
+		for offset, volume := range update.Items {
+			profile.Items[offset] = volume
+		}
+
+		snapshot := profile
+		snapshot.Items = make(map[int]float64, len(profile.Items))
+		for offset, volume := range profile.Items {
+			snapshot.Items[offset] = volume
+		}
+		onProfile(snapshot)
+	})
+}
+
+// subscribeMarketData registers deliver for key, subscribing with Tradovate
+// if it is the first subscriber.
+func (c *TradovateClient) subscribeMarketData(ctx context.Context, key mdKey, deliver func(json.RawMessage)) (*Subscription, error) {
+	feed := FeedTopOfBook
+	if key.kind == "dom" {
+		feed = FeedDepthOfMarket
+	}
+	if err := c.checkEntitlement(ctx, key.contractID, feed); err != nil {
+		return nil, err
+	}
+
+	stream, err := c.marketDataStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{"symbol": key.contractID}
+	id, first := stream.add(key, deliver)
+	if first {
+		if _, err := stream.live.current().request(ctx, mdEndpoints[key.kind].subscribe, "", body); err != nil {
+			stream.remove(key, id)
+			return nil, fmt.Errorf("error subscribing to contract %d: %w", key.contractID, err)
+		}
+	}
+
+	return &Subscription{unsubscribe: func(ctx context.Context) error {
+		if !stream.remove(key, id) {
+			return nil
+		}
+		if _, err := stream.live.current().request(ctx, mdEndpoints[key.kind].unsubscribe, "", body); err != nil {
+			return fmt.Errorf("error unsubscribing from contract %d: %w", key.contractID, err)
+		}
+		return nil
+	}}, nil
+}
+
+// marketDataStream returns the md WebSocket stream, connecting it if needed.
+func (c *TradovateClient) marketDataStream(ctx context.Context) (*marketDataStream, error) {
+	c.socketMu.Lock()
+	defer c.socketMu.Unlock()
+
+	if c.md != nil {
+		return c.md, nil
+	}
+	if c.marketDataToken() == "" {
+		return nil, fmt.Errorf("not authenticated: call authenticate before streaming market data")
+	}
+
+	stream := &marketDataStream{
+		subscribers: make(map[mdKey]map[int]func(json.RawMessage)),
+		charts:      make(map[int]*chartSeries),
+		unclaimed:   make(map[int][]chartPacket),
+	}
+	dial := func(ctx context.Context) (*tradovateSocket, error) {
+		return dialSocket(ctx, c.Environment().MarketDataURL, c.marketDataToken(), c.socketTiming(), c.socketProxy(), stream.handleEvent)
+	}
+	socket, err := dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stream.live.socket = socket
+
+	supervise, stop := context.WithCancel(context.Background())
+	stream.live.stop = stop
+	go c.superviseSocket(supervise, "market data", &stream.live, dial, stream.restore)
+
+	c.md = stream
+	return stream, nil
+}
+
+// closeMarketDataStream disconnects the md WebSocket, if connected.
+func (c *TradovateClient) closeMarketDataStream() {
+	c.socketMu.Lock()
+	defer c.socketMu.Unlock()
+	if c.md != nil {
+		c.md.live.shutdown()
+		c.md = nil
+	}
+}
+
+// restore re-establishes the stream's quote, DOM, histogram and chart
+// subscriptions on a reconnected socket.
+func (s *marketDataStream) restore(ctx context.Context, socket *tradovateSocket) error {
+	s.mu.Lock()
+	keys := make([]mdKey, 0, len(s.subscribers))
+	for key := range s.subscribers {
+		keys = append(keys, key)
+	}
+	var charts []*chartSeries
+	seen := make(map[*chartSeries]bool)
+	for _, series := range s.charts {
+		if !seen[series] {
+			seen[series] = true
+			charts = append(charts, series)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, key := range keys {
+		if _, err := socket.request(ctx, mdEndpoints[key.kind].subscribe, "", map[string]interface{}{"symbol": key.contractID}); err != nil {
+			return fmt.Errorf("error resubscribing to contract %d: %w", key.contractID, err)
+		}
+	}
+	for _, series := range charts {
+		if err := s.requestChart(ctx, socket, series); err != nil {
+			return fmt.Errorf("error resubscribing to chart for contract %d: %w", series.contractID, err)
+		}
+	}
+	return nil
+}
+
+// add registers deliver for key and reports whether it is the first
+// subscriber.
+func (s *marketDataStream) add(key mdKey, deliver func(json.RawMessage)) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	subs, ok := s.subscribers[key]
+	if !ok {
+		subs = make(map[int]func(json.RawMessage))
+		s.subscribers[key] = subs
+	}
+	subs[id] = deliver
+	return id, !ok
+}
+
+// remove unregisters a subscriber and reports whether it was the last one.
+func (s *marketDataStream) remove(key mdKey, id int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs, ok := s.subscribers[key]
+	if !ok {
+		return false
+	}
+	if _, ok := subs[id]; !ok {
+		return false
+	}
+	delete(subs, id)
+	if len(subs) > 0 {
+		return false
+	}
+	delete(s.subscribers, key)
+	return true
+}
+
+// handleEvent routes "md" events to the subscribers of each contract and
+// "chart" events to their chart.
+func (s *marketDataStream) handleEvent(ev SocketEvent) {
+	if ev.Event == "chart" {
+		s.handleChartEvent(ev.Data)
+		return
+	}
+	if ev.Event != "md" {
+		return
+	}
+
+	var payload struct {
+		Quotes     []json.RawMessage `json:"quotes"`
+		DOMs       []json.RawMessage `json:"doms"`
+		Histograms []json.RawMessage `json:"histograms"`
+	}
+	if err := json.Unmarshal(ev.Data, &payload); err != nil {
+		slog.Warn("ignoring malformed market data event", "error", err)
+		return
+	}
+	for _, quote := range payload.Quotes {
+		s.deliver("quote", quote)
+	}
+	for _, dom := range payload.DOMs {
+		s.deliver("dom", dom)
+	}
+	for _, histogram := range payload.Histograms {
+		s.deliver("histogram", histogram)
+	}
+}
+
+// deliver passes item to every subscriber of its contract.
+func (s *marketDataStream) deliver(kind string, item json.RawMessage) {
+	var head struct {
+		ContractID int `json:"contractId"`
+	}
+	if err := json.Unmarshal(item, &head); err != nil {
+		slog.Warn("ignoring malformed market data item", "kind", kind, "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	subs := make([]func(json.RawMessage), 0, len(s.subscribers[mdKey{kind, head.ContractID}]))
+	for _, deliver := range s.subscribers[mdKey{kind, head.ContractID}] {
+		subs = append(subs, deliver)
+	}
+	s.mu.Unlock()
+
+	for _, deliver := range subs {
+		deliver(item)
+	}
+}
@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMarketDataTestClient returns a client whose md WebSocket is served by
+// server.
+func newMarketDataTestClient(server *fakeSocketServer) *TradovateClient {
+	client := NewTradovateClient()
+	client.env.MarketDataURL = server.wsURL()
+	client.mdAccessToken = "md-token"
+	client.socketHeartbeat = 10 * time.Millisecond
+	return client
+}
+
+func TestSubscribeQuote(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+	client := newMarketDataTestClient(server)
+	defer client.Close()
+
+	first := make(chan models.Quote, 1)
+	second := make(chan models.Quote, 1)
+	subA, err := client.SubscribeQuote(context.Background(), 1234, func(q models.Quote) { first <- q })
+	require.NoError(t, err)
+	subB, err := client.SubscribeQuote(context.Background(), 1234, func(q models.Quote) { second <- q })
+	require.NoError(t, err)
+
+	reqs := server.received()
+	require.Len(t, reqs, 2, "one shared subscription per contract")
+	assert.Equal(t, socketRequest{Endpoint: "authorize", ID: 0, Body: "md-token"}, reqs[0])
+	assert.Equal(t, "md/subscribeQuote", reqs[1].Endpoint)
+	assert.JSONEq(t, `{"symbol":1234}`, reqs[1].Body)
+
+	server.push("md", map[string]interface{}{"quotes": []map[string]interface{}{
+		{"contractId": 9999, "timestamp": "2024-03-15T13:30:00.000Z", "entries": map[string]interface{}{}},
+		{
+			"contractId": 1234,
+			"timestamp":  "2024-03-15T13:30:00.488Z",
+			"entries": map[string]interface{}{
+				"Bid":              map[string]float64{"price": 5100.25, "size": 12},
+				"Offer":            map[string]float64{"price": 5100.5, "size": 9},
+				"TotalTradeVolume": map[string]float64{"size": 41180},
+			},
+		},
+	}})
+
+	for _, ch := range []chan models.Quote{first, second} {
+		select {
+		case q := <-ch:
+			assert.Equal(t, 1234, q.ContractID)
+			assert.Equal(t, "2024-03-15T13:30:00.488Z", q.Timestamp)
+			assert.Equal(t, models.QuoteEntry{Price: 5100.25, Size: 12}, q.Entries["Bid"])
+			assert.Equal(t, models.QuoteEntry{Price: 5100.5, Size: 9}, q.Entries["Offer"])
+			assert.Equal(t, 41180.0, q.Entries["TotalTradeVolume"].Size)
+		case <-time.After(time.Second):
+			t.Fatal("quote not delivered")
+		}
+	}
+
+	require.NoError(t, subA.Unsubscribe(context.Background()))
+	assert.Len(t, server.received(), 2, "subscription is kept while subscribers remain")
+
+	require.NoError(t, subB.Unsubscribe(context.Background()))
+	require.NoError(t, subB.Unsubscribe(context.Background()))
+	reqs = server.received()
+	require.Len(t, reqs, 3)
+	assert.Equal(t, "md/unsubscribeQuote", reqs[2].Endpoint)
+	assert.JSONEq(t, `{"symbol":1234}`, reqs[2].Body)
+}
+
+func TestSubscribeQuoteErrors(t *testing.T) {
+	client := NewTradovateClient()
+	_, err := client.SubscribeQuote(context.Background(), 1234, func(models.Quote) {})
+	assert.EqualError(t, err, "not authenticated: call authenticate before streaming market data")
+
+	server := newFakeSocketServer(t, func(req socketRequest) (int, interface{}) {
+		if req.Endpoint == "md/subscribeQuote" {
+			return 404, map[string]string{"errorText": "Unknown symbol"}
+		}
+		return 200, nil
+	})
+	client = newMarketDataTestClient(server)
+	defer client.Close()
+
+	_, err = client.SubscribeQuote(context.Background(), 1234, func(models.Quote) {})
+	assert.EqualError(t, err, "error subscribing to contract 1234: md/subscribeQuote: status 404: Unknown symbol")
+
+	// A failed subscription is not left registered, so the next attempt retries it.
+	_, err = client.SubscribeQuote(context.Background(), 1234, func(models.Quote) {})
+	assert.Error(t, err)
+	assert.Equal(t, []string{"authorize", "md/subscribeQuote", "md/subscribeQuote"}, server.endpoints())
+}
+
+func TestSubscribeDOM(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+	client := newMarketDataTestClient(server)
+	defer client.Close()
+
+	doms := make(chan models.DOM, 1)
+	quotes := make(chan models.Quote, 1)
+	sub, err := client.SubscribeDOM(context.Background(), 1234, func(d models.DOM) { doms <- d })
+	require.NoError(t, err)
+	_, err = client.SubscribeQuote(context.Background(), 1234, func(q models.Quote) { quotes <- q })
+	require.NoError(t, err)
+
+	reqs := server.received()
+	assert.Equal(t, []string{"authorize", "md/subscribeDOM", "md/subscribeQuote"}, server.endpoints())
+	assert.JSONEq(t, `{"symbol":1234}`, reqs[1].Body)
+
+	server.push("md", map[string]interface{}{"doms": []map[string]interface{}{{
+		"contractId": 1234,
+		"timestamp":  "2024-03-15T13:30:00.488Z",
+		"bids":       []map[string]float64{{"price": 5100.25, "size": 12}, {"price": 5100, "size": 30}},
+		"offers":     []map[string]float64{{"price": 5100.5, "size": 9}},
+	}}})
+
+	select {
+	case d := <-doms:
+		assert.Equal(t, models.DOM{
+			ContractID: 1234,
+			Timestamp:  "2024-03-15T13:30:00.488Z",
+			Bids:       []models.DOMLevel{{Price: 5100.25, Size: 12}, {Price: 5100, Size: 30}},
+			Offers:     []models.DOMLevel{{Price: 5100.5, Size: 9}},
+		}, d)
+	case <-time.After(time.Second):
+		t.Fatal("DOM update not delivered")
+	}
+	assert.Empty(t, quotes, "DOM updates are not delivered to quote subscribers")
+
+	require.NoError(t, sub.Unsubscribe(context.Background()))
+	assert.Equal(t, "md/unsubscribeDOM", server.endpoints()[3])
+}
+
+func TestSubscribeHistogram(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+	client := newMarketDataTestClient(server)
+	defer client.Close()
+
+	profiles := make(chan models.VolumeProfile, 3)
+	sub, err := client.SubscribeHistogram(context.Background(), 1234, func(p models.VolumeProfile) { profiles <- p })
+	require.NoError(t, err)
+	assert.Equal(t, []string{"authorize", "md/subscribeHistogram"}, server.endpoints())
+
+	histogram := func(refresh bool, items map[string]float64) map[string]interface{} {
+		return map[string]interface{}{"histograms": []map[string]interface{}{{
+			"contractId": 1234,
+			"timestamp":  "2024-03-15T13:30:00.488Z",
+			"base":       5100.25,
+			"items":      items,
+			"refresh":    refresh,
+		}}}
+	}
+	receive := func() models.VolumeProfile {
+		select {
+		case p := <-profiles:
+			return p
+		case <-time.After(time.Second):
+			t.Fatal("profile not delivered")
+			return models.VolumeProfile{}
+		}
+	}
+
+	server.push("md", histogram(true, map[string]float64{"-2": 150, "0": 900}))
+	p := receive()
+	assert.Equal(t, 1234, p.ContractID)
+	assert.Equal(t, 5100.25, p.Base)
+	assert.Equal(t, map[int]float64{-2: 150, 0: 900}, p.Items)
+
+	// Partial updates are merged into the profile.
+	server.push("md", histogram(false, map[string]float64{"0": 950, "3": 20}))
+	assert.Equal(t, map[int]float64{-2: 150, 0: 950, 3: 20}, receive().Items)
+
+	// A refresh replaces it.
+	server.push("md", histogram(true, map[string]float64{"1": 5}))
+	assert.Equal(t, map[int]float64{1: 5}, receive().Items)
+
+	require.NoError(t, sub.Unsubscribe(context.Background()))
+	assert.Equal(t, "md/unsubscribeHistogram", server.endpoints()[2])
+}
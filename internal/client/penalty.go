@@ -0,0 +1,144 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxPenaltyRetries bounds how many penalties a single call waits out
+// before giving up.
+const maxPenaltyRetries = 3
+
+// penalty is the body Tradovate returns instead of a result when a client
+// is sending too many requests. The request may be retried with the ticket
+// once Time seconds have passed, unless a captcha must be solved first.
+type penalty struct {
+	Ticket  string `json:"p-ticket"`  // Ticket to include when retrying
+	Time    int    `json:"p-time"`    // Seconds to wait before retrying
+	Captcha bool   `json:"p-captcha"` // Whether a captcha must be solved instead
+}
+
+// throttle delays every request until a penalty has been served. It is
+// shared by all calls on a client, since Tradovate penalizes the client
+// rather than a single request.
+type throttle struct {
+	mu        sync.Mutex
+	until     time.Time
+	penalties int       // Penalties imposed on the client so far
+	last      time.Time // When the latest penalty was imposed
+}
+
+// wait blocks until any penalty has expired or ctx is done.
+func (t *throttle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	d := time.Until(t.until)
+	t.mu.Unlock()
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// penalize holds back requests for d.
+func (t *throttle) penalize(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.penalties++
+	t.last = time.Now()
+	if until := t.last.Add(d); until.After(t.until) {
+		t.until = until
+	}
+}
+
+// handlePenalty applies p to the client's throttle, or returns an error if
+// the call should not be retried.
+func (c *TradovateClient) handlePenalty(ctx context.Context, p *penalty, attempt int) error {
+	if p.Captcha {
+		return &ChallengeError{Kind: ChallengeCaptcha, Message: captchaInstructions, Ticket: p.Ticket}
+	}
+	if attempt >= maxPenaltyRetries {
+		return fmt.Errorf("tradovate is still rate limiting after %d retries; try again later", maxPenaltyRetries)
+	}
+	slog.WarnContext(ctx, "tradovate imposed a time penalty; waiting before retrying", "seconds", p.Time)
+	c.throttle.penalize(time.Duration(p.Time) * time.Second)
+	return nil
+}
+
+// sendPenalized sends a request, waiting out and retrying any time penalty
+// Tradovate responds with.
+func (c *TradovateClient) sendPenalized(ctx context.Context, method, endpoint string, data []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.sendWithRetry(ctx, method, endpoint, data)
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := readPenalty(resp)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		if p == nil {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		if err := c.handlePenalty(ctx, p, attempt); err != nil {
+			return nil, err
+		}
+		data = withPenaltyTicket(data, p.Ticket)
+	}
+}
+
+// readPenalty returns the penalty in resp's body, if any. The body is
+// restored so it can still be read by the caller.
+func readPenalty(resp *http.Response) (*penalty, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !bytes.Contains(body, []byte(`"p-ticket"`)) {
+		return nil, nil
+	}
+	var p penalty
+	if err := json.Unmarshal(body, &p); err != nil || p.Ticket == "" {
+		return nil, nil
+	}
+	return &p, nil
+}
+
+// withPenaltyTicket adds ticket to a JSON object request body. Other bodies
+// are returned unchanged.
+func withPenaltyTicket(data []byte, ticket string) []byte {
+	if len(data) == 0 {
+		return data
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return data
+	}
+	fields["p-ticket"] = ticket
+	withTicket, err := json.Marshal(fields)
+	if err != nil {
+		return data
+	}
+	return withTicket
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticatePenalty(t *testing.T) {
+	var tickets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req AuthRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		tickets = append(tickets, req.PenaltyTicket)
+		if req.PenaltyTicket == "" {
+			json.NewEncoder(w).Encode(map[string]interface{}{"p-ticket": "ticket-1", "p-time": 0})
+			return
+		}
+		json.NewEncoder(w).Encode(AuthResponse{AccessToken: "test-token"})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	resp, err := client.Authenticate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "test-token", resp.AccessToken)
+	assert.Equal(t, []string{"", "ticket-1"}, tickets)
+}
+
+func TestAuthenticatePenaltyCaptcha(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{"p-ticket": "ticket-1", "p-time": 0, "p-captcha": true})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	_, err := client.Authenticate(context.Background())
+	assert.ErrorContains(t, err, "tradovate requires a captcha")
+	assert.Equal(t, 1, calls)
+}
+
+func TestRequestPenalty(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			json.NewEncoder(w).Encode(map[string]interface{}{"p-ticket": "ticket-1", "p-time": 0})
+			return
+		}
+		json.NewEncoder(w).Encode(models.Order{ID: 9})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	order, err := client.PlaceOrder(context.Background(), models.Order{AccountID: 1, ContractID: 2, Quantity: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 9, order.ID)
+	require.Len(t, bodies, 3)
+	assert.NotContains(t, bodies[0], "p-ticket")
+	assert.Contains(t, bodies[2], `"p-ticket":"ticket-1"`)
+}
+
+func TestRequestPenaltyGivesUp(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{"p-ticket": "ticket-1", "p-time": 0})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	_, err := client.GetAccounts(context.Background())
+	assert.EqualError(t, err, "tradovate is still rate limiting after 3 retries; try again later")
+	assert.Equal(t, maxPenaltyRetries+1, calls)
+}
+
+func TestThrottleSharedAcrossCalls(t *testing.T) {
+	var th throttle
+	th.penalize(30 * time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, th.wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 25*time.Millisecond)
+
+	// A shorter penalty does not cut a longer one short.
+	th.penalize(time.Hour)
+	th.penalize(time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, th.wait(ctx), context.DeadlineExceeded)
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// GetPnLSummary retrieves an account's profit and loss for the day or the
+// week. The totals are Tradovate's, from the account's cash balance. The
+// day's are also broken down by contract, from the account's positions,
+// marked at the last trade price, and today's fills. Tradovate reports
+// realized P&L for no longer period than a week.
+// Parameters:
+// - accountID: The unique identifier of the account
+// - period: "day" or "week"
+func (c *TradovateClient) GetPnLSummary(ctx context.Context, accountID int, period string) (*models.PnLSummary, error) {
+	if period != "day" && period != "week" {
+		return nil, fmt.Errorf("unsupported P&L period %q: Tradovate reports realized P&L for the day and the week", period)
+	}
+	balance, err := c.GetCashBalanceSnapshot(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching cash balance of account %d: %w", accountID, err)
+	}
+	summary := models.PnLSummary{
+		AccountID:     accountID,
+		Period:        period,
+		RealizedPnL:   balance.RealizedPnL,
+		UnrealizedPnL: balance.OpenPnL,
+	}
+	if period == "week" {
+		summary.RealizedPnL = balance.WeekRealizedPnL
+	}
+	summary.TotalPnL = summary.RealizedPnL + summary.UnrealizedPnL
+	if period != "day" {
+		return &summary, nil
+	}
+
+	positions, err := c.GetPositionsByAccount(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching positions of account %d: %w", accountID, err)
+	}
+	c.markPositions(ctx, positions)
+	fills, err := c.GetAccountFills(ctx, accountID, time.Time{}, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching fills of account %d: %w", accountID, err)
+	}
+
+	byContract := make(map[int]*models.ContractPnL)
+	contract := func(contractID int) *models.ContractPnL {
+		if byContract[contractID] == nil {
+			byContract[contractID] = &models.ContractPnL{ContractID: contractID}
+		}
+		return byContract[contractID]
+	}
+	for _, position := range positions {
+		if position.NetPos == 0 && position.RealizedPL == 0 {
+			continue
+		}
+		pnl := contract(position.ContractID)
+		pnl.NetPos = position.NetPos
+		pnl.RealizedPnL = position.RealizedPL
+		pnl.UnrealizedPnL = position.UnrealizedPL
+	}
+	for _, fill := range fills {
+		pnl := contract(fill.ContractID)
+		pnl.Fills++
+		pnl.Volume += fill.Quantity
+		if fill.Symbol != "" {
+			pnl.Symbol = fill.Symbol
+		}
+	}
+
+	for _, pnl := range byContract {
+		if pnl.Symbol == "" {
+			if found, err := c.GetContract(ctx, pnl.ContractID); err == nil {
+				pnl.Symbol = found.Name
+			}
+		}
+		pnl.TotalPnL = pnl.RealizedPnL + pnl.UnrealizedPnL
+		summary.Contracts = append(summary.Contracts, *pnl)
+	}
+	sort.Slice(summary.Contracts, func(i, j int) bool {
+		a, b := summary.Contracts[i], summary.Contracts[j]
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.ContractID < b.ContractID
+	})
+	return &summary, nil
+}
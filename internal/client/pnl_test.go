@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPnLSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cashBalance/getCashBalanceSnapshot":
+			w.Write([]byte(`{"openPnL": 250, "realizedPnL": -120, "weekRealizedPnL": 980}`))
+		case "/position/deps":
+			w.Write([]byte(`[
+				{"id": 1, "accountId": 12345, "contractId": 1234, "netPos": -2, "avgPrice": 5100},
+				{"id": 2, "accountId": 12345, "contractId": 4321, "netPos": 0, "realizedPL": -120},
+				{"id": 3, "accountId": 12345, "contractId": 9999, "netPos": 0}
+			]`))
+		case "/md/getQuote/1234":
+			w.Write([]byte(`{"contractId": 1234, "last": 5097.5}`))
+		case "/contract/item":
+			switch r.URL.Query().Get("id") {
+			case "1234":
+				w.Write([]byte(`{"id": 1234, "name": "ESZ4", "contractMaturityId": 77}`))
+			default:
+				w.Write([]byte(`{"id": 4321, "name": "NQZ4"}`))
+			}
+		case "/contractMaturity/item":
+			w.Write([]byte(`{"id": 77, "productId": 9}`))
+		case "/product/item":
+			w.Write([]byte(`{"id": 9, "name": "ES", "tickSize": 0.25, "valuePerPoint": 50}`))
+		case "/order/deps":
+			w.Write([]byte(`[{"id": 41, "accountId": 12345, "contractId": 4321, "side": "Buy"}]`))
+		case "/fill/list":
+			w.Write([]byte(`[{"id": 501, "orderId": 41, "price": 18000, "quantity": 3, "timestamp": 4102444800000}]`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	summary, err := client.GetPnLSummary(context.Background(), 12345, "day")
+	require.NoError(t, err)
+	assert.Equal(t, -120.0, summary.RealizedPnL)
+	assert.Equal(t, 250.0, summary.UnrealizedPnL)
+	assert.Equal(t, 130.0, summary.TotalPnL)
+	assert.Equal(t, []models.ContractPnL{
+		{ContractID: 1234, Symbol: "ESZ4", NetPos: -2, UnrealizedPnL: 250, TotalPnL: 250},
+		{ContractID: 4321, Symbol: "NQZ4", RealizedPnL: -120, TotalPnL: -120, Fills: 1, Volume: 3},
+	}, summary.Contracts, "flat contracts not traded today are left out")
+
+	summary, err = client.GetPnLSummary(context.Background(), 12345, "week")
+	require.NoError(t, err)
+	assert.Equal(t, 980.0, summary.RealizedPnL)
+	assert.Equal(t, 1230.0, summary.TotalPnL)
+	assert.Empty(t, summary.Contracts)
+
+	_, err = client.GetPnLSummary(context.Background(), 12345, "month")
+	assert.ErrorContains(t, err, "unsupported P&L period")
+}
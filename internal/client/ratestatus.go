@@ -0,0 +1,104 @@
+package client
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// requestWindow is how far back requests are counted in the rate limit
+// status. Tradovate's limits are per hour, with shorter bursts also
+// penalized.
+const requestWindow = time.Hour
+
+// endpointClasses lists the classes endpoints are grouped into, in the
+// order they are reported.
+var endpointClasses = []string{"auth", "marketData", "historical", "trading", "other"}
+
+// endpointClass returns the class of operation an endpoint belongs to.
+func endpointClass(endpoint string) string {
+	switch {
+	case strings.HasPrefix(endpoint, "/auth/"):
+		return "auth"
+	case strings.HasPrefix(endpoint, "/md/historical"), strings.HasPrefix(endpoint, "/md/getChart"):
+		return "historical"
+	case strings.HasPrefix(endpoint, "/md/"):
+		return "marketData"
+	case strings.HasPrefix(endpoint, "/order/"), strings.HasPrefix(endpoint, "/orderStrategy/"):
+		return "trading"
+	}
+	return "other"
+}
+
+// requestLog records when requests were sent, by endpoint class, for the
+// last requestWindow.
+type requestLog struct {
+	mu   sync.Mutex
+	sent map[string][]time.Time // Send times by class, oldest first
+}
+
+// record notes a request to endpoint sent at now.
+func (l *requestLog) record(endpoint string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.sent == nil {
+		l.sent = make(map[string][]time.Time)
+	}
+	class := endpointClass(endpoint)
+	l.sent[class] = append(prune(l.sent[class], now), now)
+}
+
+// usage returns the requests sent per class as of now.
+func (l *requestLog) usage(now time.Time) []models.EndpointUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	usage := make([]models.EndpointUsage, 0, len(endpointClasses))
+	for _, class := range endpointClasses {
+		sent := prune(l.sent[class], now)
+		if l.sent != nil {
+			l.sent[class] = sent
+		}
+		u := models.EndpointUsage{Class: class, LastHour: len(sent)}
+		for i := len(sent) - 1; i >= 0 && now.Sub(sent[i]) < time.Minute; i-- {
+			u.LastMinute++
+		}
+		if len(sent) > 0 {
+			u.ResetInSeconds = int(sent[0].Add(requestWindow).Sub(now).Seconds())
+		}
+		usage = append(usage, u)
+	}
+	return usage
+}
+
+// prune drops the times older than requestWindow before now.
+func prune(sent []time.Time, now time.Time) []time.Time {
+	i := 0
+	for i < len(sent) && now.Sub(sent[i]) >= requestWindow {
+		i++
+	}
+	return sent[i:]
+}
+
+// RateLimitStatus reports how hard the client has been using the API: the
+// requests sent per endpoint class recently, and any time penalty
+// Tradovate has imposed. It is answered locally, without sending a request.
+func (c *TradovateClient) RateLimitStatus() models.RateLimitStatus {
+	now := time.Now()
+	status := models.RateLimitStatus{Endpoints: c.requests.usage(now)}
+
+	c.throttle.mu.Lock()
+	until, penalties, last := c.throttle.until, c.throttle.penalties, c.throttle.last
+	c.throttle.mu.Unlock()
+	status.Penalties = penalties
+	if !last.IsZero() {
+		status.LastPenaltyAt = &last
+	}
+	if until.After(now) {
+		status.Penalized = true
+		status.PenaltyUntil = &until
+		status.PenaltyRemainingMs = until.Sub(now).Milliseconds()
+	}
+	return status
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointClass(t *testing.T) {
+	for endpoint, want := range map[string]string{
+		"/auth/renewAccessToken": "auth",
+		"/md/getChart":           "historical",
+		"/md/historical/bars":    "historical",
+		"/md/getQuote":           "marketData",
+		"/order/placeorder":      "trading",
+		"/orderStrategy/list":    "trading",
+		"/account/list":          "other",
+	} {
+		assert.Equal(t, want, endpointClass(endpoint), endpoint)
+	}
+}
+
+func TestRequestLogUsage(t *testing.T) {
+	now := time.Date(2024, 3, 19, 15, 0, 0, 0, time.UTC)
+	var log requestLog
+	log.record("/md/getChart", now.Add(-time.Hour))
+	log.record("/md/getChart", now.Add(-50*time.Minute))
+	log.record("/md/getChart", now.Add(-30*time.Second))
+	log.record("/order/placeorder", now.Add(-2*time.Minute))
+
+	usage := log.usage(now)
+	require.Len(t, usage, len(endpointClasses))
+	assert.Equal(t, models.EndpointUsage{Class: "auth"}, usage[0])
+	assert.Equal(t, models.EndpointUsage{Class: "historical", LastMinute: 1, LastHour: 2, ResetInSeconds: 600}, usage[2])
+	assert.Equal(t, models.EndpointUsage{Class: "trading", LastHour: 1, ResetInSeconds: 3480}, usage[3])
+}
+
+func TestRateLimitStatus(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			json.NewEncoder(w).Encode(map[string]interface{}{"p-ticket": "ticket-1", "p-time": 0})
+			return
+		}
+		json.NewEncoder(w).Encode([]models.Account{{ID: 1}})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	status := client.RateLimitStatus()
+	assert.False(t, status.Penalized)
+	assert.Zero(t, status.Penalties)
+	assert.Nil(t, status.LastPenaltyAt)
+
+	_, err := client.GetAccounts(context.Background())
+	require.NoError(t, err)
+	status = client.RateLimitStatus()
+	assert.Equal(t, 1, status.Penalties)
+	assert.NotNil(t, status.LastPenaltyAt)
+	assert.Equal(t, "other", status.Endpoints[4].Class)
+	assert.Equal(t, 2, status.Endpoints[4].LastMinute, "the retried request is counted")
+
+	client.throttle.penalize(time.Minute)
+	status = client.RateLimitStatus()
+	assert.True(t, status.Penalized)
+	require.NotNil(t, status.PenaltyUntil)
+	assert.InDelta(t, time.Minute.Milliseconds(), status.PenaltyRemainingMs, 1000)
+	assert.Equal(t, 2, status.Penalties)
+}
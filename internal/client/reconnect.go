@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultReconnectPolicy spaces out attempts to restore a dropped market
+// data or user sync WebSocket. Attempts continue until the stream is closed;
+// MaxAttempts and the retry lists are not used.
+var DefaultReconnectPolicy = RetryPolicy{
+	BaseDelay: 500 * time.Millisecond,
+	MaxDelay:  30 * time.Second,
+	Jitter:    0.2,
+}
+
+// SetReconnectPolicy replaces the backoff used when reconnecting dropped
+// WebSockets.
+func (c *TradovateClient) SetReconnectPolicy(policy RetryPolicy) {
+	c.reconnect = policy
+}
+
+// socketTiming returns how the client's sockets are kept alive.
+func (c *TradovateClient) socketTiming() socketTiming {
+	return socketTiming{heartbeat: c.socketHeartbeat, stall: c.socketStall}
+}
+
+// liveSocket holds the current socket of a stream that is reconnected when
+// its socket drops.
+type liveSocket struct {
+	mu      sync.Mutex
+	socket  *tradovateSocket
+	stopped bool
+	stop    context.CancelFunc // Stops the stream's supervisor
+}
+
+// current returns the socket in use, which may have dropped and not yet
+// been replaced.
+func (l *liveSocket) current() *tradovateSocket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.socket
+}
+
+// connected reports whether the socket in use is open, as opposed to
+// dropped and waiting to be replaced.
+func (l *liveSocket) connected() bool {
+	socket := l.current()
+	return socket != nil && socketOpen(socket)
+}
+
+// replace swaps in a reconnected socket. It reports false, leaving socket
+// for the caller to close, if the stream was shut down in the meantime.
+func (l *liveSocket) replace(socket *tradovateSocket) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.stopped {
+		return false
+	}
+	l.socket = socket
+	return true
+}
+
+// shutdown stops reconnecting and closes the socket.
+func (l *liveSocket) shutdown() {
+	l.mu.Lock()
+	l.stopped = true
+	socket := l.socket
+	l.mu.Unlock()
+	if l.stop != nil {
+		l.stop()
+	}
+	if socket != nil {
+		socket.close()
+	}
+}
+
+// superviseSocket reconnects a stream's socket each time it drops, until
+// ctx is done. dial connects a new socket and restore re-establishes the
+// stream's subscriptions on it before it replaces the dropped one.
+func (c *TradovateClient) superviseSocket(ctx context.Context, name string, live *liveSocket, dial func(ctx context.Context) (*tradovateSocket, error), restore func(ctx context.Context, socket *tradovateSocket) error) {
+	for {
+		dropped := live.current()
+		select {
+		case <-dropped.Done():
+		case <-ctx.Done():
+			return
+		}
+		slog.Warn("tradovate socket dropped; reconnecting", "socket", name, "error", dropped.closedErr())
+
+		for attempt := 1; ; attempt++ {
+			if attempt > 1 {
+				timer := time.NewTimer(c.reconnect.delay(attempt-1, nil))
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return
+				}
+			}
+
+			socket, err := c.redialSocket(ctx, dial)
+			if err == nil {
+				if err = restore(ctx, socket); err != nil {
+					socket.close()
+				}
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				slog.Warn("reconnecting tradovate socket failed", "socket", name, "attempt", attempt, "error", err)
+				continue
+			}
+			if !live.replace(socket) {
+				socket.close()
+				return
+			}
+			slog.Info("tradovate socket reconnected", "socket", name, "attempts", attempt)
+			break
+		}
+	}
+}
+
+// redialSocket connects with a fresh token, authenticating again if the
+// socket rejects the one the client holds.
+func (c *TradovateClient) redialSocket(ctx context.Context, dial func(ctx context.Context) (*tradovateSocket, error)) (*tradovateSocket, error) {
+	if err := c.ensureFreshToken(ctx); err != nil {
+		return nil, err
+	}
+	token := c.GetAccessToken()
+	socket, err := dial(ctx)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		return socket, err
+	}
+
+	if err := c.reauthenticate(ctx, token); err != nil {
+		return nil, fmt.Errorf("error re-authenticating: %w", err)
+	}
+	return dial(ctx)
+}
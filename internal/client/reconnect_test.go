@@ -0,0 +1,154 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastReconnect retries dropped sockets almost immediately.
+var fastReconnect = RetryPolicy{BaseDelay: 5 * time.Millisecond, MaxDelay: 20 * time.Millisecond}
+
+func TestMarketDataReconnect(t *testing.T) {
+	var mu sync.Mutex
+	nextChartID := 11
+	server := newFakeSocketServer(t, func(req socketRequest) (int, interface{}) {
+		if req.Endpoint == "md/getChart" {
+			mu.Lock()
+			defer mu.Unlock()
+			nextChartID += 10
+			return 200, map[string]int{"historicalId": nextChartID - 10, "realtimeId": nextChartID - 9}
+		}
+		return 200, map[string]interface{}{}
+	})
+	client := newMarketDataTestClient(server)
+	client.SetReconnectPolicy(fastReconnect)
+	defer client.Close()
+
+	quotes := make(chan models.Quote, 1)
+	bars := make(chan models.ChartBar, 1)
+	_, err := client.SubscribeQuote(context.Background(), 1234, func(q models.Quote) { quotes <- q })
+	require.NoError(t, err)
+	chart, err := client.SubscribeChart(context.Background(), 1234, ChartOptions{}, func(b models.ChartBar) { bars <- b })
+	require.NoError(t, err)
+
+	server.drop()
+	require.Eventually(t, func() bool { return server.count("md/getChart") == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"authorize", "md/subscribeQuote", "md/getChart", "authorize", "md/subscribeQuote", "md/getChart"}, server.endpoints())
+
+	server.push("md", map[string]interface{}{"quotes": []map[string]interface{}{{"contractId": 1234, "entries": map[string]interface{}{}}}})
+	select {
+	case q := <-quotes:
+		assert.Equal(t, 1234, q.ContractID)
+	case <-time.After(time.Second):
+		t.Fatal("quote not delivered after reconnecting")
+	}
+
+	// The chart is routed by the IDs of the new subscription.
+	server.push("chart", map[string]interface{}{"charts": []map[string]interface{}{
+		{"id": 11, "bars": []interface{}{map[string]interface{}{"timestamp": "2024-03-15T13:30:00.000Z", "close": 1}}},
+		{"id": 22, "bars": []interface{}{map[string]interface{}{"timestamp": "2024-03-15T13:30:00.000Z", "close": 2}}},
+	}})
+	select {
+	case b := <-bars:
+		assert.Equal(t, 2.0, b.Close)
+	case <-time.After(time.Second):
+		t.Fatal("bar not delivered after reconnecting")
+	}
+
+	require.NoError(t, chart.Unsubscribe(context.Background()))
+	reqs := server.received()
+	assert.JSONEq(t, `{"subscriptionId": 22}`, reqs[len(reqs)-1].Body)
+}
+
+func TestUserSyncReconnect(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+	client := newUserSyncTestClient(server)
+	client.userID = 42
+	client.SetReconnectPolicy(fastReconnect)
+	defer client.Close()
+
+	events := make(chan UserEvent, 1)
+	_, err := client.SubscribeUserEvents(context.Background(), func(ev UserEvent) { events <- ev })
+	require.NoError(t, err)
+
+	server.drop()
+	require.Eventually(t, func() bool { return server.count("user/syncrequest") == 2 }, time.Second, 5*time.Millisecond)
+	assert.JSONEq(t, `{"users":[42]}`, server.received()[3].Body)
+
+	server.push("props", map[string]interface{}{"entityType": "order", "eventType": "Updated", "entity": map[string]int{"id": 1}})
+	select {
+	case ev := <-events:
+		assert.Equal(t, EntityOrder, ev.EntityType)
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered after reconnecting")
+	}
+}
+
+func TestReconnectAfterStall(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+	client := newMarketDataTestClient(server)
+	client.socketStall = 50 * time.Millisecond
+	client.SetReconnectPolicy(fastReconnect)
+	defer client.Close()
+
+	_, err := client.SubscribeQuote(context.Background(), 1234, func(models.Quote) {})
+	require.NoError(t, err)
+
+	// The fake server never sends heartbeats, so the socket is declared dead.
+	require.Eventually(t, func() bool { return server.count("md/subscribeQuote") >= 2 }, time.Second, 5*time.Millisecond)
+}
+
+func TestReconnectReauthenticates(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/accessTokenRequest", r.URL.Path)
+		json.NewEncoder(w).Encode(AuthResponse{AccessToken: "new-token", MdAccessToken: "new-md-token"})
+	}))
+	defer api.Close()
+
+	var revoked atomic.Bool
+	server := newFakeSocketServer(t, func(req socketRequest) (int, interface{}) {
+		if req.Endpoint == "authorize" && revoked.Load() && req.Body != "new-md-token" {
+			return http.StatusUnauthorized, "Access is denied"
+		}
+		return 200, map[string]interface{}{}
+	})
+	client := newMarketDataTestClient(server)
+	client.SetBaseURL(api.URL)
+	client.SetReconnectPolicy(fastReconnect)
+	defer client.Close()
+
+	_, err := client.SubscribeQuote(context.Background(), 1234, func(models.Quote) {})
+	require.NoError(t, err)
+
+	// The token is revoked while the socket is down.
+	revoked.Store(true)
+	server.drop()
+
+	require.Eventually(t, func() bool { return server.count("md/subscribeQuote") == 2 }, time.Second, 5*time.Millisecond)
+	reqs := server.received()
+	assert.Equal(t, "md-token", reqs[2].Body)
+	assert.Equal(t, "new-md-token", reqs[3].Body)
+}
+
+func TestCloseStopsReconnecting(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+	client := newMarketDataTestClient(server)
+	client.SetReconnectPolicy(fastReconnect)
+
+	_, err := client.SubscribeQuote(context.Background(), 1234, func(models.Quote) {})
+	require.NoError(t, err)
+
+	client.Close()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, server.count("authorize"))
+}
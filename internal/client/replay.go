@@ -0,0 +1,140 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ReplayClock describes the state of a Market Replay session's clock.
+type ReplayClock struct {
+	StartTimestamp time.Time `json:"startTimestamp"`           // Point in history the replay starts from
+	Speed          int       `json:"speed"`                    // Playback speed in percent of real time
+	InitialBalance float64   `json:"initialBalance,omitempty"` // Starting balance of the replay account
+}
+
+// InitializeReplayClock starts a Market Replay session at startTime, playing
+// back at speed percent of real time (100 is real time). A zero
+// initialBalance keeps Tradovate's default replay account balance.
+// The client must be in the replay environment and authenticated.
+func (c *TradovateClient) InitializeReplayClock(ctx context.Context, startTime time.Time, speed int, initialBalance float64) (*ReplayClock, error) {
+	if env := c.Environment(); !env.Replay {
+		return nil, fmt.Errorf("replay clock requires the replay environment, not %s", env.Name)
+	}
+	if speed <= 0 {
+		return nil, fmt.Errorf("replay speed must be positive")
+	}
+
+	socket, err := c.replaySocket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start := startTime.UTC().Format(time.RFC3339)
+	data, err := socket.request(ctx, "replay/checkReplaySession", "", map[string]interface{}{"startTimestamp": start})
+	if err != nil {
+		return nil, fmt.Errorf("error checking replay session: %w", err)
+	}
+	var check struct {
+		CheckStatus string `json:"checkStatus"`
+	}
+	if err := json.Unmarshal(data, &check); err != nil {
+		return nil, fmt.Errorf("error decoding replay session check: %w", err)
+	}
+	if check.CheckStatus != "OK" {
+		return nil, fmt.Errorf("replay session unavailable for %s: %s", start, check.CheckStatus)
+	}
+
+	clock := ReplayClock{StartTimestamp: startTime.UTC(), Speed: speed, InitialBalance: initialBalance}
+	body := map[string]interface{}{"startTimestamp": start, "speed": speed}
+	if initialBalance > 0 {
+		body["initialBalance"] = initialBalance
+	}
+	if _, err := socket.request(ctx, "replay/initializeClock", "", body); err != nil {
+		return nil, fmt.Errorf("error initializing replay clock: %w", err)
+	}
+	return &clock, nil
+}
+
+// ChangeReplaySpeed changes the playback speed of the running replay session
+// to speed percent of real time.
+func (c *TradovateClient) ChangeReplaySpeed(ctx context.Context, speed int) error {
+	if env := c.Environment(); !env.Replay {
+		return fmt.Errorf("replay clock requires the replay environment, not %s", env.Name)
+	}
+	if speed <= 0 {
+		return fmt.Errorf("replay speed must be positive")
+	}
+
+	socket, err := c.replaySocket(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := socket.request(ctx, "replay/changeSpeed", "", map[string]interface{}{"speed": speed}); err != nil {
+		return fmt.Errorf("error changing replay speed: %w", err)
+	}
+	return nil
+}
+
+// replaySocket returns the replay session socket, connecting it if needed.
+func (c *TradovateClient) replaySocket(ctx context.Context) (*tradovateSocket, error) {
+	c.socketMu.Lock()
+	defer c.socketMu.Unlock()
+
+	if c.replay != nil {
+		select {
+		case <-c.replay.Done():
+			c.replay = nil
+		default:
+			return c.replay, nil
+		}
+	}
+	token := c.GetAccessToken()
+	if token == "" {
+		return nil, fmt.Errorf("not authenticated: call authenticate before using the replay environment")
+	}
+
+	socket, err := dialSocket(ctx, c.Environment().WebSocketURL, token, c.socketTiming(), c.socketProxy(), nil)
+	if err != nil {
+		return nil, err
+	}
+	c.replay = socket
+	return socket, nil
+}
+
+// sendReplayRequest serves a REST-style request over the replay socket,
+// adapting the response so callers can treat it like an HTTP response.
+func (c *TradovateClient) sendReplayRequest(ctx context.Context, endpoint string, data []byte) (*http.Response, error) {
+	socket, err := c.replaySocket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	path, query, _ := strings.Cut(strings.TrimPrefix(endpoint, "/"), "?")
+	resp, err := socket.send(ctx, path, query, string(data))
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+
+	return &http.Response{
+		StatusCode: resp.Status,
+		Status:     fmt.Sprintf("%d %s", resp.Status, http.StatusText(resp.Status)),
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(resp.Data)),
+	}, nil
+}
+
+// closeReplaySocket disconnects the replay session, if any.
+func (c *TradovateClient) closeReplaySocket() {
+	c.socketMu.Lock()
+	defer c.socketMu.Unlock()
+	if c.replay != nil {
+		c.replay.close()
+		c.replay = nil
+	}
+}
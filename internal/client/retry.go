@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests that fail transiently are retried.
+// Requests are only retried when doing so cannot duplicate their effect:
+// either the method is listed in RetryMethods, or the request never reached
+// Tradovate because the connection could not be established.
+type RetryPolicy struct {
+	MaxAttempts   int           // Total attempts including the first; 1 disables retries
+	BaseDelay     time.Duration // Delay before the first retry, doubled for each one after
+	MaxDelay      time.Duration // Upper bound on a single delay
+	Jitter        float64       // Fraction of each delay that is randomized, from 0 to 1
+	RetryStatuses []int         // Response status codes worth retrying
+	RetryMethods  []string      // Methods that are safe to send more than once
+}
+
+// DefaultRetryPolicy retries idempotent requests up to twice on gateway
+// errors and network failures. Order placement (POST) is never resent once
+// it may have reached Tradovate.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:   3,
+	BaseDelay:     250 * time.Millisecond,
+	MaxDelay:      5 * time.Second,
+	Jitter:        0.2,
+	RetryStatuses: []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+	RetryMethods:  []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete},
+}
+
+// SetRetryPolicy replaces the client's retry policy.
+func (c *TradovateClient) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// sendWithRetry sends a request, retrying transient failures according to
+// the client's retry policy.
+func (c *TradovateClient) sendWithRetry(ctx context.Context, method, endpoint string, data []byte) (*http.Response, error) {
+	policy := c.retry
+	for attempt := 1; ; attempt++ {
+		resp, err := c.sendRequest(ctx, method, endpoint, data)
+		if attempt >= policy.MaxAttempts || !policy.shouldRetry(method, resp, err) {
+			return resp, err
+		}
+
+		delay := policy.delay(attempt, resp)
+		if resp != nil {
+			resp.Body.Close()
+			slog.WarnContext(ctx, "retrying tradovate request", "method", method, "endpoint", endpoint, "status", resp.StatusCode, "attempt", attempt, "delay", delay)
+		} else {
+			slog.WarnContext(ctx, "retrying tradovate request", "method", method, "endpoint", endpoint, "error", err, "attempt", attempt, "delay", delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// shouldRetry reports whether a request that produced resp or err may be
+// sent again.
+func (p RetryPolicy) shouldRetry(method string, resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false
+		}
+		// A failed dial never reached the server, so any method is safe.
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return p.idempotent(method)
+	}
+
+	if !p.idempotent(method) {
+		return false
+	}
+	for _, status := range p.RetryStatuses {
+		if resp.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) idempotent(method string) bool {
+	for _, m := range p.RetryMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns how long to wait before retry number attempt, honouring a
+// Retry-After header in seconds if the server sent one.
+func (p RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return p.capDelay(time.Duration(seconds) * time.Second)
+		}
+	}
+
+	delay := p.BaseDelay << (attempt - 1)
+	if p.Jitter > 0 {
+		delay += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(delay))
+	}
+	return p.capDelay(delay)
+}
+
+func (p RetryPolicy) capDelay(delay time.Duration) time.Duration {
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastRetryPolicy is DefaultRetryPolicy with delays short enough for tests.
+func fastRetryPolicy() RetryPolicy {
+	policy := DefaultRetryPolicy
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 5 * time.Millisecond
+	return policy
+}
+
+func TestRetryTransientStatus(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode([]models.Account{{ID: 1}})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.SetRetryPolicy(fastRetryPolicy())
+
+	accounts, err := client.GetAccounts(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, accounts, 1)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.SetRetryPolicy(fastRetryPolicy())
+
+	_, err := client.GetAccounts(context.Background())
+	assert.EqualError(t, err, "status 502")
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetryNeverResendsOrders(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.SetRetryPolicy(fastRetryPolicy())
+
+	_, err := client.PlaceOrder(context.Background(), models.Order{AccountID: 1, ContractID: 2, Quantity: 1})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls, "a POST that reached the server must not be resent")
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := DefaultRetryPolicy
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "connection refused"}}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: &net.DNSError{Err: "connection reset"}}
+
+	assert.True(t, policy.shouldRetry(http.MethodPost, nil, dialErr), "unsent requests are safe to retry")
+	assert.False(t, policy.shouldRetry(http.MethodPost, nil, readErr))
+	assert.True(t, policy.shouldRetry(http.MethodGet, nil, readErr))
+	assert.False(t, policy.shouldRetry(http.MethodGet, nil, &net.DNSError{Err: "no such host", IsNotFound: true}))
+	assert.False(t, policy.shouldRetry(http.MethodGet, nil, context.Canceled))
+	assert.True(t, policy.shouldRetry(http.MethodGet, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil))
+	assert.False(t, policy.shouldRetry(http.MethodGet, &http.Response{StatusCode: http.StatusBadRequest}, nil))
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, Jitter: 0.5}
+	for attempt, base := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond} {
+		delay := policy.delay(attempt, nil)
+		assert.GreaterOrEqual(t, delay, base/2)
+		assert.LessOrEqual(t, delay, base*3/2)
+	}
+	assert.Equal(t, time.Second, policy.delay(10, nil), "delays are capped")
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"0"}}}
+	assert.Equal(t, time.Duration(0), policy.delay(1, resp))
+}
+
+func TestRetryStopsWhenContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	policy := fastRetryPolicy()
+	policy.BaseDelay = time.Hour
+	policy.MaxDelay = time.Hour
+	client.SetRetryPolicy(policy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.GetAccounts(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
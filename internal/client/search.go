@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// defaultSearchLimit is how many contracts SearchContracts returns when no
+// limit is given.
+const defaultSearchLimit = 10
+
+// Relevance of a contract to a search, from the closest match down.
+const (
+	relevanceExactContract = 100 // the contract name, e.g. MNQZ4
+	relevanceExactProduct  = 90  // the product root symbol, e.g. MNQ
+	relevancePrefix        = 80  // the start of the contract name
+	relevanceDescription   = 60  // every word starts a word of the product name
+	relevanceSuggested     = 40  // suggested by Tradovate alone
+)
+
+// SearchContracts finds unexpired contracts by symbol, such as "MNQ" or
+// "MNQZ4", or by words of the product name, such as "micro nasdaq".
+// Contracts Tradovate suggests for text are combined with the front month
+// of each product whose name matches, then ranked by relevance and, among
+// equally relevant contracts, nearest expiration first.
+// Parameters:
+// - text: The symbol or product name to look for
+// - limit: The most contracts to return; 0 for the default of 10
+func (c *TradovateClient) SearchContracts(ctx context.Context, text string, limit int) ([]models.ContractMatch, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("search text is required")
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	products, err := c.GetProducts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing products: %w", err)
+	}
+	productByID := make(map[int]models.Product, len(products))
+	for _, product := range products {
+		productByID[product.ID] = product
+	}
+
+	var suggested []models.Contract
+	endpoint := fmt.Sprintf("/contract/suggest?t=%s&l=%d", url.QueryEscape(text), limit)
+	if err := c.getList(ctx, endpoint, "contract suggestions", &suggested); err != nil {
+		return nil, err
+	}
+
+	now := c.serverNow()
+	matches := []models.ContractMatch{}
+	covered := make(map[int]bool)
+	for _, contract := range suggested {
+		maturity, err := c.GetContractMaturity(ctx, contract.ContractMaturityID)
+		if err != nil {
+			return nil, fmt.Errorf("error looking up the maturity of %s: %w", contract.Name, err)
+		}
+		if len(unexpiredMaturities([]models.ContractMaturity{*maturity}, now)) == 0 {
+			continue
+		}
+		product := productByID[maturity.ProductID]
+		covered[product.ID] = true
+		matches = append(matches, models.ContractMatch{
+			Contract:       contract,
+			Product:        product.Name,
+			Description:    product.Description,
+			ExpirationDate: maturity.ExpirationDate,
+			Relevance:      contractRelevance(text, contract.Name, product),
+		})
+	}
+
+	// Tradovate suggests by symbol, so products found by name are added
+	// with their front month.
+	for _, product := range products {
+		if len(matches) >= 2*limit {
+			break
+		}
+		if covered[product.ID] || !productMatches(text, product) {
+			continue
+		}
+		maturities, err := c.GetContractMaturities(ctx, product.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error listing maturities of %s: %w", product.Name, err)
+		}
+		maturities = unexpiredMaturities(maturities, now)
+		if len(maturities) == 0 {
+			continue
+		}
+		contract, err := c.maturityContract(ctx, maturities[0].ID)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, models.ContractMatch{
+			Contract:       *contract,
+			Product:        product.Name,
+			Description:    product.Description,
+			ExpirationDate: maturities[0].ExpirationDate,
+			Relevance:      contractRelevance(text, contract.Name, product),
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Relevance != matches[j].Relevance {
+			return matches[i].Relevance > matches[j].Relevance
+		}
+		return matches[i].ExpirationDate < matches[j].ExpirationDate
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// contractRelevance scores how closely the contract named name, of
+// product, matches the search text.
+func contractRelevance(text, name string, product models.Product) int {
+	switch {
+	case strings.EqualFold(name, text):
+		return relevanceExactContract
+	case strings.EqualFold(product.Name, text):
+		return relevanceExactProduct
+	case strings.HasPrefix(strings.ToLower(name), strings.ToLower(text)):
+		return relevancePrefix
+	case describes(text, product):
+		return relevanceDescription
+	}
+	return relevanceSuggested
+}
+
+// productMatches reports whether product is found by the search text,
+// either by its root symbol or by its name.
+func productMatches(text string, product models.Product) bool {
+	return strings.EqualFold(product.Name, text) || describes(text, product)
+}
+
+// describes reports whether every word of text starts a word of the
+// product's name, so "micro nasdaq" finds "Micro E-mini Nasdaq-100".
+func describes(text string, product models.Product) bool {
+	words := strings.FieldsFunc(strings.ToLower(product.Description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, want := range strings.Fields(strings.ToLower(text)) {
+		found := false
+		for _, word := range words {
+			if strings.HasPrefix(word, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return len(words) > 0
+}
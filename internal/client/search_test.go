@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSearchServer lists the NQ and MNQ products and suggests the MNQ
+// contracts, including one that has expired, for any text.
+func newSearchServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/product/list":
+			w.Write([]byte(`[
+				{"id": 1, "name": "NQ", "description": "E-mini Nasdaq-100"},
+				{"id": 2, "name": "MNQ", "description": "Micro E-mini Nasdaq-100"},
+				{"id": 3, "name": "ZN", "description": "10-Year T-Note"}
+			]`))
+		case "/contract/suggest":
+			assert.Equal(t, "5", r.URL.Query().Get("l"))
+			w.Write([]byte(`[
+				{"id": 22, "name": "MNQH99", "contractMaturityId": 202},
+				{"id": 21, "name": "MNQZ98", "contractMaturityId": 201},
+				{"id": 20, "name": "MNQZ20", "contractMaturityId": 200}
+			]`))
+		case "/contractMaturity/item":
+			dates := map[string]string{"200": "2020-12-18T14:30:00Z", "201": "2098-12-19T14:30:00Z", "202": "2099-03-20T13:30:00Z"}
+			id := r.URL.Query().Get("id")
+			fmt.Fprintf(w, `{"id": %s, "productId": 2, "expirationDate": %q}`, id, dates[id])
+		case "/contractMaturity/deps":
+			assert.Equal(t, "1", r.URL.Query().Get("masterid"))
+			w.Write([]byte(`[{"id": 101, "productId": 1, "expirationMonth": 209812, "expirationDate": "2098-12-19T14:30:00Z"}]`))
+		case "/contract/deps":
+			w.Write([]byte(`[{"id": 11, "name": "NQZ98", "contractMaturityId": 101}]`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestSearchContracts(t *testing.T) {
+	server := newSearchServer(t)
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	names := func(text string) []string {
+		matches, err := client.SearchContracts(context.Background(), text, 5)
+		require.NoError(t, err)
+		var names []string
+		for _, match := range matches {
+			names = append(names, match.Contract.Name)
+		}
+		return names
+	}
+
+	// Products named by the text are found with their front month, after
+	// the suggested contracts of the product given by symbol.
+	assert.Equal(t, []string{"MNQZ98", "MNQH99"}, names("MNQ"))
+	assert.Equal(t, []string{"MNQH99", "MNQZ98"}, names("mnqh99"))
+	assert.Equal(t, []string{"MNQZ98", "MNQH99"}, names("micro nasdaq"))
+	assert.Equal(t, []string{"MNQZ98", "NQZ98", "MNQH99"}, names("nasdaq"))
+
+	matches, err := client.SearchContracts(context.Background(), "micro nasdaq", 5)
+	require.NoError(t, err)
+	assert.Equal(t, "MNQ", matches[0].Product)
+	assert.Equal(t, "Micro E-mini Nasdaq-100", matches[0].Description)
+	assert.Equal(t, "2098-12-19T14:30:00Z", matches[0].ExpirationDate)
+	assert.Equal(t, relevanceDescription, matches[0].Relevance)
+
+	_, err = client.SearchContracts(context.Background(), " ", 5)
+	assert.EqualError(t, err, "search text is required")
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// SessionConflictError reports that Tradovate refused a login because the
+// user already has a session open elsewhere, and it could not be taken over.
+type SessionConflictError struct {
+	Message string // Tradovate's explanation
+}
+
+func (e *SessionConflictError) Error() string {
+	return fmt.Sprintf("another tradovate session is already open for this user: %s", e.Message)
+}
+
+// sessionConflict returns the conflict described by an authentication
+// errorText, or nil if it is an ordinary failure.
+func sessionConflict(errorText string) *SessionConflictError {
+	text := strings.ToLower(errorText)
+	if !strings.Contains(text, "session") || (!strings.Contains(text, "already") && !strings.Contains(text, "exist")) {
+		return nil
+	}
+	return &SessionConflictError{Message: errorText}
+}
+
+// sessionEnded reports whether the body of a 401 response says the session
+// was ended by another login rather than the token simply expiring.
+func sessionEnded(body []byte) bool {
+	text := strings.ToLower(string(body))
+	return strings.Contains(text, "session") && (strings.Contains(text, "another") || strings.Contains(text, "taken over"))
+}
+
+// SetSessionTakeover controls what happens when a login is refused because
+// a session is already open, typically one a previous run of this process
+// left behind. With takeover enabled, the default, the client ends the
+// session it last held and logs in again; otherwise it returns a
+// *SessionConflictError.
+func (c *TradovateClient) SetSessionTakeover(enabled bool) {
+	c.noSessionTakeover.Store(!enabled)
+}
+
+// Logout ends the Tradovate session and discards the client's tokens, so a
+// later call needs to authenticate again. The tokens are discarded even if
+// Tradovate could not be reached.
+func (c *TradovateClient) Logout(ctx context.Context) error {
+	token := c.GetAccessToken()
+	c.InvalidateToken()
+	if token == "" {
+		return nil
+	}
+	if err := c.releaseSession(ctx, token); err != nil {
+		return fmt.Errorf("error logging out: %w", err)
+	}
+	return nil
+}
+
+// InvalidateToken discards the client's tokens without contacting
+// Tradovate. The WebSocket connections opened with them are closed and the
+// token cache is removed, so the next call authenticates afresh.
+func (c *TradovateClient) InvalidateToken() {
+	c.Close()
+	c.clearTokens(false)
+	c.clearTokenCache()
+}
+
+// releaseSession ends the session token belongs to.
+func (c *TradovateClient) releaseSession(ctx context.Context, token string) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", c.apiURL()+"/auth/logout", nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	setRequestID(ctx, req)
+
+	done := c.observe(ctx, req, "/auth/logout", nil)
+	resp, err := c.httpClientFor("/auth/logout").Do(req)
+	done(resp, err)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	resp.Body.Close()
+
+	// A token that has already expired or been revoked has no session left
+	// to end.
+	if resp.StatusCode >= 400 && resp.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// takeOverSession ends the session the client last held so that a login
+// refused with conflict can be retried. It returns conflict if takeover is
+// disabled or there is no session to end.
+func (c *TradovateClient) takeOverSession(ctx context.Context, conflict *SessionConflictError) error {
+	token := c.GetAccessToken()
+	if c.noSessionTakeover.Load() || token == "" {
+		return conflict
+	}
+	slog.WarnContext(ctx, "tradovate session already open; ending it and logging in again")
+	if err := c.releaseSession(ctx, token); err != nil {
+		return fmt.Errorf("%w (ending the previous session failed: %v)", conflict, err)
+	}
+	c.InvalidateToken()
+	return nil
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogout(t *testing.T) {
+	var loggedOut []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/auth/logout", r.URL.Path)
+		loggedOut = append(loggedOut, r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "token.json")
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.SetTokenCachePath(cachePath)
+	client.setTokens(&AuthResponse{AccessToken: "test-token", MdAccessToken: "md-token", ExpirationTime: time.Now().Add(time.Hour).Format(time.RFC3339)})
+	require.FileExists(t, cachePath)
+
+	require.NoError(t, client.Logout(context.Background()))
+	assert.Equal(t, []string{"Bearer test-token"}, loggedOut)
+	assert.False(t, client.IsAuthenticated())
+	assert.Empty(t, client.mdAccessToken)
+	_, err := os.Stat(cachePath)
+	assert.True(t, os.IsNotExist(err), "token cache is removed")
+
+	// Without a session there is nothing to end.
+	require.NoError(t, client.Logout(context.Background()))
+	assert.Len(t, loggedOut, 1)
+}
+
+func TestLogoutFailureStillInvalidatesToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	assert.EqualError(t, client.Logout(context.Background()), "error logging out: status 500")
+	assert.False(t, client.IsAuthenticated())
+}
+
+func TestAuthenticateTakesOverSession(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		switch r.URL.Path {
+		case "/auth/logout":
+			assert.Equal(t, "Bearer stale-token", r.Header.Get("Authorization"))
+		case "/auth/accessTokenRequest":
+			if len(calls) == 1 {
+				json.NewEncoder(w).Encode(AuthResponse{ErrorText: "Session already exists for this user"})
+				return
+			}
+			json.NewEncoder(w).Encode(AuthResponse{AccessToken: "new-token"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "stale-token"
+
+	resp, err := client.Authenticate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "new-token", resp.AccessToken)
+	assert.Equal(t, []string{"/auth/accessTokenRequest", "/auth/logout", "/auth/accessTokenRequest"}, calls)
+}
+
+func TestAuthenticateSessionConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/auth/accessTokenRequest", r.URL.Path)
+		json.NewEncoder(w).Encode(AuthResponse{ErrorText: "Session already exists for this user"})
+	}))
+	defer server.Close()
+
+	// With takeover disabled the conflict is reported.
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "stale-token"
+	client.SetSessionTakeover(false)
+
+	_, err := client.Authenticate(context.Background())
+	var conflict *SessionConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, "Session already exists for this user", conflict.Message)
+
+	// So it is when there is no session of ours to end.
+	client = NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	_, err = client.Authenticate(context.Background())
+	assert.True(t, errors.As(err, &conflict))
+}
+
+func TestSessionConflict(t *testing.T) {
+	assert.NotNil(t, sessionConflict("Session already exists"))
+	assert.NotNil(t, sessionConflict("An active session exists for user demo"))
+	assert.Nil(t, sessionConflict("Incorrect username or password"))
+	assert.Nil(t, sessionConflict("Please verify this device"))
+}
@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// socketHeartbeatInterval is how often a heartbeat frame is sent to keep a
+// Tradovate WebSocket open; the server drops connections silent for 10s.
+const socketHeartbeatInterval = 2500 * time.Millisecond
+
+// socketStallTimeout is how long a Tradovate WebSocket may go without
+// receiving any frame, heartbeats included, before it is considered dead.
+// The server sends heartbeats well within this, so a silent connection has
+// usually been lost without either side seeing it close.
+const socketStallTimeout = 30 * time.Second
+
+// socketTiming controls how a socket is kept alive.
+type socketTiming struct {
+	heartbeat time.Duration // Interval between heartbeat frames sent
+	stall     time.Duration // Silence after which the socket fails; zero to wait forever
+}
+
+// errSocketClosed is returned for requests on a closed socket.
+var errSocketClosed = errors.New("tradovate socket closed")
+
+// SocketResponse is the response to a request sent over a Tradovate WebSocket.
+type SocketResponse struct {
+	ID     int             `json:"i"` // ID of the request this responds to
+	Status int             `json:"s"` // HTTP-style status code
+	Data   json.RawMessage `json:"d"` // Response body
+}
+
+// SocketEvent is a server-pushed event on a Tradovate WebSocket, such as a
+// market data update or an entity change.
+type SocketEvent struct {
+	Event string          `json:"e"` // Event type, e.g. "md", "chart" or "props"
+	Data  json.RawMessage `json:"d"` // Event payload
+}
+
+// socketFrame is a single item of an "a[...]" message, which may be either a
+// response or an event.
+type socketFrame struct {
+	ID     *int            `json:"i"`
+	Status int             `json:"s"`
+	Event  string          `json:"e"`
+	Data   json.RawMessage `json:"d"`
+}
+
+// tradovateSocket speaks Tradovate's WebSocket protocol: requests are sent as
+// "endpoint\nid\nquery\nbody" and answered in "a[...]" frames, with "h"
+// heartbeats and "c" close frames.
+type tradovateSocket struct {
+	ws      *wsConn
+	onEvent func(SocketEvent)
+
+	mu      sync.Mutex
+	nextID  int
+	pending map[int]chan SocketResponse
+	err     error
+	done    chan struct{}
+}
+
+// dialSocket connects to the Tradovate WebSocket at url, through the proxy
+// proxy picks if any, authorizes with token, and starts reading frames and
+// sending heartbeats. onEvent, if non-nil, is called for every server-pushed
+// event from the read goroutine.
+func dialSocket(ctx context.Context, url, token string, timing socketTiming, proxy proxyFunc, onEvent func(SocketEvent)) (*tradovateSocket, error) {
+	ws, err := dialWebSocket(ctx, url, proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	// The server opens the session with an "o" frame before accepting requests.
+	open, err := ws.readMessage()
+	if err != nil {
+		ws.close()
+		return nil, fmt.Errorf("error opening tradovate socket: %w", err)
+	}
+	if open != "o" {
+		ws.close()
+		return nil, fmt.Errorf("error opening tradovate socket: unexpected frame %q", open)
+	}
+
+	s := &tradovateSocket{
+		ws:      ws,
+		onEvent: onEvent,
+		pending: make(map[int]chan SocketResponse),
+		done:    make(chan struct{}),
+	}
+	go s.readLoop(timing.stall)
+	go s.heartbeatLoop(timing.heartbeat)
+
+	resp, err := s.send(ctx, "authorize", "", token)
+	if err != nil {
+		s.close()
+		return nil, fmt.Errorf("error authorizing tradovate socket: %w", err)
+	}
+	if resp.Status != 200 {
+		s.close()
+		apiErr := newAPIError("", "authorize", resp.Status, resp.Data)
+		if apiErr.ErrorText == "" {
+			apiErr.ErrorText = strings.Trim(string(resp.Data), `"`)
+		}
+		return nil, fmt.Errorf("error authorizing tradovate socket: %w", apiErr)
+	}
+	return s, nil
+}
+
+// request sends a JSON request to endpoint and waits for its response.
+// Responses with a non-2xx status are returned as errors.
+func (s *tradovateSocket) request(ctx context.Context, endpoint, query string, body interface{}) (json.RawMessage, error) {
+	var payload string
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling request body: %w", err)
+		}
+		payload = string(data)
+	}
+
+	resp, err := s.send(ctx, endpoint, query, payload)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status < 200 || resp.Status >= 300 {
+		apiErr := newAPIError("", endpoint, resp.Status, resp.Data)
+		if apiErr.ErrorText == "" {
+			apiErr.ErrorText = strings.Trim(string(resp.Data), `"`)
+		}
+		return nil, fmt.Errorf("%s: %w", endpoint, apiErr)
+	}
+	return resp.Data, nil
+}
+
+// send writes a raw request frame and waits for the matching response.
+func (s *tradovateSocket) send(ctx context.Context, endpoint, query, body string) (SocketResponse, error) {
+	s.mu.Lock()
+	if s.err != nil {
+		s.mu.Unlock()
+		return SocketResponse{}, s.err
+	}
+	id := s.nextID
+	s.nextID++
+	ch := make(chan SocketResponse, 1)
+	s.pending[id] = ch
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, id)
+		s.mu.Unlock()
+	}()
+
+	if err := s.ws.writeText(fmt.Sprintf("%s\n%d\n%s\n%s", endpoint, id, query, body)); err != nil {
+		s.fail(err)
+		return SocketResponse{}, fmt.Errorf("error sending %s: %w", endpoint, err)
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-s.done:
+		return SocketResponse{}, s.closedErr()
+	case <-ctx.Done():
+		return SocketResponse{}, ctx.Err()
+	}
+}
+
+// readLoop dispatches incoming frames until the connection fails or, if
+// stall is set, no frame arrives for that long.
+func (s *tradovateSocket) readLoop(stall time.Duration) {
+	for {
+		if stall > 0 {
+			s.ws.conn.SetReadDeadline(time.Now().Add(stall))
+		}
+		message, err := s.ws.readMessage()
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			err = fmt.Errorf("tradovate socket stalled: no frames for %s", stall)
+		}
+		if err != nil {
+			s.fail(err)
+			return
+		}
+		if message == "" {
+			continue
+		}
+
+		switch message[0] {
+		case 'o', 'h':
+			// Open and heartbeat frames carry no data.
+		case 'c':
+			s.fail(errSocketClosed)
+			return
+		case 'a':
+			var frames []socketFrame
+			if err := json.Unmarshal([]byte(message[1:]), &frames); err != nil {
+				slog.Warn("ignoring malformed tradovate socket frame", "error", err)
+				continue
+			}
+			for _, frame := range frames {
+				s.dispatch(frame)
+			}
+		}
+	}
+}
+
+func (s *tradovateSocket) dispatch(frame socketFrame) {
+	if frame.ID != nil {
+		s.mu.Lock()
+		ch, ok := s.pending[*frame.ID]
+		s.mu.Unlock()
+		if ok {
+			ch <- SocketResponse{ID: *frame.ID, Status: frame.Status, Data: frame.Data}
+		}
+		return
+	}
+	if frame.Event != "" && s.onEvent != nil {
+		s.onEvent(SocketEvent{Event: frame.Event, Data: frame.Data})
+	}
+}
+
+// heartbeatLoop sends an empty "[]" frame periodically until the socket closes.
+func (s *tradovateSocket) heartbeatLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.ws.writeText("[]"); err != nil {
+				s.fail(err)
+				return
+			}
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// fail records err as the reason the socket stopped and releases waiters.
+func (s *tradovateSocket) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	s.err = err
+	close(s.done)
+	s.ws.conn.Close()
+}
+
+func (s *tradovateSocket) closedErr() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Done returns a channel that is closed when the socket stops.
+func (s *tradovateSocket) Done() <-chan struct{} {
+	return s.done
+}
+
+// socketOpen reports whether s has not stopped.
+func socketOpen(s *tradovateSocket) bool {
+	select {
+	case <-s.Done():
+		return false
+	default:
+		return true
+	}
+}
+
+// close shuts the socket down.
+func (s *tradovateSocket) close() {
+	s.ws.writeFrame(opClose, []byte{0x03, 0xE8})
+	s.fail(errSocketClosed)
+}
@@ -0,0 +1,362 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// socketRequest is a request received by fakeSocketServer.
+type socketRequest struct {
+	Endpoint string
+	ID       int
+	Query    string
+	Body     string
+}
+
+// fakeSocketServer is an in-process Tradovate WebSocket server. Requests
+// are answered by handle; authorize succeeds for any non-empty token unless
+// handle says otherwise.
+type fakeSocketServer struct {
+	*httptest.Server
+	handle func(req socketRequest) (int, interface{})
+
+	mu       sync.Mutex
+	conns    []net.Conn
+	requests []socketRequest
+}
+
+func newFakeSocketServer(t *testing.T, handle func(req socketRequest) (int, interface{})) *fakeSocketServer {
+	t.Helper()
+	s := &fakeSocketServer{handle: handle}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveWebSocket))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// wsURL returns the ws:// URL of the server.
+func (s *fakeSocketServer) wsURL() string {
+	return "ws" + strings.TrimPrefix(s.Server.URL, "http") + "/v1/websocket"
+}
+
+func (s *fakeSocketServer) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		http.Error(w, "expected websocket upgrade", http.StatusBadRequest)
+		return
+	}
+	conn, rw, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", websocketAccept(r.Header.Get("Sec-WebSocket-Key")))
+	rw.Flush()
+
+	s.mu.Lock()
+	s.conns = append(s.conns, conn)
+	s.mu.Unlock()
+	s.write(conn, "o")
+
+	br := bufio.NewReader(rw)
+	for {
+		_, opcode, payload, err := readFrame(br)
+		if err != nil || opcode == opClose {
+			return
+		}
+		message := string(payload)
+		if message == "[]" {
+			continue
+		}
+
+		parts := strings.SplitN(message, "\n", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		id, _ := strconv.Atoi(parts[1])
+		req := socketRequest{Endpoint: parts[0], ID: id, Query: parts[2], Body: parts[3]}
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		s.mu.Unlock()
+
+		status, data := http.StatusOK, interface{}(map[string]interface{}{})
+		if s.handle != nil {
+			status, data = s.handle(req)
+		} else if req.Endpoint == "authorize" && req.Body == "" {
+			status, data = http.StatusUnauthorized, "Access is denied"
+		}
+		frame, _ := json.Marshal([]map[string]interface{}{{"i": id, "s": status, "d": data}})
+		s.write(conn, "a"+string(frame))
+	}
+}
+
+// push sends an event to every connected client.
+func (s *fakeSocketServer) push(event string, data interface{}) {
+	frame, _ := json.Marshal([]map[string]interface{}{{"e": event, "d": data}})
+	s.mu.Lock()
+	conns := append([]net.Conn(nil), s.conns...)
+	s.mu.Unlock()
+	for _, conn := range conns {
+		s.write(conn, "a"+string(frame))
+	}
+}
+
+// drop closes every connection without a close frame, as a network
+// failure would.
+func (s *fakeSocketServer) drop() {
+	s.mu.Lock()
+	conns := s.conns
+	s.conns = nil
+	s.mu.Unlock()
+	for _, conn := range conns {
+		conn.Close()
+	}
+}
+
+// count returns how many requests to endpoint have been received.
+func (s *fakeSocketServer) count(endpoint string) int {
+	n := 0
+	for _, e := range s.endpoints() {
+		if e == endpoint {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *fakeSocketServer) write(conn net.Conn, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeFrame(conn, opText, []byte(message), false)
+}
+
+// received returns the requests received so far.
+func (s *fakeSocketServer) received() []socketRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]socketRequest(nil), s.requests...)
+}
+
+// endpoints returns the endpoints of the requests received so far.
+func (s *fakeSocketServer) endpoints() []string {
+	var endpoints []string
+	for _, req := range s.received() {
+		endpoints = append(endpoints, req.Endpoint)
+	}
+	return endpoints
+}
+
+func TestDialSocket(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+	events := make(chan SocketEvent, 1)
+
+	socket, err := dialSocket(context.Background(), server.wsURL(), "test-token", socketTiming{heartbeat: 10 * time.Millisecond}, nil, func(ev SocketEvent) {
+		events <- ev
+	})
+	require.NoError(t, err)
+	defer socket.close()
+
+	assert.Equal(t, socketRequest{Endpoint: "authorize", ID: 0, Body: "test-token"}, server.received()[0])
+
+	data, err := socket.request(context.Background(), "account/list", "", nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(data))
+
+	server.push("props", map[string]interface{}{"entityType": "order"})
+	select {
+	case ev := <-events:
+		assert.Equal(t, "props", ev.Event)
+		assert.JSONEq(t, `{"entityType":"order"}`, string(ev.Data))
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered")
+	}
+
+	socket.close()
+	<-socket.Done()
+	_, err = socket.request(context.Background(), "account/list", "", nil)
+	assert.ErrorIs(t, err, errSocketClosed)
+}
+
+func TestDialSocketUnauthorized(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+
+	_, err := dialSocket(context.Background(), server.wsURL(), "", socketTiming{heartbeat: time.Second}, nil, nil)
+	assert.EqualError(t, err, "error authorizing tradovate socket: status 401: Access is denied")
+}
+
+func TestSocketRequestError(t *testing.T) {
+	server := newFakeSocketServer(t, func(req socketRequest) (int, interface{}) {
+		if req.Endpoint == "authorize" {
+			return http.StatusOK, nil
+		}
+		return http.StatusBadRequest, map[string]string{"errorText": "Invalid contract"}
+	})
+
+	socket, err := dialSocket(context.Background(), server.wsURL(), "test-token", socketTiming{heartbeat: time.Second}, nil, nil)
+	require.NoError(t, err)
+	defer socket.close()
+
+	_, err = socket.request(context.Background(), "contract/item", "id=1", nil)
+	assert.EqualError(t, err, "contract/item: status 400: Invalid contract")
+}
+
+// newReplayTestClient returns an authenticated client in a replay
+// environment served by server.
+func newReplayTestClient(server *fakeSocketServer) *TradovateClient {
+	client := NewTradovateClient()
+	client.env = EnvironmentReplay
+	client.env.WebSocketURL = server.wsURL()
+	client.env.MarketDataURL = server.wsURL()
+	client.baseURL = server.URL
+	client.accessToken = "replay-token"
+	client.tokenExpiry = time.Now().Add(time.Hour)
+	client.socketHeartbeat = 10 * time.Millisecond
+	return client
+}
+
+func TestInitializeReplayClock(t *testing.T) {
+	server := newFakeSocketServer(t, func(req socketRequest) (int, interface{}) {
+		switch req.Endpoint {
+		case "replay/checkReplaySession":
+			return http.StatusOK, map[string]string{"checkStatus": "OK"}
+		case "account/list":
+			return http.StatusOK, []map[string]interface{}{{"id": 7, "name": "REPLAY1"}}
+		}
+		return http.StatusOK, map[string]interface{}{}
+	})
+	client := newReplayTestClient(server)
+	defer client.Close()
+
+	start := time.Date(2024, 3, 15, 13, 30, 0, 0, time.UTC)
+	clock, err := client.InitializeReplayClock(context.Background(), start, 400, 50000)
+	require.NoError(t, err)
+	assert.Equal(t, &ReplayClock{StartTimestamp: start, Speed: 400, InitialBalance: 50000}, clock)
+
+	require.NoError(t, client.ChangeReplaySpeed(context.Background(), 25))
+
+	// REST requests are served over the replay socket.
+	accounts, err := client.GetAccounts(context.Background())
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	assert.Equal(t, "REPLAY1", accounts[0].Name)
+
+	reqs := server.received()
+	assert.Equal(t, []string{"authorize", "replay/checkReplaySession", "replay/initializeClock", "replay/changeSpeed", "account/list"}, server.endpoints())
+	assert.JSONEq(t, `{"startTimestamp":"2024-03-15T13:30:00Z"}`, reqs[1].Body)
+	assert.JSONEq(t, `{"startTimestamp":"2024-03-15T13:30:00Z","speed":400,"initialBalance":50000}`, reqs[2].Body)
+	assert.JSONEq(t, `{"speed":25}`, reqs[3].Body)
+}
+
+func TestInitializeReplayClockErrors(t *testing.T) {
+	server := newFakeSocketServer(t, func(req socketRequest) (int, interface{}) {
+		if req.Endpoint == "replay/checkReplaySession" {
+			return http.StatusOK, map[string]string{"checkStatus": "StartTimestampAdjusted"}
+		}
+		return http.StatusOK, map[string]interface{}{}
+	})
+	start := time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)
+
+	live := NewTradovateClient()
+	_, err := live.InitializeReplayClock(context.Background(), start, 100, 0)
+	assert.EqualError(t, err, "replay clock requires the replay environment, not live")
+	assert.EqualError(t, live.ChangeReplaySpeed(context.Background(), 100), "replay clock requires the replay environment, not live")
+
+	client := newReplayTestClient(server)
+	defer client.Close()
+	_, err = client.InitializeReplayClock(context.Background(), start, 0, 0)
+	assert.EqualError(t, err, "replay speed must be positive")
+
+	_, err = client.InitializeReplayClock(context.Background(), start, 100, 0)
+	assert.EqualError(t, err, "replay session unavailable for 2024-03-16T00:00:00Z: StartTimestampAdjusted")
+}
+
+// newConnectProxy returns a proxy that tunnels CONNECT requests to their
+// target, recording each target and Proxy-Authorization header. Requests
+// without the credentials in auth, if set, are refused with 407.
+func newConnectProxy(t *testing.T, auth string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var tunnels []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "expected CONNECT", http.StatusMethodNotAllowed)
+			return
+		}
+		if auth != "" && r.Header.Get("Proxy-Authorization") != auth {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		mu.Lock()
+		tunnels = append(tunnels, r.Host)
+		mu.Unlock()
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		fmt.Fprint(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		go func() {
+			io.Copy(upstream, conn)
+			upstream.Close()
+		}()
+		io.Copy(conn, upstream)
+		conn.Close()
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy, &tunnels
+}
+
+func TestDialSocketThroughProxy(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+	proxy, tunnels := newConnectProxy(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("trader:secret")))
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+
+	_, err = dialSocket(context.Background(), server.wsURL(), "test-token", socketTiming{heartbeat: time.Second}, http.ProxyURL(proxyURL), nil)
+	assert.EqualError(t, err, fmt.Sprintf("error connecting to %s: proxy %s refused tunnel: 407 Proxy Authentication Required", server.Listener.Addr(), proxyURL.Host))
+
+	proxyURL.User = url.UserPassword("trader", "secret")
+	socket, err := dialSocket(context.Background(), server.wsURL(), "test-token", socketTiming{heartbeat: time.Second}, http.ProxyURL(proxyURL), nil)
+	require.NoError(t, err)
+	defer socket.close()
+	assert.Equal(t, []string{server.Listener.Addr().String()}, *tunnels)
+
+	data, err := socket.request(context.Background(), "account/list", "", nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(data))
+}
+
+func TestSocketProxy(t *testing.T) {
+	client := NewTradovateClient()
+	require.NoError(t, client.SetTransportOptions(TransportOptions{ProxyURL: "http://proxy.internal:3128"}))
+	target, _ := url.Parse("https://md.tradovateapi.com/v1/websocket")
+	proxy, err := client.socketProxy()(&http.Request{URL: target})
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.internal:3128", proxy.Host, "sockets use the REST proxy")
+
+	fixtures, err := NewFixtureTransport(filepath.Join(t.TempDir(), "fixtures.json"), FixtureRecord, http.DefaultTransport)
+	require.NoError(t, err)
+	client.SetTransport(fixtures)
+	assert.Nil(t, client.socketProxy())
+}
@@ -0,0 +1,140 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// OrderEvent is a change to one of the user's orders.
+type OrderEvent struct {
+	EventType string       // "Created", "Updated" or "Deleted"
+	Order     models.Order // The order after the change
+}
+
+// SubscribeQuotes streams market data for contractID on the returned
+// channel until ctx is done, when the subscription is cancelled and the
+// channel closed. Each value is the contract's full top of book, so a reader
+// that falls behind skips straight to the newest one rather than receiving
+// every intermediate update.
+func (c *TradovateClient) SubscribeQuotes(ctx context.Context, contractID int) (<-chan models.MarketData, error) {
+	send, out := relay[models.MarketData](ctx, true)
+	current := models.MarketData{ContractID: contractID}
+	sub, err := c.SubscribeQuote(ctx, contractID, func(q models.Quote) {
+		current = mergeQuote(current, q)
+		send(current)
+	})
+	if err != nil {
+		return nil, err
+	}
+	go unsubscribeWhenDone(ctx, sub)
+	return out, nil
+}
+
+// SubscribeOrderEvents streams changes to the user's orders on the
+// returned channel until ctx is done, when the subscription is cancelled and
+// the channel closed. Every event is delivered, in order, however far the
+// reader falls behind.
+func (c *TradovateClient) SubscribeOrderEvents(ctx context.Context) (<-chan OrderEvent, error) {
+	send, out := relay[OrderEvent](ctx, false)
+	sub, err := c.SubscribeUserEvents(ctx, func(ev UserEvent) {
+		var order models.Order
+		if err := ev.Decode(&order); err != nil {
+			slog.Warn("ignoring malformed order event", "error", err)
+			return
+		}
+		send(OrderEvent{EventType: ev.EventType, Order: order})
+	}, EntityOrder)
+	if err != nil {
+		return nil, err
+	}
+	go unsubscribeWhenDone(ctx, sub)
+	return out, nil
+}
+
+// mergeQuote applies the entries of a quote update to md. Entries the
+// update does not carry keep their previous values.
+func mergeQuote(md models.MarketData, q models.Quote) models.MarketData {
+	if entry, ok := q.Entries["Bid"]; ok {
+		md.Bid = entry.Price
+	}
+	if entry, ok := q.Entries["Offer"]; ok {
+		md.Ask = entry.Price
+	}
+	if entry, ok := q.Entries["Trade"]; ok {
+		md.Last = entry.Price
+	}
+	if entry, ok := q.Entries["TotalTradeVolume"]; ok {
+		md.Volume = int(entry.Size)
+	}
+	if t, err := time.Parse(time.RFC3339Nano, q.Timestamp); err == nil {
+		md.Timestamp = t.UnixMilli()
+	}
+	return md
+}
+
+// unsubscribeWhenDone cancels sub once ctx is done.
+func unsubscribeWhenDone(ctx context.Context, sub *Subscription) {
+	<-ctx.Done()
+	unsubCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sub.Unsubscribe(unsubCtx); err != nil {
+		slog.Warn("error cancelling subscription", "error", err)
+	}
+}
+
+// relay returns a function that queues values without blocking, for use
+// from a stream's read goroutine, and a channel they are delivered on in
+// order. With conflate set only the newest undelivered value is kept. The
+// channel is closed once ctx is done.
+func relay[T any](ctx context.Context, conflate bool) (func(T), <-chan T) {
+	var (
+		mu      sync.Mutex
+		pending []T
+	)
+	wake := make(chan struct{}, 1)
+	out := make(chan T)
+
+	send := func(v T) {
+		mu.Lock()
+		if conflate {
+			pending = []T{v}
+		} else {
+			pending = append(pending, v)
+		}
+		mu.Unlock()
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+
+	go func() {
+		defer close(out)
+		for {
+			mu.Lock()
+			if len(pending) == 0 {
+				mu.Unlock()
+				select {
+				case <-wake:
+					continue
+				case <-ctx.Done():
+					return
+				}
+			}
+			v := pending[0]
+			pending = pending[1:]
+			mu.Unlock()
+
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return send, out
+}
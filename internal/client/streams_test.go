@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeQuotes(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+	client := newMarketDataTestClient(server)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	quotes, err := client.SubscribeQuotes(ctx, 1234)
+	require.NoError(t, err)
+
+	server.push("md", map[string]interface{}{"quotes": []map[string]interface{}{{
+		"contractId": 1234,
+		"timestamp":  "2024-03-15T13:30:00.488Z",
+		"entries": map[string]interface{}{
+			"Bid":              map[string]float64{"price": 5100.25, "size": 12},
+			"Offer":            map[string]float64{"price": 5100.5, "size": 9},
+			"Trade":            map[string]float64{"price": 5100.25, "size": 1},
+			"TotalTradeVolume": map[string]float64{"size": 41180},
+		},
+	}}})
+	receive := func() models.MarketData {
+		select {
+		case md := <-quotes:
+			return md
+		case <-time.After(time.Second):
+			t.Fatal("quote not delivered")
+			return models.MarketData{}
+		}
+	}
+	assert.Equal(t, models.MarketData{ContractID: 1234, Bid: 5100.25, Ask: 5100.5, Last: 5100.25, Volume: 41180, Timestamp: 1710509400488}, receive())
+
+	// Updates carrying only some entries keep the others.
+	server.push("md", map[string]interface{}{"quotes": []map[string]interface{}{{
+		"contractId": 1234,
+		"timestamp":  "2024-03-15T13:30:01Z",
+		"entries":    map[string]interface{}{"Offer": map[string]float64{"price": 5100.75, "size": 3}},
+	}}})
+	md := receive()
+	assert.Equal(t, 5100.25, md.Bid)
+	assert.Equal(t, 5100.75, md.Ask)
+
+	cancel()
+	require.Eventually(t, func() bool { return server.count("md/unsubscribeQuote") == 1 }, time.Second, 5*time.Millisecond)
+	_, open := <-quotes
+	assert.False(t, open, "channel is closed once ctx is done")
+}
+
+func TestSubscribeOrderEvents(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+	client := newUserSyncTestClient(server)
+	client.userID = 42
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.SubscribeOrderEvents(ctx)
+	require.NoError(t, err)
+
+	server.push("props", map[string]interface{}{"entityType": "fill", "eventType": "Created", "entity": map[string]int{"id": 9}})
+	for i, status := range []string{"Working", "Filled"} {
+		server.push("props", map[string]interface{}{"entityType": "order", "eventType": "Updated", "entity": map[string]interface{}{"id": 67890 + i, "status": status}})
+	}
+
+	// Events are not dropped while the reader is not receiving.
+	time.Sleep(20 * time.Millisecond)
+	for i, status := range []string{"Working", "Filled"} {
+		select {
+		case ev := <-events:
+			assert.Equal(t, "Updated", ev.EventType)
+			assert.Equal(t, 67890+i, ev.Order.ID)
+			assert.Equal(t, status, ev.Order.Status)
+		case <-time.After(time.Second):
+			t.Fatal("order event not delivered")
+		}
+	}
+}
+
+func TestRelayConflates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	send, out := relay[int](ctx, true)
+	for i := 1; i <= 3; i++ {
+		send(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	// The first value may already be waiting to be received.
+	got := <-out
+	if got == 1 {
+		got = <-out
+	}
+	assert.Equal(t, 3, got)
+
+	cancel()
+	_, open := <-out
+	assert.False(t, open)
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// GetAccountSummary retrieves an account together with its cash balance,
+// margin, open positions and working orders. The parts are fetched
+// concurrently; if any of them fails, so does the summary. Open positions
+// are marked at the last trade price to give their live P&L.
+// Parameters:
+// - accountID: The unique identifier of the account
+func (c *TradovateClient) GetAccountSummary(ctx context.Context, accountID int) (*models.AccountSummary, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		summary  models.AccountSummary
+		accounts []models.Account
+		balance  *models.CashBalanceSnapshot
+		margin   *models.MarginSnapshot
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fetch := func(part string, f func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := f(); err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("error fetching %s of account %d: %w", part, accountID, err)
+					cancel()
+				})
+			}
+		}()
+	}
+
+	fetch("details", func() (err error) {
+		accounts, err = c.GetAccounts(ctx)
+		return err
+	})
+	fetch("cash balance", func() (err error) {
+		balance, err = c.GetCashBalanceSnapshot(ctx, accountID)
+		return err
+	})
+	fetch("margin", func() (err error) {
+		margin, err = c.marginRequirements(ctx, accountID)
+		return err
+	})
+	fetch("positions", func() (err error) {
+		summary.Positions, err = c.GetPositionsByAccount(ctx, accountID)
+		return err
+	})
+	fetch("working orders", func() (err error) {
+		summary.WorkingOrders, err = c.GetOrders(ctx, accountID, "Working")
+		return err
+	})
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	found := false
+	for _, account := range accounts {
+		if account.ID == accountID {
+			summary.Account = account
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("account %d not found", accountID)
+	}
+
+	c.markPositions(ctx, summary.Positions)
+	withBalance(margin, balance)
+	summary.CashBalance = *balance
+	summary.Margin = *margin
+	return &summary, nil
+}
+
+// markPositions works out the unrealized P&L of open positions from the
+// last trade price of their contracts. Positions whose quote or point value
+// cannot be fetched, for example without a market data subscription, keep
+// the P&L Tradovate reported.
+func (c *TradovateClient) markPositions(ctx context.Context, positions []models.Position) {
+	var open []*models.Position
+	var contractIDs []int
+	for i := range positions {
+		if positions[i].NetPos != 0 {
+			open = append(open, &positions[i])
+			contractIDs = append(contractIDs, positions[i].ContractID)
+		}
+	}
+	if len(open) == 0 {
+		return
+	}
+
+	quotes, err := c.GetMarketDataBatch(ctx, contractIDs)
+	if err != nil {
+		slog.WarnContext(ctx, "positions not marked to market", "error", err)
+		return
+	}
+	for i, position := range open {
+		if quotes[i].Data == nil || quotes[i].Data.Last == 0 {
+			slog.WarnContext(ctx, "position not marked to market", "contractId", position.ContractID, "error", quotes[i].Error)
+			continue
+		}
+		contract, err := c.GetContract(ctx, position.ContractID)
+		if err != nil || contract.ValuePerPoint == 0 {
+			slog.WarnContext(ctx, "position not marked to market: point value unknown", "contractId", position.ContractID, "error", err)
+			continue
+		}
+		last := quotes[i].Data.Last
+		position.MarkPrice = last
+		position.UnrealizedPL = (last - position.AvgPrice) * float64(position.NetPos) * contract.ValuePerPoint
+	}
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// TickPolicy is what happens to an order price that is not a whole number
+// of the contract's ticks, such as 100.37 on ES, which trades in 0.25s.
+type TickPolicy int32
+
+const (
+	TickReject TickPolicy = iota // Fail the call before it reaches Tradovate (the default)
+	TickSnap                     // Round the price to the nearest tick
+	TickIgnore                   // Send the price as given
+)
+
+// ParseTickPolicy returns the policy named "reject", "snap" or "off". An
+// empty name is the default, TickReject.
+func ParseTickPolicy(name string) (TickPolicy, error) {
+	switch name {
+	case "", "reject":
+		return TickReject, nil
+	case "snap":
+		return TickSnap, nil
+	case "off":
+		return TickIgnore, nil
+	}
+	return TickReject, fmt.Errorf("invalid tick policy %q: must be reject, snap or off", name)
+}
+
+// SetTickPolicy controls how order prices off the contract's tick size are
+// handled. It is safe to call while requests are in flight.
+func (c *TradovateClient) SetTickPolicy(policy TickPolicy) {
+	c.tickPolicy.Store(int32(policy))
+}
+
+// contractSpecs caches the product of each contract maturity looked up, from
+// which a contract takes its tick size and point value.
+type contractSpecs struct {
+	mu       sync.Mutex
+	products map[int]models.Product // Keyed by contract maturity ID
+}
+
+// withSpecs fills in contract's tick size and point value from its product.
+// Failures are logged and leave them unset, which disables tick checks for
+// the contract; Tradovate still rejects off-tick prices.
+func (c *TradovateClient) withSpecs(ctx context.Context, contract *models.Contract) {
+	if contract.ContractMaturityID == 0 {
+		return
+	}
+	product, err := c.maturityProduct(ctx, contract.ContractMaturityID)
+	if err != nil {
+		slog.WarnContext(ctx, "could not look up contract tick size", "contractId", contract.ID, "error", err)
+		return
+	}
+	contract.TickSize = product.TickSize
+	contract.ValuePerPoint = product.ValuePerPoint
+}
+
+// maturityProduct returns the product a contract maturity belongs to.
+func (c *TradovateClient) maturityProduct(ctx context.Context, maturityID int) (models.Product, error) {
+	c.specs.mu.Lock()
+	product, ok := c.specs.products[maturityID]
+	c.specs.mu.Unlock()
+	if ok {
+		return product, nil
+	}
+
+	maturity, err := c.GetContractMaturity(ctx, maturityID)
+	if err != nil {
+		return models.Product{}, err
+	}
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/product/item?id=%d", maturity.ProductID), nil)
+	if err != nil {
+		return models.Product{}, err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
+		return models.Product{}, fmt.Errorf("error decoding product: %w", err)
+	}
+
+	c.specs.mu.Lock()
+	defer c.specs.mu.Unlock()
+	if c.specs.products == nil {
+		c.specs.products = make(map[int]models.Product)
+	}
+	c.specs.products[maturityID] = product
+	return product, nil
+}
+
+// alignPrices applies the tick policy to the prices of an order for
+// contractID. Zero prices are unset and skipped, and the contract is only
+// looked up if there is a price to check.
+func (c *TradovateClient) alignPrices(ctx context.Context, contractID int, prices ...*float64) error {
+	if TickPolicy(c.tickPolicy.Load()) == TickIgnore || !anyPriceSet(prices) {
+		return nil
+	}
+	contract, err := c.GetContract(ctx, contractID)
+	if err != nil {
+		slog.WarnContext(ctx, "skipping tick size check", "contractId", contractID, "error", err)
+		return nil
+	}
+	return c.alignToContract(ctx, contract, prices...)
+}
+
+// alignToContract applies the tick policy to prices for an order on
+// contract, rounding them in place when snapping.
+func (c *TradovateClient) alignToContract(ctx context.Context, contract *models.Contract, prices ...*float64) error {
+	policy := TickPolicy(c.tickPolicy.Load())
+	if policy == TickIgnore {
+		return nil
+	}
+	for _, price := range prices {
+		if price == nil || *price == 0 || contract.OnTick(*price) {
+			continue
+		}
+		rounded := contract.RoundToTick(*price)
+		if policy == TickReject {
+			below, above := rounded, rounded
+			if rounded < *price {
+				above = contract.RoundToTick(rounded + contract.TickSize)
+			} else {
+				below = contract.RoundToTick(rounded - contract.TickSize)
+			}
+			return fmt.Errorf("price %v is not a multiple of the %v tick size of %s: nearest valid prices are %v and %v",
+				*price, contract.TickSize, contract.Name, below, above)
+		}
+		slog.InfoContext(ctx, "rounded order price to tick size", "contract", contract.Name, "price", *price, "rounded", rounded)
+		*price = rounded
+	}
+	return nil
+}
+
+func anyPriceSet(prices []*float64) bool {
+	for _, price := range prices {
+		if price != nil && *price != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// alignChanges applies the tick policy to the new prices in changes, which
+// is updated in place. The order is looked up for its contract only when a
+// price is being changed.
+func (c *TradovateClient) alignChanges(ctx context.Context, orderID int, changes *models.OrderChanges) error {
+	if TickPolicy(c.tickPolicy.Load()) == TickIgnore || (changes.Price == nil && changes.StopPrice == nil) {
+		return nil
+	}
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/order/item?id=%d", orderID), nil)
+	if err != nil {
+		slog.WarnContext(ctx, "skipping tick size check", "orderId", orderID, "error", err)
+		return nil
+	}
+	defer resp.Body.Close()
+	var order models.Order
+	if err := json.NewDecoder(resp.Body).Decode(&order); err != nil || order.ContractID == 0 {
+		slog.WarnContext(ctx, "skipping tick size check", "orderId", orderID, "error", err)
+		return nil
+	}
+
+	// Copy the prices so snapping them does not write through the caller's
+	// pointers.
+	if changes.Price != nil {
+		price := *changes.Price
+		changes.Price = &price
+	}
+	if changes.StopPrice != nil {
+		stop := *changes.StopPrice
+		changes.StopPrice = &stop
+	}
+	return c.alignPrices(ctx, order.ContractID, changes.Price, changes.StopPrice)
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTickTestServer serves ESZ4 (contract 1234) with a 0.25 tick, recording
+// the prices sent to placeOrder and modifyOrder and counting product lookups.
+func newTickTestServer(t *testing.T, prices *[]float64, productCalls *int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contract/item":
+			json.NewEncoder(w).Encode(models.Contract{ID: 1234, Name: "ESZ4", ContractMaturityID: 77})
+		case "/contractMaturity/item":
+			json.NewEncoder(w).Encode(models.ContractMaturity{ID: 77, ProductID: 9})
+		case "/product/item":
+			*productCalls++
+			json.NewEncoder(w).Encode(models.Product{ID: 9, Name: "ES", TickSize: 0.25, ValuePerPoint: 50})
+		case "/order/item":
+			json.NewEncoder(w).Encode(models.Order{ID: 500, ContractID: 1234})
+		case "/order/placeOrder", "/order/modifyOrder":
+			var body struct {
+				Price float64 `json:"price"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			*prices = append(*prices, body.Price)
+			json.NewEncoder(w).Encode(models.Order{ID: 500})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestPlaceOrderTickPolicy(t *testing.T) {
+	var prices []float64
+	var productCalls int
+	server := newTickTestServer(t, &prices, &productCalls)
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	order := models.Order{AccountID: 1, ContractID: 1234, OrderType: "Limit", Price: 100.37, Quantity: 1, TimeInForce: "Day"}
+
+	// Rejected by default, before reaching Tradovate.
+	_, err := client.PlaceOrder(context.Background(), order)
+	assert.EqualError(t, err, "price 100.37 is not a multiple of the 0.25 tick size of ESZ4: nearest valid prices are 100.25 and 100.5")
+	assert.Empty(t, prices)
+
+	client.SetTickPolicy(TickSnap)
+	_, err = client.PlaceOrder(context.Background(), order)
+	require.NoError(t, err)
+
+	client.SetTickPolicy(TickIgnore)
+	_, err = client.PlaceOrder(context.Background(), order)
+	require.NoError(t, err)
+
+	assert.Equal(t, []float64{100.25, 100.37}, prices)
+	assert.Equal(t, 1, productCalls, "the product is cached")
+
+	// Market orders carry no price to check.
+	client.SetTickPolicy(TickReject)
+	_, err = client.PlaceOrder(context.Background(), models.Order{AccountID: 1, ContractID: 1234, OrderType: "Market", Quantity: 1})
+	require.NoError(t, err)
+}
+
+func TestModifyOrderTickPolicy(t *testing.T) {
+	var prices []float64
+	var productCalls int
+	server := newTickTestServer(t, &prices, &productCalls)
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	price := 5100.1
+	err := client.ModifyOrder(context.Background(), 500, models.OrderChanges{Price: &price})
+	assert.EqualError(t, err, "price 5100.1 is not a multiple of the 0.25 tick size of ESZ4: nearest valid prices are 5100 and 5100.25")
+
+	client.SetTickPolicy(TickSnap)
+	require.NoError(t, client.ModifyOrder(context.Background(), 500, models.OrderChanges{Price: &price}))
+	assert.Equal(t, []float64{5100}, prices)
+	assert.Equal(t, 5100.1, price, "the caller's price is left alone")
+}
+
+func TestParseTickPolicy(t *testing.T) {
+	for name, want := range map[string]TickPolicy{"": TickReject, "reject": TickReject, "snap": TickSnap, "off": TickIgnore} {
+		policy, err := ParseTickPolicy(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, policy, name)
+	}
+	_, err := ParseTickPolicy("round")
+	assert.EqualError(t, err, `invalid tick policy "round": must be reject, snap or off`)
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// OperationTimeouts overrides the client's request timeout for classes of
+// operation, since a quote snapshot should fail fast while a long
+// historical range may legitimately take much longer. A zero field keeps
+// the client's default timeout for that class.
+type OperationTimeouts struct {
+	Auth       time.Duration // Access token requests and renewals
+	MarketData time.Duration // Quote snapshots and other md requests
+	Historical time.Duration // Historical data and chart requests
+	Trading    time.Duration // Order placement, modification and cancellation
+}
+
+// SetOperationTimeouts replaces the per-operation timeout overrides. It is
+// safe to call while requests are in flight.
+func (c *TradovateClient) SetOperationTimeouts(timeouts OperationTimeouts) {
+	c.timeouts.Store(&timeouts)
+}
+
+// httpClientFor returns the HTTP client to send a request to endpoint with,
+// carrying the timeout of the endpoint's operation class.
+func (c *TradovateClient) httpClientFor(endpoint string) *http.Client {
+	timeouts := c.timeouts.Load()
+	if timeouts == nil {
+		return c.httpClient
+	}
+	timeout := timeouts.forEndpoint(endpoint)
+	if timeout <= 0 {
+		return c.httpClient
+	}
+	client := *c.httpClient
+	client.Timeout = timeout
+	return &client
+}
+
+// forEndpoint returns the timeout override for endpoint, or zero if it has
+// none.
+func (t *OperationTimeouts) forEndpoint(endpoint string) time.Duration {
+	switch endpointClass(endpoint) {
+	case "auth":
+		return t.Auth
+	case "historical":
+		return t.Historical
+	case "marketData":
+		return t.MarketData
+	case "trading":
+		return t.Trading
+	}
+	return 0
+}
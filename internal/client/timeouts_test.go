@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationTimeoutsForEndpoint(t *testing.T) {
+	timeouts := &OperationTimeouts{
+		Auth:       time.Second,
+		MarketData: 2 * time.Second,
+		Historical: 3 * time.Second,
+		Trading:    4 * time.Second,
+	}
+	tests := map[string]time.Duration{
+		"/auth/renewAccessToken":            time.Second,
+		"/md/getQuote/1234":                 2 * time.Second,
+		"/md/historical?contractId=1":       3 * time.Second,
+		"/order/placeOrder":                 4 * time.Second,
+		"/orderStrategy/startOrderStrategy": 4 * time.Second,
+		"/account/list":                     0,
+	}
+	for endpoint, want := range tests {
+		assert.Equal(t, want, timeouts.forEndpoint(endpoint), endpoint)
+	}
+}
+
+func TestOperationTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 1})
+	client.SetOperationTimeouts(OperationTimeouts{MarketData: 20 * time.Millisecond})
+
+	_, err := client.GetMarketData(context.Background(), 1234)
+	assert.ErrorContains(t, err, "Client.Timeout exceeded")
+
+	// Other operations keep the default timeout.
+	_, err = client.GetContract(context.Background(), 1234)
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Second, client.httpClient.Timeout)
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cachedToken is the on-disk form of the client's authentication state.
+type cachedToken struct {
+	BaseURL        string    `json:"baseUrl"`        // API the tokens were issued by
+	Username       string    `json:"username"`       // User the tokens were issued to
+	UserID         int       `json:"userId"`         // Tradovate ID of that user
+	AccessToken    string    `json:"accessToken"`    // JWT token for API access
+	MdAccessToken  string    `json:"mdAccessToken"`  // JWT token for market data access
+	ExpirationTime time.Time `json:"expirationTime"` // When the access token expires
+}
+
+// DefaultTokenCachePath returns the token cache location under the user's
+// cache directory, or an empty string if it cannot be determined.
+func DefaultTokenCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "mcp-tradovate", "token.json")
+}
+
+// SetTokenCachePath enables persisting tokens to path whenever they are
+// issued or renewed. An empty path disables the cache.
+func (c *TradovateClient) SetTokenCachePath(path string) {
+	c.tokenCachePath = path
+}
+
+// LoadTokenCache restores tokens saved by a previous process. It returns
+// true if a cached token for the same API and user was loaded, and false if
+// there is none or it is too close to expiry to be worth reusing.
+func (c *TradovateClient) LoadTokenCache() (bool, error) {
+	if c.tokenCachePath == "" {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(c.tokenCachePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read token cache: %w", err)
+	}
+
+	var cached cachedToken
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return false, fmt.Errorf("failed to parse token cache: %w", err)
+	}
+	if cached.BaseURL != c.apiURL() || cached.Username != os.Getenv("TRADOVATE_USERNAME") {
+		return false, nil
+	}
+	if cached.AccessToken == "" || cached.ExpirationTime.Sub(c.serverNow()) <= tokenRefreshWindow {
+		return false, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessToken = cached.AccessToken
+	c.mdAccessToken = cached.MdAccessToken
+	c.tokenExpiry = cached.ExpirationTime
+	c.userID = cached.UserID
+	return true, nil
+}
+
+// saveTokenCache writes the current tokens to the cache file, readable only
+// by the current user.
+func (c *TradovateClient) saveTokenCache() error {
+	if c.tokenCachePath == "" {
+		return nil
+	}
+
+	c.mu.RLock()
+	cached := cachedToken{
+		BaseURL:        c.baseURL,
+		Username:       os.Getenv("TRADOVATE_USERNAME"),
+		UserID:         c.userID,
+		AccessToken:    c.accessToken,
+		MdAccessToken:  c.mdAccessToken,
+		ExpirationTime: c.tokenExpiry,
+	}
+	c.mu.RUnlock()
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("failed to encode token cache: %w", err)
+	}
+
+	dir := filepath.Dir(c.tokenCachePath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create token cache directory: %w", err)
+	}
+
+	// Write to a temporary file and rename it into place so that a crash
+	// never leaves a truncated cache behind.
+	tmp, err := os.CreateTemp(dir, ".token-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.tokenCachePath); err != nil {
+		return fmt.Errorf("failed to write token cache: %w", err)
+	}
+	return nil
+}
+
+// clearTokenCache removes the cache file so a rejected token is not reused.
+func (c *TradovateClient) clearTokenCache() {
+	if c.tokenCachePath == "" {
+		return
+	}
+	if err := os.Remove(c.tokenCachePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Warn("failed to remove token cache", "error", err)
+	}
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // The exchange time zone must load where the system has no tz database
+)
+
+// ExchangeLocation is the time zone of the CME Group exchanges Tradovate
+// trades on.
+var ExchangeLocation = func() *time.Location {
+	loc, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		panic(fmt.Sprintf("client: loading exchange time zone: %v", err))
+	}
+	return loc
+}()
+
+// tradingDayOpen is the hour, in ExchangeLocation, at which CME trading
+// days begin, on the evening of the previous calendar day.
+const tradingDayOpen = 17
+
+// tradingDayStart returns when the trading day in progress at now began:
+// 17:00 Chicago time on the previous evening, or on the Thursday evening
+// over the weekend, when no trading day opens on Friday or Saturday.
+func tradingDayStart(now time.Time) time.Time {
+	now = now.In(ExchangeLocation)
+	y, m, d := now.Date()
+	start := time.Date(y, m, d, tradingDayOpen, 0, 0, 0, ExchangeLocation)
+	if now.Before(start) {
+		start = start.AddDate(0, 0, -1)
+	}
+	for start.Weekday() == time.Friday || start.Weekday() == time.Saturday {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start
+}
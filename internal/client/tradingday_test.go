@@ -0,0 +1,30 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTradingDayStart(t *testing.T) {
+	chicago := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 3, day, hour, minute, 0, 0, ExchangeLocation)
+	}
+	for name, tt := range map[string]struct {
+		now, want time.Time
+	}{
+		// 2024-03-19 is a Tuesday.
+		"Early session":          {chicago(19, 0, 30), chicago(18, 17, 0)},
+		"Before the open":        {chicago(19, 16, 59), chicago(18, 17, 0)},
+		"At the open":            {chicago(19, 17, 0), chicago(19, 17, 0)},
+		"Evening":                {chicago(19, 22, 0), chicago(19, 17, 0)},
+		"Friday evening":         {chicago(22, 18, 0), chicago(21, 17, 0)},
+		"Saturday":               {chicago(23, 12, 0), chicago(21, 17, 0)},
+		"Sunday before the open": {chicago(24, 16, 0), chicago(21, 17, 0)},
+		"Sunday open":            {chicago(24, 17, 30), chicago(24, 17, 0)},
+		"UTC midnight":           {time.Date(2024, 3, 20, 0, 30, 0, 0, time.UTC), chicago(19, 17, 0)},
+	} {
+		assert.True(t, tt.want.Equal(tradingDayStart(tt.now)), "%s: got %v", name, tradingDayStart(tt.now))
+	}
+}
@@ -5,13 +5,21 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/0xjmp/mcp-tradovate/internal/logging"
 	"github.com/0xjmp/mcp-tradovate/internal/models"
 )
 
@@ -20,47 +28,180 @@ import (
 // for implementing alternative client implementations.
 type TradovateClientInterface interface {
 	// Authenticate performs the initial authentication with Tradovate and returns the auth response.
-	Authenticate() (*AuthResponse, error)
+	Authenticate(ctx context.Context) (*AuthResponse, error)
+	// AuthenticateWithCredentials authenticates with explicit credentials instead of the environment.
+	AuthenticateWithCredentials(ctx context.Context, creds models.Credentials) (*AuthResponse, error)
+	// IsAuthenticated reports whether the client holds an access token that has not expired.
+	IsAuthenticated() bool
+	// TokenExpiresAt returns when the access token expires; zero if unknown or not authenticated.
+	TokenExpiresAt() time.Time
 	// GetAccounts retrieves all accounts associated with the authenticated user.
-	GetAccounts() ([]models.Account, error)
+	GetAccounts(ctx context.Context) ([]models.Account, error)
+	// GetUserProperties retrieves the settings stored on the user's profile.
+	GetUserProperties(ctx context.Context) ([]models.UserProperty, error)
+	// GetUserPlugins retrieves the add-ons and market data entitlements the user subscribes to.
+	GetUserPlugins(ctx context.Context) ([]models.UserPlugin, error)
+	// ResolveAccount finds an active account by ID or name.
+	ResolveAccount(ctx context.Context, account string) (*models.Account, error)
 	// GetRiskLimits retrieves the risk limits for a specific account.
-	GetRiskLimits(accountID int) (*models.RiskLimit, error)
+	GetRiskLimits(ctx context.Context, accountID int) (*models.RiskLimit, error)
 	// SetRiskLimits updates the risk limits for a specific account.
-	SetRiskLimits(limits models.RiskLimit) error
+	SetRiskLimits(ctx context.Context, limits models.RiskLimit) error
+	// GetMarginSnapshot retrieves the margin usage and available buying power of an account.
+	GetMarginSnapshot(ctx context.Context, accountID int) (*models.MarginSnapshot, error)
+	// GetAccountAlerts retrieves broker notices and triggered alerts, newest first.
+	GetAccountAlerts(ctx context.Context, accountID int, unhandledOnly bool) ([]models.AccountAlert, error)
+	// GetAutoLiquidation retrieves the auto-liquidation settings of an account.
+	GetAutoLiquidation(ctx context.Context, accountID int) (*models.AutoLiquidation, error)
+	// SetAutoLiquidation replaces the auto-liquidation settings of an account.
+	SetAutoLiquidation(ctx context.Context, settings models.AutoLiquidation) (*models.AutoLiquidation, error)
+	// GetCashBalanceSnapshot retrieves an account's current balance and P&L.
+	GetCashBalanceSnapshot(ctx context.Context, accountID int) (*models.CashBalanceSnapshot, error)
+	// GetAccountSummary retrieves an account with its balance, margin, positions and working orders.
+	GetAccountSummary(ctx context.Context, accountID int) (*models.AccountSummary, error)
+	// GetPnLSummary retrieves an account's realized and unrealized P&L for the day or week.
+	GetPnLSummary(ctx context.Context, accountID int, period string) (*models.PnLSummary, error)
+	// GetAccountPermissions reports whether the user may trade an account and why not.
+	GetAccountPermissions(ctx context.Context, accountID int) (*models.AccountPermissions, error)
 	// PlaceOrder submits a new order to Tradovate.
-	PlaceOrder(order models.Order) (*models.Order, error)
+	PlaceOrder(ctx context.Context, order models.Order) (*models.Order, error)
+	// PlaceOCO submits two linked orders where filling one cancels the other.
+	PlaceOCO(ctx context.Context, oco models.OCOOrder) (*models.OCOOrder, error)
+	// PlaceBracketOrder submits an entry order with attached take-profit and stop-loss orders.
+	PlaceBracketOrder(ctx context.Context, bracket models.BracketOrder) (*models.OrderStrategy, error)
+	// ModifyOrder changes the price, quantity or type of a working order.
+	ModifyOrder(ctx context.Context, orderID int, changes models.OrderChanges) error
+	// LiquidatePosition flattens an account's position in a contract at market.
+	LiquidatePosition(ctx context.Context, accountID, contractID int) (int, error)
 	// CancelOrder cancels an existing order by its ID.
-	CancelOrder(orderID int) error
+	CancelOrder(ctx context.Context, orderID int) error
+	// GetOrders retrieves the orders of an account, optionally only those with the given status.
+	GetOrders(ctx context.Context, accountID int, status string) ([]models.Order, error)
+	// GetOrder retrieves a single order with its current status and fill progress.
+	GetOrder(ctx context.Context, orderID int) (*models.Order, error)
+	// GetExecutionReports retrieves the exchange execution reports of an order or an account.
+	GetExecutionReports(ctx context.Context, orderID, accountID int) ([]models.ExecutionReport, error)
+	// GetCommandReports retrieves the outcome of every command sent on an order, including rejection reasons.
+	GetCommandReports(ctx context.Context, orderID int) ([]models.CommandReport, error)
+	// GetCommandHistory retrieves the commands sent on an order or an account's orders, with their outcomes.
+	GetCommandHistory(ctx context.Context, orderID, accountID int) ([]models.Command, error)
 	// GetFills retrieves all fills for a specific order.
-	GetFills(orderID int) ([]models.Fill, error)
+	GetFills(ctx context.Context, orderID int) ([]models.Fill, error)
+	// GetAccountFills retrieves an account's fills between two times, with the symbols traded.
+	GetAccountFills(ctx context.Context, accountID int, start, end time.Time) ([]models.Fill, error)
 	// GetPositions retrieves all current positions for the authenticated user.
-	GetPositions() ([]models.Position, error)
+	GetPositions(ctx context.Context) ([]models.Position, error)
+	// GetPositionsByAccount retrieves the current positions held in a single account.
+	GetPositionsByAccount(ctx context.Context, accountID int) ([]models.Position, error)
+	// InitializeReplayClock starts a Market Replay session at startTime and speed percent of real time.
+	InitializeReplayClock(ctx context.Context, startTime time.Time, speed int, initialBalance float64) (*ReplayClock, error)
+	// ChangeReplaySpeed changes the playback speed of the running replay session.
+	ChangeReplaySpeed(ctx context.Context, speed int) error
 	// GetContracts retrieves all available trading contracts.
-	GetContracts() ([]models.Contract, error)
+	GetContracts(ctx context.Context) ([]models.Contract, error)
+	// GetContract retrieves a single contract by its ID.
+	GetContract(ctx context.Context, contractID int) (*models.Contract, error)
+	// FindContract retrieves a contract by its name, e.g. ESZ4.
+	FindContract(ctx context.Context, name string) (*models.Contract, error)
+	// GetProducts retrieves all futures products with their tick sizes and point values.
+	GetProducts(ctx context.Context) ([]models.Product, error)
+	// GetProductSessions retrieves the daily trading sessions of all products.
+	GetProductSessions(ctx context.Context) ([]models.ProductSession, error)
+	// GetContractMaturities retrieves the listed maturities of a product, or of every product.
+	GetContractMaturities(ctx context.Context, productID int) ([]models.ContractMaturity, error)
+	// GetProductFees retrieves the fees and commissions charged for trading products.
+	GetProductFees(ctx context.Context, productIDs []int) ([]models.ProductFees, error)
+	// GetContractMargin retrieves the margin required to hold one contract.
+	GetContractMargin(ctx context.Context, contractID int) (*models.ProductMargin, error)
+	// GetContractMaturity retrieves the expiration details of a contract maturity.
+	GetContractMaturity(ctx context.Context, maturityID int) (*models.ContractMaturity, error)
+	// ResolveFrontMonth maps a product root symbol to its most actively traded contract.
+	ResolveFrontMonth(ctx context.Context, productSymbol string) (*models.FrontMonth, error)
+	// SearchContracts finds unexpired contracts by symbol or product name, most relevant first.
+	SearchContracts(ctx context.Context, text string, limit int) ([]models.ContractMatch, error)
 	// GetMarketData retrieves current market data for a specific contract.
-	GetMarketData(contractID int) (*models.MarketData, error)
+	GetMarketData(ctx context.Context, contractID int) (*models.MarketData, error)
+	// SubscribeQuotes streams market data for a contract until ctx is done.
+	SubscribeQuotes(ctx context.Context, contractID int) (<-chan models.MarketData, error)
+	// GetMarketDataBatch retrieves current market data for several contracts at once.
+	GetMarketDataBatch(ctx context.Context, contractIDs []int) ([]models.MarketDataResult, error)
 	// GetHistoricalData retrieves historical market data for a specific contract.
-	GetHistoricalData(contractID int, startTime, endTime time.Time, interval string) ([]models.HistoricalData, error)
+	GetHistoricalData(ctx context.Context, contractID int, startTime, endTime time.Time, interval string) ([]models.HistoricalData, error)
+	// GetServerTime returns Tradovate's current time.
+	GetServerTime(ctx context.Context) (time.Time, error)
+	// ClockSkew returns how far Tradovate's clock is ahead of the local one.
+	ClockSkew() time.Duration
+	// RateLimitStatus reports recent request counts and any time penalty in force.
+	RateLimitStatus() models.RateLimitStatus
+	// ConnectionStatus reports the environment and the state of the client's WebSockets.
+	ConnectionStatus() models.ConnectionStatus
 }
 
 // TradovateClient handles API communication with Tradovate.
 // It implements the TradovateClientInterface and manages the HTTP client,
 // authentication state, and base URL configuration.
+//
+// A client is safe for concurrent use: API calls, background token renewal
+// and the setters documented as safe to call while requests are in flight
+// may run from any number of goroutines. Concurrent calls that find the
+// token expired or revoked share a single renewal or login. The remaining
+// setters, such as SetRetryPolicy, SetTokenCachePath and SetDeviceID,
+// configure the client and must be called before it is shared.
 type TradovateClient struct {
-	httpClient  *http.Client
-	accessToken string
-	baseURL     string
+	httpClient *http.Client
+
+	mu            sync.RWMutex // Guards the session state below
+	accessToken   string
+	mdAccessToken string
+	tokenExpiry   time.Time // When accessToken expires; zero if unknown
+	userID        int       // ID of the authenticated user; zero if unknown
+	env           Environment
+	baseURL       string
+
+	refreshMu         sync.Mutex                         // Serializes token renewal and re-authentication
+	tokenCachePath    string                             // File tokens are persisted to; empty to disable
+	deviceID          string                             // Identifies this installation to Tradovate
+	retry             RetryPolicy                        // How transient failures are retried
+	throttle          throttle                           // Holds requests back while a penalty is served
+	requests          requestLog                         // Recent requests, for the rate limit status
+	noCompression     atomic.Bool                        // Whether gzip responses are disabled
+	noSessionTakeover atomic.Bool                        // Whether a login blocked by an open session fails instead of ending it
+	timeouts          atomic.Pointer[OperationTimeouts]  // Per-operation timeout overrides, if any
+	credentials       atomic.Pointer[models.Credentials] // Credentials supplied by the embedding application, if any
+	challengeTicket   atomic.Pointer[string]             // Ticket of an unresolved authentication challenge, if any
+	hooks             atomic.Pointer[Hooks]              // Observers of every HTTP request, if any
+	clockSkew         atomic.Int64                       // Tradovate's clock minus the local clock, in nanoseconds
+	skewWarned        atomic.Bool                        // Whether excessive clock skew has been reported
+	checkEntitlements atomic.Bool                        // Whether market data entitlements are verified before md calls
+	entitlements      entitlements                       // Cached market data entitlements
+	tickPolicy        atomic.Int32                       // TickPolicy applied to order prices
+	specs             contractSpecs                      // Cached tick sizes and point values
+	clientOrders      clientOrders                       // Orders placed with a client order ID
+
+	socketMu        sync.Mutex        // Guards replay, md and user
+	replay          *tradovateSocket  // Market Replay session socket, if connected
+	md              *marketDataStream // Market data WebSocket, if connected
+	user            *userSyncStream   // User sync WebSocket, if connected
+	socketHeartbeat time.Duration     // Interval between WebSocket heartbeats
+	socketStall     time.Duration     // Silence after which a WebSocket is considered dead
+	reconnect       RetryPolicy       // Backoff between attempts to reconnect a dropped WebSocket
 }
 
+// tokenRefreshWindow is how long before expiry the access token is renewed.
+const tokenRefreshWindow = 10 * time.Minute
+
 // AuthRequest represents the authentication request body sent to Tradovate.
 // All fields are required for successful authentication.
 type AuthRequest struct {
-	Name         string `json:"name"`       // Username for Tradovate account
-	Password     string `json:"password"`   // Password for Tradovate account
-	AppID        string `json:"appId"`      // Application ID provided by Tradovate
-	AppVersion   string `json:"appVersion"` // Application version string
-	ClientID     string `json:"cid"`        // OAuth client ID
-	ClientSecret string `json:"sec"`        // OAuth client secret
+	Name         string `json:"name"`               // Username for Tradovate account
+	Password     string `json:"password"`           // Password for Tradovate account
+	AppID        string `json:"appId"`              // Application ID provided by Tradovate
+	AppVersion   string `json:"appVersion"`         // Application version string
+	ClientID     string `json:"cid"`                // OAuth client ID
+	ClientSecret string `json:"sec"`                // OAuth client secret
+	DeviceID     string `json:"deviceId,omitempty"` // Stable ID of this installation
+
+	PenaltyTicket string `json:"p-ticket,omitempty"` // Ticket from a previous penalty response
 }
 
 // AuthResponse represents the authentication response from Tradovate.
@@ -72,57 +213,168 @@ type AuthResponse struct {
 	UserID         int    `json:"userId"`              // Unique identifier for the user
 	Name           string `json:"name"`                // Username of the authenticated user
 	ErrorText      string `json:"errorText,omitempty"` // Error message if authentication fails
+
+	PenaltyTicket  string `json:"p-ticket,omitempty"`  // Set when Tradovate imposes a time penalty
+	PenaltyTime    int    `json:"p-time,omitempty"`    // Seconds to wait before retrying with the ticket
+	PenaltyCaptcha bool   `json:"p-captcha,omitempty"` // Whether a captcha must be solved first
 }
 
 // NewTradovateClient creates a new Tradovate client with default configuration.
-// It sets up an HTTP client with a 10-second timeout and uses the live Tradovate API URL.
+// It sets up an HTTP client with a 10-second timeout and uses the live Tradovate
+// environment; use SetEnvironment to switch to demo.
 func NewTradovateClient() *TradovateClient {
+	// A random device ID is only stable for the life of the client; callers
+	// that persist one replace it with SetDeviceID.
+	deviceID, _ := newDeviceID()
 	return &TradovateClient{
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: defaultTransport(),
 		},
-		baseURL: "https://live.tradovate.com/v1",
+		env:             EnvironmentLive,
+		baseURL:         EnvironmentLive.BaseURL,
+		retry:           DefaultRetryPolicy,
+		socketHeartbeat: socketHeartbeatInterval,
+		socketStall:     socketStallTimeout,
+		reconnect:       DefaultReconnectPolicy,
+		deviceID:        deviceID,
 	}
 }
 
 // SetBaseURL sets the base URL for API requests.
 // This is useful for testing or switching between demo and live environments.
+// It is safe to call while requests are in flight.
 func (c *TradovateClient) SetBaseURL(url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.baseURL = url
 }
 
-// Authenticate performs the authentication with Tradovate using environment variables.
-// Required environment variables:
+// Close releases the client's WebSocket connections.
+func (c *TradovateClient) Close() error {
+	c.closeReplaySocket()
+	c.closeMarketDataStream()
+	c.closeUserSyncStream()
+	return nil
+}
+
+// Authenticate performs the authentication with Tradovate. It uses the
+// credentials last passed to AuthenticateWithCredentials, or else these
+// environment variables:
 // - TRADOVATE_USERNAME: Tradovate account username
 // - TRADOVATE_PASSWORD: Tradovate account password
 // - TRADOVATE_APP_ID: Application ID from Tradovate
 // - TRADOVATE_APP_VERSION: Application version string
 // - TRADOVATE_CID: OAuth client ID
 // - TRADOVATE_SEC: OAuth client secret
-func (c *TradovateClient) Authenticate() (*AuthResponse, error) {
-	authReq := AuthRequest{
-		Name:         os.Getenv("TRADOVATE_USERNAME"),
+// If Tradovate imposes a time penalty, the request is retried with the
+// penalty ticket once the penalty has been served.
+func (c *TradovateClient) Authenticate(ctx context.Context) (*AuthResponse, error) {
+	creds := c.credentials.Load()
+	if creds == nil {
+		creds = credentialsFromEnv()
+	}
+	return c.authenticate(ctx, *creds)
+}
+
+// AuthenticateWithCredentials authenticates with the given credentials
+// instead of the environment. The credentials are kept, without being
+// persisted, so later re-authentication after a token expires or is
+// revoked uses them too.
+func (c *TradovateClient) AuthenticateWithCredentials(ctx context.Context, creds models.Credentials) (*AuthResponse, error) {
+	if creds.Username == "" || creds.Password == "" {
+		return nil, fmt.Errorf("username and password are required")
+	}
+	c.credentials.Store(&creds)
+	c.resetEntitlements()
+	return c.authenticate(ctx, creds)
+}
+
+// credentialsFromEnv reads credentials from the TRADOVATE_* environment
+// variables.
+func credentialsFromEnv() *models.Credentials {
+	return &models.Credentials{
+		Username:     os.Getenv("TRADOVATE_USERNAME"),
 		Password:     os.Getenv("TRADOVATE_PASSWORD"),
 		AppID:        os.Getenv("TRADOVATE_APP_ID"),
 		AppVersion:   os.Getenv("TRADOVATE_APP_VERSION"),
 		ClientID:     os.Getenv("TRADOVATE_CID"),
 		ClientSecret: os.Getenv("TRADOVATE_SEC"),
 	}
+}
+
+// authenticate requests an access token with creds.
+func (c *TradovateClient) authenticate(ctx context.Context, creds models.Credentials) (*AuthResponse, error) {
+	authReq := AuthRequest{
+		Name:         creds.Username,
+		Password:     creds.Password,
+		AppID:        creds.AppID,
+		AppVersion:   creds.AppVersion,
+		ClientID:     creds.ClientID,
+		ClientSecret: creds.ClientSecret,
+		DeviceID:     c.deviceID,
+	}
+	// Resume a login the user was asked to complete out of band.
+	if ticket := c.challengeTicket.Load(); ticket != nil {
+		authReq.PenaltyTicket = *ticket
+	}
+
+	tookOver := false
+	for attempt := 0; ; attempt++ {
+		authResp, err := c.requestAccessToken(ctx, authReq)
+		var conflict *SessionConflictError
+		if errors.As(err, &conflict) && !tookOver {
+			if err := c.takeOverSession(ctx, conflict); err != nil {
+				return nil, fmt.Errorf("authentication failed: %w", err)
+			}
+			tookOver = true
+			authResp, err = c.requestAccessToken(ctx, authReq)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if authResp.PenaltyTicket == "" {
+			c.challengeTicket.Store(nil)
+			c.setTokens(authResp)
+			return authResp, nil
+		}
+
+		p := &penalty{Ticket: authResp.PenaltyTicket, Time: authResp.PenaltyTime, Captcha: authResp.PenaltyCaptcha}
+		if err := c.handlePenalty(ctx, p, attempt); err != nil {
+			if p.Captcha {
+				c.challengeTicket.Store(&p.Ticket)
+			}
+			return nil, fmt.Errorf("authentication failed: %w", err)
+		}
+		authReq.PenaltyTicket = authResp.PenaltyTicket
+	}
+}
+
+// requestAccessToken sends a single access token request.
+func (c *TradovateClient) requestAccessToken(ctx context.Context, authReq AuthRequest) (*AuthResponse, error) {
+	if err := c.throttle.wait(ctx); err != nil {
+		return nil, err
+	}
+	c.requests.record("/auth/accessTokenRequest", time.Now())
 
 	jsonData, err := json.Marshal(authReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal auth request: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", c.baseURL+"/auth/accessTokenRequest", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.apiURL()+"/auth/accessTokenRequest", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	setRequestID(ctx, req)
 
-	resp, err := c.httpClient.Do(req)
+	done := c.observe(ctx, req, "/auth/accessTokenRequest", jsonData)
+	resp, err := c.httpClientFor("/auth/accessTokenRequest").Do(req)
+	done(resp, err)
 	if err != nil {
+		slog.WarnContext(ctx, "tradovate authentication request failed", "error", err)
 		return nil, fmt.Errorf("failed to send request: %v", err)
 	}
 	defer resp.Body.Close()
@@ -133,23 +385,189 @@ func (c *TradovateClient) Authenticate() (*AuthResponse, error) {
 	}
 
 	if authResp.ErrorText != "" {
+		if challenge := verificationChallenge(authResp.ErrorText); challenge != nil {
+			return nil, fmt.Errorf("authentication failed: %w", challenge)
+		}
+		if conflict := sessionConflict(authResp.ErrorText); conflict != nil {
+			return nil, fmt.Errorf("authentication failed: %w", conflict)
+		}
 		return nil, fmt.Errorf("authentication failed: %s", authResp.ErrorText)
 	}
 
-	c.accessToken = authResp.AccessToken
 	return &authResp, nil
 }
 
+// setTokens stores the tokens and expiration time from an auth response.
+func (c *TradovateClient) setTokens(authResp *AuthResponse) {
+	c.mu.Lock()
+	c.accessToken = authResp.AccessToken
+	if authResp.MdAccessToken != "" {
+		c.mdAccessToken = authResp.MdAccessToken
+	}
+	if authResp.UserID != 0 {
+		c.userID = authResp.UserID
+	}
+	c.tokenExpiry = time.Time{}
+	if expiry, err := time.Parse(time.RFC3339, authResp.ExpirationTime); err == nil {
+		c.tokenExpiry = expiry
+	}
+	c.mu.Unlock()
+	if err := c.saveTokenCache(); err != nil {
+		slog.Warn("failed to persist tradovate token", "error", err)
+	}
+}
+
+// clearTokens discards the client's tokens, and the user ID with them if
+// forgetUser is set.
+func (c *TradovateClient) clearTokens(forgetUser bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessToken = ""
+	c.mdAccessToken = ""
+	c.tokenExpiry = time.Time{}
+	if forgetUser {
+		c.userID = 0
+	}
+}
+
+// marketDataToken returns the token market data WebSockets authorize with.
+func (c *TradovateClient) marketDataToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.mdAccessToken
+}
+
+// apiURL returns the base URL of the REST API.
+func (c *TradovateClient) apiURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.baseURL
+}
+
+// knownUserID returns the ID of the authenticated user, or zero if it is
+// not known yet.
+func (c *TradovateClient) knownUserID() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.userID
+}
+
+// setUserID records the ID of the authenticated user.
+func (c *TradovateClient) setUserID(userID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.userID = userID
+}
+
+// renewAccessToken exchanges the current access token for a fresh one
+// without resending the user's password.
+func (c *TradovateClient) renewAccessToken(ctx context.Context) error {
+	resp, err := c.sendRequest(ctx, "GET", "/auth/renewAccessToken", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to renew access token: status %d", resp.StatusCode)
+	}
+
+	var authResp AuthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+		return fmt.Errorf("failed to decode renewal response: %w", err)
+	}
+	if authResp.ErrorText != "" {
+		return fmt.Errorf("failed to renew access token: %s", authResp.ErrorText)
+	}
+	if authResp.AccessToken == "" {
+		return fmt.Errorf("failed to renew access token: no token returned")
+	}
+
+	c.setTokens(&authResp)
+	return nil
+}
+
+// ensureFreshToken renews the access token if it expires within
+// tokenRefreshWindow, falling back to a full authentication if the renewal
+// fails. Concurrent callers wait for a single renewal.
+func (c *TradovateClient) ensureFreshToken(ctx context.Context) error {
+	if !c.tokenDue() {
+		return nil
+	}
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	if !c.tokenDue() {
+		return nil
+	}
+
+	err := c.renewAccessToken(ctx)
+	if err == nil {
+		slog.DebugContext(ctx, "renewed tradovate access token", "expiresAt", c.TokenExpiresAt())
+		return nil
+	}
+	slog.WarnContext(ctx, "tradovate token renewal failed; re-authenticating", "error", err)
+	if _, err := c.Authenticate(ctx); err != nil {
+		return fmt.Errorf("error refreshing access token: %w", err)
+	}
+	return nil
+}
+
+// tokenDue reports whether the access token expires within
+// tokenRefreshWindow and should be renewed.
+func (c *TradovateClient) tokenDue() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.accessToken != "" && !c.tokenExpiry.IsZero() && c.tokenExpiry.Sub(c.serverNow()) <= tokenRefreshWindow
+}
+
+// reauthenticate logs in again after Tradovate rejected stale, the token a
+// request was sent with. Concurrent callers rejected with the same token
+// share one login; if the token has already been replaced it is left alone.
+func (c *TradovateClient) reauthenticate(ctx context.Context, stale string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+	if current := c.GetAccessToken(); current != "" && current != stale {
+		return nil
+	}
+	_, err := c.Authenticate(ctx)
+	return err
+}
+
+// IsAuthenticated reports whether the client holds an access token that
+// has not yet expired. It does not contact Tradovate, so a token revoked
+// server-side is still reported as valid.
+func (c *TradovateClient) IsAuthenticated() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.accessToken == "" {
+		return false
+	}
+	return c.tokenExpiry.IsZero() || c.serverNow().Before(c.tokenExpiry)
+}
+
+// TokenExpiresAt returns when the access token expires, or the zero time if
+// the client is not authenticated or Tradovate did not say.
+func (c *TradovateClient) TokenExpiresAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.accessToken == "" {
+		return time.Time{}
+	}
+	return c.tokenExpiry
+}
+
 // GetAccessToken returns the current access token.
 // This token is used for authenticating subsequent API requests.
 func (c *TradovateClient) GetAccessToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.accessToken
 }
 
 // GetAccounts retrieves all accounts associated with the authenticated user.
 // Returns a slice of Account objects containing account details and balances.
-func (c *TradovateClient) GetAccounts() ([]models.Account, error) {
-	resp, err := c.doRequest("GET", "/account/list", nil)
+func (c *TradovateClient) GetAccounts(ctx context.Context) ([]models.Account, error) {
+	resp, err := c.doRequest(ctx, "GET", "/account/list", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -166,8 +584,8 @@ func (c *TradovateClient) GetAccounts() ([]models.Account, error) {
 // GetRiskLimits retrieves the risk limits for a specific account.
 // Parameters:
 // - accountID: The unique identifier of the account
-func (c *TradovateClient) GetRiskLimits(accountID int) (*models.RiskLimit, error) {
-	resp, err := c.doRequest("GET", fmt.Sprintf("/account/riskLimits/%d", accountID), nil)
+func (c *TradovateClient) GetRiskLimits(ctx context.Context, accountID int) (*models.RiskLimit, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/account/riskLimits/%d", accountID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -181,10 +599,179 @@ func (c *TradovateClient) GetRiskLimits(accountID int) (*models.RiskLimit, error
 	return &limits, nil
 }
 
+// GetAutoLiquidation retrieves the auto-liquidation settings of an account.
+// Parameters:
+// - accountID: The unique identifier of the account
+func (c *TradovateClient) GetAutoLiquidation(ctx context.Context, accountID int) (*models.AutoLiquidation, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/userAccountAutoLiq/deps?masterid=%d", accountID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var settings []models.AutoLiquidation
+	if err := json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("error decoding auto-liquidation settings: %w", err)
+	}
+	if len(settings) == 0 {
+		return nil, fmt.Errorf("no auto-liquidation settings for account %d", accountID)
+	}
+	return &settings[0], nil
+}
+
+// SetAutoLiquidation replaces the auto-liquidation settings of an account
+// and returns them as Tradovate stored them. Thresholds left unset are
+// removed, so callers changing a single threshold should start from
+// GetAutoLiquidation.
+func (c *TradovateClient) SetAutoLiquidation(ctx context.Context, settings models.AutoLiquidation) (*models.AutoLiquidation, error) {
+	if settings.AccountID <= 0 {
+		return nil, fmt.Errorf("account ID is required")
+	}
+	resp, err := c.doRequest(ctx, "POST", "/userAccountAutoLiq/update", settings)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var updated models.AutoLiquidation
+	if err := json.NewDecoder(resp.Body).Decode(&updated); err != nil {
+		return nil, fmt.Errorf("error decoding auto-liquidation settings: %w", err)
+	}
+	return &updated, nil
+}
+
+// GetMarginSnapshot retrieves an account's margin requirements and works out
+// the buying power left for new orders from its net liquidation value.
+// Parameters:
+// - accountID: The unique identifier of the account
+func (c *TradovateClient) GetMarginSnapshot(ctx context.Context, accountID int) (*models.MarginSnapshot, error) {
+	snapshot, err := c.marginRequirements(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := c.GetCashBalanceSnapshot(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	withBalance(snapshot, balance)
+	return snapshot, nil
+}
+
+// marginRequirements retrieves an account's latest margin requirements,
+// without the net liquidation value and available margin.
+func (c *TradovateClient) marginRequirements(ctx context.Context, accountID int) (*models.MarginSnapshot, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/marginSnapshot/deps?masterid=%d", accountID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var snapshots []models.MarginSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snapshots); err != nil {
+		return nil, fmt.Errorf("error decoding margin snapshot: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("no margin snapshot for account %d", accountID)
+	}
+	snapshot := snapshots[len(snapshots)-1]
+	snapshot.AccountID = accountID
+	return &snapshot, nil
+}
+
+// withBalance completes a margin snapshot with the account's balance.
+func withBalance(snapshot *models.MarginSnapshot, balance *models.CashBalanceSnapshot) {
+	snapshot.NetLiq = balance.NetLiq
+	snapshot.AvailableMargin = balance.NetLiq - snapshot.TotalUsedMargin
+}
+
+// GetCashBalanceSnapshot retrieves an account's current balance, including
+// the open profit and loss of its positions.
+// Parameters:
+// - accountID: The unique identifier of the account
+func (c *TradovateClient) GetCashBalanceSnapshot(ctx context.Context, accountID int) (*models.CashBalanceSnapshot, error) {
+	resp, err := c.doRequest(ctx, "POST", "/cashBalance/getCashBalanceSnapshot", map[string]int{"accountId": accountID})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var balance models.CashBalanceSnapshot
+	if err := json.NewDecoder(resp.Body).Decode(&balance); err != nil {
+		return nil, fmt.Errorf("error decoding cash balance snapshot: %w", err)
+	}
+	return &balance, nil
+}
+
+// GetAccountPermissions works out whether the user may place orders on an
+// account. Accounts shared with the user are view-only until the owner's
+// permission is approved, and any account can be put in liquidation-only
+// mode by its owner or by Tradovate's risk desk.
+// Parameters:
+// - accountID: The unique identifier of the account
+func (c *TradovateClient) GetAccountPermissions(ctx context.Context, accountID int) (*models.AccountPermissions, error) {
+	resp, err := c.doRequest(ctx, "GET", "/tradingPermission/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var permissions []models.TradingPermission
+	if err := json.NewDecoder(resp.Body).Decode(&permissions); err != nil {
+		return nil, fmt.Errorf("error decoding trading permissions: %w", err)
+	}
+
+	result := &models.AccountPermissions{AccountID: accountID, CanTrade: true}
+	for _, permission := range permissions {
+		if permission.AccountID != accountID {
+			continue
+		}
+		result.Permissions = append(result.Permissions, permission)
+	}
+	// Accounts the user owns have no permission records; shared accounts
+	// need one that has been approved.
+	if len(result.Permissions) > 0 {
+		approved := false
+		for _, permission := range result.Permissions {
+			if permission.Status == "Approved" || permission.Status == "Accepted" {
+				approved = true
+			}
+		}
+		if !approved {
+			result.CanTrade = false
+			result.Restrictions = append(result.Restrictions, fmt.Sprintf("view-only: trading permission is %s", result.Permissions[len(result.Permissions)-1].Status))
+		}
+	}
+
+	statusResp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/accountRiskStatus/item?id=%d", accountID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer statusResp.Body.Close()
+
+	var status struct {
+		AdminAction          string `json:"adminAction"`
+		LiquidateOnly        string `json:"liquidateOnly"`
+		UserTriggeredLiqOnly bool   `json:"userTriggeredLiqOnly"`
+	}
+	if err := json.NewDecoder(statusResp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("error decoding account risk status: %w", err)
+	}
+	if status.LiquidateOnly != "" || status.UserTriggeredLiqOnly {
+		result.LiquidationOnly = true
+		reason := "liquidation-only: orders may only reduce open positions"
+		if status.AdminAction != "" {
+			reason += " (" + status.AdminAction + ")"
+		}
+		result.Restrictions = append(result.Restrictions, reason)
+	}
+
+	return result, nil
+}
+
 // SetRiskLimits updates the risk limits for a specific account.
 // The limits parameter must include all required risk limit fields.
-func (c *TradovateClient) SetRiskLimits(limits models.RiskLimit) error {
-	resp, err := c.doRequest("POST", "/account/setRiskLimits", limits)
+func (c *TradovateClient) SetRiskLimits(ctx context.Context, limits models.RiskLimit) error {
+	resp, err := c.doRequest(ctx, "POST", "/account/setRiskLimits", limits)
 	if err != nil {
 		return err
 	}
@@ -200,8 +787,43 @@ func (c *TradovateClient) SetRiskLimits(limits models.RiskLimit) error {
 // PlaceOrder submits a new order to Tradovate.
 // The order parameter must include all required order fields such as
 // account ID, contract ID, order type, quantity, and time in force.
-func (c *TradovateClient) PlaceOrder(order models.Order) (*models.Order, error) {
-	resp, err := c.doRequest("POST", "/order/placeOrder", order)
+// Prices that are not a whole number of ticks are handled per SetTickPolicy.
+// An order with a ClientOrderID is placed at most once; see placeOnce. An
+// order with an ActivationTime is held by Tradovate until that time, which
+// must be in the future.
+func (c *TradovateClient) PlaceOrder(ctx context.Context, order models.Order) (*models.Order, error) {
+	if order.ActivationTime != "" {
+		activation, err := parseActivationTime(order.ActivationTime, c.serverNow())
+		if err != nil {
+			return nil, err
+		}
+		order.ActivationTime = activation
+	}
+	if err := c.alignPrices(ctx, order.ContractID, &order.Price, &order.StopPrice); err != nil {
+		return nil, err
+	}
+	if order.ClientOrderID != "" {
+		return c.placeOnce(ctx, order)
+	}
+	return c.placeOrder(ctx, order)
+}
+
+// parseActivationTime checks that value is an RFC 3339 time after now and
+// returns it in UTC, the form Tradovate expects.
+func parseActivationTime(value string, now time.Time) (string, error) {
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", fmt.Errorf("invalid activationTime %q: must be an RFC 3339 time such as 2024-06-03T09:30:00-04:00", value)
+	}
+	if !at.After(now) {
+		return "", fmt.Errorf("activationTime %s is not in the future", value)
+	}
+	return at.UTC().Format(time.RFC3339), nil
+}
+
+// placeOrder sends order to Tradovate.
+func (c *TradovateClient) placeOrder(ctx context.Context, order models.Order) (*models.Order, error) {
+	resp, err := c.doRequest(ctx, "POST", "/order/placeOrder", order)
 	if err != nil {
 		return nil, err
 	}
@@ -215,10 +837,196 @@ func (c *TradovateClient) PlaceOrder(order models.Order) (*models.Order, error)
 	return &placedOrder, nil
 }
 
+// PlaceOCO submits both legs of a one-cancels-other order in a single
+// request, so they are working together or not at all. Both legs must be for
+// the same account, contract and quantity. The returned legs carry the IDs
+// Tradovate assigned.
+func (c *TradovateClient) PlaceOCO(ctx context.Context, oco models.OCOOrder) (*models.OCOOrder, error) {
+	first, second := oco.First, oco.Second
+	if first.AccountID != second.AccountID || first.ContractID != second.ContractID || first.Quantity != second.Quantity {
+		return nil, fmt.Errorf("OCO legs must share account, contract and quantity")
+	}
+	if err := c.alignPrices(ctx, first.ContractID, &first.Price, &first.StopPrice, &second.Price, &second.StopPrice); err != nil {
+		return nil, err
+	}
+
+	type otherLeg struct {
+		Side      string  `json:"side"`
+		OrderType string  `json:"orderType"`
+		Price     float64 `json:"price,omitempty"`
+		StopPrice float64 `json:"stopPrice,omitempty"`
+	}
+	body := struct {
+		models.Order
+		Other otherLeg `json:"other"`
+	}{
+		Order: first,
+		Other: otherLeg{Side: second.Side, OrderType: second.OrderType, Price: second.Price, StopPrice: second.StopPrice},
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/order/placeOCO", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OrderID       int    `json:"orderId"`
+		OCOID         int    `json:"ocoId"`
+		FailureReason string `json:"failureReason"`
+		FailureText   string `json:"failureText"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding OCO response: %w", err)
+	}
+	if result.FailureReason != "" && result.FailureReason != "Success" {
+		return nil, fmt.Errorf("OCO order rejected: %s: %s", result.FailureReason, result.FailureText)
+	}
+
+	first.ID = result.OrderID
+	second.ID = result.OCOID
+	return &models.OCOOrder{First: first, Second: second}, nil
+}
+
+// bracketStrategyTypeID is Tradovate's order strategy type for brackets.
+const bracketStrategyTypeID = 2
+
+// PlaceBracketOrder starts a bracket order strategy: the entry order is
+// placed now, and once it fills Tradovate attaches a take-profit and a
+// stop-loss at the given offsets, cancelling one when the other fills.
+func (c *TradovateClient) PlaceBracketOrder(ctx context.Context, bracket models.BracketOrder) (*models.OrderStrategy, error) {
+	if bracket.TargetOffset <= 0 || bracket.StopOffset <= 0 {
+		return nil, fmt.Errorf("bracket offsets must be positive")
+	}
+
+	// Strategies are started by symbol rather than contract ID.
+	contract, err := c.GetContract(ctx, bracket.ContractID)
+	if err != nil {
+		return nil, fmt.Errorf("error looking up contract %d: %w", bracket.ContractID, err)
+	}
+	if err := c.alignToContract(ctx, contract, &bracket.Price, &bracket.TargetOffset, &bracket.StopOffset); err != nil {
+		return nil, err
+	}
+
+	// A long entry takes profit above and stops out below; a short the reverse.
+	target, stop := bracket.TargetOffset, -bracket.StopOffset
+	if bracket.Side == "Sell" {
+		target, stop = -target, -stop
+	}
+
+	entry := map[string]interface{}{
+		"orderQty":    bracket.Quantity,
+		"orderType":   bracket.OrderType,
+		"timeInForce": bracket.TimeInForce,
+	}
+	if bracket.Price != 0 {
+		entry["price"] = bracket.Price
+	}
+	params, err := json.Marshal(map[string]interface{}{
+		"entryVersion": entry,
+		"brackets": []map[string]interface{}{{
+			"qty":          bracket.Quantity,
+			"profitTarget": target,
+			"stopLoss":     stop,
+			"trailingStop": false,
+		}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling bracket parameters: %w", err)
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/orderStrategy/startOrderStrategy", map[string]interface{}{
+		"accountId":           bracket.AccountID,
+		"symbol":              contract.Name,
+		"orderStrategyTypeId": bracketStrategyTypeID,
+		"action":              bracket.Side,
+		"params":              string(params),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OrderStrategy *models.OrderStrategy `json:"orderStrategy"`
+		ErrorText     string                `json:"errorText"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding order strategy response: %w", err)
+	}
+	if result.ErrorText != "" {
+		return nil, fmt.Errorf("bracket order rejected: %s", result.ErrorText)
+	}
+	if result.OrderStrategy == nil {
+		return nil, fmt.Errorf("bracket order rejected: no order strategy returned")
+	}
+	return result.OrderStrategy, nil
+}
+
+// ModifyOrder changes a working order in place, keeping its queue position
+// where the exchange allows it, rather than cancelling and replacing it.
+// Only the fields set in changes are modified. New prices are checked
+// against the order's tick size as PlaceOrder does.
+func (c *TradovateClient) ModifyOrder(ctx context.Context, orderID int, changes models.OrderChanges) error {
+	if err := c.alignChanges(ctx, orderID, &changes); err != nil {
+		return err
+	}
+
+	body := struct {
+		OrderID int `json:"orderId"`
+		models.OrderChanges
+	}{OrderID: orderID, OrderChanges: changes}
+
+	resp, err := c.doRequest(ctx, "POST", "/order/modifyOrder", body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to modify order: status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// LiquidatePosition closes the account's whole position in contractID with a
+// market order and cancels its working orders in that contract. It returns
+// the ID of the closing order.
+func (c *TradovateClient) LiquidatePosition(ctx context.Context, accountID, contractID int) (int, error) {
+	resp, err := c.doRequest(ctx, "POST", "/order/liquidatePosition", map[string]interface{}{
+		"accountId":  accountID,
+		"contractId": contractID,
+		"admin":      false,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("failed to liquidate position: status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		OrderID       int    `json:"orderId"`
+		FailureReason string `json:"failureReason"`
+		FailureText   string `json:"failureText"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("error decoding liquidation response: %w", err)
+	}
+	if result.FailureReason != "" && result.FailureReason != "Success" {
+		return 0, fmt.Errorf("liquidation rejected: %s: %s", result.FailureReason, result.FailureText)
+	}
+
+	return result.OrderID, nil
+}
+
 // CancelOrder cancels an existing order by its ID.
 // Returns an error if the order cannot be cancelled or doesn't exist.
-func (c *TradovateClient) CancelOrder(orderID int) error {
-	resp, err := c.doRequest("DELETE", fmt.Sprintf("/order/cancel/%d", orderID), nil)
+func (c *TradovateClient) CancelOrder(ctx context.Context, orderID int) error {
+	resp, err := c.doRequest(ctx, "DELETE", fmt.Sprintf("/order/cancel/%d", orderID), nil)
 	if err != nil {
 		return err
 	}
@@ -231,11 +1039,150 @@ func (c *TradovateClient) CancelOrder(orderID int) error {
 	return nil
 }
 
+// GetOrders retrieves the orders placed today, both working and finished.
+// Parameters:
+// - accountID: Only return orders of this account; 0 for every account
+// - status: Only return orders with this status, e.g. "Working"; empty for all
+func (c *TradovateClient) GetOrders(ctx context.Context, accountID int, status string) ([]models.Order, error) {
+	endpoint := "/order/list"
+	if accountID != 0 {
+		endpoint = fmt.Sprintf("/order/deps?masterid=%d", accountID)
+	}
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var orders []models.Order
+	if err := json.NewDecoder(resp.Body).Decode(&orders); err != nil {
+		return nil, fmt.Errorf("error decoding orders: %w", err)
+	}
+
+	if status == "" {
+		return orders, nil
+	}
+	matching := make([]models.Order, 0, len(orders))
+	for _, order := range orders {
+		if strings.EqualFold(order.Status, status) {
+			matching = append(matching, order)
+		}
+	}
+	return matching, nil
+}
+
+// GetOrder retrieves a single order by its ID. The filled quantity and
+// average price are computed from the order's fills, so they are current
+// even while the order is partially filled.
+func (c *TradovateClient) GetOrder(ctx context.Context, orderID int) (*models.Order, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/order/item?id=%d", orderID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var order models.Order
+	if err := json.NewDecoder(resp.Body).Decode(&order); err != nil {
+		return nil, fmt.Errorf("error decoding order: %w", err)
+	}
+	if order.ID == 0 {
+		return nil, fmt.Errorf("order %d not found", orderID)
+	}
+
+	fills, err := c.GetFills(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting fills for order %d: %w", orderID, err)
+	}
+	if len(fills) > 0 {
+		var quantity int
+		var notional float64
+		for _, fill := range fills {
+			quantity += fill.Quantity
+			notional += fill.Price * float64(fill.Quantity)
+		}
+		order.FilledQty = quantity
+		if quantity > 0 {
+			order.AveragePrice = notional / float64(quantity)
+		}
+	}
+
+	return &order, nil
+}
+
+// GetExecutionReports retrieves the execution reports the exchange sent
+// for an order's acknowledgement, fills, cancellation or rejection.
+// Parameters:
+// - orderID: Only return reports for this order; 0 to use accountID instead
+// - accountID: Only return reports for this account's orders; 0 for every account
+func (c *TradovateClient) GetExecutionReports(ctx context.Context, orderID, accountID int) ([]models.ExecutionReport, error) {
+	endpoint := "/executionReport/list"
+	if orderID != 0 {
+		endpoint = fmt.Sprintf("/executionReport/deps?masterid=%d", orderID)
+	}
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var reports []models.ExecutionReport
+	if err := json.NewDecoder(resp.Body).Decode(&reports); err != nil {
+		return nil, fmt.Errorf("error decoding execution reports: %w", err)
+	}
+
+	if orderID != 0 || accountID == 0 {
+		return reports, nil
+	}
+	matching := make([]models.ExecutionReport, 0, len(reports))
+	for _, report := range reports {
+		if report.AccountID == accountID {
+			matching = append(matching, report)
+		}
+	}
+	return matching, nil
+}
+
+// GetCommandReports retrieves the reports of every command sent on an order,
+// oldest first. A rejected order's reason is only available here.
+// Parameters:
+// - orderID: The unique identifier of the order
+func (c *TradovateClient) GetCommandReports(ctx context.Context, orderID int) ([]models.CommandReport, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/command/deps?masterid=%d", orderID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var commands []struct {
+		ID int `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&commands); err != nil {
+		return nil, fmt.Errorf("error decoding commands: %w", err)
+	}
+
+	reports := []models.CommandReport{}
+	for _, command := range commands {
+		resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/commandReport/deps?masterid=%d", command.ID), nil)
+		if err != nil {
+			return nil, err
+		}
+		var commandReports []models.CommandReport
+		err = json.NewDecoder(resp.Body).Decode(&commandReports)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding command reports: %w", err)
+		}
+		reports = append(reports, commandReports...)
+	}
+
+	return reports, nil
+}
+
 // GetFills retrieves all fills for a specific order.
 // Parameters:
 // - orderID: The unique identifier of the order
-func (c *TradovateClient) GetFills(orderID int) ([]models.Fill, error) {
-	resp, err := c.doRequest("GET", fmt.Sprintf("/fill/list/%d", orderID), nil)
+func (c *TradovateClient) GetFills(ctx context.Context, orderID int) ([]models.Fill, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/fill/list/%d", orderID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -251,8 +1198,26 @@ func (c *TradovateClient) GetFills(orderID int) ([]models.Fill, error) {
 
 // GetPositions retrieves all current positions for the authenticated user.
 // Returns a slice of Position objects containing position details and P&L information.
-func (c *TradovateClient) GetPositions() ([]models.Position, error) {
-	resp, err := c.doRequest("GET", "/position/list", nil)
+func (c *TradovateClient) GetPositions(ctx context.Context) ([]models.Position, error) {
+	resp, err := c.doRequest(ctx, "GET", "/position/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var positions []models.Position
+	if err := json.NewDecoder(resp.Body).Decode(&positions); err != nil {
+		return nil, fmt.Errorf("error decoding positions: %w", err)
+	}
+
+	return positions, nil
+}
+
+// GetPositionsByAccount retrieves the current positions held in one account.
+// Parameters:
+// - accountID: The unique identifier of the account
+func (c *TradovateClient) GetPositionsByAccount(ctx context.Context, accountID int) ([]models.Position, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/position/deps?masterid=%d", accountID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -268,8 +1233,8 @@ func (c *TradovateClient) GetPositions() ([]models.Position, error) {
 
 // GetContracts retrieves all available trading contracts.
 // Returns a slice of Contract objects containing contract specifications.
-func (c *TradovateClient) GetContracts() ([]models.Contract, error) {
-	resp, err := c.doRequest("GET", "/contract/list", nil)
+func (c *TradovateClient) GetContracts(ctx context.Context) ([]models.Contract, error) {
+	resp, err := c.doRequest(ctx, "GET", "/contract/list", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -283,11 +1248,187 @@ func (c *TradovateClient) GetContracts() ([]models.Contract, error) {
 	return contracts, nil
 }
 
+// GetContract retrieves a single contract by its ID.
+// Parameters:
+// - contractID: The unique identifier of the contract
+func (c *TradovateClient) GetContract(ctx context.Context, contractID int) (*models.Contract, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/contract/item?id=%d", contractID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var contract models.Contract
+	if err := json.NewDecoder(resp.Body).Decode(&contract); err != nil {
+		return nil, fmt.Errorf("error decoding contract: %w", err)
+	}
+	c.withSpecs(ctx, &contract)
+
+	return &contract, nil
+}
+
+// FindContract retrieves a contract by its name.
+// Parameters:
+// - name: The contract name, e.g. "ESZ4"
+func (c *TradovateClient) FindContract(ctx context.Context, name string) (*models.Contract, error) {
+	resp, err := c.doRequest(ctx, "GET", "/contract/find?name="+url.QueryEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var contract models.Contract
+	if err := json.NewDecoder(resp.Body).Decode(&contract); err != nil {
+		return nil, fmt.Errorf("error decoding contract: %w", err)
+	}
+	if contract.ID == 0 {
+		return nil, fmt.Errorf("unknown contract %q", name)
+	}
+
+	return &contract, nil
+}
+
+// GetContractMaturity retrieves the expiration details of a contract maturity.
+// Parameters:
+// - maturityID: The unique identifier of the contract maturity
+func (c *TradovateClient) GetContractMaturity(ctx context.Context, maturityID int) (*models.ContractMaturity, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/contractMaturity/item?id=%d", maturityID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var maturity models.ContractMaturity
+	if err := json.NewDecoder(resp.Body).Decode(&maturity); err != nil {
+		return nil, fmt.Errorf("error decoding contract maturity: %w", err)
+	}
+
+	return &maturity, nil
+}
+
+// GetProducts retrieves all futures products.
+// Returns a slice of Product objects containing tick sizes and point values.
+func (c *TradovateClient) GetProducts(ctx context.Context) ([]models.Product, error) {
+	resp, err := c.doRequest(ctx, "GET", "/product/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var products []models.Product
+	if err := json.NewDecoder(resp.Body).Decode(&products); err != nil {
+		return nil, fmt.Errorf("error decoding products: %w", err)
+	}
+
+	return products, nil
+}
+
+// GetProductSessions retrieves the daily trading session of every product.
+func (c *TradovateClient) GetProductSessions(ctx context.Context) ([]models.ProductSession, error) {
+	resp, err := c.doRequest(ctx, "GET", "/productSession/list", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var sessions []models.ProductSession
+	if err := json.NewDecoder(resp.Body).Decode(&sessions); err != nil {
+		return nil, fmt.Errorf("error decoding product sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// GetProductFees retrieves the exchange, clearing and brokerage fees and
+// the commission charged per contract for each product, along with the
+// round-trip cost of opening and closing one contract.
+// Parameters:
+// - productIDs: The products to get fees for
+func (c *TradovateClient) GetProductFees(ctx context.Context, productIDs []int) ([]models.ProductFees, error) {
+	if len(productIDs) == 0 {
+		return nil, fmt.Errorf("at least one product ID is required")
+	}
+	resp, err := c.doRequest(ctx, "POST", "/product/getProductFeeParams", map[string][]int{"productIds": productIDs})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Params []models.ProductFees `json:"params"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding product fees: %w", err)
+	}
+	for i := range result.Params {
+		result.Params[i].RoundTripCost = 2 * result.Params[i].PerSide()
+	}
+
+	return result.Params, nil
+}
+
+// GetContractMargin retrieves the initial and maintenance margin required
+// to hold one contract. Tradovate sets margins by product, so the
+// contract's product is looked up through its maturity.
+// Parameters:
+// - contractID: The unique identifier of the contract
+func (c *TradovateClient) GetContractMargin(ctx context.Context, contractID int) (*models.ProductMargin, error) {
+	contract, err := c.GetContract(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+	if contract.ContractMaturityID == 0 {
+		return nil, fmt.Errorf("contract %d has no maturity to look up its product by", contractID)
+	}
+	maturity, err := c.GetContractMaturity(ctx, contract.ContractMaturityID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/productMargin/item?id=%d", maturity.ProductID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var margin models.ProductMargin
+	if err := json.NewDecoder(resp.Body).Decode(&margin); err != nil {
+		return nil, fmt.Errorf("error decoding product margin: %w", err)
+	}
+
+	return &margin, nil
+}
+
+// GetContractMaturities retrieves listed contract maturities and their
+// expiration schedule.
+// Parameters:
+// - productID: Only return maturities of this product; 0 for every product
+func (c *TradovateClient) GetContractMaturities(ctx context.Context, productID int) ([]models.ContractMaturity, error) {
+	endpoint := "/contractMaturity/list"
+	if productID != 0 {
+		endpoint = fmt.Sprintf("/contractMaturity/deps?masterid=%d", productID)
+	}
+	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var maturities []models.ContractMaturity
+	if err := json.NewDecoder(resp.Body).Decode(&maturities); err != nil {
+		return nil, fmt.Errorf("error decoding contract maturities: %w", err)
+	}
+
+	return maturities, nil
+}
+
 // GetMarketData retrieves current market data for a specific contract.
 // Parameters:
 // - contractID: The unique identifier of the contract
-func (c *TradovateClient) GetMarketData(contractID int) (*models.MarketData, error) {
-	resp, err := c.doRequest("GET", fmt.Sprintf("/md/getQuote/%d", contractID), nil)
+func (c *TradovateClient) GetMarketData(ctx context.Context, contractID int) (*models.MarketData, error) {
+	if err := c.checkEntitlement(ctx, contractID, FeedTopOfBook); err != nil {
+		return nil, err
+	}
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/md/getQuote/%d", contractID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -301,75 +1442,194 @@ func (c *TradovateClient) GetMarketData(contractID int) (*models.MarketData, err
 	return &marketData, nil
 }
 
+// maxBatchContracts bounds how many contracts GetMarketDataBatch accepts,
+// and batchConcurrency how many of their quotes are requested at once.
+const (
+	maxBatchContracts = 100
+	batchConcurrency  = 8
+)
+
+// GetMarketDataBatch retrieves current market data for several contracts,
+// requesting them in parallel. Results are in the order of contractIDs; a
+// contract whose quote could not be fetched has its error set instead of
+// failing the whole batch.
+func (c *TradovateClient) GetMarketDataBatch(ctx context.Context, contractIDs []int) ([]models.MarketDataResult, error) {
+	if len(contractIDs) == 0 {
+		return nil, fmt.Errorf("no contracts requested")
+	}
+	if len(contractIDs) > maxBatchContracts {
+		return nil, fmt.Errorf("too many contracts: %d requested, at most %d allowed", len(contractIDs), maxBatchContracts)
+	}
+
+	results := make([]models.MarketDataResult, len(contractIDs))
+	sem := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+	for i, contractID := range contractIDs {
+		i, contractID := i, contractID
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i].ContractID = contractID
+			data, err := c.GetMarketData(ctx, contractID)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			results[i].Data = data
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 // GetHistoricalData retrieves historical market data for a specific contract.
 // Parameters:
 // - contractID: The unique identifier of the contract
 // - startTime: The start time for historical data
 // - endTime: The end time for historical data
-// - interval: The time interval for data points (e.g., "1m", "5m", "1h")
-func (c *TradovateClient) GetHistoricalData(contractID int, startTime, endTime time.Time, interval string) ([]models.HistoricalData, error) {
-	params := map[string]interface{}{
-		"contractId": contractID,
-		"startTime":  startTime.Unix(),
-		"endTime":    endTime.Unix(),
-		"interval":   interval,
+// - interval: The time interval for data points (e.g., "1s", "15s", "1m", "1h"), or IntervalTick
+// Long ranges at fine resolutions are fetched in several requests.
+func (c *TradovateClient) GetHistoricalData(ctx context.Context, contractID int, startTime, endTime time.Time, interval string) ([]models.HistoricalData, error) {
+	if err := c.checkEntitlement(ctx, contractID, FeedHistorical); err != nil {
+		return nil, err
 	}
-
-	resp, err := c.doRequest("GET", "/md/historical", params)
+	windows, err := historicalWindows(startTime, endTime, interval)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	var data []models.HistoricalData
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("error decoding historical data: %w", err)
+	if len(windows) == 1 {
+		return c.getHistoricalWindow(ctx, contractID, windows[0], interval)
 	}
 
+	data := make([]models.HistoricalData, 0)
+	for _, window := range windows {
+		chunk, err := c.getHistoricalWindow(ctx, contractID, window, interval)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching %s data from %s: %w", interval, window.start.Format(time.RFC3339), err)
+		}
+		data = append(data, chunk...)
+	}
 	return data, nil
 }
 
+// setRequestID forwards the MCP request ID carried by ctx, if any, to
+// Tradovate in the X-Request-ID header.
+func setRequestID(ctx context.Context, req *http.Request) {
+	if requestID := logging.RequestID(ctx); requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+}
+
 // doRequest performs an HTTP request to the Tradovate API.
-// It handles request creation, authentication, and error responses.
+// It handles request creation, authentication, and error responses. The
+// access token is renewed shortly before it expires, and a request rejected
+// with 401 is retried once after re-authenticating. Transient failures are
+// retried according to the client's RetryPolicy.
+// The request is bound to ctx, and the MCP request ID it carries is logged
+// and forwarded to Tradovate.
 // Parameters:
 // - method: HTTP method (GET, POST, etc.)
 // - endpoint: API endpoint path
 // - body: Optional request body for POST/PUT requests
-func (c *TradovateClient) doRequest(method, endpoint string, body interface{}) (*http.Response, error) {
-	var bodyReader io.Reader
+func (c *TradovateClient) doRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
+	var data []byte
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("error marshaling request body: %w", err)
 		}
-		bodyReader = bytes.NewBuffer(jsonData)
+		data = jsonData
+	}
+
+	if err := c.ensureFreshToken(ctx); err != nil {
+		return nil, err
+	}
+
+	token := c.GetAccessToken()
+	resp, err := c.sendPenalized(ctx, method, endpoint, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// A token revoked or expired server-side is replaced and the request
+	// retried once.
+	if resp.StatusCode == http.StatusUnauthorized && token != "" {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		c.clearTokenCache()
+		if sessionEnded(body) {
+			slog.WarnContext(ctx, "tradovate session was ended by another login; logging in again", "method", method, "endpoint", endpoint)
+		} else {
+			slog.WarnContext(ctx, "tradovate rejected access token; re-authenticating", "method", method, "endpoint", endpoint)
+		}
+		if err := c.reauthenticate(ctx, token); err != nil {
+			return nil, fmt.Errorf("error re-authenticating: %w", err)
+		}
+		resp, err = c.sendPenalized(ctx, method, endpoint, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode >= 400 {
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, newAPIError(method, endpoint, resp.StatusCode, data)
+	}
+
+	return resp, nil
+}
+
+// sendRequest sends a single request carrying the current access token.
+func (c *TradovateClient) sendRequest(ctx context.Context, method, endpoint string, data []byte) (*http.Response, error) {
+	if err := c.throttle.wait(ctx); err != nil {
+		return nil, err
+	}
+	c.requests.record(endpoint, time.Now())
+	c.mu.RLock()
+	replay, baseURL, token := c.env.Replay, c.baseURL, c.accessToken
+	c.mu.RUnlock()
+	if replay && !strings.HasPrefix(endpoint, "/auth/") {
+		return c.sendReplayRequest(ctx, endpoint, data)
 	}
 
-	req, err := http.NewRequest(method, c.baseURL+endpoint, bodyReader)
+	var bodyReader io.Reader
+	if data != nil {
+		bodyReader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+endpoint, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	if c.accessToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
+	setRequestID(ctx, req)
+	c.acceptGzip(req)
 
-	resp, err := c.httpClient.Do(req)
+	done := c.observe(ctx, req, endpoint, data)
+	start := time.Now()
+	resp, err := c.httpClientFor(endpoint).Do(req)
+	done(resp, err)
 	if err != nil {
+		slog.WarnContext(ctx, "tradovate request failed", "method", method, "endpoint", endpoint, "error", err)
 		return nil, fmt.Errorf("error sending request: %w", err)
 	}
-
-	if resp.StatusCode >= 400 {
-		var errResp struct {
-			ErrorText string `json:"errorText"`
-		}
-		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
-			return nil, fmt.Errorf("status %d", resp.StatusCode)
-		}
-		resp.Body.Close()
-		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, errResp.ErrorText)
+	c.observeServerDate(ctx, resp, start)
+	if err := decompressResponse(resp); err != nil {
+		return nil, err
 	}
+	slog.DebugContext(ctx, "tradovate request", "method", method, "endpoint", endpoint, "status", resp.StatusCode, "duration", time.Since(start))
 
 	return resp, nil
 }
@@ -1,15 +1,21 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/0xjmp/mcp-tradovate/internal/logging"
 	"github.com/0xjmp/mcp-tradovate/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewTradovateClient(t *testing.T) {
@@ -66,13 +72,64 @@ func TestAuthenticate(t *testing.T) {
 	os.Setenv("TRADOVATE_SEC", "testsec")
 
 	// Test authentication
-	authResp, err := client.Authenticate()
+	authResp, err := client.Authenticate(context.Background())
 	assert.NoError(t, err)
 	assert.Equal(t, "test-token", authResp.AccessToken)
 	assert.Equal(t, 12345, authResp.UserID)
 	assert.Equal(t, "test-token", client.GetAccessToken())
 }
 
+func TestAuthenticateWithCredentials(t *testing.T) {
+	var names []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var authReq AuthRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&authReq))
+		names = append(names, authReq.Name)
+		assert.Equal(t, "vault-pass", authReq.Password)
+		assert.Equal(t, "vault-cid", authReq.ClientID)
+		json.NewEncoder(w).Encode(AuthResponse{AccessToken: "test-token"})
+	}))
+	defer server.Close()
+
+	t.Setenv("TRADOVATE_USERNAME", "envuser")
+	t.Setenv("TRADOVATE_PASSWORD", "envpass")
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	_, err := client.AuthenticateWithCredentials(context.Background(), models.Credentials{})
+	assert.EqualError(t, err, "username and password are required")
+	assert.Empty(t, names)
+
+	creds := models.Credentials{Username: "vault-user", Password: "vault-pass", ClientID: "vault-cid"}
+	resp, err := client.AuthenticateWithCredentials(context.Background(), creds)
+	require.NoError(t, err)
+	assert.Equal(t, "test-token", resp.AccessToken)
+
+	// Re-authentication keeps using the explicit credentials.
+	_, err = client.Authenticate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vault-user", "vault-user"}, names)
+}
+
+func TestIsAuthenticated(t *testing.T) {
+	client := NewTradovateClient()
+	assert.False(t, client.IsAuthenticated())
+	assert.True(t, client.TokenExpiresAt().IsZero())
+
+	expiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	client.setTokens(&AuthResponse{AccessToken: "test-token", ExpirationTime: expiry.Format(time.RFC3339)})
+	assert.True(t, client.IsAuthenticated())
+	assert.Equal(t, expiry, client.TokenExpiresAt())
+
+	client.tokenExpiry = time.Now().Add(-time.Minute)
+	assert.False(t, client.IsAuthenticated(), "expired tokens are not authenticated")
+
+	// A token without a known expiry is assumed valid.
+	client.tokenExpiry = time.Time{}
+	assert.True(t, client.IsAuthenticated())
+}
+
 func TestAuthenticateError(t *testing.T) {
 	// Setup test server that returns an error
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -86,7 +143,7 @@ func TestAuthenticateError(t *testing.T) {
 	client := NewTradovateClient()
 	client.SetBaseURL(server.URL)
 
-	_, err := client.Authenticate()
+	_, err := client.Authenticate(context.Background())
 	assert.Error(t, err)
 	assert.Equal(t, "authentication failed: Invalid credentials", err.Error())
 }
@@ -95,7 +152,7 @@ func TestAuthenticateNetworkError(t *testing.T) {
 	client := NewTradovateClient()
 	client.SetBaseURL("http://invalid-url")
 
-	_, err := client.Authenticate()
+	_, err := client.Authenticate(context.Background())
 	assert.Error(t, err)
 }
 
@@ -124,7 +181,7 @@ func TestGetAccounts(t *testing.T) {
 	client.SetBaseURL(server.URL)
 	client.accessToken = "test-token"
 
-	accounts, err := client.GetAccounts()
+	accounts, err := client.GetAccounts(context.Background())
 	assert.NoError(t, err)
 	assert.Len(t, accounts, 1)
 	assert.Equal(t, "Test Account", accounts[0].Name)
@@ -157,7 +214,7 @@ func TestSetRiskLimits(t *testing.T) {
 		TrailingStop:   50.0,
 	}
 
-	err := client.SetRiskLimits(limits)
+	err := client.SetRiskLimits(context.Background(), limits)
 	assert.NoError(t, err)
 }
 
@@ -182,14 +239,57 @@ func TestGetRiskLimits(t *testing.T) {
 	client.SetBaseURL(server.URL)
 	client.accessToken = "test-token"
 
-	limits, err := client.GetRiskLimits(12345)
+	limits, err := client.GetRiskLimits(context.Background(), 12345)
 	assert.NoError(t, err)
 	assert.Equal(t, 12345, limits.AccountID)
 	assert.Equal(t, 1000.0, limits.DayMaxLoss)
 }
 
+func TestAutoLiquidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/userAccountAutoLiq/deps":
+			assert.Equal(t, "GET", r.Method)
+			assert.Equal(t, "12345", r.URL.Query().Get("masterid"))
+			w.Write([]byte(`[{"id": 12345, "dailyLossAutoLiq": 1000, "marginPercentageAlert": 80}]`))
+		case "/userAccountAutoLiq/update":
+			assert.Equal(t, "POST", r.Method)
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"id": 12345, "dailyLossAutoLiq": 1000, "weeklyLossAutoLiq": 2500, "marginPercentageAlert": 80}`, string(body))
+			w.Write(body)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	settings, err := client.GetAutoLiquidation(context.Background(), 12345)
+	require.NoError(t, err)
+	assert.Equal(t, 12345, settings.AccountID)
+	assert.Equal(t, 1000.0, *settings.DailyLossAutoLiq)
+	assert.Nil(t, settings.WeeklyLossAutoLiq)
+
+	weeklyLoss := 2500.0
+	settings.WeeklyLossAutoLiq = &weeklyLoss
+	updated, err := client.SetAutoLiquidation(context.Background(), *settings)
+	require.NoError(t, err)
+	assert.Equal(t, 2500.0, *updated.WeeklyLossAutoLiq)
+
+	_, err = client.SetAutoLiquidation(context.Background(), models.AutoLiquidation{})
+	assert.EqualError(t, err, "account ID is required")
+}
+
 func TestPlaceOrder(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The limit price is checked against the contract's tick size first.
+		if r.URL.Path == "/contract/item" {
+			json.NewEncoder(w).Encode(models.Contract{ID: 54321, Name: "ESZ4"})
+			return
+		}
 		assert.Equal(t, "POST", r.Method)
 		assert.Equal(t, "/order/placeOrder", r.URL.Path)
 		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
@@ -217,12 +317,44 @@ func TestPlaceOrder(t *testing.T) {
 		TimeInForce: "Day",
 	}
 
-	placedOrder, err := client.PlaceOrder(order)
+	placedOrder, err := client.PlaceOrder(context.Background(), order)
 	assert.NoError(t, err)
 	assert.Equal(t, 67890, placedOrder.ID)
 	assert.Equal(t, order.AccountID, placedOrder.AccountID)
 }
 
+func TestPlaceOrderActivationTime(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var order models.Order
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&order))
+		sent = append(sent, order.ActivationTime)
+		json.NewEncoder(w).Encode(order)
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	order := models.Order{AccountID: 1, ContractID: 1234, OrderType: "Market", Quantity: 1, TimeInForce: "Day"}
+	open := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	order.ActivationTime = open.In(time.FixedZone("EDT", -4*3600)).Format(time.RFC3339)
+	_, err := client.PlaceOrder(context.Background(), order)
+	require.NoError(t, err)
+	assert.Equal(t, []string{open.UTC().Format(time.RFC3339)}, sent, "sent in UTC")
+
+	order.ActivationTime = "2024-06-03T09:30:00-04:00"
+	_, err = client.PlaceOrder(context.Background(), order)
+	assert.EqualError(t, err, "activationTime 2024-06-03T09:30:00-04:00 is not in the future")
+
+	order.ActivationTime = "09:30"
+	_, err = client.PlaceOrder(context.Background(), order)
+	assert.EqualError(t, err, `invalid activationTime "09:30": must be an RFC 3339 time such as 2024-06-03T09:30:00-04:00`)
+	assert.Len(t, sent, 1)
+}
+
 func TestCancelOrder(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "DELETE", r.Method)
@@ -236,10 +368,451 @@ func TestCancelOrder(t *testing.T) {
 	client.SetBaseURL(server.URL)
 	client.accessToken = "test-token"
 
-	err := client.CancelOrder(67890)
+	err := client.CancelOrder(context.Background(), 67890)
 	assert.NoError(t, err)
 }
 
+func TestGetMarginSnapshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/marginSnapshot/deps":
+			assert.Equal(t, "12345", r.URL.Query().Get("masterid"))
+			w.Write([]byte(`[{"id": 12345, "timestamp": "2024-03-15T13:30:00Z", "initialMargin": 13200, "maintenanceMargin": 12000, "totalUsedMargin": 14500, "autoLiqLevel": 1000}]`))
+		case "/cashBalance/getCashBalanceSnapshot":
+			assert.Equal(t, "POST", r.Method)
+			body, _ := io.ReadAll(r.Body)
+			assert.JSONEq(t, `{"accountId": 12345}`, string(body))
+			w.Write([]byte(`{"totalCashValue": 49000, "netLiq": 50250.5}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	snapshot, err := client.GetMarginSnapshot(context.Background(), 12345)
+	require.NoError(t, err)
+	assert.Equal(t, &models.MarginSnapshot{
+		AccountID:         12345,
+		Timestamp:         "2024-03-15T13:30:00Z",
+		NetLiq:            50250.5,
+		InitialMargin:     13200,
+		MaintenanceMargin: 12000,
+		TotalUsedMargin:   14500,
+		AutoLiqLevel:      1000,
+		AvailableMargin:   35750.5,
+	}, snapshot)
+}
+
+func TestGetMarginSnapshotMissing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	_, err := client.GetMarginSnapshot(context.Background(), 12345)
+	assert.EqualError(t, err, "no margin snapshot for account 12345")
+}
+
+func TestGetAccountSummary(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/account/list":
+			w.Write([]byte(`[{"id": 11111, "name": "Other"}, {"id": 12345, "name": "Demo"}]`))
+		case "/cashBalance/getCashBalanceSnapshot":
+			w.Write([]byte(`{"totalCashValue": 50000, "netLiq": 50250.5, "openPnL": 250.5, "realizedPnL": -120}`))
+		case "/marginSnapshot/deps":
+			w.Write([]byte(`[{"id": 12345, "totalUsedMargin": 14500}]`))
+		case "/position/deps":
+			w.Write([]byte(`[{"id": 1, "accountId": 12345, "contractId": 1234, "netPos": -2, "avgPrice": 5100}, {"id": 2, "accountId": 12345, "contractId": 4321, "netPos": 0}]`))
+		case "/order/deps":
+			w.Write([]byte(`[{"id": 67890, "accountId": 12345, "status": "Working"}, {"id": 67891, "accountId": 12345, "status": "Filled"}]`))
+		case "/md/getQuote/1234":
+			w.Write([]byte(`{"contractId": 1234, "last": 5097.5}`))
+		case "/contract/item":
+			w.Write([]byte(`{"id": 1234, "name": "ESZ4", "contractMaturityId": 77}`))
+		case "/contractMaturity/item":
+			w.Write([]byte(`{"id": 77, "productId": 9}`))
+		case "/product/item":
+			w.Write([]byte(`{"id": 9, "name": "ES", "tickSize": 0.25, "valuePerPoint": 50}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	summary, err := client.GetAccountSummary(context.Background(), 12345)
+	require.NoError(t, err)
+	assert.Equal(t, "Demo", summary.Account.Name)
+	assert.Equal(t, 250.5, summary.CashBalance.OpenPnL)
+	assert.Equal(t, -120.0, summary.CashBalance.RealizedPnL)
+	assert.Equal(t, 35750.5, summary.Margin.AvailableMargin)
+	require.Len(t, summary.Positions, 2)
+	assert.Equal(t, 5097.5, summary.Positions[0].MarkPrice)
+	assert.Equal(t, 250.0, summary.Positions[0].UnrealizedPL, "short 2 ES from 5100, now 5097.50")
+	assert.Zero(t, summary.Positions[1].MarkPrice, "flat positions are not marked")
+	require.Len(t, summary.WorkingOrders, 1)
+	assert.Equal(t, 67890, summary.WorkingOrders[0].ID)
+
+	_, err = client.GetAccountSummary(context.Background(), 99999)
+	assert.Error(t, err)
+}
+
+func TestGetAccountPermissions(t *testing.T) {
+	riskStatus := `{"id": 12345}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		switch r.URL.Path {
+		case "/tradingPermission/list":
+			w.Write([]byte(`[
+				{"id": 1, "userId": 7, "accountId": 12345, "status": "Requested"},
+				{"id": 2, "userId": 7, "accountId": 54321, "status": "Accepted"}
+			]`))
+		case "/accountRiskStatus/item":
+			w.Write([]byte(riskStatus))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	permissions, err := client.GetAccountPermissions(context.Background(), 12345)
+	require.NoError(t, err)
+	assert.False(t, permissions.CanTrade)
+	assert.Equal(t, []string{"view-only: trading permission is Requested"}, permissions.Restrictions)
+
+	permissions, err = client.GetAccountPermissions(context.Background(), 54321)
+	require.NoError(t, err)
+	assert.True(t, permissions.CanTrade)
+	assert.Empty(t, permissions.Restrictions)
+
+	riskStatus = `{"id": 99, "liquidateOnly": "2024-03-15T13:30:00Z", "adminAction": "LiquidateOnlyModeByRisk"}`
+	permissions, err = client.GetAccountPermissions(context.Background(), 99)
+	require.NoError(t, err)
+	assert.True(t, permissions.CanTrade)
+	assert.True(t, permissions.LiquidationOnly)
+	assert.Equal(t, []string{"liquidation-only: orders may only reduce open positions (LiquidateOnlyModeByRisk)"}, permissions.Restrictions)
+}
+
+func TestPlaceOCO(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/contract/item" {
+			json.NewEncoder(w).Encode(models.Contract{ID: 1234, Name: "ESM4"})
+			return
+		}
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/order/placeOCO", r.URL.Path)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "Limit", body["orderType"])
+		assert.Equal(t, 5120.0, body["price"])
+		assert.Equal(t, map[string]interface{}{"side": "Sell", "orderType": "Stop", "stopPrice": 5080.0}, body["other"])
+		json.NewEncoder(w).Encode(map[string]int{"orderId": 101, "ocoId": 102})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	leg := models.Order{AccountID: 1, ContractID: 1234, Side: "Sell", Quantity: 2, TimeInForce: "GTC"}
+	target, stop := leg, leg
+	target.OrderType, target.Price = "Limit", 5120
+	stop.OrderType, stop.StopPrice = "Stop", 5080
+
+	oco, err := client.PlaceOCO(context.Background(), models.OCOOrder{First: target, Second: stop})
+	require.NoError(t, err)
+	assert.Equal(t, 101, oco.First.ID)
+	assert.Equal(t, 102, oco.Second.ID)
+	assert.Equal(t, 5080.0, oco.Second.StopPrice)
+
+	stop.Quantity = 3
+	_, err = client.PlaceOCO(context.Background(), models.OCOOrder{First: target, Second: stop})
+	assert.EqualError(t, err, "OCO legs must share account, contract and quantity")
+}
+
+func TestPlaceOCORejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"failureReason": "RiskCheck", "failureText": "Exceeds position limit"})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	_, err := client.PlaceOCO(context.Background(), models.OCOOrder{})
+	assert.EqualError(t, err, "OCO order rejected: RiskCheck: Exceeds position limit")
+}
+
+func TestPlaceBracketOrder(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contract/item":
+			json.NewEncoder(w).Encode(models.Contract{ID: 1234, Name: "ESM4"})
+		case "/orderStrategy/startOrderStrategy":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"orderStrategy": map[string]interface{}{"id": 55, "accountId": 1, "contractId": 1234, "status": "ExecutionStarted"},
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	strategy, err := client.PlaceBracketOrder(context.Background(), models.BracketOrder{
+		AccountID:    1,
+		ContractID:   1234,
+		Side:         "Sell",
+		Quantity:     2,
+		OrderType:    "Limit",
+		Price:        5100,
+		TimeInForce:  "Day",
+		TargetOffset: 10,
+		StopOffset:   5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &models.OrderStrategy{ID: 55, AccountID: 1, ContractID: 1234, Status: "ExecutionStarted"}, strategy)
+
+	assert.Equal(t, "ESM4", body["symbol"])
+	assert.Equal(t, "Sell", body["action"])
+	assert.Equal(t, 2.0, body["orderStrategyTypeId"])
+	assert.JSONEq(t, `{
+		"entryVersion": {"orderQty": 2, "orderType": "Limit", "price": 5100, "timeInForce": "Day"},
+		"brackets": [{"qty": 2, "profitTarget": -10, "stopLoss": 5, "trailingStop": false}]
+	}`, body["params"].(string))
+}
+
+func TestPlaceBracketOrderErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/contract/item" {
+			json.NewEncoder(w).Encode(models.Contract{ID: 1234, Name: "ESM4"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"errorText": "Insufficient margin"})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	bracket := models.BracketOrder{AccountID: 1, ContractID: 1234, Side: "Buy", Quantity: 1, OrderType: "Market", TargetOffset: 10}
+	_, err := client.PlaceBracketOrder(context.Background(), bracket)
+	assert.EqualError(t, err, "bracket offsets must be positive")
+
+	bracket.StopOffset = 5
+	_, err = client.PlaceBracketOrder(context.Background(), bracket)
+	assert.EqualError(t, err, "bracket order rejected: Insufficient margin")
+}
+
+func TestModifyOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/order/item":
+			json.NewEncoder(w).Encode(models.Order{ID: 67890, ContractID: 1234})
+			return
+		case "/contract/item":
+			json.NewEncoder(w).Encode(models.Contract{ID: 1234, Name: "ESM4"})
+			return
+		}
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/order/modifyOrder", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"orderId": 67890, "orderQty": 2, "price": 5100.25}`, string(body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	quantity, price := 2, 5100.25
+	err := client.ModifyOrder(context.Background(), 67890, models.OrderChanges{Quantity: &quantity, Price: &price})
+	assert.NoError(t, err)
+}
+
+func TestLiquidatePosition(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/order/liquidatePosition", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"accountId": 12345, "contractId": 1234, "admin": false}`, string(body))
+		json.NewEncoder(w).Encode(map[string]interface{}{"orderId": 777})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	orderID, err := client.LiquidatePosition(context.Background(), 12345, 1234)
+	assert.NoError(t, err)
+	assert.Equal(t, 777, orderID)
+}
+
+func TestLiquidatePositionRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"failureReason": "UnknownReason", "failureText": "No position to liquidate"})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	_, err := client.LiquidatePosition(context.Background(), 12345, 1234)
+	assert.EqualError(t, err, "liquidation rejected: UnknownReason: No position to liquidate")
+}
+
+func TestGetOrders(t *testing.T) {
+	orders := []models.Order{
+		{ID: 1, AccountID: 12345, ContractID: 1234, Status: "Working"},
+		{ID: 2, AccountID: 12345, ContractID: 1234, Status: "Filled"},
+		{ID: 3, AccountID: 12345, ContractID: 5678, Status: "Working"},
+	}
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		requested = append(requested, r.URL.RequestURI())
+		json.NewEncoder(w).Encode(orders)
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	all, err := client.GetOrders(context.Background(), 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, orders, all)
+
+	working, err := client.GetOrders(context.Background(), 12345, "working")
+	require.NoError(t, err)
+	assert.Equal(t, []models.Order{orders[0], orders[2]}, working)
+
+	assert.Equal(t, []string{"/order/list", "/order/deps?masterid=12345"}, requested)
+}
+
+func TestGetOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/order/item":
+			if r.URL.Query().Get("id") != "67890" {
+				w.Write([]byte("null"))
+				return
+			}
+			json.NewEncoder(w).Encode(models.Order{ID: 67890, AccountID: 12345, Quantity: 3, Status: "Working"})
+		case "/fill/list/67890":
+			json.NewEncoder(w).Encode([]models.Fill{
+				{ID: 1, OrderID: 67890, Price: 5100, Quantity: 1},
+				{ID: 2, OrderID: 67890, Price: 5100.75, Quantity: 1},
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	order, err := client.GetOrder(context.Background(), 67890)
+	require.NoError(t, err)
+	assert.Equal(t, "Working", order.Status)
+	assert.Equal(t, 2, order.FilledQty)
+	assert.Equal(t, 5100.375, order.AveragePrice)
+
+	_, err = client.GetOrder(context.Background(), 1)
+	assert.EqualError(t, err, "order 1 not found")
+}
+
+func TestGetExecutionReports(t *testing.T) {
+	reports := []models.ExecutionReport{
+		{ID: 1, OrderID: 67890, AccountID: 12345, ExecType: "New", OrdStatus: "Working"},
+		{ID: 2, OrderID: 67890, AccountID: 12345, ExecType: "Trade", OrdStatus: "Filled", CumQty: 1, AvgPx: 5100.25, LastQty: 1, LastPx: 5100.25},
+		{ID: 3, OrderID: 11111, AccountID: 54321, ExecType: "Rejected", OrdStatus: "Rejected", RejectReason: "RiskCheck", Text: "Insufficient margin"},
+	}
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		requested = append(requested, r.URL.RequestURI())
+		if r.URL.Path == "/executionReport/deps" {
+			json.NewEncoder(w).Encode(reports[:2])
+			return
+		}
+		json.NewEncoder(w).Encode(reports)
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	byOrder, err := client.GetExecutionReports(context.Background(), 67890, 0)
+	require.NoError(t, err)
+	assert.Equal(t, reports[:2], byOrder)
+
+	byAccount, err := client.GetExecutionReports(context.Background(), 0, 54321)
+	require.NoError(t, err)
+	assert.Equal(t, []models.ExecutionReport{reports[2]}, byAccount)
+
+	assert.Equal(t, []string{"/executionReport/deps?masterid=67890", "/executionReport/list"}, requested)
+}
+
+func TestGetCommandReports(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		switch r.URL.RequestURI() {
+		case "/command/deps?masterid=67890":
+			json.NewEncoder(w).Encode([]map[string]interface{}{{"id": 1, "orderId": 67890, "commandType": "New"}})
+		case "/commandReport/deps?masterid=1":
+			json.NewEncoder(w).Encode([]models.CommandReport{
+				{ID: 10, CommandID: 1, CommandStatus: "RiskRejected", RejectReason: "RiskCheck", Text: "Insufficient margin"},
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.RequestURI())
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	reports, err := client.GetCommandReports(context.Background(), 67890)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.True(t, reports[0].Rejected())
+	assert.Equal(t, "Insufficient margin", reports[0].Text)
+}
+
 func TestGetFills(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
@@ -263,7 +836,7 @@ func TestGetFills(t *testing.T) {
 	client.SetBaseURL(server.URL)
 	client.accessToken = "test-token"
 
-	fills, err := client.GetFills(67890)
+	fills, err := client.GetFills(context.Background(), 67890)
 	assert.NoError(t, err)
 	assert.Len(t, fills, 1)
 	assert.Equal(t, 67890, fills[0].OrderID)
@@ -294,12 +867,31 @@ func TestGetPositions(t *testing.T) {
 	client.SetBaseURL(server.URL)
 	client.accessToken = "test-token"
 
-	positions, err := client.GetPositions()
+	positions, err := client.GetPositions(context.Background())
 	assert.NoError(t, err)
 	assert.Len(t, positions, 1)
 	assert.Equal(t, 5, positions[0].NetPos)
 }
 
+func TestGetPositionsByAccount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/position/deps", r.URL.Path)
+		assert.Equal(t, "12345", r.URL.Query().Get("masterid"))
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		json.NewEncoder(w).Encode([]models.Position{{ID: 1, AccountID: 12345, ContractID: 54321, NetPos: -2}})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	positions, err := client.GetPositionsByAccount(context.Background(), 12345)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Position{{ID: 1, AccountID: 12345, ContractID: 54321, NetPos: -2}}, positions)
+}
+
 func TestGetContracts(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
@@ -323,12 +915,51 @@ func TestGetContracts(t *testing.T) {
 	client.SetBaseURL(server.URL)
 	client.accessToken = "test-token"
 
-	contracts, err := client.GetContracts()
+	contracts, err := client.GetContracts(context.Background())
 	assert.NoError(t, err)
 	assert.Len(t, contracts, 1)
 	assert.Equal(t, "ES Mar24", contracts[0].Name)
 }
 
+func TestGetProductSessions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/productSession/list", r.URL.Path)
+		w.Write([]byte(`[{"id": 10, "productId": 1, "openTime": "17:00", "startTime": "17:00", "stopTime": "16:00", "closeTime": "16:00", "sundayOpen": "17:00"}]`))
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	sessions, err := client.GetProductSessions(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []models.ProductSession{{ID: 10, ProductID: 1, OpenTime: "17:00", StartTime: "17:00", StopTime: "16:00", CloseTime: "16:00", SundayOpen: "17:00"}}, sessions)
+}
+
+func TestFindContract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/contract/find", r.URL.Path)
+		if r.URL.Query().Get("name") != "ESZ4" {
+			w.Write([]byte("null"))
+			return
+		}
+		json.NewEncoder(w).Encode(models.Contract{ID: 3570, Name: "ESZ4"})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	contract, err := client.FindContract(context.Background(), "ESZ4")
+	require.NoError(t, err)
+	assert.Equal(t, 3570, contract.ID)
+
+	_, err = client.FindContract(context.Background(), "XXZ4")
+	assert.EqualError(t, err, `unknown contract "XXZ4"`)
+}
+
 func TestGetMarketData(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
@@ -351,12 +982,43 @@ func TestGetMarketData(t *testing.T) {
 	client.SetBaseURL(server.URL)
 	client.accessToken = "test-token"
 
-	data, err := client.GetMarketData(54321)
+	data, err := client.GetMarketData(context.Background(), 54321)
 	assert.NoError(t, err)
 	assert.Equal(t, 54321, data.ContractID)
 	assert.Equal(t, 100.25, data.Bid)
 }
 
+func TestGetMarketDataBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/md/getQuote/2" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"errorText": "Unknown contract"})
+			return
+		}
+		var contractID int
+		fmt.Sscanf(r.URL.Path, "/md/getQuote/%d", &contractID)
+		json.NewEncoder(w).Encode(models.MarketData{ContractID: contractID, Last: float64(contractID) * 100})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	results, err := client.GetMarketDataBatch(context.Background(), []int{3, 1, 2})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, 3, results[0].ContractID)
+	assert.Equal(t, 300.0, results[0].Data.Last)
+	assert.Equal(t, 1, results[1].ContractID)
+	assert.Equal(t, models.MarketDataResult{ContractID: 2, Error: "status 404: Unknown contract"}, results[2])
+
+	_, err = client.GetMarketDataBatch(context.Background(), nil)
+	assert.EqualError(t, err, "no contracts requested")
+	_, err = client.GetMarketDataBatch(context.Background(), make([]int, 101))
+	assert.EqualError(t, err, "too many contracts: 101 requested, at most 100 allowed")
+}
+
 func TestGetHistoricalData(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
@@ -385,7 +1047,7 @@ func TestGetHistoricalData(t *testing.T) {
 	startTime := time.Now().Add(-24 * time.Hour)
 	endTime := time.Now()
 
-	data, err := client.GetHistoricalData(54321, startTime, endTime, "1h")
+	data, err := client.GetHistoricalData(context.Background(), 54321, startTime, endTime, "1h")
 	assert.NoError(t, err)
 	assert.Len(t, data, 1)
 	assert.Equal(t, 54321, data[0].ContractID)
@@ -427,7 +1089,7 @@ func TestDoRequestError(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := client.doRequest(tt.method, tt.path, tt.body)
+			_, err := client.doRequest(context.Background(), tt.method, tt.path, tt.body)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
@@ -459,7 +1121,7 @@ func TestClientErrorHandling(t *testing.T) {
 		{
 			name: "GetAccounts error",
 			test: func() error {
-				_, err := client.GetAccounts()
+				_, err := client.GetAccounts(context.Background())
 				return err
 			},
 			wantErr: true,
@@ -467,7 +1129,7 @@ func TestClientErrorHandling(t *testing.T) {
 		{
 			name: "GetRiskLimits error",
 			test: func() error {
-				_, err := client.GetRiskLimits(1)
+				_, err := client.GetRiskLimits(context.Background(), 1)
 				return err
 			},
 			wantErr: true,
@@ -475,14 +1137,14 @@ func TestClientErrorHandling(t *testing.T) {
 		{
 			name: "SetRiskLimits error",
 			test: func() error {
-				return client.SetRiskLimits(models.RiskLimit{})
+				return client.SetRiskLimits(context.Background(), models.RiskLimit{})
 			},
 			wantErr: true,
 		},
 		{
 			name: "PlaceOrder error",
 			test: func() error {
-				_, err := client.PlaceOrder(models.Order{})
+				_, err := client.PlaceOrder(context.Background(), models.Order{})
 				return err
 			},
 			wantErr: true,
@@ -490,14 +1152,14 @@ func TestClientErrorHandling(t *testing.T) {
 		{
 			name: "CancelOrder error",
 			test: func() error {
-				return client.CancelOrder(1)
+				return client.CancelOrder(context.Background(), 1)
 			},
 			wantErr: true,
 		},
 		{
 			name: "GetFills error",
 			test: func() error {
-				_, err := client.GetFills(1)
+				_, err := client.GetFills(context.Background(), 1)
 				return err
 			},
 			wantErr: true,
@@ -505,7 +1167,7 @@ func TestClientErrorHandling(t *testing.T) {
 		{
 			name: "GetPositions error",
 			test: func() error {
-				_, err := client.GetPositions()
+				_, err := client.GetPositions(context.Background())
 				return err
 			},
 			wantErr: true,
@@ -513,7 +1175,7 @@ func TestClientErrorHandling(t *testing.T) {
 		{
 			name: "GetContracts error",
 			test: func() error {
-				_, err := client.GetContracts()
+				_, err := client.GetContracts(context.Background())
 				return err
 			},
 			wantErr: true,
@@ -521,7 +1183,7 @@ func TestClientErrorHandling(t *testing.T) {
 		{
 			name: "GetMarketData error",
 			test: func() error {
-				_, err := client.GetMarketData(1)
+				_, err := client.GetMarketData(context.Background(), 1)
 				return err
 			},
 			wantErr: true,
@@ -529,7 +1191,7 @@ func TestClientErrorHandling(t *testing.T) {
 		{
 			name: "GetHistoricalData error",
 			test: func() error {
-				_, err := client.GetHistoricalData(1, time.Now(), time.Now(), "1h")
+				_, err := client.GetHistoricalData(context.Background(), 1, time.Now(), time.Now(), "1h")
 				return err
 			},
 			wantErr: true,
@@ -566,7 +1228,7 @@ func TestAuthenticateInvalidCredentials(t *testing.T) {
 	client := NewTradovateClient()
 	client.SetBaseURL(server.URL)
 
-	_, err := client.Authenticate()
+	_, err := client.Authenticate(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "Invalid credentials")
 }
@@ -580,7 +1242,7 @@ func TestAuthenticateInvalidResponse(t *testing.T) {
 	client := NewTradovateClient()
 	client.SetBaseURL(server.URL)
 
-	_, err := client.Authenticate()
+	_, err := client.Authenticate(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to decode response")
 }
@@ -595,7 +1257,7 @@ func TestNetworkTimeoutError(t *testing.T) {
 	client.SetBaseURL(server.URL)
 	client.httpClient.Timeout = 1 * time.Second
 
-	_, err := client.Authenticate()
+	_, err := client.Authenticate(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "context deadline exceeded")
 }
@@ -610,7 +1272,7 @@ func TestInvalidParameterValidation(t *testing.T) {
 		Quantity:   -10,
 		ContractID: -1,
 	}
-	_, err := client.PlaceOrder(order)
+	_, err := client.PlaceOrder(context.Background(), order)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "status 404")
 
@@ -622,22 +1284,22 @@ func TestInvalidParameterValidation(t *testing.T) {
 		MaxPositionQty: -10,
 		TrailingStop:   -50,
 	}
-	err = client.SetRiskLimits(limits)
+	err = client.SetRiskLimits(context.Background(), limits)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "status 404")
 
 	// Test invalid order ID
-	err = client.CancelOrder(-1)
+	err = client.CancelOrder(context.Background(), -1)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "status 404")
 
 	// Test invalid contract ID
-	_, err = client.GetMarketData(-1)
+	_, err = client.GetMarketData(context.Background(), -1)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "status 404")
 
 	// Test invalid historical data parameters
-	_, err = client.GetHistoricalData(-1, time.Now(), time.Now().Add(-24*time.Hour), "invalid")
+	_, err = client.GetHistoricalData(context.Background(), -1, time.Now(), time.Now().Add(-24*time.Hour), "invalid")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "status 400")
 }
@@ -653,27 +1315,27 @@ func TestInvalidResponseHandling(t *testing.T) {
 	client.SetBaseURL(server.URL)
 
 	// Test various endpoints with invalid responses
-	_, err := client.Authenticate()
+	_, err := client.Authenticate(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid")
 
-	_, err = client.GetAccounts()
+	_, err = client.GetAccounts(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid")
 
-	_, err = client.GetPositions()
+	_, err = client.GetPositions(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid")
 
-	_, err = client.GetContracts()
+	_, err = client.GetContracts(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid")
 
-	_, err = client.GetMarketData(1)
+	_, err = client.GetMarketData(context.Background(), 1)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid")
 
-	_, err = client.GetHistoricalData(1, time.Now().Add(-24*time.Hour), time.Now(), "1h")
+	_, err = client.GetHistoricalData(context.Background(), 1, time.Now().Add(-24*time.Hour), time.Now(), "1h")
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "invalid")
 }
@@ -691,7 +1353,7 @@ func TestGetAccountsError(t *testing.T) {
 	client.SetBaseURL(server.URL)
 	client.accessToken = "test-token"
 
-	_, err := client.GetAccounts()
+	_, err := client.GetAccounts(context.Background())
 	assert.Error(t, err)
 }
 
@@ -712,7 +1374,7 @@ func TestSetRiskLimitsError(t *testing.T) {
 		AccountID: 12345,
 	}
 
-	err := client.SetRiskLimits(limits)
+	err := client.SetRiskLimits(context.Background(), limits)
 	assert.Error(t, err)
 }
 
@@ -729,7 +1391,7 @@ func TestGetRiskLimitsError(t *testing.T) {
 	client.SetBaseURL(server.URL)
 	client.accessToken = "test-token"
 
-	_, err := client.GetRiskLimits(12345)
+	_, err := client.GetRiskLimits(context.Background(), 12345)
 	assert.Error(t, err)
 }
 
@@ -750,7 +1412,7 @@ func TestPlaceOrderError(t *testing.T) {
 		AccountID: 12345,
 	}
 
-	_, err := client.PlaceOrder(order)
+	_, err := client.PlaceOrder(context.Background(), order)
 	assert.Error(t, err)
 }
 
@@ -767,7 +1429,7 @@ func TestCancelOrderError(t *testing.T) {
 	client.SetBaseURL(server.URL)
 	client.accessToken = "test-token"
 
-	err := client.CancelOrder(67890)
+	err := client.CancelOrder(context.Background(), 67890)
 	assert.Error(t, err)
 }
 
@@ -784,7 +1446,7 @@ func TestGetFillsError(t *testing.T) {
 	client.SetBaseURL(server.URL)
 	client.accessToken = "test-token"
 
-	_, err := client.GetFills(67890)
+	_, err := client.GetFills(context.Background(), 67890)
 	assert.Error(t, err)
 }
 
@@ -794,26 +1456,445 @@ func TestNetworkErrors(t *testing.T) {
 	client.accessToken = "test-token"
 
 	// Test GetAccounts network error
-	_, err := client.GetAccounts()
+	_, err := client.GetAccounts(context.Background())
 	assert.Error(t, err)
 
 	// Test SetRiskLimits network error
-	err = client.SetRiskLimits(models.RiskLimit{AccountID: 12345})
+	err = client.SetRiskLimits(context.Background(), models.RiskLimit{AccountID: 12345})
 	assert.Error(t, err)
 
 	// Test GetRiskLimits network error
-	_, err = client.GetRiskLimits(12345)
+	_, err = client.GetRiskLimits(context.Background(), 12345)
 	assert.Error(t, err)
 
 	// Test PlaceOrder network error
-	_, err = client.PlaceOrder(models.Order{AccountID: 12345})
+	_, err = client.PlaceOrder(context.Background(), models.Order{AccountID: 12345})
 	assert.Error(t, err)
 
 	// Test CancelOrder network error
-	err = client.CancelOrder(67890)
+	err = client.CancelOrder(context.Background(), 67890)
 	assert.Error(t, err)
 
 	// Test GetFills network error
-	_, err = client.GetFills(67890)
+	_, err = client.GetFills(context.Background(), 67890)
+	assert.Error(t, err)
+}
+
+func TestGetContract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		// The tick size and point value come from the contract's product.
+		switch r.URL.Path {
+		case "/contractMaturity/item":
+			assert.Equal(t, "777", r.URL.Query().Get("id"))
+			json.NewEncoder(w).Encode(models.ContractMaturity{ID: 777, ProductID: 9})
+			return
+		case "/product/item":
+			assert.Equal(t, "9", r.URL.Query().Get("id"))
+			json.NewEncoder(w).Encode(models.Product{ID: 9, Name: "ES", TickSize: 0.25, ValuePerPoint: 50})
+			return
+		}
+		assert.Equal(t, "/contract/item", r.URL.Path)
+		assert.Equal(t, "54321", r.URL.Query().Get("id"))
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		json.NewEncoder(w).Encode(models.Contract{
+			ID:                 54321,
+			Name:               "ESZ4",
+			ContractMaturityID: 777,
+		})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	contract, err := client.GetContract(context.Background(), 54321)
+	assert.NoError(t, err)
+	assert.Equal(t, "ESZ4", contract.Name)
+	assert.Equal(t, 777, contract.ContractMaturityID)
+	assert.Equal(t, 0.25, contract.TickSize)
+	assert.Equal(t, 50.0, contract.ValuePerPoint)
+}
+
+func TestGetProducts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/product/list", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		w.Write([]byte(`[{"id": 1, "name": "ES", "productType": "Futures", "months": "HMUZ", "tickSize": 0.25, "valuePerPoint": 50}]`))
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	products, err := client.GetProducts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []models.Product{{ID: 1, Name: "ES", ProductType: "Futures", Months: "HMUZ", TickSize: 0.25, ValuePerPoint: 50}}, products)
+}
+
+func TestGetProductFees(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/product/getProductFeeParams", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"productIds": [1]}`, string(body))
+		w.Write([]byte(`{"params": [{"productId": 1, "clearingFee": 0.19, "exchangeFee": 1.38, "nfaFee": 0.02, "commission": 0.59, "orderRoutingFee": 0.1}]}`))
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	fees, err := client.GetProductFees(context.Background(), []int{1})
+	require.NoError(t, err)
+	require.Len(t, fees, 1)
+	assert.InDelta(t, 2.28, fees[0].PerSide(), 1e-9)
+	assert.InDelta(t, 4.56, fees[0].RoundTripCost, 1e-9)
+
+	_, err = client.GetProductFees(context.Background(), nil)
+	assert.EqualError(t, err, "at least one product ID is required")
+}
+
+func TestGetContractMargin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/contract/item":
+			if r.URL.Query().Get("id") == "1234" {
+				w.Write([]byte(`{"id": 1234, "name": "ESZ4", "contractMaturityId": 77}`))
+			} else {
+				w.Write([]byte(`{"id": 4321, "name": "SPREAD"}`))
+			}
+		case "/contractMaturity/item":
+			w.Write([]byte(`{"id": 77, "productId": 9}`))
+		case "/product/item":
+			w.Write([]byte(`{"id": 9, "name": "ES", "tickSize": 0.25, "valuePerPoint": 50}`))
+		case "/productMargin/item":
+			assert.Equal(t, "9", r.URL.Query().Get("id"))
+			w.Write([]byte(`{"id": 9, "initialMargin": 13200, "maintenanceMargin": 12000}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	margin, err := client.GetContractMargin(context.Background(), 1234)
+	require.NoError(t, err)
+	assert.Equal(t, &models.ProductMargin{ProductID: 9, InitialMargin: 13200, MaintenanceMargin: 12000}, margin)
+
+	_, err = client.GetContractMargin(context.Background(), 4321)
+	assert.EqualError(t, err, "contract 4321 has no maturity to look up its product by")
+}
+
+func TestGetContractMaturities(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		requested = append(requested, r.URL.RequestURI())
+		json.NewEncoder(w).Encode([]models.ContractMaturity{
+			{ID: 777, ProductID: 1, ExpirationMonth: 202412, ExpirationDate: "2024-12-20T14:30:00Z", IsFront: true},
+		})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	maturities, err := client.GetContractMaturities(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, maturities, 1)
+	assert.True(t, maturities[0].IsFront)
+
+	_, err = client.GetContractMaturities(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/contractMaturity/deps?masterid=1", "/contractMaturity/list"}, requested)
+}
+
+func TestGetContractMaturity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/contractMaturity/item", r.URL.Path)
+		assert.Equal(t, "777", r.URL.Query().Get("id"))
+
+		json.NewEncoder(w).Encode(models.ContractMaturity{
+			ID:              777,
+			ProductID:       1,
+			ExpirationMonth: 202412,
+			ExpirationDate:  "2024-12-20T14:30:00Z",
+		})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	maturity, err := client.GetContractMaturity(context.Background(), 777)
+	assert.NoError(t, err)
+	assert.Equal(t, 202412, maturity.ExpirationMonth)
+	assert.Equal(t, "2024-12-20T14:30:00Z", maturity.ExpirationDate)
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "req-123", r.Header.Get("X-Request-ID"))
+		json.NewEncoder(w).Encode([]models.Account{})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	_, err := client.GetAccounts(logging.WithRequestID(context.Background(), "req-123"))
+	assert.NoError(t, err)
+}
+
+func TestRequestContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]models.Account{})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.GetAccounts(ctx)
 	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "context canceled")
+}
+
+func TestTokenRenewalBeforeExpiry(t *testing.T) {
+	renewed := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/renewAccessToken":
+			renewed++
+			assert.Equal(t, "Bearer old-token", r.Header.Get("Authorization"))
+			json.NewEncoder(w).Encode(AuthResponse{
+				AccessToken:    "new-token",
+				ExpirationTime: time.Now().Add(80 * time.Minute).UTC().Format(time.RFC3339),
+			})
+		case "/account/list":
+			assert.Equal(t, "Bearer new-token", r.Header.Get("Authorization"))
+			json.NewEncoder(w).Encode([]models.Account{})
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "old-token"
+	client.tokenExpiry = time.Now().Add(5 * time.Minute)
+
+	_, err := client.GetAccounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, renewed)
+	assert.Equal(t, "new-token", client.accessToken)
+	assert.WithinDuration(t, time.Now().Add(80*time.Minute), client.tokenExpiry, time.Minute)
+
+	// A token well within its lifetime is not renewed again.
+	_, err = client.GetAccounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, renewed)
+}
+
+func TestTokenRenewalFallsBackToAuthentication(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/renewAccessToken":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/auth/accessTokenRequest":
+			json.NewEncoder(w).Encode(AuthResponse{AccessToken: "fresh-token"})
+		case "/account/list":
+			assert.Equal(t, "Bearer fresh-token", r.Header.Get("Authorization"))
+			json.NewEncoder(w).Encode([]models.Account{})
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "expired-token"
+	client.tokenExpiry = time.Now().Add(-time.Minute)
+
+	_, err := client.GetAccounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "fresh-token", client.accessToken)
+}
+
+func TestUnauthorizedRetry(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/accessTokenRequest":
+			json.NewEncoder(w).Encode(AuthResponse{AccessToken: "fresh-token"})
+		case "/order/placeOrder":
+			calls++
+			var order models.Order
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&order))
+			assert.Equal(t, 2, order.Quantity)
+			if r.Header.Get("Authorization") != "Bearer fresh-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			json.NewEncoder(w).Encode(order)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "revoked-token"
+
+	order, err := client.PlaceOrder(context.Background(), models.Order{Quantity: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 2, order.Quantity)
+	assert.Equal(t, 2, calls)
+}
+
+func TestUnauthorizedRetryOnlyOnce(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/auth/accessTokenRequest" {
+			json.NewEncoder(w).Encode(AuthResponse{AccessToken: "still-rejected"})
+			return
+		}
+		calls++
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"errorText": "Access is denied"})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "revoked-token"
+
+	_, err := client.GetAccounts(context.Background())
+	assert.EqualError(t, err, "status 401: Access is denied")
+	assert.Equal(t, 2, calls)
+}
+
+func TestTokenCache(t *testing.T) {
+	t.Setenv("TRADOVATE_USERNAME", "trader")
+	expiry := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(AuthResponse{
+			AccessToken:    "cached-token",
+			MdAccessToken:  "cached-md-token",
+			ExpirationTime: expiry.Format(time.RFC3339),
+			UserID:         42,
+		})
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cache", "token.json")
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.SetTokenCachePath(path)
+	_, err := client.Authenticate(context.Background())
+	require.NoError(t, err)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// A new process reuses the cached tokens without authenticating.
+	restarted := NewTradovateClient()
+	restarted.SetBaseURL(server.URL)
+	restarted.SetTokenCachePath(path)
+	loaded, err := restarted.LoadTokenCache()
+	require.NoError(t, err)
+	assert.True(t, loaded)
+	assert.Equal(t, "cached-token", restarted.accessToken)
+	assert.Equal(t, "cached-md-token", restarted.mdAccessToken)
+	assert.True(t, expiry.Equal(restarted.tokenExpiry))
+	assert.Equal(t, 42, restarted.userID)
+
+	// Tokens for another environment or user are ignored.
+	other := NewTradovateClient()
+	other.SetTokenCachePath(path)
+	loaded, err = other.LoadTokenCache()
+	require.NoError(t, err)
+	assert.False(t, loaded)
+
+	t.Setenv("TRADOVATE_USERNAME", "someone-else")
+	fresh := NewTradovateClient()
+	fresh.SetBaseURL(server.URL)
+	fresh.SetTokenCachePath(path)
+	loaded, err = fresh.LoadTokenCache()
+	require.NoError(t, err)
+	assert.False(t, loaded)
+}
+
+func TestTokenCacheExpiredOrCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	client := NewTradovateClient()
+	client.SetTokenCachePath(path)
+
+	loaded, err := client.LoadTokenCache()
+	require.NoError(t, err)
+	assert.False(t, loaded)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"baseUrl": "https://live.tradovate.com/v1", "accessToken": "old", "expirationTime": "2020-01-01T00:00:00Z"}`), 0600))
+	loaded, err = client.LoadTokenCache()
+	require.NoError(t, err)
+	assert.False(t, loaded)
+	assert.Empty(t, client.accessToken)
+
+	require.NoError(t, os.WriteFile(path, []byte(`not json`), 0600))
+	_, err = client.LoadTokenCache()
+	assert.ErrorContains(t, err, "failed to parse token cache")
+}
+
+func TestTokenCacheClearedOnUnauthorized(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{}`), 0600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(AuthResponse{ErrorText: "Access is denied"})
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.SetTokenCachePath(path)
+	client.accessToken = "revoked-token"
+
+	_, err := client.GetAccounts(context.Background())
+	require.Error(t, err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSetEnvironment(t *testing.T) {
+	client := NewTradovateClient()
+	assert.Equal(t, EnvironmentLive, client.Environment())
+
+	client.accessToken = "live-token"
+	require.NoError(t, client.SetEnvironment("Demo"))
+	assert.Equal(t, "https://demo.tradovateapi.com/v1", client.baseURL)
+	assert.Equal(t, "wss://demo.tradovateapi.com/v1/websocket", client.Environment().WebSocketURL)
+	assert.Equal(t, "wss://md-demo.tradovateapi.com/v1/websocket", client.Environment().MarketDataURL)
+	assert.Empty(t, client.accessToken, "tokens must not leak across environments")
+
+	err := client.SetEnvironment("paper")
+	assert.EqualError(t, err, `unknown environment "paper": must be one of demo, live, replay`)
+	assert.Equal(t, "demo", client.Environment().Name)
+
+	require.NoError(t, client.SetEnvironment("live"))
+	assert.Equal(t, "https://live.tradovate.com/v1", client.baseURL)
 }
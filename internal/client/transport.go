@@ -0,0 +1,112 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TransportOptions tunes the HTTP connections the client makes to the
+// Tradovate REST API. Zero fields take their value from
+// DefaultTransportOptions.
+type TransportOptions struct {
+	MaxIdleConnsPerHost int           // Idle connections kept open for reuse
+	IdleConnTimeout     time.Duration // How long an idle connection is kept
+	DialTimeout         time.Duration // Limit on establishing a TCP connection
+	TLSHandshakeTimeout time.Duration // Limit on the TLS handshake
+	ProxyURL            string        // Proxy for all requests and WebSockets; empty to use HTTPS_PROXY and NO_PROXY
+}
+
+// DefaultTransportOptions keeps a handful of connections to Tradovate warm
+// and honours the standard proxy environment variables.
+var DefaultTransportOptions = TransportOptions{
+	MaxIdleConnsPerHost: 10,
+	IdleConnTimeout:     90 * time.Second,
+	DialTimeout:         10 * time.Second,
+	TLSHandshakeTimeout: 10 * time.Second,
+}
+
+// SetTransportOptions replaces the client's HTTP transport with one built
+// from opts. Connections of the previous transport are closed once idle. It
+// must not be called while requests are in flight.
+func (c *TradovateClient) SetTransportOptions(opts TransportOptions) error {
+	transport, err := newTransport(opts)
+	if err != nil {
+		return err
+	}
+	if previous, ok := c.httpClient.Transport.(*http.Transport); ok {
+		previous.CloseIdleConnections()
+	}
+	c.httpClient.Transport = transport
+	return nil
+}
+
+// SetTransport replaces the round tripper REST requests are sent with,
+// e.g. with a FixtureTransport in tests. It must not be called while
+// requests are in flight.
+func (c *TradovateClient) SetTransport(rt http.RoundTripper) {
+	c.httpClient.Transport = rt
+}
+
+// socketProxy returns how the client's WebSockets pick a proxy: the same
+// way as its REST requests, or not at all if REST requests are sent with a
+// round tripper other than an http.Transport.
+func (c *TradovateClient) socketProxy() proxyFunc {
+	if transport, ok := c.httpClient.Transport.(*http.Transport); ok && transport.Proxy != nil {
+		return transport.Proxy
+	}
+	return nil
+}
+
+// defaultTransport returns a transport built from DefaultTransportOptions.
+func defaultTransport() *http.Transport {
+	transport, err := newTransport(DefaultTransportOptions)
+	if err != nil {
+		panic(err)
+	}
+	return transport
+}
+
+// newTransport builds an http.Transport from opts.
+func newTransport(opts TransportOptions) (*http.Transport, error) {
+	opts = opts.withDefaults()
+
+	proxy := http.ProxyFromEnvironment
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", opts.ProxyURL)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	dialer := &net.Dialer{Timeout: opts.DialTimeout, KeepAlive: 30 * time.Second}
+	return &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          opts.MaxIdleConnsPerHost * 2,
+		MaxIdleConnsPerHost:   opts.MaxIdleConnsPerHost,
+		IdleConnTimeout:       opts.IdleConnTimeout,
+		TLSHandshakeTimeout:   opts.TLSHandshakeTimeout,
+		ExpectContinueTimeout: time.Second,
+	}, nil
+}
+
+func (o TransportOptions) withDefaults() TransportOptions {
+	if o.MaxIdleConnsPerHost <= 0 {
+		o.MaxIdleConnsPerHost = DefaultTransportOptions.MaxIdleConnsPerHost
+	}
+	if o.IdleConnTimeout <= 0 {
+		o.IdleConnTimeout = DefaultTransportOptions.IdleConnTimeout
+	}
+	if o.DialTimeout <= 0 {
+		o.DialTimeout = DefaultTransportOptions.DialTimeout
+	}
+	if o.TLSHandshakeTimeout <= 0 {
+		o.TLSHandshakeTimeout = DefaultTransportOptions.TLSHandshakeTimeout
+	}
+	return o
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransport(t *testing.T) {
+	transport, err := newTransport(TransportOptions{MaxIdleConnsPerHost: 32, DialTimeout: 3 * time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, 32, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, DefaultTransportOptions.IdleConnTimeout, transport.IdleConnTimeout)
+	assert.Equal(t, DefaultTransportOptions.TLSHandshakeTimeout, transport.TLSHandshakeTimeout)
+
+	req, _ := http.NewRequest("GET", "https://live.tradovateapi.com/v1/account/list", nil)
+	transport, err = newTransport(TransportOptions{ProxyURL: "http://proxy.internal:3128"})
+	require.NoError(t, err)
+	proxy, err := transport.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.internal:3128", proxy.Host)
+
+	_, err = newTransport(TransportOptions{ProxyURL: "not a url"})
+	assert.EqualError(t, err, `invalid proxy URL "not a url"`)
+}
+
+func TestSetTransportOptionsProxy(t *testing.T) {
+	// Requests are sent to the proxy with the Tradovate URL as the target.
+	var target string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.URL.String()
+		w.Write([]byte(`[]`))
+	}))
+	defer proxy.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL("http://tradovate.invalid/v1")
+	require.NoError(t, client.SetTransportOptions(TransportOptions{ProxyURL: proxy.URL}))
+
+	_, err := client.GetAccounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "http://tradovate.invalid/v1/account/list", target)
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// GetUserProperties retrieves the settings stored on the authenticated
+// user's profile.
+func (c *TradovateClient) GetUserProperties(ctx context.Context) ([]models.UserProperty, error) {
+	userID, err := c.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/userProperty/deps?masterid=%d", userID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var properties []models.UserProperty
+	if err := json.NewDecoder(resp.Body).Decode(&properties); err != nil {
+		return nil, fmt.Errorf("error decoding user properties: %w", err)
+	}
+
+	return properties, nil
+}
+
+// GetUserPlugins retrieves the add-ons the authenticated user has
+// subscribed to, including market data entitlements such as exchange
+// bundles.
+func (c *TradovateClient) GetUserPlugins(ctx context.Context) ([]models.UserPlugin, error) {
+	userID, err := c.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/userPlugin/deps?masterid=%d", userID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var plugins []models.UserPlugin
+	if err := json.NewDecoder(resp.Body).Decode(&plugins); err != nil {
+		return nil, fmt.Errorf("error decoding user plugins: %w", err)
+	}
+
+	return plugins, nil
+}
+
+// currentUserID returns the ID of the authenticated user, asking Tradovate
+// if it was not part of the access token response.
+func (c *TradovateClient) currentUserID(ctx context.Context) (int, error) {
+	if userID := c.knownUserID(); userID != 0 {
+		return userID, nil
+	}
+
+	resp, err := c.doRequest(ctx, "GET", "/auth/me", nil)
+	if err != nil {
+		return 0, fmt.Errorf("error looking up user: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var me struct {
+		UserID int `json:"userId"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&me); err != nil {
+		return 0, fmt.Errorf("error decoding user: %w", err)
+	}
+	if me.UserID == 0 {
+		return 0, fmt.Errorf("error looking up user: no user ID returned")
+	}
+	c.setUserID(me.UserID)
+	return me.UserID, nil
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetUserProperties(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		requested = append(requested, r.URL.RequestURI())
+		switch r.URL.Path {
+		case "/auth/me":
+			w.Write([]byte(`{"userId": 42, "name": "trader"}`))
+		case "/userProperty/deps":
+			w.Write([]byte(`[{"id": 1, "userId": 42, "propertyId": 3, "value": "America/Chicago"}]`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+
+	properties, err := client.GetUserProperties(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []models.UserProperty{{ID: 1, UserID: 42, PropertyID: 3, Value: "America/Chicago"}}, properties)
+
+	// The user ID is remembered after the first lookup.
+	_, err = client.GetUserProperties(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/auth/me", "/userProperty/deps?masterid=42", "/userProperty/deps?masterid=42"}, requested)
+}
+
+func TestGetUserPlugins(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/userPlugin/deps", r.URL.Path)
+		assert.Equal(t, "42", r.URL.Query().Get("masterid"))
+		w.Write([]byte(`[
+			{"id": 1, "userId": 42, "pluginName": "CME_Bundle", "approval": true, "startDate": {"year": 2024, "month": 1, "day": 1}, "expirationDate": {"year": 2024, "month": 12, "day": 31}},
+			{"id": 2, "userId": 42, "pluginName": "Level2", "approval": false}
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewTradovateClient()
+	client.SetBaseURL(server.URL)
+	client.accessToken = "test-token"
+	client.userID = 42
+
+	plugins, err := client.GetUserPlugins(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plugins, 2)
+	assert.Equal(t, "CME_Bundle", plugins[0].PluginName)
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(t, plugins[0].Active(now))
+	assert.False(t, plugins[0].Active(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, plugins[1].Active(now), "plugins awaiting approval are not active")
+}
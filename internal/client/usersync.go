@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// Entity types pushed by the user sync WebSocket.
+const (
+	EntityOrder       = "order"
+	EntityFill        = "fill"
+	EntityPosition    = "position"
+	EntityCashBalance = "cashBalance"
+	EntityAccount     = "account"
+)
+
+// UserEvent is a change to one of the user's entities, pushed by the user
+// sync WebSocket when an order changes state, a fill arrives, or a position
+// or cash balance moves.
+type UserEvent struct {
+	EntityType string          `json:"entityType"` // Kind of entity, e.g. EntityOrder
+	EventType  string          `json:"eventType"`  // "Created", "Updated" or "Deleted"
+	Entity     json.RawMessage `json:"entity"`     // The entity as returned by the REST API
+}
+
+// Decode unmarshals the event's entity into v, e.g. a *models.Order for
+// order events.
+func (e UserEvent) Decode(v interface{}) error {
+	if err := json.Unmarshal(e.Entity, v); err != nil {
+		return fmt.Errorf("error decoding %s event: %w", e.EntityType, err)
+	}
+	return nil
+}
+
+// userSubscriber receives the user events of the entity types it asked for.
+type userSubscriber struct {
+	entityTypes map[string]bool // Empty for every type
+	onEvent     func(UserEvent)
+}
+
+// userSyncStream fans the events of a synchronized user WebSocket out to
+// its subscribers. If the WebSocket drops, it is reconnected and
+// synchronized again.
+type userSyncStream struct {
+	live liveSocket
+
+	mu          sync.Mutex
+	nextID      int
+	subscribers map[int]userSubscriber
+}
+
+// SubscribeUserEvents streams changes to the user's orders, fills,
+// positions, cash balances and accounts, calling onEvent for each until the
+// subscription is cancelled. If entityTypes is given, only events for those
+// types are delivered. onEvent is called from the stream's read goroutine
+// and must not block.
+func (c *TradovateClient) SubscribeUserEvents(ctx context.Context, onEvent func(UserEvent), entityTypes ...string) (*Subscription, error) {
+	stream, err := c.userSyncStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sub := userSubscriber{entityTypes: make(map[string]bool), onEvent: onEvent}
+	for _, t := range entityTypes {
+		sub.entityTypes[t] = true
+	}
+
+	stream.mu.Lock()
+	id := stream.nextID
+	stream.nextID++
+	stream.subscribers[id] = sub
+	stream.mu.Unlock()
+
+	return &Subscription{unsubscribe: func(context.Context) error {
+		stream.mu.Lock()
+		delete(stream.subscribers, id)
+		stream.mu.Unlock()
+		return nil
+	}}, nil
+}
+
+// userSyncStream returns the user sync stream, connecting and sending
+// user/syncrequest if needed.
+func (c *TradovateClient) userSyncStream(ctx context.Context) (*userSyncStream, error) {
+	c.socketMu.Lock()
+	defer c.socketMu.Unlock()
+
+	if c.user != nil {
+		return c.user, nil
+	}
+	if c.GetAccessToken() == "" {
+		return nil, fmt.Errorf("not authenticated: call authenticate before subscribing to user events")
+	}
+
+	stream := &userSyncStream{subscribers: make(map[int]userSubscriber)}
+	dial := func(ctx context.Context) (*tradovateSocket, error) {
+		return dialSocket(ctx, c.Environment().WebSocketURL, c.GetAccessToken(), c.socketTiming(), c.socketProxy(), stream.handleEvent)
+	}
+	socket, err := dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := c.knownUserID()
+	if userID == 0 {
+		data, err := socket.request(ctx, "auth/me", "", nil)
+		if err != nil {
+			socket.close()
+			return nil, fmt.Errorf("error looking up user: %w", err)
+		}
+		var me struct {
+			UserID int `json:"userId"`
+		}
+		if err := json.Unmarshal(data, &me); err != nil {
+			socket.close()
+			return nil, fmt.Errorf("error decoding user: %w", err)
+		}
+		userID = me.UserID
+		c.setUserID(userID)
+	}
+
+	syncUser := func(ctx context.Context, socket *tradovateSocket) error {
+		if _, err := socket.request(ctx, "user/syncrequest", "", map[string]interface{}{"users": []int{userID}}); err != nil {
+			return fmt.Errorf("error synchronizing user %d: %w", userID, err)
+		}
+		return nil
+	}
+	if err := syncUser(ctx, socket); err != nil {
+		socket.close()
+		return nil, err
+	}
+	stream.live.socket = socket
+
+	supervise, stop := context.WithCancel(context.Background())
+	stream.live.stop = stop
+	go c.superviseSocket(supervise, "user sync", &stream.live, dial, syncUser)
+
+	c.user = stream
+	return stream, nil
+}
+
+// closeUserSyncStream disconnects the user sync WebSocket, if connected.
+func (c *TradovateClient) closeUserSyncStream() {
+	c.socketMu.Lock()
+	defer c.socketMu.Unlock()
+	if c.user != nil {
+		c.user.live.shutdown()
+		c.user = nil
+	}
+}
+
+// handleEvent delivers "props" events to the subscribers interested in
+// their entity type.
+func (s *userSyncStream) handleEvent(ev SocketEvent) {
+	if ev.Event != "props" {
+		return
+	}
+
+	var event UserEvent
+	if err := json.Unmarshal(ev.Data, &event); err != nil {
+		slog.Warn("ignoring malformed user event", "error", err)
+		return
+	}
+
+	s.mu.Lock()
+	var subs []func(UserEvent)
+	for _, sub := range s.subscribers {
+		if len(sub.entityTypes) == 0 || sub.entityTypes[event.EntityType] {
+			subs = append(subs, sub.onEvent)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, onEvent := range subs {
+		onEvent(event)
+	}
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUserSyncTestClient returns a client whose user WebSocket is served by
+// server.
+func newUserSyncTestClient(server *fakeSocketServer) *TradovateClient {
+	client := NewTradovateClient()
+	client.env.WebSocketURL = server.wsURL()
+	client.accessToken = "access-token"
+	client.socketHeartbeat = 10 * time.Millisecond
+	return client
+}
+
+func TestSubscribeUserEvents(t *testing.T) {
+	server := newFakeSocketServer(t, func(req socketRequest) (int, interface{}) {
+		if req.Endpoint == "auth/me" {
+			return 200, map[string]interface{}{"userId": 42, "name": "trader"}
+		}
+		return 200, map[string]interface{}{}
+	})
+	client := newUserSyncTestClient(server)
+	defer client.Close()
+
+	all := make(chan UserEvent, 2)
+	orders := make(chan UserEvent, 2)
+	subAll, err := client.SubscribeUserEvents(context.Background(), func(ev UserEvent) { all <- ev })
+	require.NoError(t, err)
+	_, err = client.SubscribeUserEvents(context.Background(), func(ev UserEvent) { orders <- ev }, EntityOrder)
+	require.NoError(t, err)
+
+	reqs := server.received()
+	assert.Equal(t, []string{"authorize", "auth/me", "user/syncrequest"}, server.endpoints())
+	assert.Equal(t, "access-token", reqs[0].Body)
+	assert.JSONEq(t, `{"users":[42]}`, reqs[2].Body)
+	assert.Equal(t, 42, client.userID)
+
+	server.push("props", map[string]interface{}{
+		"entityType": "position",
+		"eventType":  "Updated",
+		"entity":     map[string]interface{}{"id": 3, "accountId": 1, "contractId": 1234, "netPos": 2},
+	})
+	server.push("props", map[string]interface{}{
+		"entityType": "order",
+		"eventType":  "Created",
+		"entity":     map[string]interface{}{"id": 7, "accountId": 1, "contractId": 1234, "status": "Working"},
+	})
+
+	receive := func(ch chan UserEvent) UserEvent {
+		select {
+		case ev := <-ch:
+			return ev
+		case <-time.After(time.Second):
+			t.Fatal("event not delivered")
+			return UserEvent{}
+		}
+	}
+
+	ev := receive(all)
+	assert.Equal(t, EntityPosition, ev.EntityType)
+	var position models.Position
+	require.NoError(t, ev.Decode(&position))
+	assert.Equal(t, 2, position.NetPos)
+	assert.Equal(t, EntityOrder, receive(all).EntityType)
+
+	ev = receive(orders)
+	assert.Equal(t, "Created", ev.EventType)
+	var order models.Order
+	require.NoError(t, ev.Decode(&order))
+	assert.Equal(t, 7, order.ID)
+	assert.Equal(t, "Working", order.Status)
+
+	// Unsubscribed handlers receive nothing further.
+	require.NoError(t, subAll.Unsubscribe(context.Background()))
+	server.push("props", map[string]interface{}{"entityType": "order", "eventType": "Updated", "entity": map[string]interface{}{"id": 7}})
+	receive(orders)
+	assert.Empty(t, all)
+}
+
+func TestSubscribeUserEventsKnownUser(t *testing.T) {
+	server := newFakeSocketServer(t, nil)
+	client := newUserSyncTestClient(server)
+	client.userID = 42
+	defer client.Close()
+
+	_, err := client.SubscribeUserEvents(context.Background(), func(UserEvent) {})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"authorize", "user/syncrequest"}, server.endpoints())
+
+	unauthenticated := NewTradovateClient()
+	_, err = unauthenticated.SubscribeUserEvents(context.Background(), func(UserEvent) {})
+	assert.EqualError(t, err, "not authenticated: call authenticate before subscribing to user events")
+}
@@ -0,0 +1,358 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket opcodes from RFC 6455.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// maxMessageBytes bounds the size of a single WebSocket message.
+const maxMessageBytes = 16 << 20
+
+// websocketGUID is the fixed GUID used to derive Sec-WebSocket-Accept.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// errWebSocketClosed is returned when the peer closes the connection.
+var errWebSocketClosed = errors.New("websocket closed")
+
+// wsConn is a minimal RFC 6455 client connection supporting text messages.
+type wsConn struct {
+	conn    net.Conn
+	br      *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// proxyFunc picks the proxy for a request, as http.Transport.Proxy does.
+// It returns a nil URL to connect directly.
+type proxyFunc func(*http.Request) (*url.URL, error)
+
+// dialWebSocket opens a WebSocket connection to rawURL (ws:// or wss://),
+// tunnelled through the proxy that proxy picks, if any.
+func dialWebSocket(ctx context.Context, rawURL string, proxy proxyFunc) (*wsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid websocket URL: %w", err)
+	}
+
+	host := u.Host
+	switch u.Scheme {
+	case "ws":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	case "wss":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		}
+	default:
+		return nil, fmt.Errorf("invalid websocket URL scheme %q", u.Scheme)
+	}
+
+	conn, err := dialTCP(ctx, u, host, proxy)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s: %w", u.Host, err)
+	}
+	if u.Scheme == "wss" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error connecting to %s: %w", u.Host, err)
+		}
+		conn = tlsConn
+	}
+
+	// Bound the handshake by ctx; the deadline is cleared once it completes.
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	ws, err := handshakeWebSocket(conn, u)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return ws, nil
+}
+
+// dialTCP connects to host, the address of u. If proxy picks a proxy for
+// u, the connection is tunnelled through it with HTTP CONNECT, so sockets
+// reach Tradovate the same way REST requests do.
+func dialTCP(ctx context.Context, u *url.URL, host string, proxy proxyFunc) (net.Conn, error) {
+	var dialer net.Dialer
+	var proxyURL *url.URL
+	if proxy != nil {
+		// Proxies are chosen by the scheme of the HTTP request the
+		// WebSocket upgrades, so HTTPS_PROXY applies to wss://.
+		target := *u
+		target.Scheme = "http"
+		if u.Scheme == "wss" {
+			target.Scheme = "https"
+		}
+		var err error
+		if proxyURL, err = proxy(&http.Request{Method: "GET", URL: &target}); err != nil {
+			return nil, fmt.Errorf("error choosing proxy: %w", err)
+		}
+	}
+	if proxyURL == nil {
+		return dialer.DialContext(ctx, "tcp", host)
+	}
+
+	proxyHost := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		proxyHost = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", proxyHost)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to proxy %s: %w", proxyURL.Host, err)
+	}
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname(), MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error connecting to proxy %s: %w", proxyURL.Host, err)
+		}
+		conn = tlsConn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	connect := &http.Request{
+		Method: "CONNECT",
+		URL:    &url.URL{Opaque: host},
+		Host:   host,
+		Header: http.Header{},
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		connect.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := connect.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error sending CONNECT to proxy %s: %w", proxyURL.Host, err)
+	}
+	// The proxy says nothing after its response until the client speaks,
+	// so no bytes of the tunnel are left in br.
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, connect)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error reading CONNECT response from proxy %s: %w", proxyURL.Host, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s refused tunnel: %s", proxyURL.Host, resp.Status)
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// handshakeWebSocket performs the opening handshake over conn.
+func handshakeWebSocket(conn net.Conn, u *url.URL) (*wsConn, error) {
+	keyBytes := make([]byte, 16)
+	if _, err := rand.Read(keyBytes); err != nil {
+		return nil, fmt.Errorf("error generating websocket key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes)
+
+	req := &http.Request{
+		Method:     "GET",
+		URL:        u,
+		Host:       u.Host,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Upgrade":               {"websocket"},
+			"Connection":            {"Upgrade"},
+			"Sec-WebSocket-Key":     {key},
+			"Sec-WebSocket-Version": {"13"},
+		},
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, fmt.Errorf("error sending websocket handshake: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, fmt.Errorf("error reading websocket handshake: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("websocket handshake failed: status %d", resp.StatusCode)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") || resp.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key) {
+		return nil, fmt.Errorf("websocket handshake failed: invalid upgrade response")
+	}
+
+	return &wsConn{conn: conn, br: br}, nil
+}
+
+// websocketAccept computes the Sec-WebSocket-Accept value for key.
+func websocketAccept(key string) string {
+	sum := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// readMessage returns the next text or binary message, answering pings and
+// reassembling fragmented messages along the way.
+func (c *wsConn) readMessage() (string, error) {
+	var message []byte
+	for {
+		fin, opcode, payload, err := readFrame(c.br)
+		if err != nil {
+			return "", err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return "", err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			c.writeFrame(opClose, payload)
+			return "", errWebSocketClosed
+		}
+
+		message = append(message, payload...)
+		if len(message) > maxMessageBytes {
+			return "", fmt.Errorf("websocket message exceeds %d bytes", maxMessageBytes)
+		}
+		if fin {
+			return string(message), nil
+		}
+	}
+}
+
+// writeText sends message as a single text frame.
+func (c *wsConn) writeText(message string) error {
+	return c.writeFrame(opText, []byte(message))
+}
+
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return writeFrame(c.conn, opcode, payload, true)
+}
+
+// close sends a close frame and closes the underlying connection.
+func (c *wsConn) close() error {
+	c.writeFrame(opClose, []byte{0x03, 0xE8}) // 1000: normal closure
+	return c.conn.Close()
+}
+
+// readFrame reads a single frame, unmasking its payload if necessary.
+func readFrame(r io.Reader) (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxMessageBytes {
+		return false, 0, nil, fmt.Errorf("websocket frame exceeds %d bytes", maxMessageBytes)
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes payload as a single final frame. Clients must mask the
+// frames they send; servers must not.
+func writeFrame(w io.Writer, opcode byte, payload []byte, mask bool) error {
+	header := []byte{0x80 | opcode, 0}
+	switch {
+	case len(payload) < 126:
+		header[1] = byte(len(payload))
+	case len(payload) <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(len(payload)))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(len(payload)))
+	}
+
+	data := payload
+	if mask {
+		header[1] |= 0x80
+		var key [4]byte
+		if _, err := rand.Read(key[:]); err != nil {
+			return err
+		}
+		header = append(header, key[:]...)
+		data = make([]byte, len(payload))
+		for i := range payload {
+			data[i] = payload[i] ^ key[i%4]
+		}
+	}
+
+	_, err := w.Write(append(header, data...))
+	return err
+}
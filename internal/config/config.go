@@ -0,0 +1,256 @@
+// Package config loads the operator configuration file for the MCP server.
+// The configuration holds settings that may be tightened mid-session, such as
+// risk limits and symbol whitelists, and can be reloaded without a restart.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Config represents the contents of the configuration file.
+type Config struct {
+	LogLevel       string     `json:"logLevel,omitempty"`       // Minimum log level: debug, info, warn or error
+	RequestTimeout Duration   `json:"requestTimeout,omitempty"` // Deadline applied to each tool call, e.g. "15s"
+	RiskLimits     RiskLimits `json:"riskLimits"`               // Limits enforced before orders are sent
+	AllowedSymbols []string   `json:"allowedSymbols,omitempty"` // Contract names or product roots orders may trade
+	RateLimits     RateLimits `json:"rateLimits"`               // Inbound call rate limits per tool
+
+	DisableCompression bool      `json:"disableCompression,omitempty"` // Request uncompressed responses from Tradovate
+	Transport          Transport `json:"transport"`                    // Connection tuning for the Tradovate API, applied at startup
+	Timeouts           Timeouts  `json:"timeouts"`                     // Tradovate API timeouts per kind of operation
+	VerifyEntitlements bool      `json:"verifyEntitlements,omitempty"` // Check market data subscriptions before market data calls
+	TickPrices         string    `json:"tickPrices,omitempty"`         // Off-tick order prices: reject (default), snap or off
+}
+
+// Timeouts overrides the Tradovate API request timeout for kinds of
+// operation. Zero values keep the client's default of 10s.
+type Timeouts struct {
+	Auth       Duration `json:"auth,omitempty"`       // Authentication and token renewal
+	MarketData Duration `json:"marketData,omitempty"` // Quote snapshots and other market data
+	Historical Duration `json:"historical,omitempty"` // Historical data and charts
+	Trading    Duration `json:"trading,omitempty"`    // Placing, modifying and cancelling orders
+}
+
+// Transport tunes the HTTP connections made to the Tradovate API. Zero
+// values keep the client's defaults.
+type Transport struct {
+	MaxIdleConnsPerHost int      `json:"maxIdleConnsPerHost,omitempty"` // Idle connections kept open for reuse
+	IdleConnTimeout     Duration `json:"idleConnTimeout,omitempty"`     // How long an idle connection is kept
+	DialTimeout         Duration `json:"dialTimeout,omitempty"`         // Limit on establishing a connection
+	TLSHandshakeTimeout Duration `json:"tlsHandshakeTimeout,omitempty"` // Limit on the TLS handshake
+	Proxy               string   `json:"proxy,omitempty"`               // Proxy URL; defaults to HTTPS_PROXY
+}
+
+// RiskLimits are server-side checks applied to orders before they reach Tradovate.
+type RiskLimits struct {
+	MaxOrderQuantity int `json:"maxOrderQuantity,omitempty"` // Largest quantity a single order may carry
+}
+
+// RateLimits caps how often MCP clients may call each tool.
+type RateLimits struct {
+	Default *RateLimit           `json:"default,omitempty"` // Limit for tools without their own entry
+	Tools   map[string]RateLimit `json:"tools,omitempty"`   // Limits keyed by tool name
+}
+
+// RateLimit allows Limit calls per Window, e.g. 5 calls per "1m".
+type RateLimit struct {
+	Limit  int      `json:"limit"`  // Calls allowed per window
+	Window Duration `json:"window"` // Length of the window
+}
+
+// For returns the rate limit that applies to tool, if any.
+func (r RateLimits) For(tool string) (RateLimit, bool) {
+	if limit, ok := r.Tools[tool]; ok {
+		return limit, true
+	}
+	if r.Default != nil {
+		return *r.Default, true
+	}
+	return RateLimit{}, false
+}
+
+func (r RateLimit) validate(name string) error {
+	if r.Limit < 1 {
+		return fmt.Errorf("rateLimits.%s.limit must be at least 1", name)
+	}
+	if r.Window.Duration <= 0 {
+		return fmt.Errorf("rateLimits.%s.window must be positive", name)
+	}
+	return nil
+}
+
+// Duration is a time.Duration that is written in configuration files as a
+// Go duration string such as "500ms" or "1m".
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string: %w", err)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Duration.String())
+}
+
+// Level returns the configured log level, defaulting to info.
+func (c *Config) Level() slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// SymbolAllowed reports whether orders may be placed on the contract named
+// name. An empty whitelist allows every symbol. A whitelist entry matches
+// either the exact contract name or its product root, so "ES" allows "ESZ6".
+func (c *Config) SymbolAllowed(name string) bool {
+	if len(c.AllowedSymbols) == 0 {
+		return true
+	}
+	for _, symbol := range c.AllowedSymbols {
+		if strings.EqualFold(name, symbol) || isContractOf(name, symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// isContractOf reports whether name is a dated contract of the product root,
+// i.e. the root followed by a month code and year digits.
+func isContractOf(name, root string) bool {
+	if len(name) < len(root)+2 || !strings.EqualFold(name[:len(root)], root) {
+		return false
+	}
+	suffix := name[len(root):]
+	if !strings.ContainsRune("FGHJKMNQUVXZ", rune(suffix[0])) {
+		return false
+	}
+	for _, r := range suffix[1:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// validate checks the configuration for values that cannot be applied.
+func (c *Config) validate() error {
+	if c.LogLevel != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+			return fmt.Errorf("invalid logLevel %q", c.LogLevel)
+		}
+	}
+	if c.RequestTimeout.Duration < 0 {
+		return fmt.Errorf("requestTimeout must not be negative")
+	}
+	if c.Transport.MaxIdleConnsPerHost < 0 {
+		return fmt.Errorf("transport.maxIdleConnsPerHost must not be negative")
+	}
+	if c.Transport.IdleConnTimeout.Duration < 0 || c.Transport.DialTimeout.Duration < 0 || c.Transport.TLSHandshakeTimeout.Duration < 0 {
+		return fmt.Errorf("transport timeouts must not be negative")
+	}
+	for name, d := range map[string]Duration{
+		"auth":       c.Timeouts.Auth,
+		"marketData": c.Timeouts.MarketData,
+		"historical": c.Timeouts.Historical,
+		"trading":    c.Timeouts.Trading,
+	} {
+		if d.Duration < 0 {
+			return fmt.Errorf("timeouts.%s must not be negative", name)
+		}
+	}
+	switch c.TickPrices {
+	case "", "reject", "snap", "off":
+	default:
+		return fmt.Errorf("invalid tickPrices %q: must be reject, snap or off", c.TickPrices)
+	}
+	if c.RiskLimits.MaxOrderQuantity < 0 {
+		return fmt.Errorf("riskLimits.maxOrderQuantity must not be negative")
+	}
+	if c.RateLimits.Default != nil {
+		if err := c.RateLimits.Default.validate("default"); err != nil {
+			return err
+		}
+	}
+	for tool, limit := range c.RateLimits.Tools {
+		if err := limit.validate("tools." + tool); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load reads and validates the JSON configuration file at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Store holds the active configuration and allows it to be swapped
+// atomically while requests are being served.
+type Store struct {
+	path    string
+	current atomic.Pointer[Config]
+}
+
+// NewStore loads the configuration file at path into a new Store.
+func NewStore(path string) (*Store, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{path: path}
+	s.current.Store(cfg)
+	return s, nil
+}
+
+// Current returns the active configuration. A nil Store yields an empty
+// configuration so callers need not check whether a file was configured.
+func (s *Store) Current() *Config {
+	if s == nil {
+		return &Config{}
+	}
+	return s.current.Load()
+}
+
+// Reload re-reads the configuration file. If the file cannot be loaded the
+// previous configuration stays active and the error is returned.
+func (s *Store) Reload() (*Config, error) {
+	cfg, err := Load(s.path)
+	if err != nil {
+		return nil, err
+	}
+	s.current.Store(cfg)
+	return cfg, nil
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, path, contents string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0600))
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfig(t, path, `{
+		"logLevel": "debug",
+		"requestTimeout": "15s",
+		"riskLimits": {"maxOrderQuantity": 5},
+		"allowedSymbols": ["ES", "NQH5"],
+		"transport": {"maxIdleConnsPerHost": 20, "dialTimeout": "3s", "proxy": "http://proxy:3128"},
+		"timeouts": {"marketData": "2s", "historical": "1m"},
+		"verifyEntitlements": true,
+		"tickPrices": "snap"
+	}`)
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelDebug, cfg.Level())
+	assert.Equal(t, 15*time.Second, cfg.RequestTimeout.Duration)
+	assert.Equal(t, 5, cfg.RiskLimits.MaxOrderQuantity)
+	assert.Equal(t, []string{"ES", "NQH5"}, cfg.AllowedSymbols)
+	assert.Equal(t, 20, cfg.Transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 3*time.Second, cfg.Transport.DialTimeout.Duration)
+	assert.Equal(t, "http://proxy:3128", cfg.Transport.Proxy)
+	assert.False(t, cfg.DisableCompression)
+	assert.Equal(t, 2*time.Second, cfg.Timeouts.MarketData.Duration)
+	assert.Equal(t, time.Minute, cfg.Timeouts.Historical.Duration)
+	assert.True(t, cfg.VerifyEntitlements)
+	assert.Equal(t, "snap", cfg.TickPrices)
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		errMsg   string
+	}{
+		{"malformed JSON", `{`, "failed to parse config"},
+		{"bad duration", `{"requestTimeout": "soon"}`, "failed to parse config"},
+		{"bad log level", `{"logLevel": "loud"}`, "invalid logLevel"},
+		{"bad tick policy", `{"tickPrices": "round"}`, "invalid tickPrices"},
+		{"negative quantity", `{"riskLimits": {"maxOrderQuantity": -1}}`, "maxOrderQuantity must not be negative"},
+		{"negative idle connections", `{"transport": {"maxIdleConnsPerHost": -1}}`, "maxIdleConnsPerHost must not be negative"},
+		{"negative dial timeout", `{"transport": {"dialTimeout": "-1s"}}`, "transport timeouts must not be negative"},
+		{"negative timeout", `{"timeouts": {"historical": "-5s"}}`, "timeouts.historical must not be negative"},
+		{"zero rate limit", `{"rateLimits": {"default": {"limit": 0, "window": "1m"}}}`, "rateLimits.default.limit must be at least 1"},
+		{"missing window", `{"rateLimits": {"tools": {"placeOrder": {"limit": 5}}}}`, "rateLimits.tools.placeOrder.window must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			writeConfig(t, path, tt.contents)
+			_, err := Load(path)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+
+	_, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "failed to read config")
+}
+
+func TestSymbolAllowed(t *testing.T) {
+	assert.True(t, (&Config{}).SymbolAllowed("CLF5"))
+
+	cfg := &Config{AllowedSymbols: []string{"ES", "NQH5"}}
+	assert.True(t, cfg.SymbolAllowed("ES"))
+	assert.True(t, cfg.SymbolAllowed("ESZ4"))
+	assert.True(t, cfg.SymbolAllowed("NQH5"))
+	assert.False(t, cfg.SymbolAllowed("NQM5"))
+	assert.False(t, cfg.SymbolAllowed("ESTX50"))
+	assert.False(t, cfg.SymbolAllowed("CLF5"))
+}
+
+func TestStoreReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeConfig(t, path, `{"riskLimits": {"maxOrderQuantity": 10}}`)
+
+	store, err := NewStore(path)
+	require.NoError(t, err)
+	assert.Equal(t, 10, store.Current().RiskLimits.MaxOrderQuantity)
+
+	writeConfig(t, path, `{"riskLimits": {"maxOrderQuantity": 2}}`)
+	cfg, err := store.Reload()
+	require.NoError(t, err)
+	assert.Equal(t, 2, cfg.RiskLimits.MaxOrderQuantity)
+	assert.Same(t, cfg, store.Current())
+
+	// A broken file keeps the previous configuration active.
+	writeConfig(t, path, `{"riskLimits":`)
+	_, err = store.Reload()
+	require.Error(t, err)
+	assert.Equal(t, 2, store.Current().RiskLimits.MaxOrderQuantity)
+}
+
+func TestNilStoreCurrent(t *testing.T) {
+	var store *Store
+	cfg := store.Current()
+	require.NotNil(t, cfg)
+	assert.Equal(t, slog.LevelInfo, cfg.Level())
+	assert.Zero(t, cfg.RiskLimits.MaxOrderQuantity)
+}
+
+func TestRateLimitsFor(t *testing.T) {
+	limits := RateLimits{
+		Tools: map[string]RateLimit{"placeOrder": {Limit: 5, Window: Duration{time.Minute}}},
+	}
+	limit, ok := limits.For("placeOrder")
+	assert.True(t, ok)
+	assert.Equal(t, 5, limit.Limit)
+	_, ok = limits.For("getPositions")
+	assert.False(t, ok)
+
+	limits.Default = &RateLimit{Limit: 60, Window: Duration{time.Minute}}
+	limit, ok = limits.For("getPositions")
+	assert.True(t, ok)
+	assert.Equal(t, 60, limit.Limit)
+}
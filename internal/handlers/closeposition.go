@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// ClosedPosition describes a position closed by closePosition.
+type ClosedPosition struct {
+	AccountID      int           `json:"accountId"`                // Account that held the position
+	ContractID     int           `json:"contractId"`               // Contract of the position
+	ClosedNetPos   int           `json:"closedNetPos"`             // Net position before closing; positive if long
+	CanceledOrders []int         `json:"canceledOrders,omitempty"` // Working orders in the contract cancelled first, such as protective stops and targets
+	Order          *models.Order `json:"order"`                    // Order closing the position
+	Fills          []models.Fill `json:"fills,omitempty"`          // Fills of the closing order so far
+}
+
+// closePositionRequest holds the parameters of closePosition.
+type closePositionRequest struct {
+	AccountID int `json:"accountId" validate:"required,gt=0" desc:"Account holding the position"`
+	contractRef
+}
+
+// handleClosePosition processes requests to close one position at market.
+// The account's working orders in the contract, such as the stop and target
+// of a bracket, are cancelled before the position is closed, so none can
+// fill afterwards and open a new position. If one cannot be cancelled, the
+// position is left open, and if the position then cannot be closed, it is
+// left without its protection: cancelled orders are not placed again. In
+// both cases the error lists the orders already cancelled.
+// Required parameters:
+// - accountId: (float64) The account holding the position
+// - contractId: (float64) The contract of the position, or
+// - symbol: (string) The contract, e.g. "ESZ4" or "ES front month", of one of the account's positions
+func handleClosePosition(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req closePositionRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		position, err := openPosition(ctx, client, req.AccountID, req.contractRef)
+		if err != nil {
+			return nil, err
+		}
+		contractID := position.ContractID
+		closed := ClosedPosition{AccountID: req.AccountID, ContractID: contractID, ClosedNetPos: position.NetPos}
+		working, err := workingOrders(ctx, client, req.AccountID, contractID)
+		if err != nil {
+			return nil, err
+		}
+		if closed.CanceledOrders, err = cancelOrders(ctx, client, working); err != nil {
+			return closed, unprotected(closed.CanceledOrders, err)
+		}
+
+		orderID, err := client.LiquidatePosition(ctx, req.AccountID, contractID)
+		if err != nil {
+			return closed, unprotected(closed.CanceledOrders, err)
+		}
+		closed.Order = &models.Order{ID: orderID, AccountID: req.AccountID, ContractID: contractID}
+		if err := checkRejected(ctx, client, closed.Order); err != nil {
+			return closed, unprotected(closed.CanceledOrders, err)
+		}
+		// As with liquidatePosition, the order may not have filled yet; its
+		// ID is enough to follow it up with getOrder and getFills.
+		if order, err := client.GetOrder(ctx, orderID); err == nil {
+			closed.Order = order
+		}
+		if fills, err := client.GetFills(ctx, orderID); err == nil {
+			closed.Fills = fills
+		}
+		return closed, nil
+	}
+}
+
+// openPosition returns the account's net position in the contract ref
+// names, with its average entry price. A symbol is first matched against
+// the contracts the account holds. It returns an error if the position is
+// flat.
+func openPosition(ctx context.Context, client client.TradovateClientInterface, accountID int, ref contractRef) (models.Position, error) {
+	contractID, err := ref.resolvePosition(ctx, client, accountID)
+	if err != nil {
+		return models.Position{}, err
+	}
+
+	positions, err := client.GetPositionsByAccount(ctx, accountID)
+	if err != nil {
+		return models.Position{}, fmt.Errorf("error fetching positions of account %d: %w", accountID, err)
+	}
+	open := models.Position{AccountID: accountID, ContractID: contractID}
+	for _, position := range positions {
+		if position.ContractID != contractID || position.NetPos == 0 {
+			continue
+		}
+		open.NetPos += position.NetPos
+		if open.AvgPrice == 0 {
+			open.AvgPrice = position.AvgPrice
+		}
+	}
+	if open.NetPos == 0 {
+		return models.Position{}, fmt.Errorf("account %d has no open position in contract %d", accountID, contractID)
+	}
+	return open, nil
+}
+
+// workingOrders returns the account's orders in a contract that can still
+// fill. Besides Working orders these include ones pending, being replaced
+// or suspended, such as a stop whose price is being moved.
+func workingOrders(ctx context.Context, client client.TradovateClientInterface, accountID, contractID int) ([]models.Order, error) {
+	orders, err := client.GetOrders(ctx, accountID, "")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching working orders of account %d: %w", accountID, err)
+	}
+	working := make([]models.Order, 0, len(orders))
+	for _, order := range orders {
+		if order.ContractID == contractID && !finalOrderStatuses[order.Status] {
+			working = append(working, order)
+		}
+	}
+	return working, nil
+}
+
+// cancelOrders cancels orders in turn and returns their IDs. It stops at
+// the first that cannot be cancelled, returning the IDs of those cancelled
+// before it.
+func cancelOrders(ctx context.Context, client client.TradovateClientInterface, orders []models.Order) ([]int, error) {
+	var canceled []int
+	for _, order := range orders {
+		if err := client.CancelOrder(ctx, order.ID); err != nil {
+			return canceled, fmt.Errorf("error cancelling order %d, position left open: %w", order.ID, err)
+		}
+		canceled = append(canceled, order.ID)
+	}
+	return canceled, nil
+}
+
+// unprotected adds to err the IDs of the orders cancelled before it, which
+// are left cancelled. Failed calls return no result, so the error is where
+// the caller learns that the position lost its stop and target.
+func unprotected(canceled []int, err error) error {
+	if len(canceled) == 0 {
+		return err
+	}
+	ids := make([]string, len(canceled))
+	for i, id := range canceled {
+		ids[i] = strconv.Itoa(id)
+	}
+	return fmt.Errorf("%w; working orders %s were already cancelled and have not been placed again", err, strings.Join(ids, ", "))
+}
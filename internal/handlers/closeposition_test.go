@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleClosePosition(t *testing.T) {
+	var calls []string
+	mockClient := &MockTradovateClient{
+		getPositionsByAccountFunc: func(accountID int) ([]models.Position, error) {
+			return []models.Position{
+				{AccountID: accountID, ContractID: 1234, NetPos: -2},
+				{AccountID: accountID, ContractID: 5678, NetPos: 1},
+			}, nil
+		},
+		getOrdersFunc: func(accountID int, status string) ([]models.Order, error) {
+			assert.Empty(t, status, "orders pending or being replaced are cancelled too")
+			return []models.Order{
+				{ID: 40, ContractID: 1234, OrderType: "Market", Side: "Sell", Quantity: 2, Status: "Filled"},
+				{ID: 41, ContractID: 1234, OrderType: "Stop", Side: "Buy", Quantity: 2, Status: "PendingReplace"},
+				{ID: 42, ContractID: 5678, OrderType: "Limit", Side: "Sell", Quantity: 1, Status: "Working"},
+				{ID: 43, ContractID: 1234, OrderType: "Limit", Side: "Buy", Quantity: 2, Status: "Working"},
+			}, nil
+		},
+		cancelOrderFunc: func(orderID int) error {
+			assert.NotEqual(t, 40, orderID, "filled orders are not cancelled")
+			calls = append(calls, "cancel")
+			return nil
+		},
+		liquidatePositionFunc: func(accountID, contractID int) (int, error) {
+			calls = append(calls, "liquidate")
+			assert.Equal(t, 1234, contractID)
+			return 99, nil
+		},
+		getOrderFunc: func(orderID int) (*models.Order, error) {
+			return &models.Order{ID: orderID, AccountID: 12345, ContractID: 1234, Side: "Buy", Quantity: 2, Status: "Filled", FilledQty: 2, AveragePrice: 5100.25}, nil
+		},
+		getFillsFunc: func(orderID int) ([]models.Fill, error) {
+			return []models.Fill{{ID: 501, OrderID: orderID, ContractID: 1234, Action: "Buy", Price: 5100.25, Quantity: 2}}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["closePosition"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234)})
+	require.NoError(t, err)
+	closed := result.(ClosedPosition)
+	assert.Equal(t, []string{"cancel", "cancel", "liquidate"}, calls, "protective orders are cancelled first")
+	assert.Equal(t, -2, closed.ClosedNetPos)
+	assert.Equal(t, []int{41, 43}, closed.CanceledOrders)
+	assert.Equal(t, "Filled", closed.Order.Status)
+	require.Len(t, closed.Fills, 1)
+	assert.Equal(t, 5100.25, closed.Fills[0].Price)
+
+	// A failed cancel leaves the position open.
+	calls = nil
+	mockClient.cancelOrderFunc = func(orderID int) error { return errors.New("status 500") }
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234)})
+	assert.EqualError(t, err, "error cancelling order 41, position left open: status 500")
+	assert.Empty(t, calls)
+
+	// Orders cancelled before a failure are reported, as they stay cancelled.
+	mockClient.cancelOrderFunc = func(orderID int) error {
+		if orderID == 43 {
+			return errors.New("status 500")
+		}
+		return nil
+	}
+	result, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234)})
+	assert.EqualError(t, err, "error cancelling order 43, position left open: status 500; working orders 41 were already cancelled and have not been placed again")
+	assert.Equal(t, []int{41}, result.(ClosedPosition).CanceledOrders)
+	assert.Empty(t, calls)
+}
+
+func TestClosePositionLiquidationFails(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		getPositionsByAccountFunc: func(accountID int) ([]models.Position, error) {
+			return []models.Position{{AccountID: accountID, ContractID: 1234, NetPos: 2}}, nil
+		},
+		getOrdersFunc: func(accountID int, status string) ([]models.Order, error) {
+			return []models.Order{
+				{ID: 41, ContractID: 1234, OrderType: "Stop", Side: "Sell", Quantity: 2},
+				{ID: 43, ContractID: 1234, OrderType: "Limit", Side: "Sell", Quantity: 2},
+			}, nil
+		},
+		liquidatePositionFunc: func(accountID, contractID int) (int, error) {
+			return 0, errors.New("status 500")
+		},
+	}
+	handler := NewHandlers(mockClient)["closePosition"].Handler
+	params := map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234)}
+
+	result, err := handler(context.Background(), params)
+	assert.EqualError(t, err, "status 500; working orders 41, 43 were already cancelled and have not been placed again")
+	assert.Equal(t, []int{41, 43}, result.(ClosedPosition).CanceledOrders)
+
+	mockClient.liquidatePositionFunc = func(accountID, contractID int) (int, error) { return 99, nil }
+	mockClient.getCommandReportsFunc = func(commandID int) ([]models.CommandReport, error) {
+		return []models.CommandReport{{CommandID: commandID, CommandStatus: "ExecutionRejected", RejectReason: "RiskCheck", Text: "Exceeds margin"}}, nil
+	}
+	result, err = handler(context.Background(), params)
+	assert.EqualError(t, err, "order 99 rejected: RiskCheck: Exceeds margin; working orders 41, 43 were already cancelled and have not been placed again")
+	assert.Equal(t, []int{41, 43}, result.(ClosedPosition).CanceledOrders)
+}
+
+func TestClosePositionInvalidParams(t *testing.T) {
+	handler := NewHandlers(&MockTradovateClient{})["closePosition"].Handler
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Missing account", map[string]interface{}{"contractId": float64(1234)}, "missing required field: accountId"},
+		{"Missing contract", map[string]interface{}{"accountId": float64(12345)}, "missing required field: contractId or symbol"},
+		{"Both contracts", map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234), "symbol": "ESZ4"}, "only one of contractId and symbol may be given"},
+		{"Flat", map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234)}, "account 12345 has no open position in contract 1234"},
+		{"Symbol not held", map[string]interface{}{"accountId": float64(12345), "symbol": "ESZ4"}, "account 12345 has no open position in ESZ4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// timeNow returns the current time. It is a variable so tests can pin the clock.
+var timeNow = time.Now
+
+// ExpiringPosition is an open position on a contract that expires within the
+// warning window.
+type ExpiringPosition struct {
+	models.Position
+	ContractName   string `json:"contractName"`   // Name of the expiring contract
+	ExpirationDate string `json:"expirationDate"` // Contract expiration timestamp
+}
+
+// ExpiringOrder is a working order on a contract that expires within the
+// warning window.
+type ExpiringOrder struct {
+	models.Order
+	ContractName   string `json:"contractName"`   // Name of the expiring contract
+	ExpirationDate string `json:"expirationDate"` // Contract expiration timestamp
+}
+
+// ExpiringExposure summarizes open positions and working orders approaching
+// contract expiry.
+type ExpiringExposure struct {
+	WarningDays int                `json:"warningDays"` // Window used to select expiring contracts
+	Positions   []ExpiringPosition `json:"positions"`   // Open positions within the window
+	Orders      []ExpiringOrder    `json:"orders"`      // Orders that can still fill within the window
+}
+
+// expiryLookup resolves contracts and their expirations through the Tradovate client,
+// caching results for the lifetime of a single handler call.
+type expiryLookup struct {
+	ctx         context.Context
+	client      client.TradovateClientInterface
+	warningDays int
+	contracts   map[int]*contractExpiry
+	resolved    map[int]*models.Contract
+}
+
+// contractExpiry is the cached expiry information for a single contract.
+type contractExpiry struct {
+	name       string
+	date       string
+	expiration time.Time
+}
+
+func newExpiryLookup(ctx context.Context, client client.TradovateClientInterface, warningDays int) *expiryLookup {
+	return &expiryLookup{
+		ctx:         ctx,
+		client:      client,
+		warningDays: warningDays,
+		contracts:   make(map[int]*contractExpiry),
+		resolved:    make(map[int]*models.Contract),
+	}
+}
+
+// contract returns contractID's contract, or nil if it cannot be looked up.
+// Like expiries, failures are cached.
+func (l *expiryLookup) contract(contractID int) *models.Contract {
+	if contract, ok := l.resolved[contractID]; ok {
+		return contract
+	}
+	contract, err := l.client.GetContract(l.ctx, contractID)
+	if err != nil {
+		contract = nil
+	}
+	l.resolved[contractID] = contract
+	return contract
+}
+
+// name returns the name of contractID, or "" if it cannot be looked up.
+func (l *expiryLookup) name(contractID int) string {
+	if contract := l.contract(contractID); contract != nil {
+		return contract.Name
+	}
+	return ""
+}
+
+// lookup returns the expiry of contractID, or nil if it cannot be determined.
+// Lookup failures are cached so each contract is only resolved once.
+func (l *expiryLookup) lookup(contractID int) *contractExpiry {
+	if expiry, ok := l.contracts[contractID]; ok {
+		return expiry
+	}
+	l.contracts[contractID] = nil
+
+	contract := l.contract(contractID)
+	if contract == nil || contract.ContractMaturityID == 0 {
+		return nil
+	}
+	maturity, err := l.client.GetContractMaturity(l.ctx, contract.ContractMaturityID)
+	if err != nil || maturity == nil {
+		return nil
+	}
+	expiration, err := parseExpirationDate(maturity.ExpirationDate)
+	if err != nil {
+		return nil
+	}
+
+	expiry := &contractExpiry{name: contract.Name, date: maturity.ExpirationDate, expiration: expiration}
+	l.contracts[contractID] = expiry
+	return expiry
+}
+
+// expiresInDays returns the whole days until contractID expires and whether
+// that falls inside the warning window.
+func (l *expiryLookup) expiresInDays(contractID int) (int, bool) {
+	expiry := l.lookup(contractID)
+	if expiry == nil {
+		return 0, false
+	}
+	days := int(math.Floor(expiry.expiration.Sub(timeNow()).Hours() / 24))
+	return days, days <= l.warningDays
+}
+
+// annotatePositions sets ExpiresInDays on positions whose contract is within
+// the warning window. Contracts whose expiry cannot be resolved are left as is.
+func (l *expiryLookup) annotatePositions(positions []models.Position) {
+	for i := range positions {
+		if days, ok := l.expiresInDays(positions[i].ContractID); ok {
+			positions[i].ExpiresInDays = &days
+		}
+	}
+}
+
+// annotateOrder sets ExpiresInDays on order if its contract is within the
+// warning window.
+func (l *expiryLookup) annotateOrder(order *models.Order) {
+	if order == nil {
+		return
+	}
+	if days, ok := l.expiresInDays(order.ContractID); ok {
+		order.ExpiresInDays = &days
+	}
+}
+
+// parseExpirationDate parses a Tradovate expiration timestamp, accepting both
+// full timestamps and bare dates.
+func parseExpirationDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// getExpiringExposureRequest holds the parameters of getExpiringExposure.
+type getExpiringExposureRequest struct {
+	Days *int `json:"days" validate:"gte=0" desc:"Warning window in days (default: the server's -expiry-warning-days)"`
+}
+
+// handleGetExpiringExposure processes expiring exposure requests. It lists
+// the open positions and the orders that can still fill on contracts
+// expiring within the window.
+// Optional parameters:
+// - days: (float64) Warning window in days, defaults to the configured window
+func handleGetExpiringExposure(client client.TradovateClientInterface, o options) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req getExpiringExposureRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		warningDays := o.expiryWarningDays
+		if req.Days != nil {
+			warningDays = *req.Days
+		}
+
+		positions, err := client.GetPositions(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		orders, err := client.GetOrders(ctx, 0, "")
+		if err != nil {
+			return nil, err
+		}
+
+		lookup := newExpiryLookup(ctx, client, warningDays)
+		exposure := ExpiringExposure{WarningDays: warningDays, Positions: []ExpiringPosition{}, Orders: []ExpiringOrder{}}
+		for _, position := range positions {
+			if position.NetPos == 0 {
+				continue
+			}
+			days, ok := lookup.expiresInDays(position.ContractID)
+			if !ok {
+				continue
+			}
+			position.ExpiresInDays = &days
+			expiry := lookup.lookup(position.ContractID)
+			exposure.Positions = append(exposure.Positions, ExpiringPosition{
+				Position:       position,
+				ContractName:   expiry.name,
+				ExpirationDate: expiry.date,
+			})
+		}
+		for _, order := range orders {
+			if finalOrderStatuses[order.Status] {
+				continue
+			}
+			days, ok := lookup.expiresInDays(order.ContractID)
+			if !ok {
+				continue
+			}
+			order.ExpiresInDays = &days
+			expiry := lookup.lookup(order.ContractID)
+			exposure.Orders = append(exposure.Orders, ExpiringOrder{
+				Order:          order,
+				ContractName:   expiry.name,
+				ExpirationDate: expiry.date,
+			})
+		}
+
+		return exposure, nil
+	}
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExpiryMock returns a mock client where contract 1 expires in three days,
+// contract 2 in thirty days, and contract 3 cannot be resolved.
+func newExpiryMock(positions []models.Position) *MockTradovateClient {
+	now := time.Date(2024, 12, 17, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+
+	expirations := map[int]string{
+		101: now.Add(3*24*time.Hour + time.Hour).Format(time.RFC3339),
+		102: now.Add(30 * 24 * time.Hour).Format("2006-01-02"),
+	}
+
+	return &MockTradovateClient{
+		getPositionsFunc: func() ([]models.Position, error) {
+			return positions, nil
+		},
+		getContractFunc: func(contractID int) (*models.Contract, error) {
+			if contractID == 3 {
+				return nil, errors.New("not found")
+			}
+			return &models.Contract{ID: contractID, Name: "ESZ4", ContractMaturityID: 100 + contractID}, nil
+		},
+		getMaturityFunc: func(maturityID int) (*models.ContractMaturity, error) {
+			return &models.ContractMaturity{ID: maturityID, ExpirationDate: expirations[maturityID]}, nil
+		},
+	}
+}
+
+func TestGetPositionsExpiryAnnotation(t *testing.T) {
+	defer func() { timeNow = time.Now }()
+
+	mockClient := newExpiryMock([]models.Position{
+		{ID: 1, ContractID: 1, NetPos: 2},
+		{ID: 2, ContractID: 2, NetPos: 1},
+		{ID: 3, ContractID: 3, NetPos: 1},
+	})
+
+	result, err := NewHandlers(mockClient)["getPositions"].Handler(context.Background(), nil)
+	require.NoError(t, err)
+
+	positions := result.([]models.Position)
+	require.NotNil(t, positions[0].ExpiresInDays)
+	assert.Equal(t, 3, *positions[0].ExpiresInDays)
+	assert.Nil(t, positions[1].ExpiresInDays)
+	assert.Nil(t, positions[2].ExpiresInDays)
+
+	result, err = NewHandlers(mockClient, WithExpiryWarningDays(45))["getPositions"].Handler(context.Background(), nil)
+	require.NoError(t, err)
+	positions = result.([]models.Position)
+	require.NotNil(t, positions[1].ExpiresInDays)
+	assert.Equal(t, 29, *positions[1].ExpiresInDays)
+}
+
+func TestPlaceOrderExpiryAnnotation(t *testing.T) {
+	defer func() { timeNow = time.Now }()
+
+	mockClient := newExpiryMock(nil)
+	mockClient.placeOrderFunc = func(order models.Order) (*models.Order, error) {
+		order.ID = 1
+		return &order, nil
+	}
+
+	result, err := NewHandlers(mockClient)["placeOrder"].Handler(context.Background(), map[string]interface{}{
+		"accountId":   float64(1),
+		"contractId":  float64(1),
+		"orderType":   "Market",
+		"quantity":    float64(1),
+		"timeInForce": "Day",
+	})
+	require.NoError(t, err)
+
+	order := result.(*models.Order)
+	require.NotNil(t, order.ExpiresInDays)
+	assert.Equal(t, 3, *order.ExpiresInDays)
+}
+
+func TestListOrdersExpiryAnnotation(t *testing.T) {
+	defer func() { timeNow = time.Now }()
+
+	mockClient := newExpiryMock(nil)
+	mockClient.getOrdersFunc = func(accountID int, status string) ([]models.Order, error) {
+		return []models.Order{
+			{ID: 1, AccountID: accountID, ContractID: 1, Status: "Working"},
+			{ID: 2, AccountID: accountID, ContractID: 2, Status: "Working"},
+			{ID: 3, AccountID: accountID, ContractID: 3, Status: "Working"},
+		}, nil
+	}
+
+	result, err := NewHandlers(mockClient)["listOrders"].Handler(context.Background(), map[string]interface{}{"accountId": float64(1)})
+	require.NoError(t, err)
+
+	orders := result.([]models.Order)
+	require.Len(t, orders, 3)
+	require.NotNil(t, orders[0].ExpiresInDays)
+	assert.Equal(t, 3, *orders[0].ExpiresInDays)
+	assert.Nil(t, orders[1].ExpiresInDays)
+	assert.Nil(t, orders[2].ExpiresInDays)
+}
+
+func TestHandleGetExpiringExposure(t *testing.T) {
+	defer func() { timeNow = time.Now }()
+
+	mockClient := newExpiryMock([]models.Position{
+		{ID: 1, ContractID: 1, NetPos: 2},
+		{ID: 2, ContractID: 2, NetPos: -1},
+		{ID: 3, ContractID: 1, NetPos: 0},
+	})
+	handler := NewHandlers(mockClient)["getExpiringExposure"].Handler
+
+	result, err := handler(context.Background(), nil)
+	require.NoError(t, err)
+	exposure := result.(ExpiringExposure)
+	assert.Equal(t, defaultExpiryWarningDays, exposure.WarningDays)
+	require.Len(t, exposure.Positions, 1)
+	assert.Equal(t, 1, exposure.Positions[0].ID)
+	assert.Equal(t, "ESZ4", exposure.Positions[0].ContractName)
+	assert.Empty(t, exposure.Orders)
+
+	mockClient.getOrdersFunc = func(accountID int, status string) ([]models.Order, error) {
+		assert.Zero(t, accountID, "orders of every account are checked")
+		return []models.Order{
+			{ID: 11, ContractID: 1, OrderType: "Stop", Status: "Working"},
+			{ID: 12, ContractID: 1, OrderType: "Limit", Status: "Filled"},
+			{ID: 13, ContractID: 2, OrderType: "Limit", Status: "PendingNew"},
+			{ID: 14, ContractID: 3, OrderType: "Limit", Status: "Working"},
+		}, nil
+	}
+	result, err = handler(context.Background(), nil)
+	require.NoError(t, err)
+	exposure = result.(ExpiringExposure)
+	require.Len(t, exposure.Orders, 1, "filled orders and later or unknown expiries are left out")
+	assert.Equal(t, 11, exposure.Orders[0].ID)
+	assert.Equal(t, "ESZ4", exposure.Orders[0].ContractName)
+	require.NotNil(t, exposure.Orders[0].ExpiresInDays)
+	assert.Equal(t, 3, *exposure.Orders[0].ExpiresInDays)
+
+	result, err = handler(context.Background(), map[string]interface{}{"days": float64(31)})
+	require.NoError(t, err)
+	assert.Len(t, result.(ExpiringExposure).Positions, 2)
+	assert.Len(t, result.(ExpiringExposure).Orders, 2)
+
+	_, err = handler(context.Background(), map[string]interface{}{"days": "soon"})
+	assert.Error(t, err)
+
+	_, err = handler(context.Background(), map[string]interface{}{"days": float64(-1)})
+	assert.Error(t, err)
+
+	mockClient.getOrdersFunc = func(int, string) ([]models.Order, error) {
+		return nil, errors.New("client error")
+	}
+	_, err = handler(context.Background(), nil)
+	assert.Error(t, err)
+
+	mockClient.getPositionsFunc = func() ([]models.Position, error) {
+		return nil, errors.New("client error")
+	}
+	_, err = handler(context.Background(), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+const (
+	// maxExports caps the exports kept for reading; older ones are dropped.
+	maxExports = 20
+	// exportURIPrefix starts the URI of every export resource.
+	exportURIPrefix = "tradovate://exports/"
+	// csvMimeType is the MIME type of exported data.
+	csvMimeType = "text/csv"
+)
+
+// Resource describes data the MCP client can read with resources/read.
+type Resource struct {
+	URI      string `json:"uri"`      // Identifies the resource to resources/read
+	Name     string `json:"name"`     // Human-readable name
+	MimeType string `json:"mimeType"` // Format of the contents
+	Size     int    `json:"size"`     // Length of the contents in bytes
+}
+
+// ResourceContents is a resource as read by resources/read.
+type ResourceContents struct {
+	URI      string `json:"uri"`      // Resource read
+	MimeType string `json:"mimeType"` // Format of Text
+	Text     string `json:"text"`     // The contents
+}
+
+// Exports keeps exported data for the MCP client to read as resources. It
+// holds the newest maxExports exports in memory.
+type Exports struct {
+	mu        sync.Mutex
+	nextID    int
+	resources []Resource
+	contents  map[string]string
+}
+
+// NewExports returns an empty export store.
+func NewExports() *Exports {
+	return &Exports{contents: make(map[string]string)}
+}
+
+// add stores text as a new resource named name and returns it.
+func (e *Exports) add(name, mimeType, text string) Resource {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.nextID++
+	resource := Resource{
+		URI:      fmt.Sprintf("%s%d", exportURIPrefix, e.nextID),
+		Name:     name,
+		MimeType: mimeType,
+		Size:     len(text),
+	}
+	e.resources = append(e.resources, resource)
+	e.contents[resource.URI] = text
+	if len(e.resources) > maxExports {
+		delete(e.contents, e.resources[0].URI)
+		e.resources = e.resources[1:]
+	}
+	return resource
+}
+
+// List returns the exports that can be read, oldest first.
+func (e *Exports) List() []Resource {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Resource{}, e.resources...)
+}
+
+// Read returns the contents of the export at uri.
+func (e *Exports) Read(uri string) (*ResourceContents, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, resource := range e.resources {
+		if resource.URI == uri {
+			return &ResourceContents{URI: uri, MimeType: resource.MimeType, Text: e.contents[uri]}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown resource %q", uri)
+}
+
+// WithExports keeps exported data in exports, for the server to serve as
+// resources. Without it, exports are kept where nothing reads them unless
+// written to a file.
+func WithExports(exports *Exports) Option {
+	return func(o *options) {
+		o.exports = exports
+	}
+}
+
+// HistoricalExport reports where exported historical data went.
+type HistoricalExport struct {
+	URI      string `json:"uri,omitempty"`  // Resource to read the CSV from, when not written to a file
+	Path     string `json:"path,omitempty"` // File the CSV was written to, if asked for
+	MimeType string `json:"mimeType"`       // Format of the export: text/csv
+	Rows     int    `json:"rows"`           // Bars exported, not counting the header
+	Bytes    int    `json:"bytes"`          // Size of the CSV
+}
+
+// exportHistoricalDataRequest holds the parameters of exportHistoricalData.
+type exportHistoricalDataRequest struct {
+	getHistoricalDataRequest
+	Path string `json:"path" desc:"File to write the CSV to; it must not exist. Without it the CSV is kept as a resource"`
+}
+
+// handleExportHistoricalData processes requests to export historical data
+// as CSV rather than return it inline. The CSV has a header row and a row
+// per bar: its start time in RFC 3339 format, in UTC, then its prices and
+// volume. It is written to path if given, and kept as a resource for
+// resources/read otherwise.
+// Required parameters:
+// - contractId: (float64) The contract ID to get data for, or
+// - symbol: (string) The contract to get data for, e.g. "MESZ4", "ES" or "ES front month"
+// - startTime: (string) Start time in RFC3339 format, or relative as parseTime reads it
+// - endTime: (string) End time in RFC3339 format, or relative
+// - interval: (string) Time interval for data points
+// Optional parameters:
+// - path: (string) The file to write the CSV to, which must not exist
+func handleExportHistoricalData(client client.TradovateClientInterface, o options) interface{} {
+	fetch := handleGetHistoricalData(client).(func(context.Context, map[string]interface{}) (interface{}, error))
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req exportHistoricalDataRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		result, err := fetch(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		bars := result.([]models.HistoricalData)
+		text, err := historicalCSV(bars)
+		if err != nil {
+			return nil, err
+		}
+
+		export := HistoricalExport{MimeType: csvMimeType, Rows: len(bars), Bytes: len(text)}
+		if req.Path != "" {
+			if err := writeNewFile(req.Path, text); err != nil {
+				return nil, err
+			}
+			export.Path = req.Path
+			return export, nil
+		}
+		name := fmt.Sprintf("%s bars of %s from %s to %s", req.Interval, contractName(req.contractRef), req.StartTime, req.EndTime)
+		export.URI = o.exports.add(name, csvMimeType, text).URI
+		return export, nil
+	}
+}
+
+// historicalCSV formats bars as CSV.
+func historicalCSV(bars []models.HistoricalData) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"time", "contractId", "open", "high", "low", "close", "volume"})
+	price := func(p float64) string { return strconv.FormatFloat(p, 'f', -1, 64) }
+	for _, bar := range bars {
+		w.Write([]string{
+			time.Unix(bar.Timestamp, 0).UTC().Format(time.RFC3339),
+			strconv.Itoa(bar.ContractID),
+			price(bar.Open),
+			price(bar.High),
+			price(bar.Low),
+			price(bar.Close),
+			strconv.Itoa(bar.Volume),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("error writing CSV: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// writeNewFile writes text to a file at path that must not already exist,
+// so an export never overwrites anything.
+func writeNewFile(path, text string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("error creating export file: %w", err)
+	}
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing export file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error writing export file: %w", err)
+	}
+	return nil
+}
+
+// contractName names the contract ref refers to, for describing exports.
+func contractName(ref contractRef) string {
+	if ref.Symbol != "" {
+		return ref.Symbol
+	}
+	if ref.ContractID != nil {
+		return fmt.Sprintf("contract %d", *ref.ContractID)
+	}
+	return "contract"
+}
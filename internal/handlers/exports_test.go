@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleExportHistoricalData(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		getHistoricalDataFunc: func(contractID int, startTime, endTime time.Time, interval string) ([]models.HistoricalData, error) {
+			return []models.HistoricalData{
+				{ContractID: contractID, Timestamp: 1710864000, Open: 5100, High: 5102.25, Low: 5099.5, Close: 5101, Volume: 42},
+				{ContractID: contractID, Timestamp: 1710867600, Open: 5101, High: 5101, Low: 5098, Close: 5098.75, Volume: 17},
+			}, nil
+		},
+	}
+	exports := NewExports()
+	handler := NewHandlers(mockClient, WithExports(exports))["exportHistoricalData"].Handler
+	params := map[string]interface{}{
+		"contractId": float64(1234),
+		"startTime":  "2024-03-19T00:00:00Z",
+		"endTime":    "2024-03-20T00:00:00Z",
+		"interval":   "1h",
+	}
+	want := "time,contractId,open,high,low,close,volume\n" +
+		"2024-03-19T16:00:00Z,1234,5100,5102.25,5099.5,5101,42\n" +
+		"2024-03-19T17:00:00Z,1234,5101,5101,5098,5098.75,17\n"
+
+	result, err := handler(context.Background(), params)
+	require.NoError(t, err)
+	export := result.(HistoricalExport)
+	assert.Equal(t, HistoricalExport{URI: "tradovate://exports/1", MimeType: "text/csv", Rows: 2, Bytes: len(want)}, export)
+	assert.Equal(t, []Resource{{
+		URI:      "tradovate://exports/1",
+		Name:     "1h bars of contract 1234 from 2024-03-19T00:00:00Z to 2024-03-20T00:00:00Z",
+		MimeType: "text/csv",
+		Size:     len(want),
+	}}, exports.List())
+	contents, err := exports.Read(export.URI)
+	require.NoError(t, err)
+	assert.Equal(t, want, contents.Text)
+
+	// Written to a file, the export is not kept as a resource.
+	path := filepath.Join(t.TempDir(), "es.csv")
+	params["path"] = path
+	result, err = handler(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, HistoricalExport{Path: path, MimeType: "text/csv", Rows: 2, Bytes: len(want)}, result)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, string(data))
+	assert.Len(t, exports.List(), 1)
+
+	_, err = handler(context.Background(), params)
+	assert.ErrorContains(t, err, "error creating export file", "existing files are not overwritten")
+
+	delete(params, "path")
+	delete(params, "interval")
+	_, err = handler(context.Background(), params)
+	assert.EqualError(t, err, "missing required field: interval")
+}
+
+func TestExportsKeepsNewest(t *testing.T) {
+	exports := NewExports()
+	for i := 1; i <= maxExports+2; i++ {
+		exports.add(fmt.Sprintf("export %d", i), csvMimeType, "a,b\n")
+	}
+	resources := exports.List()
+	require.Len(t, resources, maxExports)
+	assert.Equal(t, "tradovate://exports/3", resources[0].URI)
+
+	_, err := exports.Read("tradovate://exports/2")
+	assert.EqualError(t, err, `unknown resource "tradovate://exports/2"`)
+	contents, err := exports.Read("tradovate://exports/3")
+	require.NoError(t, err)
+	assert.Equal(t, ResourceContents{URI: "tradovate://exports/3", MimeType: "text/csv", Text: "a,b\n"}, *contents)
+}
@@ -4,339 +4,2000 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/config"
 	"github.com/0xjmp/mcp-tradovate/internal/models"
 )
 
 // Handler represents a request handler with its description and implementation.
 type Handler struct {
-	Description string                                            // Human-readable description of the handler's purpose
-	Handler     func(map[string]interface{}) (interface{}, error) // Function that processes the request
+	Description string                                                             // Human-readable description of the handler's purpose
+	Params      *Schema                                                            // Parameters the handler takes, if declared
+	Paged       bool                                                               // Whether to publish the limit and cursor parameters of its list result
+	Handler     func(context.Context, map[string]interface{}) (interface{}, error) // Function that processes the request
+}
+
+// InputSchema returns the schema of the handler's parameters, with those of
+// pagination if it is paged. Handlers that do not declare theirs accept any
+// object.
+func (h Handler) InputSchema() *Schema {
+	schema := h.Params
+	if schema == nil {
+		schema = &Schema{Type: "object", Properties: map[string]*Property{}}
+	}
+	if h.Paged {
+		schema = schema.withPageParams()
+	}
+	return schema
 }
 
 // Handlers is a map of handler names to their implementations.
 type Handlers map[string]Handler
 
+// Option configures optional behaviour of the handlers returned by NewHandlers.
+type Option func(*options)
+
+// options holds the settings shared by all handlers.
+type options struct {
+	expiryWarningDays int           // Annotate contracts expiring within this many days
+	config            *config.Store // Reloadable risk limits and symbol whitelist
+	exports           *Exports      // Exported data, served as resources
+	journal           *Journal      // Trade annotations
+	transport         string        // MCP transport the server answers on
+	startedAt         time.Time     // When the handlers were created
+}
+
+// defaultExpiryWarningDays is the default window for contract expiry warnings.
+const defaultExpiryWarningDays = 5
+
+// WithExpiryWarningDays sets how many days before expiry positions and orders
+// are annotated with an expiresInDays warning.
+func WithExpiryWarningDays(days int) Option {
+	return func(o *options) {
+		o.expiryWarningDays = days
+	}
+}
+
+// WithConfig enforces the risk limits and symbol whitelist of the active
+// configuration in store. Changes picked up by a reload apply to the next call.
+func WithConfig(store *config.Store) Option {
+	return func(o *options) {
+		o.config = store
+	}
+}
+
+// WithTransport names the MCP transport the server answers on, reported by
+// getServerStatus.
+func WithTransport(transport string) Option {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
 // NewHandlers creates a new set of handlers using the provided Tradovate client.
 // It initializes all available handlers with their descriptions and implementations.
-func NewHandlers(client client.TradovateClientInterface) Handlers {
+func NewHandlers(client client.TradovateClientInterface, opts ...Option) Handlers {
+	o := options{expiryWarningDays: defaultExpiryWarningDays, exports: NewExports(), journal: newJournal(""), startedAt: timeNow()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	subs := &quoteSubscriptions{}
+	alerts := &priceAlerts{}
+
 	return map[string]Handler{
 		"authenticate": {
 			Description: "Authenticate with Tradovate API",
-			Handler: func(params map[string]interface{}) (interface{}, error) {
-				return handleAuthenticate(client)
+			Params:      schemaOf(noParams{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				return handleAuthenticate(ctx, client)
+			},
+		},
+		"getAuthStatus": {
+			Description: "Report whether the server is authenticated with Tradovate and when its token expires",
+			Params:      schemaOf(noParams{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				return authStatus(client, time.Now().Add(client.ClockSkew())), nil
+			},
+		},
+		"getServerTime": {
+			Description: "Get Tradovate's current time and how far the local clock is off from it",
+			Params:      schemaOf(noParams{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				return handleGetServerTime(ctx, client)
 			},
 		},
+		"getServerStatus": {
+			Description: "Report the server's uptime, transport, environment, authentication, WebSocket connections, subscriptions, alerts and order risk checks",
+			Params:      schemaOf(noParams{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				return serverStatus(ctx, client, o, subs, alerts, timeNow()), nil
+			},
+		},
+		"getRateLimitStatus": {
+			Description: "Report recent Tradovate API requests per endpoint class and any rate limit penalty in force, to pace further calls",
+			Params:      schemaOf(noParams{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				return client.RateLimitStatus(), nil
+			},
+		},
+		"selectAccount": {
+			Description: "Select the default account, by ID or name, for this session's calls that omit accountId. Not available over the HTTP transport, which keeps no session: give accountId with each call there",
+			Params:      schemaOf(selectAccountRequest{}),
+			Handler:     handleSelectAccount(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
 		"getAccounts": {
 			Description: "Get all accounts for the authenticated user",
-			Handler: func(params map[string]interface{}) (interface{}, error) {
-				return client.GetAccounts()
+			Params:      schemaOf(noParams{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				return client.GetAccounts(ctx)
 			},
 		},
 		"getPositions": {
-			Description: "Get current positions",
-			Handler: func(params map[string]interface{}) (interface{}, error) {
-				return client.GetPositions()
-			},
+			Description: "Get current positions with their contract symbols, optionally for a single account or symbol, filtered and sorted",
+			Params:      schemaOf(getPositionsRequest{}),
+			Handler:     handleGetPositions(client, o).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"getExpiringExposure": {
+			Description: "Get open positions and working orders on contracts approaching expiry",
+			Params:      schemaOf(getExpiringExposureRequest{}),
+			Handler:     handleGetExpiringExposure(client, o).(func(context.Context, map[string]interface{}) (interface{}, error)),
 		},
 		"placeOrder": {
 			Description: "Place a new order",
-			Handler:     handlePlaceOrder(client).(func(map[string]interface{}) (interface{}, error)),
+			Params:      schemaOf(placeOrderRequest{}),
+			Handler:     handlePlaceOrder(client, o).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"placeOcoOrder": {
+			Description: "Place a take-profit and a stop-loss order where filling one cancels the other",
+			Params:      schemaOf(placeOcoOrderRequest{}),
+			Handler:     handlePlaceOcoOrder(client, o).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"placeBracketOrder": {
+			Description: "Place an entry order with an attached take-profit and stop-loss",
+			Params:      schemaOf(placeBracketOrderRequest{}),
+			Handler:     handlePlaceBracketOrder(client, o).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"riskCheck": {
+			Description: "Preview an order without placing it: whether it breaks local or broker risk limits (position size, day loss, margin) and the position it would leave",
+			Params:      schemaOf(riskCheckRequest{}),
+			Handler:     handleRiskCheck(client, o).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"marginPreview": {
+			Description: "Estimate the margin an order would take or free and the buying power left after it, netted against the current position, without placing it",
+			Params:      schemaOf(marginPreviewRequest{}),
+			Handler:     handleMarginPreview(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"modifyOrder": {
+			Description: "Modify the price, quantity or type of a working order without cancelling it",
+			Params:      schemaOf(modifyOrderRequest{}),
+			Handler:     handleModifyOrder(client, o).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"liquidatePosition": {
+			Description: "Flatten an account's position in a contract at market and cancel its working orders there",
+			Params:      schemaOf(liquidatePositionRequest{}),
+			Handler:     handleLiquidatePosition(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"closePosition": {
+			Description: "Close one position at market, cancelling the working orders in its contract, such as protective stops, first",
+			Params:      schemaOf(closePositionRequest{}),
+			Handler:     handleClosePosition(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"reversePosition": {
+			Description: "Flip a position, e.g. long 2 to short 2, with one market order, optionally carrying its stop and target offsets over to the new position",
+			Params:      schemaOf(reversePositionRequest{}),
+			Handler:     handleReversePosition(client, o).(func(context.Context, map[string]interface{}) (interface{}, error)),
 		},
 		"cancelOrder": {
 			Description: "Cancel an existing order",
-			Handler: func(params map[string]interface{}) (interface{}, error) {
-				orderID := int(params["orderId"].(float64))
-				if err := client.CancelOrder(orderID); err != nil {
+			Params:      schemaOf(orderRequest{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				var req orderRequest
+				if err := decodeParams(params, &req); err != nil {
+					return nil, err
+				}
+				if err := client.CancelOrder(ctx, req.OrderID); err != nil {
 					return nil, err
 				}
 				return map[string]bool{"success": true}, nil
 			},
 		},
+		"getOrder": {
+			Description: "Get an order's current status, filled quantity and average fill price",
+			Params:      schemaOf(orderRequest{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				var req orderRequest
+				if err := decodeParams(params, &req); err != nil {
+					return nil, err
+				}
+				return client.GetOrder(ctx, req.OrderID)
+			},
+		},
+		"listOrders": {
+			Description: "List today's orders, optionally only those of an account, status or contract, open or placed since a time, sorted",
+			Params:      schemaOf(listOrdersRequest{}),
+			Paged:       true,
+			Handler:     handleListOrders(client, o).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"getExecutionReports": {
+			Description: "Get the exchange acknowledgements, fills and rejects of an order or account, with reject reasons",
+			Params:      schemaOf(getExecutionReportsRequest{}),
+			Handler:     handleGetExecutionReports(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"annotateTrade": {
+			Description: "Attach a note, tags or a strategy label to an order or fill in the local trade journal",
+			Params:      schemaOf(annotateTradeRequest{}),
+			Handler:     handleAnnotateTrade(o.journal).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"getJournal": {
+			Description: "Get an account's fills with the notes, tags and strategies attached to them and their orders, optionally filtered by tag or strategy",
+			Params:      schemaOf(getJournalRequest{}),
+			Paged:       true,
+			Handler:     handleGetJournal(client, o.journal).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"getCommandHistory": {
+			Description: "Get the commands sent on an order or account's orders, with rejected and risk-blocked ones and their failure text",
+			Params:      schemaOf(getCommandHistoryRequest{}),
+			Handler:     handleGetCommandHistory(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
 		"getFills": {
 			Description: "Get fills for a specific order",
-			Handler: func(params map[string]interface{}) (interface{}, error) {
-				orderID := int(params["orderId"].(float64))
-				return client.GetFills(orderID)
+			Params:      schemaOf(orderRequest{}),
+			Paged:       true,
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				var req orderRequest
+				if err := decodeParams(params, &req); err != nil {
+					return nil, err
+				}
+				return client.GetFills(ctx, req.OrderID)
 			},
 		},
+		"getFillsByAccount": {
+			Description: "Get an account's fills between two times, today's by default, with the symbols traded",
+			Params:      schemaOf(getFillsByAccountRequest{}),
+			Handler:     handleGetFillsByAccount(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
 		"getContracts": {
 			Description: "Get available contracts",
-			Handler: func(params map[string]interface{}) (interface{}, error) {
-				return client.GetContracts()
+			Params:      schemaOf(noParams{}),
+			Paged:       true,
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				return client.GetContracts(ctx)
+			},
+		},
+		"getProducts": {
+			Description: "Get the product catalog with tick sizes, point values, listed months and trading hours",
+			Params:      schemaOf(getProductsRequest{}),
+			Handler:     handleGetProducts(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"resolveFrontMonth": {
+			Description: "Find the currently active contract of a product such as ES or NQ, accounting for rolls",
+			Params:      schemaOf(resolveFrontMonthRequest{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				var req resolveFrontMonthRequest
+				if err := decodeParams(params, &req); err != nil {
+					return nil, err
+				}
+				return client.ResolveFrontMonth(ctx, req.Symbol)
+			},
+		},
+		"searchContracts": {
+			Description: "Find contracts by symbol or product name, e.g. MNQ or micro nasdaq, most relevant first",
+			Params:      schemaOf(searchContractsRequest{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				var req searchContractsRequest
+				if err := decodeParams(params, &req); err != nil {
+					return nil, err
+				}
+				return client.SearchContracts(ctx, req.Text, req.MaxResults)
 			},
 		},
 		"getMarketData": {
 			Description: "Get real-time market data for a contract",
-			Handler:     handleGetMarketData(client).(func(map[string]interface{}) (interface{}, error)),
+			Params:      schemaOf(getMarketDataRequest{}),
+			Handler:     handleGetMarketData(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"getQuotes": {
+			Description: "Get real-time market data for several contracts at once by ID or symbol, e.g. a watchlist",
+			Params:      schemaOf(getQuotesRequest{}),
+			Handler:     handleGetQuotes(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"subscribeMarketData": {
+			Description: "Stream a contract's quotes, throttled, as notifications and into a buffer read with readMarketData",
+			Params:      schemaOf(subscribeMarketDataRequest{}),
+			Handler:     handleSubscribeMarketData(client, subs).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"readMarketData": {
+			Description: "Read the quotes buffered by a market data subscription",
+			Params:      schemaOf(readMarketDataRequest{}),
+			Handler:     handleReadMarketData(subs).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"unsubscribeMarketData": {
+			Description: "Stop a market data subscription",
+			Params:      schemaOf(subscriptionRequest{}),
+			Handler:     handleUnsubscribeMarketData(subs).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"listSubscriptions": {
+			Description: "List the streaming subscriptions this session has open, with their age and update counts",
+			Params:      schemaOf(noParams{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				return subs.list(ctx, time.Now()), nil
+			},
+		},
+		"createPriceAlert": {
+			Description: "Watch a contract and send a notification, and optionally post to a webhook, once its last price reaches a threshold",
+			Params:      schemaOf(createPriceAlertRequest{}),
+			Handler:     handleCreatePriceAlert(client, alerts).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"listAlerts": {
+			Description: "List the price alerts, active and triggered",
+			Params:      schemaOf(noParams{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				return alerts.list(), nil
+			},
+		},
+		"deleteAlert": {
+			Description: "Stop and forget a price alert",
+			Params:      schemaOf(deleteAlertRequest{}),
+			Handler:     handleDeleteAlert(alerts).(func(context.Context, map[string]interface{}) (interface{}, error)),
 		},
 		"getHistoricalData": {
 			Description: "Get historical price data for a contract",
-			Handler:     handleGetHistoricalData(client).(func(map[string]interface{}) (interface{}, error)),
+			Params:      schemaOf(getHistoricalDataRequest{}),
+			Paged:       true,
+			Handler:     handleGetHistoricalData(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"exportHistoricalData": {
+			Description: "Export historical price data as CSV to a file, or to a resource read with resources/read, instead of returning the bars inline",
+			Params:      schemaOf(exportHistoricalDataRequest{}),
+			Handler:     handleExportHistoricalData(client, o).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"resampleHistoricalData": {
+			Description: "Get historical bars at any interval, e.g. 15m or 4h, built from finer bars and aligned to a time zone and trading sessions",
+			Params:      schemaOf(resampleHistoricalDataRequest{}),
+			Paged:       true,
+			Handler:     handleResampleHistoricalData(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
 		},
 		"setRiskLimits": {
 			Description: "Set risk limits for an account",
-			Handler:     handleSetRiskLimits(client).(func(map[string]interface{}) (interface{}, error)),
+			Params:      schemaOf(setRiskLimitsRequest{}),
+			Handler:     handleSetRiskLimits(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"getAccountPermissions": {
+			Description: "Check whether an account can be traded, or is view-only or liquidation-only",
+			Params:      schemaOf(accountRequest{}),
+			Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+				params = withActiveAccount(ctx, params)
+				var req accountRequest
+				if err := decodeParams(params, &req); err != nil {
+					return nil, err
+				}
+				return client.GetAccountPermissions(ctx, req.AccountID)
+			},
+		},
+		"getCashBalance": {
+			Description: "Get an account's real-time cash balance, open P&L and P&L realized today",
+			Params:      schemaOf(accountRequest{}),
+			Handler:     handleGetCashBalance(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"getMarginSnapshot": {
+			Description: "Get an account's margin usage and available buying power",
+			Params:      schemaOf(accountRequest{}),
+			Handler:     handleGetMarginSnapshot(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"getAccountSummary": {
+			Description: "Get an account's balance, P&L, margin, open positions with live P&L and working orders in one call; a good first call of a session",
+			Params:      schemaOf(accountRequest{}),
+			Handler:     handleGetAccountSummary(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"portfolioSummary": {
+			Description: "Roll up all accounts: equity, day P&L, open risk to stops, margin utilization and position concentration",
+			Params:      schemaOf(noParams{}),
+			Handler:     handlePortfolioSummary(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"aggregatedPnL": {
+			Description: "Total realized and unrealized P&L for the day or week, per account and, for the day, per contract",
+			Params:      schemaOf(aggregatedPnLRequest{}),
+			Handler:     handleAggregatedPnL(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
 		},
 		"getRiskLimits": {
 			Description: "Get current risk management limits for an account",
-			Handler:     handleGetRiskLimits(client).(func(map[string]interface{}) (interface{}, error)),
+			Params:      schemaOf(getRiskLimitsRequest{}),
+			Handler:     handleGetRiskLimits(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"getAccountAlerts": {
+			Description: "Get margin calls, trade desk messages, system notices and triggered alerts, newest first",
+			Params:      schemaOf(getAccountAlertsRequest{}),
+			Handler:     handleGetAccountAlerts(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"getAutoLiquidation": {
+			Description: "Get the broker-side auto-liquidation thresholds of an account",
+			Params:      schemaOf(accountRequest{}),
+			Handler:     handleGetAutoLiquidation(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"setAutoLiquidation": {
+			Description: "Change the broker-side auto-liquidation thresholds of an account; pass 0 to remove a threshold",
+			Params:      schemaOf(setAutoLiquidationRequest{}),
+			Handler:     handleSetAutoLiquidation(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"initializeReplayClock": {
+			Description: "Start a Market Replay session at a point in history (replay environment only)",
+			Params:      schemaOf(initializeReplayClockRequest{}),
+			Handler:     handleInitializeReplayClock(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
+		},
+		"changeReplaySpeed": {
+			Description: "Change the playback speed of the running Market Replay session",
+			Params:      schemaOf(changeReplaySpeedRequest{}),
+			Handler:     handleChangeReplaySpeed(client).(func(context.Context, map[string]interface{}) (interface{}, error)),
 		},
 	}
 }
 
 // handleAuthenticate processes authentication requests.
 // It calls the Tradovate client's Authenticate method and returns the response.
-func handleAuthenticate(client client.TradovateClientInterface) (interface{}, error) {
-	return client.Authenticate()
+func handleAuthenticate(ctx context.Context, client client.TradovateClientInterface) (interface{}, error) {
+	return client.Authenticate(ctx)
+}
+
+// AuthStatus reports the client's authentication state without contacting
+// Tradovate.
+type AuthStatus struct {
+	Authenticated    bool       `json:"authenticated"`
+	ExpiresAt        *time.Time `json:"expiresAt,omitempty"`
+	ExpiresInSeconds *int       `json:"expiresInSeconds,omitempty"`
+}
+
+func authStatus(client client.TradovateClientInterface, now time.Time) AuthStatus {
+	status := AuthStatus{Authenticated: client.IsAuthenticated()}
+	if expiresAt := client.TokenExpiresAt(); !expiresAt.IsZero() {
+		seconds := int(expiresAt.Sub(now).Seconds())
+		if seconds < 0 {
+			seconds = 0
+		}
+		status.ExpiresAt = &expiresAt
+		status.ExpiresInSeconds = &seconds
+	}
+	return status
+}
+
+// ServerTime compares Tradovate's clock with the local one.
+type ServerTime struct {
+	ServerTime time.Time `json:"serverTime"`
+	LocalTime  time.Time `json:"localTime"`
+	SkewMs     int64     `json:"skewMs"` // Server time minus local time
+}
+
+func handleGetServerTime(ctx context.Context, client client.TradovateClientInterface) (interface{}, error) {
+	serverTime, err := client.GetServerTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ServerTime{
+		ServerTime: serverTime,
+		LocalTime:  time.Now(),
+		SkewMs:     client.ClockSkew().Milliseconds(),
+	}, nil
+}
+
+// selectAccountRequest holds the parameters of selectAccount.
+type selectAccountRequest struct {
+	Account interface{} `json:"account" validate:"required" desc:"Account ID (number) or name (string) to select"`
+}
+
+// handleSelectAccount processes default account selection requests. The
+// selection lasts for the session the request arrived on and applies to no
+// other; requests that belong to no session, such as those over HTTP, must
+// give accountId on every call instead.
+// Required parameters:
+// - account: (string or float64) The ID or name of the account to select
+func handleSelectAccount(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req selectAccountRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		var account string
+		switch v := req.Account.(type) {
+		case string:
+			account = v
+		case float64:
+			account = strconv.Itoa(int(v))
+		default:
+			return nil, fmt.Errorf("invalid type for account: expected account ID or name")
+		}
+		sess := sessionOf(ctx)
+		if sess == nil {
+			return nil, fmt.Errorf("selectAccount needs a session, which this transport does not keep: give accountId with each call instead")
+		}
+		selected, err := client.ResolveAccount(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+		sess.accountID.Store(int64(selected.ID))
+		return selected, nil
+	}
+}
+
+// withActiveAccount returns params with accountId defaulted to the account
+// selected on the request's session, if one is selected and params does not
+// name an account.
+func withActiveAccount(ctx context.Context, params map[string]interface{}) map[string]interface{} {
+	if _, ok := params["accountId"]; ok {
+		return params
+	}
+	accountID := activeAccountID(ctx)
+	if accountID == 0 {
+		return params
+	}
+	withAccount := make(map[string]interface{}, len(params)+1)
+	for k, v := range params {
+		withAccount[k] = v
+	}
+	withAccount["accountId"] = float64(accountID)
+	return withAccount
+}
+
+// noParams is the request of tools that take no parameters.
+type noParams struct{}
+
+// accountRequest holds the parameters of tools that report on a single
+// account.
+type accountRequest struct {
+	AccountID int `json:"accountId" validate:"required,gt=0" desc:"Account ID"`
+}
+
+// orderRequest holds the parameters of tools that act on a single order.
+type orderRequest struct {
+	OrderID int `json:"orderId" validate:"required,gt=0" desc:"Order ID"`
+}
+
+// resolveFrontMonthRequest holds the parameters of resolveFrontMonth.
+type resolveFrontMonthRequest struct {
+	Symbol string `json:"symbol" validate:"required" desc:"Product symbol, e.g. ES or NQ"`
+}
+
+// searchContractsRequest holds the parameters of searchContracts. The
+// number of results is not called limit, which is taken by pagination.
+type searchContractsRequest struct {
+	Text       string `json:"text" validate:"required" desc:"Symbol or words of the product name to look for"`
+	MaxResults int    `json:"maxResults" validate:"gt=0" desc:"Most contracts to return (default 10)"`
+}
+
+// orderPrices holds the prices of an order. Each order type takes a limit
+// price, a trigger price, both or neither, and only those: an MIT order
+// triggers at its stop price like a stop, but when the market touches it
+// from the other side.
+type orderPrices struct {
+	Price     *float64 `json:"price" validate:"gt=0,required_if=orderType Limit StopLimit,excluded_unless=orderType Limit StopLimit" desc:"Limit price"`
+	StopPrice *float64 `json:"stopPrice" validate:"gt=0,required_if=orderType Stop StopLimit MIT,excluded_unless=orderType Stop StopLimit MIT" desc:"Trigger price"`
+}
+
+// contractRef names the contract a tool acts on, either by ID or by a
+// symbol resolved by resolveSymbol.
+type contractRef struct {
+	ContractID *int   `json:"contractId" validate:"gt=0" desc:"Contract ID, or give symbol instead"`
+	Symbol     string `json:"symbol" desc:"Contract name such as MESZ4, product root such as ES, or e.g. ES front month; instead of contractId"`
+}
+
+// resolve returns the ID of the contract the parameters name.
+func (r contractRef) resolve(ctx context.Context, client client.TradovateClientInterface) (int, error) {
+	switch {
+	case r.ContractID != nil && r.Symbol != "":
+		return 0, fmt.Errorf("only one of contractId and symbol may be given")
+	case r.ContractID != nil:
+		return *r.ContractID, nil
+	case r.Symbol != "":
+		contract, err := resolveSymbol(ctx, client, r.Symbol)
+		if err != nil {
+			return 0, err
+		}
+		return contract.ID, nil
+	}
+	return 0, paramErrorf("missing required field: contractId or symbol")
+}
+
+// resolvePosition returns the ID of the contract the parameters name, like
+// resolve, except that a symbol is matched against the contracts the
+// account holds first.
+func (r contractRef) resolvePosition(ctx context.Context, client client.TradovateClientInterface, accountID int) (int, error) {
+	if r.Symbol != "" && r.ContractID == nil {
+		return positionContract(ctx, client, accountID, r.Symbol)
+	}
+	return r.resolve(ctx, client)
+}
+
+// frontMonthSuffix asks resolveSymbol for a product's front month, as in
+// "ES front month".
+const frontMonthSuffix = " front month"
+
+// resolveSymbol returns the contract a symbol names: a contract name such as
+// MESZ4, or a product root such as ES, optionally followed by "front month",
+// which names the product's most actively traded contract.
+func resolveSymbol(ctx context.Context, client client.TradovateClientInterface, symbol string) (*models.Contract, error) {
+	symbol = strings.TrimSpace(symbol)
+	if symbol == "" {
+		return nil, fmt.Errorf("invalid symbol")
+	}
+	root := symbol
+	if len(symbol) > len(frontMonthSuffix) && strings.EqualFold(symbol[len(symbol)-len(frontMonthSuffix):], frontMonthSuffix) {
+		root = strings.TrimSpace(symbol[:len(symbol)-len(frontMonthSuffix)])
+	} else if contract, err := client.FindContract(ctx, symbol); err == nil && contract != nil {
+		return contract, nil
+	}
+	frontMonth, err := client.ResolveFrontMonth(ctx, root)
+	if err != nil || frontMonth == nil || frontMonth.Contract.ID == 0 {
+		return nil, fmt.Errorf("no contract or product found for symbol %s", symbol)
+	}
+	return &frontMonth.Contract, nil
+}
+
+// placeOrderRequest holds the parameters of placeOrder.
+type placeOrderRequest struct {
+	AccountID int `json:"accountId" validate:"required,gt=0" desc:"Account ID to place the order for"`
+	contractRef
+	OrderType   string `json:"orderType" validate:"required,oneof=Market Limit Stop StopLimit MIT" desc:"Order type"`
+	Quantity    int    `json:"quantity" validate:"required,gt=0" desc:"Number of contracts to trade"`
+	TimeInForce string `json:"timeInForce" validate:"required" desc:"Time in force, e.g. Day or GTC"`
+	orderPrices
+	ClientOrderID  string `json:"clientOrderId" desc:"Caller-chosen ID that makes retries return the existing order"`
+	ActivationTime string `json:"activationTime" desc:"RFC 3339 time the order starts working"`
 }
 
 // handlePlaceOrder processes order placement requests.
 // Required parameters:
 // - accountId: (float64) The account ID to place the order for
-// - contractId: (float64) The contract ID to trade
-// - orderType: (string) The type of order (e.g., "Market", "Limit")
+// - contractId: (float64) The contract ID to trade, or
+// - symbol: (string) The contract to trade, e.g. "MESZ4", "ES" or "ES front month"
+// - orderType: (string) Market, Limit, Stop, StopLimit or MIT
 // - quantity: (float64) The number of contracts to trade
 // - timeInForce: (string) The time in force for the order
 // Optional parameters:
-// - price: (float64) The limit price (required for limit orders)
-func handlePlaceOrder(client client.TradovateClientInterface) interface{} {
-	return func(params map[string]interface{}) (interface{}, error) {
-		// Validate required fields
-		requiredFields := []string{"accountId", "contractId", "orderType", "quantity", "timeInForce"}
-		for _, field := range requiredFields {
-			if _, ok := params[field]; !ok {
-				return nil, fmt.Errorf("missing required field: %s", field)
+// - price: (float64) The limit price (required for Limit and StopLimit orders)
+// - stopPrice: (float64) The trigger price (required for Stop, StopLimit and MIT orders)
+// - clientOrderId: (string) Caller-chosen ID that makes retries return the existing order
+// - activationTime: (string) RFC 3339 time the order starts working, e.g. at the cash open
+func handlePlaceOrder(client client.TradovateClientInterface, o options) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req placeOrderRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		contractID, err := req.resolve(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		order := models.Order{
+			AccountID:      req.AccountID,
+			ContractID:     contractID,
+			OrderType:      req.OrderType,
+			Quantity:       req.Quantity,
+			TimeInForce:    req.TimeInForce,
+			ClientOrderID:  req.ClientOrderID,
+			ActivationTime: req.ActivationTime,
+		}
+		if req.Price != nil {
+			order.Price = *req.Price
+		}
+		if req.StopPrice != nil {
+			order.StopPrice = *req.StopPrice
+		}
+
+		if err := checkOrderLimits(ctx, client, o.config.Current(), order); err != nil {
+			return nil, err
+		}
+
+		placed, err := client.PlaceOrder(ctx, order)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRejected(ctx, client, placed); err != nil {
+			return nil, err
+		}
+		newExpiryLookup(ctx, client, o.expiryWarningDays).annotateOrder(placed)
+		return placed, nil
+	}
+}
+
+// getExecutionReportsRequest holds the parameters of getExecutionReports.
+type getExecutionReportsRequest struct {
+	OrderID   int    `json:"orderId" validate:"gt=0" desc:"Only return reports for this order"`
+	AccountID int    `json:"accountId" validate:"gt=0" desc:"Only return reports for this account's orders"`
+	StartTime string `json:"startTime" desc:"Only return reports at or after this time, RFC 3339 or relative, e.g. today"`
+	EndTime   string `json:"endTime" desc:"Only return reports before this time, RFC 3339 or relative"`
+}
+
+// handleGetExecutionReports processes execution report requests. Commands
+// rejected by Tradovate's risk checks never reach the exchange, so for an
+// order they are reported alongside the exchange's reports, with the reason.
+// Parameters (one of orderId and accountId is required):
+// - orderId: (float64) Only return reports for this order
+// - accountId: (float64) Only return reports for this account's orders
+// - startTime: (string) Only return reports at or after this time, RFC3339 or relative, e.g. "today"
+// - endTime: (string) Only return reports before this time, RFC3339 or relative
+func handleGetExecutionReports(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req getExecutionReportsRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		if req.OrderID == 0 && req.AccountID == 0 {
+			return nil, paramErrorf("missing required field: orderId or accountId")
+		}
+		start, end, err := timeRange(req.StartTime, req.EndTime)
+		if err != nil {
+			return nil, err
+		}
+
+		reports, err := client.GetExecutionReports(ctx, req.OrderID, req.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		if req.OrderID != 0 {
+			reports = withRiskRejects(ctx, client, req.OrderID, reports)
+		}
+		if start.IsZero() && end.IsZero() {
+			return reports, nil
+		}
+		matching := make([]models.ExecutionReport, 0, len(reports))
+		for _, report := range reports {
+			at, err := time.Parse(time.RFC3339, report.Timestamp)
+			if err != nil || (!start.IsZero() && at.Before(start)) || (!end.IsZero() && !at.Before(end)) {
+				continue
 			}
+			matching = append(matching, report)
 		}
+		return matching, nil
+	}
+}
 
-		// Type assertions with validation
-		accountID, ok := params["accountId"].(float64)
-		if !ok {
-			return nil, fmt.Errorf("invalid type assertion for accountId")
+// timeRange parses the optional startTime and endTime parameters, each an
+// RFC3339 time or a relative one as parseTime reads them. Either is zero when
+// not given.
+func timeRange(startTime, endTime string) (start, end time.Time, err error) {
+	now := timeNow()
+	bounds := []struct {
+		name  string
+		value string
+		t     *time.Time
+	}{{"startTime", startTime, &start}, {"endTime", endTime, &end}}
+	for _, bound := range bounds {
+		if bound.value == "" {
+			continue
+		}
+		if *bound.t, err = parseTime(bound.value, now); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid %s format: %w", bound.name, err)
 		}
+	}
+	if !start.IsZero() && !end.IsZero() && !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("endTime must be after startTime")
+	}
+	return start, end, nil
+}
 
-		contractID, ok := params["contractId"].(float64)
-		if !ok {
-			return nil, fmt.Errorf("invalid type assertion for contractId")
+// getFillsByAccountRequest holds the parameters of getFillsByAccount.
+type getFillsByAccountRequest struct {
+	AccountID int    `json:"accountId" validate:"required,gt=0" desc:"Account whose fills to return"`
+	StartTime string `json:"startTime" desc:"Only return fills at or after this time, RFC 3339 or relative, e.g. -4h (default: the start of the trading day, 17:00 Chicago time)"`
+	EndTime   string `json:"endTime" desc:"Only return fills before this time, RFC 3339 or relative"`
+}
+
+// handleGetFillsByAccount processes requests for an account's fills.
+// Optional parameters:
+// - accountId: (float64) The account whose fills to return (default: the active account, if set)
+// - startTime: (string) Only return fills at or after this time, RFC3339 or relative, e.g. "-4h" (default: the start of the trading day, 17:00 Chicago time)
+// - endTime: (string) Only return fills before this time, RFC3339 or relative
+func handleGetFillsByAccount(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req getFillsByAccountRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		start, end, err := timeRange(req.StartTime, req.EndTime)
+		if err != nil {
+			return nil, err
 		}
+		return client.GetAccountFills(ctx, req.AccountID, start, end)
+	}
+}
 
-		orderType, ok := params["orderType"].(string)
-		if !ok {
-			return nil, fmt.Errorf("invalid type assertion for orderType")
+// getCommandHistoryRequest holds the parameters of getCommandHistory.
+type getCommandHistoryRequest struct {
+	OrderID      int  `json:"orderId" validate:"gt=0" desc:"Only return commands sent on this order"`
+	AccountID    int  `json:"accountId" validate:"gt=0" desc:"Only return commands sent on this account's orders"`
+	RejectedOnly bool `json:"rejectedOnly" desc:"Only return commands that were rejected"`
+}
+
+// handleGetCommandHistory processes command history requests.
+// Parameters (one of orderId and accountId is required):
+// - orderId: (float64) Only return commands sent on this order
+// - accountId: (float64) Only return commands sent on this account's orders (default: the active account, if set)
+// - rejectedOnly: (bool) Only return commands that were rejected
+func handleGetCommandHistory(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		if params["orderId"] == nil {
+			params = withActiveAccount(ctx, params)
+		}
+		var req getCommandHistoryRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		if req.OrderID == 0 && req.AccountID == 0 {
+			return nil, paramErrorf("missing required field: orderId or accountId")
 		}
 
-		quantity, ok := params["quantity"].(float64)
-		if !ok {
-			return nil, fmt.Errorf("invalid type assertion for quantity")
+		commands, err := client.GetCommandHistory(ctx, req.OrderID, req.AccountID)
+		if err != nil {
+			return nil, err
 		}
+		if !req.RejectedOnly {
+			return commands, nil
+		}
+		rejected := make([]models.Command, 0, len(commands))
+		for _, command := range commands {
+			if command.Rejected {
+				rejected = append(rejected, command)
+			}
+		}
+		return rejected, nil
+	}
+}
 
-		timeInForce, ok := params["timeInForce"].(string)
-		if !ok {
-			return nil, fmt.Errorf("invalid type assertion for timeInForce")
+// withRiskRejects adds the commands on orderID that were rejected without
+// an execution report to reports, and fills in the reason of rejections
+// the exchange reported without one. Reports are returned oldest first. If
+// the command reports cannot be fetched, reports are returned unchanged.
+func withRiskRejects(ctx context.Context, client client.TradovateClientInterface, orderID int, reports []models.ExecutionReport) []models.ExecutionReport {
+	commands, err := client.GetCommandReports(ctx, orderID)
+	if err != nil {
+		return reports
+	}
+	byCommand := make(map[int]int, len(reports))
+	for i, report := range reports {
+		byCommand[report.CommandID] = i
+	}
+	for _, command := range commands {
+		if !command.Rejected() {
+			continue
+		}
+		if i, ok := byCommand[command.CommandID]; ok {
+			if reports[i].RejectReason == "" {
+				reports[i].RejectReason = command.RejectReason
+			}
+			if reports[i].Text == "" {
+				reports[i].Text = command.Text
+			}
+			continue
+		}
+		reports = append(reports, models.ExecutionReport{
+			OrderID:      orderID,
+			CommandID:    command.CommandID,
+			Timestamp:    command.Timestamp,
+			ExecType:     "Rejected",
+			OrdStatus:    "Rejected",
+			RejectReason: command.RejectReason,
+			Text:         command.Text,
+		})
+	}
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].Timestamp < reports[j].Timestamp })
+	return reports
+}
+
+// checkRejected returns an error carrying Tradovate's reason if a placed
+// order was rejected. Placement succeeds even when the order is rejected by
+// risk checks, so the reason has to be fetched from its command reports. If
+// the reports cannot be fetched the order is assumed to be working.
+func checkRejected(ctx context.Context, client client.TradovateClientInterface, placed *models.Order) error {
+	if placed == nil || placed.ID == 0 {
+		return nil
+	}
+	reports, err := client.GetCommandReports(ctx, placed.ID)
+	if err != nil {
+		return nil
+	}
+	for _, report := range reports {
+		if !report.Rejected() {
+			continue
+		}
+		reason := report.Text
+		if report.RejectReason != "" {
+			reason = report.RejectReason + ": " + reason
+		}
+		return fmt.Errorf("order %d rejected: %s", placed.ID, reason)
+	}
+	return nil
+}
+
+// placeOcoOrderRequest holds the parameters of placeOcoOrder.
+type placeOcoOrderRequest struct {
+	AccountID       int     `json:"accountId" validate:"required,gt=0" desc:"Account ID to place the orders for"`
+	ContractID      int     `json:"contractId" validate:"required,gt=0" desc:"Contract ID of the position to exit"`
+	Side            string  `json:"side" validate:"required,oneof=Buy Sell" desc:"Side of both legs: Sell exits a long, Buy a short"`
+	Quantity        int     `json:"quantity" validate:"required,gt=0" desc:"Number of contracts for each leg"`
+	TakeProfitPrice float64 `json:"takeProfitPrice" validate:"required,gt=0" desc:"Limit price of the take-profit leg"`
+	StopLossPrice   float64 `json:"stopLossPrice" validate:"required,gt=0" desc:"Stop price of the stop-loss leg"`
+	TimeInForce     string  `json:"timeInForce" desc:"Time in force of both legs (default GTC)"`
+}
+
+// handlePlaceOcoOrder processes one-cancels-other order requests, pairing a
+// limit take-profit with a stop-loss that exit an open position. Both legs
+// are on the position's contract and on the side that reduces it.
+// Required parameters:
+// - accountId: (float64) The account ID to place the orders for
+// - contractId: (float64) The contract ID to trade
+// - side: (string) "Buy" or "Sell", the side of both legs
+// - quantity: (float64) The number of contracts for each leg
+// - takeProfitPrice: (float64) Limit price of the take-profit leg
+// - stopLossPrice: (float64) Stop price of the stop-loss leg
+// Optional parameters:
+// - timeInForce: (string) The time in force for both legs (default "GTC")
+func handlePlaceOcoOrder(client client.TradovateClientInterface, o options) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		req := placeOcoOrderRequest{TimeInForce: "GTC"}
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
 		}
 
-		// Price is optional for market orders
-		var price float64
-		if orderType == "Limit" {
-			priceVal, ok := params["price"].(float64)
-			if !ok {
-				return nil, fmt.Errorf("price is required for Limit orders")
+		// Selling exits a long, so the target sits above the stop; buying
+		// exits a short, so it sits below.
+		if req.Side == "Sell" && req.TakeProfitPrice <= req.StopLossPrice {
+			return nil, fmt.Errorf("takeProfitPrice must be above stopLossPrice for a Sell OCO")
+		}
+		if req.Side == "Buy" && req.TakeProfitPrice >= req.StopLossPrice {
+			return nil, fmt.Errorf("takeProfitPrice must be below stopLossPrice for a Buy OCO")
+		}
+		if err := checkExitsPosition(ctx, client, req.AccountID, req.ContractID, req.Side, req.Quantity); err != nil {
+			return nil, err
+		}
+
+		leg := models.Order{
+			AccountID:   req.AccountID,
+			ContractID:  req.ContractID,
+			Side:        req.Side,
+			Quantity:    req.Quantity,
+			TimeInForce: req.TimeInForce,
+		}
+		if err := checkOrderLimits(ctx, client, o.config.Current(), leg); err != nil {
+			return nil, err
+		}
+
+		target, stop := leg, leg
+		target.OrderType = "Limit"
+		target.Price = req.TakeProfitPrice
+		stop.OrderType = "Stop"
+		stop.StopPrice = req.StopLossPrice
+
+		return client.PlaceOCO(ctx, models.OCOOrder{First: target, Second: stop})
+	}
+}
+
+// checkExitsPosition returns an error unless quantity contracts on side
+// would exit, without reversing, the account's position in the contract.
+func checkExitsPosition(ctx context.Context, client client.TradovateClientInterface, accountID, contractID int, side string, quantity int) error {
+	positions, err := client.GetPositionsByAccount(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("error looking up positions of account %d: %w", accountID, err)
+	}
+	netPos := 0
+	for _, position := range positions {
+		if position.ContractID == contractID {
+			netPos += position.NetPos
+		}
+	}
+	switch {
+	case netPos == 0:
+		return fmt.Errorf("account %d has no open position in contract %d to exit", accountID, contractID)
+	case side == "Sell" && netPos < 0:
+		return fmt.Errorf("a Sell OCO exits a long position, but account %d is short %d of contract %d", accountID, -netPos, contractID)
+	case side == "Buy" && netPos > 0:
+		return fmt.Errorf("a Buy OCO exits a short position, but account %d is long %d of contract %d", accountID, netPos, contractID)
+	}
+	held := netPos
+	if held < 0 {
+		held = -held
+	}
+	if quantity > held {
+		return fmt.Errorf("quantity %d exceeds the %d contracts held in contract %d", quantity, held, contractID)
+	}
+	return nil
+}
+
+// placeBracketOrderRequest holds the parameters of placeBracketOrder.
+type placeBracketOrderRequest struct {
+	AccountID       int      `json:"accountId" validate:"required,gt=0" desc:"Account ID to place the order for"`
+	ContractID      int      `json:"contractId" validate:"required,gt=0" desc:"Contract ID to trade"`
+	Side            string   `json:"side" validate:"required,oneof=Buy Sell" desc:"Side of the entry order"`
+	Quantity        int      `json:"quantity" validate:"required,gt=0" desc:"Number of contracts to trade"`
+	OrderType       string   `json:"orderType" validate:"oneof=Market Limit" desc:"Entry order type (default Market)"`
+	Price           *float64 `json:"price" validate:"gt=0,required_if=orderType Limit" desc:"Entry limit price"`
+	TimeInForce     string   `json:"timeInForce" desc:"Time in force of the entry order (default Day)"`
+	TargetOffset    *float64 `json:"targetOffset" validate:"gt=0" desc:"Take-profit distance from the entry, in points"`
+	TakeProfitTicks *int     `json:"takeProfitTicks" validate:"gt=0" desc:"Take-profit distance from the entry, in ticks"`
+	TakeProfitPrice *float64 `json:"takeProfitPrice" validate:"gt=0" desc:"Take-profit price, for Limit entries"`
+	StopOffset      *float64 `json:"stopOffset" validate:"gt=0" desc:"Stop-loss distance from the entry, in points"`
+	StopLossTicks   *int     `json:"stopLossTicks" validate:"gt=0" desc:"Stop-loss distance from the entry, in ticks"`
+	StopLossPrice   *float64 `json:"stopLossPrice" validate:"gt=0" desc:"Stop-loss price, for Limit entries"`
+}
+
+// handlePlaceBracketOrder processes bracket order requests. Each exit is
+// given as an offset in points, a number of ticks, or, for Limit entries,
+// an absolute price.
+// Required parameters:
+// - accountId: (float64) The account ID to place the order for
+// - contractId: (float64) The contract ID to trade
+// - side: (string) "Buy" or "Sell", the side of the entry order
+// - quantity: (float64) The number of contracts to trade
+// - targetOffset, takeProfitTicks or takeProfitPrice: (float64) Where to take profit
+// - stopOffset, stopLossTicks or stopLossPrice: (float64) Where to stop out
+// Optional parameters:
+// - orderType: (string) Entry order type, "Market" (default) or "Limit"
+// - price: (float64) The entry limit price (required for Limit entries)
+// - timeInForce: (string) Time in force of the entry order (default "Day")
+func handlePlaceBracketOrder(client client.TradovateClientInterface, o options) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		req := placeBracketOrderRequest{OrderType: "Market", TimeInForce: "Day"}
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+
+		bracket := models.BracketOrder{
+			AccountID:   req.AccountID,
+			ContractID:  req.ContractID,
+			Side:        req.Side,
+			Quantity:    req.Quantity,
+			OrderType:   req.OrderType,
+			TimeInForce: req.TimeInForce,
+		}
+		if req.OrderType == "Limit" {
+			bracket.Price = *req.Price
+		}
+
+		exits := bracketExits{client: client, bracket: bracket}
+		var err error
+		target := bracketExit{offset: req.TargetOffset, ticks: req.TakeProfitTicks, price: req.TakeProfitPrice,
+			offsetName: "targetOffset", ticksName: "takeProfitTicks", priceName: "takeProfitPrice"}
+		if bracket.TargetOffset, err = exits.offset(ctx, target, 1); err != nil {
+			return nil, err
+		}
+		stop := bracketExit{offset: req.StopOffset, ticks: req.StopLossTicks, price: req.StopLossPrice,
+			offsetName: "stopOffset", ticksName: "stopLossTicks", priceName: "stopLossPrice"}
+		if bracket.StopOffset, err = exits.offset(ctx, stop, -1); err != nil {
+			return nil, err
+		}
+
+		entry := models.Order{AccountID: bracket.AccountID, ContractID: bracket.ContractID, Quantity: bracket.Quantity}
+		if err := checkOrderLimits(ctx, client, o.config.Current(), entry); err != nil {
+			return nil, err
+		}
+
+		return client.PlaceBracketOrder(ctx, bracket)
+	}
+}
+
+// bracketExits works out the offsets of a bracket's exits from the ways
+// they can be given.
+type bracketExits struct {
+	client   client.TradovateClientInterface
+	bracket  models.BracketOrder
+	tickSize float64 // Looked up on first use
+}
+
+// bracketExit is one exit of a bracket, given as exactly one of an offset
+// in points, a number of ticks or a price, with the parameters they come
+// from.
+type bracketExit struct {
+	offset *float64
+	ticks  *int
+	price  *float64
+
+	offsetName, ticksName, priceName string
+}
+
+// offset returns the distance in points from entry to exit. For a Buy entry
+// a direction of 1 puts the exit above the entry and -1 below; for a Sell
+// entry the reverse.
+func (e *bracketExits) offset(ctx context.Context, exit bracketExit, direction float64) (float64, error) {
+	given := 0
+	for _, set := range []bool{exit.offset != nil, exit.ticks != nil, exit.price != nil} {
+		if set {
+			given++
+		}
+	}
+	switch given {
+	case 0:
+		return 0, paramErrorf("missing required field: %s, %s or %s", exit.offsetName, exit.ticksName, exit.priceName)
+	case 1:
+	default:
+		return 0, fmt.Errorf("only one of %s, %s and %s may be given", exit.offsetName, exit.ticksName, exit.priceName)
+	}
+
+	switch {
+	case exit.ticks != nil:
+		if e.tickSize == 0 {
+			contract, err := e.client.GetContract(ctx, e.bracket.ContractID)
+			if err != nil {
+				return 0, fmt.Errorf("error looking up contract %d: %w", e.bracket.ContractID, err)
 			}
-			price = priceVal
+			if contract.TickSize <= 0 {
+				return 0, fmt.Errorf("tick size of %s is unknown: give %s instead", contract.Name, exit.offsetName)
+			}
+			e.tickSize = contract.TickSize
+		}
+		return float64(*exit.ticks) * e.tickSize, nil
+	case exit.price != nil:
+		if e.bracket.OrderType != "Limit" {
+			return 0, fmt.Errorf("%s needs a Limit entry, whose fill price is known: give %s or %s instead", exit.priceName, exit.offsetName, exit.ticksName)
 		}
+		if e.bracket.Side == "Sell" {
+			direction = -direction
+		}
+		offset := (*exit.price - e.bracket.Price) * direction
+		if offset <= 0 {
+			where := "above"
+			if direction < 0 {
+				where = "below"
+			}
+			return 0, fmt.Errorf("%s must be %s the entry price of a %s order", exit.priceName, where, e.bracket.Side)
+		}
+		return offset, nil
+	}
+	return *exit.offset, nil
+}
 
-		order := models.Order{
-			AccountID:   int(accountID),
-			ContractID:  int(contractID),
-			OrderType:   orderType,
-			Price:       price,
-			Quantity:    int(quantity),
-			TimeInForce: timeInForce,
+// handleListOrders processes order listing requests. Tradovate keeps the
+// orders of the current trading day. Orders on contracts expiring within
+// the warning window carry expiresInDays, as placed orders do.
+// Optional parameters:
+// - accountId: (float64) Only list this account's orders (default: the active account, if set)
+// - status: (string) Only list orders with this status, e.g. "Working", "Filled" or "Canceled"
+// - contractId: (float64) Only list orders for this contract
+// - since: (string) Only list orders placed at or after this RFC3339 time
+// - onlyOpen: (bool) Only list orders that are still working or pending
+// - sortBy: (string) time, quantity or price
+// - descending: (bool) Sort largest, or latest, first
+func handleListOrders(client client.TradovateClientInterface, o options) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req listOrdersRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		var since time.Time
+		if params["since"] != nil {
+			var err error
+			if since, err = time.Parse(time.RFC3339, req.Since); err != nil {
+				return nil, fmt.Errorf("invalid since format: %w", err)
+			}
 		}
 
-		return client.PlaceOrder(order)
+		orders, err := client.GetOrders(ctx, req.AccountID, req.Status)
+		if err != nil {
+			return nil, err
+		}
+		matching := make([]models.Order, 0, len(orders))
+		for _, order := range orders {
+			if req.ContractID != 0 && order.ContractID != req.ContractID {
+				continue
+			}
+			if !since.IsZero() && !placedSince(order, since) {
+				continue
+			}
+			if req.OnlyOpen && finalOrderStatuses[order.Status] {
+				continue
+			}
+			matching = append(matching, order)
+		}
+		req.sort(matching)
+		lookup := newExpiryLookup(ctx, client, o.expiryWarningDays)
+		for i := range matching {
+			lookup.annotateOrder(&matching[i])
+		}
+		return matching, nil
 	}
 }
 
-// handleSetRiskLimits processes risk limit update requests.
+// listOrdersRequest holds the parameters of listOrders.
+type listOrdersRequest struct {
+	AccountID  int    `json:"accountId" validate:"gt=0" desc:"Only list this account's orders"`
+	Status     string `json:"status" desc:"Only list orders with this status, e.g. Working, Filled or Canceled"`
+	ContractID int    `json:"contractId" validate:"gt=0" desc:"Only list orders for this contract"`
+	Since      string `json:"since" desc:"Only list orders placed at or after this RFC 3339 time"`
+	OnlyOpen   bool   `json:"onlyOpen" desc:"Only list orders that are still working or pending"`
+	SortBy     string `json:"sortBy" validate:"oneof=time quantity price" desc:"Field to sort by, smallest or earliest first"`
+	Descending bool   `json:"descending" desc:"Sort largest, or latest, first"`
+}
+
+// finalOrderStatuses are the statuses of orders that can no longer fill.
+var finalOrderStatuses = map[string]bool{
+	"Filled":    true,
+	"Canceled":  true,
+	"Rejected":  true,
+	"Expired":   true,
+	"Completed": true,
+}
+
+// sort sorts orders by the requested field, keeping their order otherwise.
+// Orders without a known placement time sort first by time.
+func (r listOrdersRequest) sort(orders []models.Order) {
+	var less func(a, b models.Order) bool
+	switch r.SortBy {
+	case "time":
+		less = func(a, b models.Order) bool {
+			placedA, _ := placedAt(a)
+			placedB, _ := placedAt(b)
+			return placedA.Before(placedB)
+		}
+	case "quantity":
+		less = func(a, b models.Order) bool { return a.Quantity < b.Quantity }
+	case "price":
+		less = func(a, b models.Order) bool { return a.Price < b.Price }
+	default:
+		return
+	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		if r.Descending {
+			return less(orders[j], orders[i])
+		}
+		return less(orders[i], orders[j])
+	})
+}
+
+// placedAt returns when order was placed, if it is known.
+func placedAt(order models.Order) (time.Time, bool) {
+	if placed, err := time.Parse(time.RFC3339, order.Timestamp); err == nil {
+		return placed, true
+	}
+	if order.CreatedAt == 0 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(order.CreatedAt), true
+}
+
+// placedSince reports whether order was placed at or after since. Orders
+// without a timestamp cannot be shown to match and are left out.
+func placedSince(order models.Order, since time.Time) bool {
+	placed, ok := placedAt(order)
+	return ok && !placed.Before(since)
+}
+
+// liquidatePositionRequest holds the parameters of liquidatePosition.
+type liquidatePositionRequest struct {
+	AccountID int `json:"accountId" validate:"required,gt=0" desc:"Account holding the position"`
+	contractRef
+}
+
+// handleLiquidatePosition processes requests to flatten a position at
+// market. Working orders in the contract are cancelled with it.
 // Required parameters:
-// - accountId: (float64) The account ID to set limits for
-// - dayMaxLoss: (float64) Maximum loss allowed per day
-// - maxDrawdown: (float64) Maximum drawdown allowed
-// - maxPositionQty: (float64) Maximum position size allowed
-// - trailingStop: (float64) Trailing stop percentage
-func handleSetRiskLimits(client client.TradovateClientInterface) interface{} {
-	return func(params map[string]interface{}) (interface{}, error) {
-		accountID, ok := params["accountId"].(float64)
-		if !ok {
-			return nil, fmt.Errorf("missing or invalid accountId")
+// - accountId: (float64) The account holding the position
+// - contractId: (float64) The contract to flatten, or
+// - symbol: (string) The contract, e.g. "ESZ4" or "ES front month", of one of the account's positions
+func handleLiquidatePosition(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req liquidatePositionRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		contractID, err := req.resolvePosition(ctx, client, req.AccountID)
+		if err != nil {
+			return nil, err
 		}
 
-		dayMaxLoss, ok := params["dayMaxLoss"].(float64)
-		if !ok || dayMaxLoss < 0 {
-			return nil, fmt.Errorf("missing or invalid dayMaxLoss")
+		orderID, err := client.LiquidatePosition(ctx, req.AccountID, contractID)
+		if err != nil {
+			return nil, err
 		}
+		closing := &models.Order{ID: orderID, AccountID: req.AccountID, ContractID: contractID}
+		if err := checkRejected(ctx, client, closing); err != nil {
+			return nil, err
+		}
+		// The order may not have filled yet, or be unavailable for a moment;
+		// either way its ID is enough to follow it up with getOrder.
+		if order, err := client.GetOrder(ctx, orderID); err == nil {
+			closing = order
+		}
+		return closing, nil
+	}
+}
 
-		maxDrawdown, ok := params["maxDrawdown"].(float64)
-		if !ok || maxDrawdown < 0 {
-			return nil, fmt.Errorf("missing or invalid maxDrawdown")
+// handleGetPositions processes position requests, naming the contract of
+// each position in its symbol.
+// Optional parameters:
+// - accountId: (float64) Only return positions held in this account
+// - symbol: (string) Only return positions in this contract, e.g. "MNQZ4" or "MNQ front month"
+// - onlyOpen: (bool) Leave out flat positions
+// - onlyLosing: (bool) Only return positions with an unrealized loss
+// - minAbsNetPos: (float64) Only return positions of at least this many contracts, long or short
+// - sortBy: (string) unrealizedPL, realizedPL, netPos or symbol
+// - descending: (bool) Sort largest first
+func handleGetPositions(client client.TradovateClientInterface, o options) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req getPositionsRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		var symbol string
+		if req.Symbol != nil {
+			if symbol = strings.TrimSpace(*req.Symbol); symbol == "" {
+				return nil, fmt.Errorf("invalid symbol")
+			}
 		}
 
-		maxPositionQty, ok := params["maxPositionQty"].(float64)
-		if !ok || maxPositionQty < 0 {
-			return nil, fmt.Errorf("missing or invalid maxPositionQty")
+		var positions []models.Position
+		var err error
+		if req.AccountID != nil {
+			positions, err = client.GetPositionsByAccount(ctx, *req.AccountID)
+		} else {
+			positions, err = client.GetPositions(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+		kept := make([]models.Position, 0, len(positions))
+		for _, position := range positions {
+			if req.keep(position) {
+				kept = append(kept, position)
+			}
 		}
+		positions = kept
 
-		trailingStop, ok := params["trailingStop"].(float64)
-		if !ok || trailingStop < 0 {
-			return nil, fmt.Errorf("missing or invalid trailingStop")
+		// A contract that cannot be looked up leaves its positions unnamed,
+		// so they never match a symbol.
+		lookup := newExpiryLookup(ctx, client, o.expiryWarningDays)
+		matching := make([]models.Position, 0, len(positions))
+		for _, position := range positions {
+			position.Symbol = lookup.name(position.ContractID)
+			if symbol != "" && !strings.EqualFold(position.Symbol, symbol) {
+				continue
+			}
+			matching = append(matching, position)
+		}
+		// A symbol that is not a held contract's name may still name one,
+		// as "ES front month" does; one naming no contract matches nothing.
+		if symbol != "" && len(matching) == 0 {
+			if contract, err := resolveSymbol(ctx, client, symbol); err == nil {
+				for _, position := range positions {
+					if position.ContractID == contract.ID {
+						position.Symbol = lookup.name(position.ContractID)
+						matching = append(matching, position)
+					}
+				}
+			}
+		}
+		lookup.annotatePositions(matching)
+		req.sort(matching)
+		return matching, nil
+	}
+}
+
+// getPositionsRequest holds the parameters of getPositions.
+type getPositionsRequest struct {
+	AccountID    *int    `json:"accountId" validate:"gt=0" desc:"Only return positions held in this account"`
+	Symbol       *string `json:"symbol" desc:"Only return positions in this contract, e.g. MNQZ4 or MNQ front month"`
+	OnlyOpen     bool    `json:"onlyOpen" desc:"Leave out flat positions"`
+	OnlyLosing   bool    `json:"onlyLosing" desc:"Only return positions with an unrealized loss"`
+	MinAbsNetPos int     `json:"minAbsNetPos" validate:"gte=0" desc:"Only return positions of at least this many contracts, long or short"`
+	SortBy       string  `json:"sortBy" validate:"oneof=unrealizedPL realizedPL netPos symbol" desc:"Field to sort by, smallest first"`
+	Descending   bool    `json:"descending" desc:"Sort largest first"`
+}
+
+// keep reports whether position passes the request's filters.
+func (r getPositionsRequest) keep(position models.Position) bool {
+	netPos := abs(position.NetPos)
+	return (!r.OnlyOpen || netPos != 0) &&
+		netPos >= r.MinAbsNetPos &&
+		(!r.OnlyLosing || position.UnrealizedPL < 0)
+}
+
+// sort sorts positions by the requested field, keeping their order
+// otherwise.
+func (r getPositionsRequest) sort(positions []models.Position) {
+	var less func(a, b models.Position) bool
+	switch r.SortBy {
+	case "unrealizedPL":
+		less = func(a, b models.Position) bool { return a.UnrealizedPL < b.UnrealizedPL }
+	case "realizedPL":
+		less = func(a, b models.Position) bool { return a.RealizedPL < b.RealizedPL }
+	case "netPos":
+		less = func(a, b models.Position) bool { return a.NetPos < b.NetPos }
+	case "symbol":
+		less = func(a, b models.Position) bool { return a.Symbol < b.Symbol }
+	default:
+		return
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		if r.Descending {
+			return less(positions[j], positions[i])
+		}
+		return less(positions[i], positions[j])
+	})
+}
+
+// positionContract returns the contract of the account's open position
+// whose name is symbol, or failing that, of the contract symbol resolves to,
+// such as "ES front month".
+func positionContract(ctx context.Context, client client.TradovateClientInterface, accountID int, symbol string) (int, error) {
+	positions, err := client.GetPositionsByAccount(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+	open := make(map[int]bool, len(positions))
+	for _, position := range positions {
+		if position.NetPos == 0 {
+			continue
+		}
+		open[position.ContractID] = true
+		contract, err := client.GetContract(ctx, position.ContractID)
+		if err != nil {
+			return 0, fmt.Errorf("error looking up contract %d: %w", position.ContractID, err)
+		}
+		if contract != nil && strings.EqualFold(contract.Name, symbol) {
+			return position.ContractID, nil
+		}
+	}
+	if len(open) > 0 {
+		if contract, err := resolveSymbol(ctx, client, symbol); err == nil && open[contract.ID] {
+			return contract.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("account %d has no open position in %s", accountID, symbol)
+}
+
+// modifyOrderRequest holds the parameters of modifyOrder. Changing the
+// type replaces the order's prices, so the new type's prices must come with
+// it, as when placing an order.
+type modifyOrderRequest struct {
+	OrderID   int    `json:"orderId" validate:"required,gt=0" desc:"Order ID to modify"`
+	Quantity  *int   `json:"quantity" validate:"gt=0" desc:"New number of contracts"`
+	OrderType string `json:"orderType" validate:"oneof=Market Limit Stop StopLimit MIT" desc:"New order type"`
+	orderPrices
+	TimeInForce string `json:"timeInForce" desc:"New time in force"`
+}
+
+// handleModifyOrder processes order modification requests.
+// Required parameters:
+// - orderId: (float64) The order to modify
+// Optional parameters (at least one is required):
+// - quantity: (float64) The new number of contracts
+// - price: (float64) The new limit price
+// - stopPrice: (float64) The new stop price
+// - orderType: (string) The new order type, given with the prices it takes as for placeOrder
+// - timeInForce: (string) The new time in force
+func handleModifyOrder(client client.TradovateClientInterface, o options) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req modifyOrderRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		if limit := o.config.Current().RiskLimits.MaxOrderQuantity; limit > 0 && req.Quantity != nil && *req.Quantity > limit {
+			return nil, fmt.Errorf("order quantity %d exceeds configured maximum of %d", *req.Quantity, limit)
+		}
+
+		changes := models.OrderChanges{
+			Quantity:    req.Quantity,
+			Price:       req.Price,
+			StopPrice:   req.StopPrice,
+			OrderType:   req.OrderType,
+			TimeInForce: req.TimeInForce,
+		}
+		if changes == (models.OrderChanges{}) {
+			return nil, fmt.Errorf("nothing to modify: set quantity, price, stopPrice, orderType or timeInForce")
+		}
+
+		if err := client.ModifyOrder(ctx, req.OrderID, changes); err != nil {
+			return nil, err
+		}
+		return map[string]bool{"success": true}, nil
+	}
+}
+
+// setRiskLimitsRequest holds the parameters of setRiskLimits.
+type setRiskLimitsRequest struct {
+	AccountID      int     `json:"accountId" validate:"required,gt=0" desc:"Account ID to set limits for"`
+	DayMaxLoss     float64 `json:"dayMaxLoss" validate:"required,gte=0" desc:"Maximum loss allowed per day"`
+	MaxDrawdown    float64 `json:"maxDrawdown" validate:"required,gte=0" desc:"Maximum drawdown allowed"`
+	MaxPositionQty int     `json:"maxPositionQty" validate:"required,gte=0" desc:"Maximum position size allowed"`
+	TrailingStop   float64 `json:"trailingStop" validate:"required,gte=0" desc:"Trailing stop percentage"`
+}
+
+// handleSetRiskLimits processes risk limit update requests.
+// Required parameters:
+// - accountId: (float64) The account ID to set limits for
+// - dayMaxLoss: (float64) Maximum loss allowed per day
+// - maxDrawdown: (float64) Maximum drawdown allowed
+// - maxPositionQty: (float64) Maximum position size allowed
+// - trailingStop: (float64) Trailing stop percentage
+func handleSetRiskLimits(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req setRiskLimitsRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
 		}
 
 		limits := models.RiskLimit{
-			AccountID:      int(accountID),
-			DayMaxLoss:     dayMaxLoss,
-			MaxDrawdown:    maxDrawdown,
-			MaxPositionQty: int(maxPositionQty),
-			TrailingStop:   trailingStop,
+			AccountID:      req.AccountID,
+			DayMaxLoss:     req.DayMaxLoss,
+			MaxDrawdown:    req.MaxDrawdown,
+			MaxPositionQty: req.MaxPositionQty,
+			TrailingStop:   req.TrailingStop,
 		}
-		return nil, client.SetRiskLimits(limits)
+		return nil, client.SetRiskLimits(ctx, limits)
 	}
 }
 
+// getMarketDataRequest holds the parameters of getMarketData.
+type getMarketDataRequest struct {
+	contractRef
+}
+
 // handleGetMarketData processes market data requests.
 // Required parameters:
-// - contractId: (float64) The contract ID to get data for
+// - contractId: (float64) The contract ID to get data for, or
+// - symbol: (string) The contract to get data for, e.g. "MESZ4", "ES" or "ES front month"
 func handleGetMarketData(client client.TradovateClientInterface) interface{} {
-	return func(params map[string]interface{}) (interface{}, error) {
-		contractIDFloat, ok := params["contractId"]
-		if !ok {
-			return nil, fmt.Errorf("missing contractId")
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req getMarketDataRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		contractID, err := req.resolve(ctx, client)
+		if err != nil {
+			return nil, err
 		}
+		return client.GetMarketData(ctx, contractID)
+	}
+}
+
+// getQuotesRequest holds the parameters of getQuotes. Their elements are
+// checked one by one, so an invalid one can be named.
+type getQuotesRequest struct {
+	ContractIDs []interface{} `json:"contractIds" desc:"Contract IDs to get market data for"`
+	Symbols     []interface{} `json:"symbols" desc:"Contract names to get market data for, e.g. ESZ4"`
+}
 
-		contractID, ok := contractIDFloat.(float64)
-		if !ok {
-			return nil, fmt.Errorf("invalid type assertion for contractId")
+// handleGetQuotes processes batch market data requests. Quotes are keyed by
+// the contract ID or symbol they were requested by; a symbol that does not
+// name a contract has its error set like a quote that could not be fetched.
+// Parameters (at least one is required):
+// - contractIds: ([]float64) The contract IDs to get market data for
+// - symbols: ([]string) The contract names to get market data for, e.g. "ESZ4"
+func handleGetQuotes(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req getQuotesRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		if req.ContractIDs == nil && req.Symbols == nil {
+			return nil, paramErrorf("missing required field: contractIds or symbols")
 		}
 
-		if contractID < 0 {
-			return nil, fmt.Errorf("invalid contractId")
+		var keys []string
+		var contractIDs []int
+		for _, v := range req.ContractIDs {
+			contractID, ok := toNumber(v)
+			if !ok || contractID <= 0 || contractID != math.Trunc(contractID) {
+				return nil, fmt.Errorf("invalid contractId in contractIds: %v", v)
+			}
+			keys = append(keys, strconv.Itoa(int(contractID)))
+			contractIDs = append(contractIDs, int(contractID))
 		}
 
-		return client.GetMarketData(int(contractID))
+		quotes := make(map[string]models.MarketDataResult)
+		for _, v := range req.Symbols {
+			symbol, ok := v.(string)
+			if !ok || strings.TrimSpace(symbol) == "" {
+				return nil, fmt.Errorf("invalid symbol in symbols: %v", v)
+			}
+			contract, err := client.FindContract(ctx, symbol)
+			if err != nil {
+				quotes[symbol] = models.MarketDataResult{Error: err.Error()}
+				continue
+			}
+			keys = append(keys, symbol)
+			contractIDs = append(contractIDs, contract.ID)
+		}
+
+		if len(contractIDs) == 0 && len(quotes) == 0 {
+			return nil, fmt.Errorf("no contracts requested")
+		}
+		if len(contractIDs) > 0 {
+			results, err := client.GetMarketDataBatch(ctx, contractIDs)
+			if err != nil {
+				return nil, err
+			}
+			for i, result := range results {
+				quotes[keys[i]] = result
+			}
+		}
+		return quotes, nil
 	}
 }
 
+// getHistoricalDataRequest holds the parameters of getHistoricalData.
+type getHistoricalDataRequest struct {
+	contractRef
+	StartTime string `json:"startTime" validate:"required" desc:"Start time, in RFC 3339 format or relative, e.g. -24h, today or last 5 trading days"`
+	EndTime   string `json:"endTime" validate:"required" desc:"End time, in RFC 3339 format or relative, e.g. now"`
+	Interval  string `json:"interval" validate:"required" desc:"Time interval for data points"`
+}
+
 // handleGetHistoricalData processes historical market data requests.
 // Required parameters:
-// - contractId: (float64) The contract ID to get data for
-// - startTime: (string) Start time in RFC3339 format
-// - endTime: (string) End time in RFC3339 format
+// - contractId: (float64) The contract ID to get data for, or
+// - symbol: (string) The contract to get data for, e.g. "MESZ4", "ES" or "ES front month"
+// - startTime: (string) Start time in RFC3339 format, or relative as parseTime reads it, e.g. "last 5 trading days"
+// - endTime: (string) End time in RFC3339 format, or relative, e.g. "now"
 // - interval: (string) Time interval for data points
 func handleGetHistoricalData(client client.TradovateClientInterface) interface{} {
-	return func(params map[string]interface{}) (interface{}, error) {
-		contractIDFloat, ok := params["contractId"]
-		if !ok {
-			return nil, fmt.Errorf("missing contractId")
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req getHistoricalDataRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+
+		now := timeNow()
+		startTime, err := parseTime(req.StartTime, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start time")
+		}
+		endTime, err := parseTime(req.EndTime, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end time")
+		}
+		if endTime.Before(startTime) {
+			return nil, fmt.Errorf("end time must be after start time")
 		}
 
-		contractID, ok := contractIDFloat.(float64)
-		if !ok {
-			return nil, fmt.Errorf("invalid type assertion for contractId")
+		contractID, err := req.resolve(ctx, client)
+		if err != nil {
+			return nil, err
 		}
+		return client.GetHistoricalData(ctx, contractID, startTime, endTime, req.Interval)
+	}
+}
+
+// getRiskLimitsRequest holds the parameters of getRiskLimits.
+type getRiskLimitsRequest struct {
+	AccountID int `json:"accountId" validate:"required,gt=0" desc:"Account ID to get limits for"`
+}
 
-		if contractID < 0 {
-			return nil, fmt.Errorf("invalid contractId")
+// handleGetRiskLimits processes risk limit requests.
+// Required parameters:
+// - accountId: (float64) The account ID to get limits for
+func handleGetRiskLimits(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req getRiskLimitsRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
 		}
+		return client.GetRiskLimits(ctx, req.AccountID)
+	}
+}
 
-		startTimeStr, ok := params["startTime"].(string)
-		if !ok {
-			return nil, fmt.Errorf("missing startTime")
+// handleGetAutoLiquidation processes auto-liquidation settings requests.
+// Required parameters:
+// - accountId: (float64) The account ID to get the settings for
+func handleGetAutoLiquidation(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req accountRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		return client.GetAutoLiquidation(ctx, req.AccountID)
+	}
+}
+
+// setAutoLiquidationRequest holds the parameters of setAutoLiquidation.
+type setAutoLiquidationRequest struct {
+	AccountID               int      `json:"accountId" validate:"required,gt=0" desc:"Account ID to change the settings of"`
+	DailyLossAutoLiq        *float64 `json:"dailyLossAutoLiq" validate:"gte=0" desc:"Daily loss that flattens the account; 0 removes it"`
+	WeeklyLossAutoLiq       *float64 `json:"weeklyLossAutoLiq" validate:"gte=0" desc:"Weekly loss that flattens the account; 0 removes it"`
+	DailyProfitAutoLiq      *float64 `json:"dailyProfitAutoLiq" validate:"gte=0" desc:"Daily profit that flattens the account; 0 removes it"`
+	WeeklyProfitAutoLiq     *float64 `json:"weeklyProfitAutoLiq" validate:"gte=0" desc:"Weekly profit that flattens the account; 0 removes it"`
+	MarginPercentageAlert   *float64 `json:"marginPercentageAlert" validate:"gte=0" desc:"Margin usage percentage that raises an alert; 0 removes it"`
+	MarginPercentageLiqOnly *float64 `json:"marginPercentageLiqOnly" validate:"gte=0" desc:"Margin usage percentage that makes the account liquidation-only; 0 removes it"`
+	MarginPercentageAutoLiq *float64 `json:"marginPercentageAutoLiq" validate:"gte=0" desc:"Margin usage percentage that flattens the account; 0 removes it"`
+	TrailingMaxDrawdown     *float64 `json:"trailingMaxDrawdown" validate:"gte=0" desc:"Trailing drawdown that flattens the account; 0 removes it"`
+}
+
+// handleSetAutoLiquidation processes auto-liquidation settings updates. The
+// current settings are fetched and only the given thresholds are changed.
+// Required parameters:
+// - accountId: (float64) The account ID to change the settings of
+// Optional parameters (at least one is required; 0 removes the threshold):
+// - dailyLossAutoLiq, weeklyLossAutoLiq: (float64) Losses that flatten the account
+// - dailyProfitAutoLiq, weeklyProfitAutoLiq: (float64) Profits that flatten the account
+// - marginPercentageAlert, marginPercentageLiqOnly, marginPercentageAutoLiq: (float64) Margin usage percentages
+// - trailingMaxDrawdown: (float64) Trailing drawdown that flattens the account
+func handleSetAutoLiquidation(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req setAutoLiquidationRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
 		}
 
-		startTime, err := time.Parse(time.RFC3339, startTimeStr)
+		settings, err := client.GetAutoLiquidation(ctx, req.AccountID)
 		if err != nil {
-			return nil, fmt.Errorf("invalid start time")
+			return nil, err
+		}
+		if settings.ChangesLocked {
+			return nil, fmt.Errorf("auto-liquidation settings of account %d are locked", req.AccountID)
 		}
 
-		endTimeStr, ok := params["endTime"].(string)
-		if !ok {
-			return nil, fmt.Errorf("missing endTime")
+		thresholds := []struct {
+			value *float64
+			field **float64
+		}{
+			{req.DailyLossAutoLiq, &settings.DailyLossAutoLiq},
+			{req.WeeklyLossAutoLiq, &settings.WeeklyLossAutoLiq},
+			{req.DailyProfitAutoLiq, &settings.DailyProfitAutoLiq},
+			{req.WeeklyProfitAutoLiq, &settings.WeeklyProfitAutoLiq},
+			{req.MarginPercentageAlert, &settings.MarginPercentageAlert},
+			{req.MarginPercentageLiqOnly, &settings.MarginPercentageLiqOnly},
+			{req.MarginPercentageAutoLiq, &settings.MarginPercentageAutoLiq},
+			{req.TrailingMaxDrawdown, &settings.TrailingMaxDrawdown},
 		}
+		changed := false
+		for _, threshold := range thresholds {
+			if threshold.value == nil {
+				continue
+			}
+			if *threshold.value == 0 {
+				*threshold.field = nil
+			} else {
+				*threshold.field = threshold.value
+			}
+			changed = true
+		}
+		if !changed {
+			return nil, fmt.Errorf("no auto-liquidation threshold to change")
+		}
+
+		settings.AccountID = req.AccountID
+		return client.SetAutoLiquidation(ctx, *settings)
+	}
+}
 
-		endTime, err := time.Parse(time.RFC3339, endTimeStr)
+// getProductsRequest holds the parameters of getProducts.
+type getProductsRequest struct {
+	Symbol string `json:"symbol" desc:"Only return the product with this root symbol, e.g. ES"`
+}
+
+// handleGetProducts processes product catalog requests. Each product comes
+// with its trading hours.
+// Optional parameters:
+// - symbol: (string) Only return the product with this root symbol, e.g. "ES"
+func handleGetProducts(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req getProductsRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+
+		products, err := client.GetProducts(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("invalid end time")
+			return nil, err
+		}
+		if req.Symbol != "" {
+			matching := products[:0]
+			for _, product := range products {
+				if strings.EqualFold(product.Name, req.Symbol) {
+					matching = append(matching, product)
+				}
+			}
+			if len(matching) == 0 {
+				return nil, fmt.Errorf("unknown product %q", req.Symbol)
+			}
+			products = matching
 		}
 
-		if endTime.Before(startTime) {
-			return nil, fmt.Errorf("end time must be after start time")
+		sessions, err := client.GetProductSessions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		hours := make(map[int]models.ProductSession, len(sessions))
+		for _, session := range sessions {
+			hours[session.ProductID] = session
+		}
+		for i := range products {
+			if session, ok := hours[products[i].ID]; ok {
+				products[i].TradingHours = &session
+			}
 		}
+		return products, nil
+	}
+}
 
-		interval, ok := params["interval"].(string)
-		if !ok {
-			return nil, fmt.Errorf("missing interval")
+// handleGetCashBalance processes cash balance requests.
+// Required parameters:
+// - accountId: (float64) The account ID to get the cash balance for (default: the active account, if set)
+func handleGetCashBalance(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req accountRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
 		}
+		return client.GetCashBalanceSnapshot(ctx, req.AccountID)
+	}
+}
 
-		return client.GetHistoricalData(int(contractID), startTime, endTime, interval)
+// handleGetMarginSnapshot processes margin snapshot requests.
+// Required parameters:
+// - accountId: (float64) The account ID to get the margin snapshot for
+func handleGetMarginSnapshot(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req accountRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		return client.GetMarginSnapshot(ctx, req.AccountID)
 	}
 }
 
-// handleGetRiskLimits processes risk limit requests.
+// getAccountAlertsRequest holds the parameters of getAccountAlerts.
+type getAccountAlertsRequest struct {
+	AccountID     int  `json:"accountId" validate:"gt=0" desc:"Only include broker notices about this account"`
+	UnhandledOnly bool `json:"unhandledOnly" desc:"Skip alerts that have been read or dealt with"`
+}
+
+// handleGetAccountAlerts processes account alert requests.
+// Optional parameters:
+// - accountId: (float64) Only include broker notices about this account (default: the active account, if set)
+// - unhandledOnly: (bool) Skip alerts that have been read or dealt with
+func handleGetAccountAlerts(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req getAccountAlertsRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		return client.GetAccountAlerts(ctx, req.AccountID, req.UnhandledOnly)
+	}
+}
+
+// handleGetAccountSummary processes account summary requests.
 // Required parameters:
-// - accountId: (float64) The account ID to get limits for
-func handleGetRiskLimits(client client.TradovateClientInterface) interface{} {
-	return func(params map[string]interface{}) (interface{}, error) {
-		accountIDFloat, ok := params["accountId"]
-		if !ok {
-			return nil, fmt.Errorf("missing accountId")
+// - accountId: (float64) The account ID to summarize
+func handleGetAccountSummary(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req accountRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
 		}
+		return client.GetAccountSummary(ctx, req.AccountID)
+	}
+}
 
-		accountID, ok := accountIDFloat.(float64)
-		if !ok {
-			return nil, fmt.Errorf("invalid type assertion for accountId")
+// aggregatedPnLRequest holds the parameters of aggregatedPnL.
+type aggregatedPnLRequest struct {
+	AccountID *int   `json:"accountId" validate:"gt=0" desc:"Only total this account (default: the active account, or else every account)"`
+	Period    string `json:"period" validate:"oneof=day week" desc:"day (default) or week"`
+}
+
+// handleAggregatedPnL processes requests for the realized and unrealized
+// P&L of one account or all of them, in total and by account. The day's P&L
+// is also broken down by contract.
+// Optional parameters:
+// - accountId: (float64) Only total this account (default: the active account, if set, or else every account)
+// - period: (string) day (default) or week
+func handleAggregatedPnL(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		req := aggregatedPnLRequest{Period: "day"}
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
 		}
 
-		if accountID < 0 {
-			return nil, fmt.Errorf("invalid accountId")
+		var accounts []models.Account
+		if req.AccountID != nil {
+			accounts = []models.Account{{ID: *req.AccountID}}
+		} else {
+			var err error
+			if accounts, err = client.GetAccounts(ctx); err != nil {
+				return nil, err
+			}
 		}
 
-		return client.GetRiskLimits(int(accountID))
+		report := models.PnLReport{Period: req.Period, Accounts: make([]models.PnLSummary, 0, len(accounts))}
+		for _, account := range accounts {
+			summary, err := client.GetPnLSummary(ctx, account.ID, req.Period)
+			if err != nil {
+				return nil, err
+			}
+			summary.AccountName = account.Name
+			report.RealizedPnL += summary.RealizedPnL
+			report.UnrealizedPnL += summary.UnrealizedPnL
+			report.TotalPnL += summary.TotalPnL
+			report.Accounts = append(report.Accounts, *summary)
+		}
+		return report, nil
 	}
 }
 
-// validateRequiredParams checks if all required parameters are present in the request.
-// It returns an error if any required parameter is missing.
-func validateRequiredParams(params map[string]interface{}, required []string) error {
-	for _, field := range required {
-		if _, ok := params[field]; !ok {
-			return fmt.Errorf("missing required field: %s", field)
+// defaultReplaySpeed plays a replay session back in real time.
+const defaultReplaySpeed = 100
+
+// initializeReplayClockRequest holds the parameters of initializeReplayClock.
+type initializeReplayClockRequest struct {
+	StartTimestamp string  `json:"startTimestamp" validate:"required" desc:"Point in history to start from, in RFC 3339 format"`
+	Speed          int     `json:"speed" validate:"gt=0" desc:"Playback speed in percent of real time (default 100)"`
+	InitialBalance float64 `json:"initialBalance" validate:"gte=0" desc:"Starting balance of the replay account"`
+}
+
+// handleInitializeReplayClock processes replay clock initialization requests.
+// Required parameters:
+// - startTimestamp: (string) Point in history to start from, in RFC3339 format
+// Optional parameters:
+// - speed: (float64) Playback speed in percent of real time (default 100)
+// - initialBalance: (float64) Starting balance of the replay account
+func handleInitializeReplayClock(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		req := initializeReplayClockRequest{Speed: defaultReplaySpeed}
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
 		}
+		startTime, err := time.Parse(time.RFC3339, req.StartTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid startTimestamp format: %w", err)
+		}
+		return client.InitializeReplayClock(ctx, startTime, req.Speed, req.InitialBalance)
 	}
-	return nil
 }
 
-// assertFloat64 attempts to convert an interface{} to float64.
-// It returns an error if the conversion fails.
-func assertFloat64(value interface{}, paramName string) (float64, error) {
-	switch v := value.(type) {
-	case float64:
-		return v, nil
-	default:
-		return 0, fmt.Errorf("invalid type assertion for %s", paramName)
+// changeReplaySpeedRequest holds the parameters of changeReplaySpeed.
+type changeReplaySpeedRequest struct {
+	Speed int `json:"speed" validate:"required,gt=0" desc:"Playback speed in percent of real time"`
+}
+
+// handleChangeReplaySpeed processes replay speed change requests.
+// Required parameters:
+// - speed: (float64) Playback speed in percent of real time
+func handleChangeReplaySpeed(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req changeReplaySpeedRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		if err := client.ChangeReplaySpeed(ctx, req.Speed); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"success": true, "speed": req.Speed}, nil
 	}
 }
 
-// assertString attempts to convert an interface{} to string.
-// It returns an error if the conversion fails.
-func assertString(value interface{}, paramName string) (string, error) {
-	switch v := value.(type) {
-	case string:
-		return v, nil
-	default:
-		return "", fmt.Errorf("invalid type assertion for %s", paramName)
+// checkOrderLimits rejects orders that break the configured risk limits or
+// trade a contract outside the symbol whitelist.
+func checkOrderLimits(ctx context.Context, client client.TradovateClientInterface, cfg *config.Config, order models.Order) error {
+	if limit := cfg.RiskLimits.MaxOrderQuantity; limit > 0 && order.Quantity > limit {
+		return fmt.Errorf("order quantity %d exceeds configured maximum of %d", order.Quantity, limit)
 	}
+	if len(cfg.AllowedSymbols) > 0 {
+		contract, err := client.GetContract(ctx, order.ContractID)
+		if err != nil {
+			return fmt.Errorf("failed to check symbol whitelist: %w", err)
+		}
+		if !cfg.SymbolAllowed(contract.Name) {
+			return fmt.Errorf("contract %s is not in the configured symbol whitelist", contract.Name)
+		}
+	}
+	return nil
 }
@@ -1,13 +1,19 @@
 package handlers
 
 import (
+	"context"
 	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/config"
 	"github.com/0xjmp/mcp-tradovate/internal/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Command represents a command request
@@ -19,90 +25,434 @@ type Command struct {
 
 // MockTradovateClient is a mock implementation for testing
 type MockTradovateClient struct {
-	setRiskLimitsFunc     func(models.RiskLimit) error
-	authenticateFunc      func() (*client.AuthResponse, error)
-	getAccountsFunc       func() ([]models.Account, error)
-	placeOrderFunc        func(models.Order) (*models.Order, error)
-	cancelOrderFunc       func(int) error
-	getFillsFunc          func(int) ([]models.Fill, error)
-	getPositionsFunc      func() ([]models.Position, error)
-	getContractsFunc      func() ([]models.Contract, error)
-	getMarketDataFunc     func(int) (*models.MarketData, error)
-	getRiskLimitsFunc     func(int) (*models.RiskLimit, error)
-	getHistoricalDataFunc func(int, time.Time, time.Time, string) ([]models.HistoricalData, error)
-}
-
-func (m *MockTradovateClient) SetRiskLimits(limits models.RiskLimit) error {
+	setRiskLimitsFunc               func(models.RiskLimit) error
+	authenticateFunc                func() (*client.AuthResponse, error)
+	getAccountsFunc                 func() ([]models.Account, error)
+	placeOrderFunc                  func(models.Order) (*models.Order, error)
+	cancelOrderFunc                 func(int) error
+	getFillsFunc                    func(int) ([]models.Fill, error)
+	getPositionsFunc                func() ([]models.Position, error)
+	getContractsFunc                func() ([]models.Contract, error)
+	getContractFunc                 func(int) (*models.Contract, error)
+	getMaturityFunc                 func(int) (*models.ContractMaturity, error)
+	getMarketDataFunc               func(int) (*models.MarketData, error)
+	getRiskLimitsFunc               func(int) (*models.RiskLimit, error)
+	getHistoricalDataFunc           func(int, time.Time, time.Time, string) ([]models.HistoricalData, error)
+	initializeReplayClockFunc       func(time.Time, int, float64) (*client.ReplayClock, error)
+	changeReplaySpeedFunc           func(int) error
+	modifyOrderFunc                 func(int, models.OrderChanges) error
+	placeOCOFunc                    func(models.OCOOrder) (*models.OCOOrder, error)
+	placeBracketOrderFunc           func(models.BracketOrder) (*models.OrderStrategy, error)
+	liquidatePositionFunc           func(int, int) (int, error)
+	getOrdersFunc                   func(int, string) ([]models.Order, error)
+	getOrderFunc                    func(int) (*models.Order, error)
+	getExecutionReportsFunc         func(int, int) ([]models.ExecutionReport, error)
+	getCommandReportsFunc           func(int) ([]models.CommandReport, error)
+	getProductsFunc                 func() ([]models.Product, error)
+	getContractMaturitiesFunc       func(int) ([]models.ContractMaturity, error)
+	getMarginSnapshotFunc           func(int) (*models.MarginSnapshot, error)
+	getProductFeesFunc              func([]int) ([]models.ProductFees, error)
+	getAccountPermissionsFunc       func(int) (*models.AccountPermissions, error)
+	getUserPropertiesFunc           func() ([]models.UserProperty, error)
+	getUserPluginsFunc              func() ([]models.UserPlugin, error)
+	getPositionsByAccountFunc       func(int) ([]models.Position, error)
+	isAuthenticatedFunc             func() bool
+	tokenExpiresAtFunc              func() time.Time
+	resolveAccountFunc              func(string) (*models.Account, error)
+	authenticateWithCredentialsFunc func(models.Credentials) (*client.AuthResponse, error)
+	getServerTimeFunc               func() (time.Time, error)
+	clockSkewFunc                   func() time.Duration
+	getMarketDataBatchFunc          func([]int) ([]models.MarketDataResult, error)
+	getCashBalanceSnapshotFunc      func(int) (*models.CashBalanceSnapshot, error)
+	getAccountSummaryFunc           func(int) (*models.AccountSummary, error)
+	getAutoLiquidationFunc          func(int) (*models.AutoLiquidation, error)
+	setAutoLiquidationFunc          func(models.AutoLiquidation) (*models.AutoLiquidation, error)
+	resolveFrontMonthFunc           func(string) (*models.FrontMonth, error)
+	getAccountAlertsFunc            func(int, bool) ([]models.AccountAlert, error)
+	searchContractsFunc             func(text string, limit int) ([]models.ContractMatch, error)
+	findContractFunc                func(name string) (*models.Contract, error)
+	subscribeQuotesFunc             func(ctx context.Context, contractID int) (<-chan models.MarketData, error)
+	getProductSessionsFunc          func() ([]models.ProductSession, error)
+	getCommandHistoryFunc           func(orderID, accountID int) ([]models.Command, error)
+	getAccountFillsFunc             func(int, time.Time, time.Time) ([]models.Fill, error)
+	getPnLSummaryFunc               func(accountID int, period string) (*models.PnLSummary, error)
+	getContractMarginFunc           func(int) (*models.ProductMargin, error)
+	rateLimitStatusFunc             func() models.RateLimitStatus
+	connectionStatusFunc            func() models.ConnectionStatus
+}
+
+func (m *MockTradovateClient) SetRiskLimits(ctx context.Context, limits models.RiskLimit) error {
 	if m.setRiskLimitsFunc != nil {
 		return m.setRiskLimitsFunc(limits)
 	}
 	return nil
 }
 
-func (m *MockTradovateClient) Authenticate() (*client.AuthResponse, error) {
+func (m *MockTradovateClient) Authenticate(ctx context.Context) (*client.AuthResponse, error) {
 	if m.authenticateFunc != nil {
 		return m.authenticateFunc()
 	}
 	return nil, nil
 }
 
-func (m *MockTradovateClient) GetAccounts() ([]models.Account, error) {
+func (m *MockTradovateClient) GetAccounts(ctx context.Context) ([]models.Account, error) {
 	if m.getAccountsFunc != nil {
 		return m.getAccountsFunc()
 	}
 	return nil, nil
 }
 
-func (m *MockTradovateClient) GetRiskLimits(accountID int) (*models.RiskLimit, error) {
+func (m *MockTradovateClient) GetRiskLimits(ctx context.Context, accountID int) (*models.RiskLimit, error) {
 	if m.getRiskLimitsFunc != nil {
 		return m.getRiskLimitsFunc(accountID)
 	}
 	return nil, nil
 }
 
-func (m *MockTradovateClient) PlaceOrder(order models.Order) (*models.Order, error) {
+func (m *MockTradovateClient) PlaceOrder(ctx context.Context, order models.Order) (*models.Order, error) {
 	if m.placeOrderFunc != nil {
 		return m.placeOrderFunc(order)
 	}
 	return nil, nil
 }
 
-func (m *MockTradovateClient) CancelOrder(orderID int) error {
+func (m *MockTradovateClient) CancelOrder(ctx context.Context, orderID int) error {
 	if m.cancelOrderFunc != nil {
 		return m.cancelOrderFunc(orderID)
 	}
 	return nil
 }
 
-func (m *MockTradovateClient) GetFills(orderID int) ([]models.Fill, error) {
+func (m *MockTradovateClient) GetFills(ctx context.Context, orderID int) ([]models.Fill, error) {
 	if m.getFillsFunc != nil {
 		return m.getFillsFunc(orderID)
 	}
 	return nil, nil
 }
 
-func (m *MockTradovateClient) GetPositions() ([]models.Position, error) {
+func (m *MockTradovateClient) GetPositions(ctx context.Context) ([]models.Position, error) {
 	if m.getPositionsFunc != nil {
 		return m.getPositionsFunc()
 	}
 	return nil, nil
 }
 
-func (m *MockTradovateClient) GetContracts() ([]models.Contract, error) {
+func (m *MockTradovateClient) GetContracts(ctx context.Context) ([]models.Contract, error) {
 	if m.getContractsFunc != nil {
 		return m.getContractsFunc()
 	}
 	return nil, nil
 }
 
-func (m *MockTradovateClient) GetMarketData(contractID int) (*models.MarketData, error) {
+func (m *MockTradovateClient) GetContract(ctx context.Context, contractID int) (*models.Contract, error) {
+	if m.getContractFunc != nil {
+		return m.getContractFunc(contractID)
+	}
+	return nil, nil
+}
+
+func (m *MockTradovateClient) GetContractMaturity(ctx context.Context, maturityID int) (*models.ContractMaturity, error) {
+	if m.getMaturityFunc != nil {
+		return m.getMaturityFunc(maturityID)
+	}
+	return nil, nil
+}
+
+func (m *MockTradovateClient) GetMarketData(ctx context.Context, contractID int) (*models.MarketData, error) {
 	if m.getMarketDataFunc != nil {
 		return m.getMarketDataFunc(contractID)
 	}
 	return nil, nil
 }
 
-func (m *MockTradovateClient) GetHistoricalData(contractID int, startTime, endTime time.Time, interval string) ([]models.HistoricalData, error) {
+func (m *MockTradovateClient) InitializeReplayClock(ctx context.Context, startTime time.Time, speed int, initialBalance float64) (*client.ReplayClock, error) {
+	if m.initializeReplayClockFunc != nil {
+		return m.initializeReplayClockFunc(startTime, speed, initialBalance)
+	}
+	return nil, nil
+}
+
+func (m *MockTradovateClient) ChangeReplaySpeed(ctx context.Context, speed int) error {
+	if m.changeReplaySpeedFunc != nil {
+		return m.changeReplaySpeedFunc(speed)
+	}
+	return nil
+}
+
+func (m *MockTradovateClient) ModifyOrder(ctx context.Context, orderID int, changes models.OrderChanges) error {
+	if m.modifyOrderFunc != nil {
+		return m.modifyOrderFunc(orderID, changes)
+	}
+	return nil
+}
+
+func (m *MockTradovateClient) PlaceOCO(ctx context.Context, oco models.OCOOrder) (*models.OCOOrder, error) {
+	if m.placeOCOFunc != nil {
+		return m.placeOCOFunc(oco)
+	}
+	return &oco, nil
+}
+
+func (m *MockTradovateClient) PlaceBracketOrder(ctx context.Context, bracket models.BracketOrder) (*models.OrderStrategy, error) {
+	if m.placeBracketOrderFunc != nil {
+		return m.placeBracketOrderFunc(bracket)
+	}
+	return &models.OrderStrategy{ID: 1, AccountID: bracket.AccountID, ContractID: bracket.ContractID}, nil
+}
+
+func (m *MockTradovateClient) LiquidatePosition(ctx context.Context, accountID, contractID int) (int, error) {
+	if m.liquidatePositionFunc != nil {
+		return m.liquidatePositionFunc(accountID, contractID)
+	}
+	return 1, nil
+}
+
+func (m *MockTradovateClient) GetOrders(ctx context.Context, accountID int, status string) ([]models.Order, error) {
+	if m.getOrdersFunc != nil {
+		return m.getOrdersFunc(accountID, status)
+	}
+	return []models.Order{}, nil
+}
+
+func (m *MockTradovateClient) GetOrder(ctx context.Context, orderID int) (*models.Order, error) {
+	if m.getOrderFunc != nil {
+		return m.getOrderFunc(orderID)
+	}
+	return &models.Order{ID: orderID, Status: "Working"}, nil
+}
+
+func (m *MockTradovateClient) GetExecutionReports(ctx context.Context, orderID, accountID int) ([]models.ExecutionReport, error) {
+	if m.getExecutionReportsFunc != nil {
+		return m.getExecutionReportsFunc(orderID, accountID)
+	}
+	return []models.ExecutionReport{}, nil
+}
+
+func (m *MockTradovateClient) GetCommandReports(ctx context.Context, orderID int) ([]models.CommandReport, error) {
+	if m.getCommandReportsFunc != nil {
+		return m.getCommandReportsFunc(orderID)
+	}
+	return []models.CommandReport{}, nil
+}
+
+func (m *MockTradovateClient) GetProducts(ctx context.Context) ([]models.Product, error) {
+	if m.getProductsFunc != nil {
+		return m.getProductsFunc()
+	}
+	return []models.Product{}, nil
+}
+
+func (m *MockTradovateClient) GetContractMaturities(ctx context.Context, productID int) ([]models.ContractMaturity, error) {
+	if m.getContractMaturitiesFunc != nil {
+		return m.getContractMaturitiesFunc(productID)
+	}
+	return []models.ContractMaturity{}, nil
+}
+
+func (m *MockTradovateClient) GetMarginSnapshot(ctx context.Context, accountID int) (*models.MarginSnapshot, error) {
+	if m.getMarginSnapshotFunc != nil {
+		return m.getMarginSnapshotFunc(accountID)
+	}
+	return &models.MarginSnapshot{AccountID: accountID}, nil
+}
+
+func (m *MockTradovateClient) GetProductFees(ctx context.Context, productIDs []int) ([]models.ProductFees, error) {
+	if m.getProductFeesFunc != nil {
+		return m.getProductFeesFunc(productIDs)
+	}
+	return []models.ProductFees{}, nil
+}
+
+func (m *MockTradovateClient) GetAccountPermissions(ctx context.Context, accountID int) (*models.AccountPermissions, error) {
+	if m.getAccountPermissionsFunc != nil {
+		return m.getAccountPermissionsFunc(accountID)
+	}
+	return &models.AccountPermissions{AccountID: accountID, CanTrade: true}, nil
+}
+
+func (m *MockTradovateClient) GetUserProperties(ctx context.Context) ([]models.UserProperty, error) {
+	if m.getUserPropertiesFunc != nil {
+		return m.getUserPropertiesFunc()
+	}
+	return []models.UserProperty{}, nil
+}
+
+func (m *MockTradovateClient) GetUserPlugins(ctx context.Context) ([]models.UserPlugin, error) {
+	if m.getUserPluginsFunc != nil {
+		return m.getUserPluginsFunc()
+	}
+	return []models.UserPlugin{}, nil
+}
+
+func (m *MockTradovateClient) GetPositionsByAccount(ctx context.Context, accountID int) ([]models.Position, error) {
+	if m.getPositionsByAccountFunc != nil {
+		return m.getPositionsByAccountFunc(accountID)
+	}
+	return []models.Position{}, nil
+}
+
+func (m *MockTradovateClient) IsAuthenticated() bool {
+	if m.isAuthenticatedFunc != nil {
+		return m.isAuthenticatedFunc()
+	}
+	return true
+}
+
+func (m *MockTradovateClient) TokenExpiresAt() time.Time {
+	if m.tokenExpiresAtFunc != nil {
+		return m.tokenExpiresAtFunc()
+	}
+	return time.Time{}
+}
+
+func (m *MockTradovateClient) ResolveAccount(ctx context.Context, account string) (*models.Account, error) {
+	if m.resolveAccountFunc != nil {
+		return m.resolveAccountFunc(account)
+	}
+	return &models.Account{ID: 1, Name: account, Active: true}, nil
+}
+
+func (m *MockTradovateClient) AuthenticateWithCredentials(ctx context.Context, creds models.Credentials) (*client.AuthResponse, error) {
+	if m.authenticateWithCredentialsFunc != nil {
+		return m.authenticateWithCredentialsFunc(creds)
+	}
+	return nil, nil
+}
+
+func (m *MockTradovateClient) GetServerTime(ctx context.Context) (time.Time, error) {
+	if m.getServerTimeFunc != nil {
+		return m.getServerTimeFunc()
+	}
+	return time.Now(), nil
+}
+
+func (m *MockTradovateClient) ClockSkew() time.Duration {
+	if m.clockSkewFunc != nil {
+		return m.clockSkewFunc()
+	}
+	return 0
+}
+
+func (m *MockTradovateClient) GetMarketDataBatch(ctx context.Context, contractIDs []int) ([]models.MarketDataResult, error) {
+	if m.getMarketDataBatchFunc != nil {
+		return m.getMarketDataBatchFunc(contractIDs)
+	}
+	return nil, nil
+}
+
+func (m *MockTradovateClient) GetCashBalanceSnapshot(ctx context.Context, accountID int) (*models.CashBalanceSnapshot, error) {
+	if m.getCashBalanceSnapshotFunc != nil {
+		return m.getCashBalanceSnapshotFunc(accountID)
+	}
+	return &models.CashBalanceSnapshot{}, nil
+}
+
+func (m *MockTradovateClient) GetAccountSummary(ctx context.Context, accountID int) (*models.AccountSummary, error) {
+	if m.getAccountSummaryFunc != nil {
+		return m.getAccountSummaryFunc(accountID)
+	}
+	return &models.AccountSummary{}, nil
+}
+
+func (m *MockTradovateClient) GetAutoLiquidation(ctx context.Context, accountID int) (*models.AutoLiquidation, error) {
+	if m.getAutoLiquidationFunc != nil {
+		return m.getAutoLiquidationFunc(accountID)
+	}
+	return &models.AutoLiquidation{AccountID: accountID}, nil
+}
+
+func (m *MockTradovateClient) SetAutoLiquidation(ctx context.Context, settings models.AutoLiquidation) (*models.AutoLiquidation, error) {
+	if m.setAutoLiquidationFunc != nil {
+		return m.setAutoLiquidationFunc(settings)
+	}
+	return &settings, nil
+}
+
+func (m *MockTradovateClient) ResolveFrontMonth(ctx context.Context, productSymbol string) (*models.FrontMonth, error) {
+	if m.resolveFrontMonthFunc != nil {
+		return m.resolveFrontMonthFunc(productSymbol)
+	}
+	return &models.FrontMonth{Product: productSymbol}, nil
+}
+
+func (m *MockTradovateClient) GetAccountAlerts(ctx context.Context, accountID int, unhandledOnly bool) ([]models.AccountAlert, error) {
+	if m.getAccountAlertsFunc != nil {
+		return m.getAccountAlertsFunc(accountID, unhandledOnly)
+	}
+	return nil, nil
+}
+
+func (m *MockTradovateClient) SearchContracts(ctx context.Context, text string, limit int) ([]models.ContractMatch, error) {
+	if m.searchContractsFunc != nil {
+		return m.searchContractsFunc(text, limit)
+	}
+	return []models.ContractMatch{}, nil
+}
+
+func (m *MockTradovateClient) FindContract(ctx context.Context, name string) (*models.Contract, error) {
+	if m.findContractFunc != nil {
+		return m.findContractFunc(name)
+	}
+	return nil, errors.New("unknown contract " + name)
+}
+
+func (m *MockTradovateClient) SubscribeQuotes(ctx context.Context, contractID int) (<-chan models.MarketData, error) {
+	if m.subscribeQuotesFunc != nil {
+		return m.subscribeQuotesFunc(ctx, contractID)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockTradovateClient) GetProductSessions(ctx context.Context) ([]models.ProductSession, error) {
+	if m.getProductSessionsFunc != nil {
+		return m.getProductSessionsFunc()
+	}
+	return []models.ProductSession{}, nil
+}
+
+func (m *MockTradovateClient) GetCommandHistory(ctx context.Context, orderID, accountID int) ([]models.Command, error) {
+	if m.getCommandHistoryFunc != nil {
+		return m.getCommandHistoryFunc(orderID, accountID)
+	}
+	return []models.Command{}, nil
+}
+
+func (m *MockTradovateClient) GetAccountFills(ctx context.Context, accountID int, start, end time.Time) ([]models.Fill, error) {
+	if m.getAccountFillsFunc != nil {
+		return m.getAccountFillsFunc(accountID, start, end)
+	}
+	return []models.Fill{}, nil
+}
+
+func (m *MockTradovateClient) GetPnLSummary(ctx context.Context, accountID int, period string) (*models.PnLSummary, error) {
+	if m.getPnLSummaryFunc != nil {
+		return m.getPnLSummaryFunc(accountID, period)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockTradovateClient) GetContractMargin(ctx context.Context, contractID int) (*models.ProductMargin, error) {
+	if m.getContractMarginFunc != nil {
+		return m.getContractMarginFunc(contractID)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockTradovateClient) RateLimitStatus() models.RateLimitStatus {
+	if m.rateLimitStatusFunc != nil {
+		return m.rateLimitStatusFunc()
+	}
+	return models.RateLimitStatus{}
+}
+
+func (m *MockTradovateClient) ConnectionStatus() models.ConnectionStatus {
+	if m.connectionStatusFunc != nil {
+		return m.connectionStatusFunc()
+	}
+	return models.ConnectionStatus{Environment: "demo"}
+}
+
+func (m *MockTradovateClient) GetHistoricalData(ctx context.Context, contractID int, startTime, endTime time.Time, interval string) ([]models.HistoricalData, error) {
 	if m.getHistoricalDataFunc != nil {
 		return m.getHistoricalDataFunc(contractID, startTime, endTime, interval)
 	}
@@ -155,7 +505,7 @@ func TestHandleAuthenticate(t *testing.T) {
 			handlers := NewHandlers(mockClient)
 			authHandler := handlers["authenticate"]
 
-			result, err := authHandler.Handler(nil)
+			result, err := authHandler.Handler(context.Background(), nil)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -210,7 +560,7 @@ func TestHandleSetRiskLimits(t *testing.T) {
 				"trailingStop":   float64(50.0),
 			},
 			wantErr: true,
-			errMsg:  "missing or invalid accountId",
+			errMsg:  "missing required field: accountId",
 		},
 		{
 			name: "Invalid day max loss",
@@ -222,7 +572,7 @@ func TestHandleSetRiskLimits(t *testing.T) {
 				"trailingStop":   float64(50.0),
 			},
 			wantErr: true,
-			errMsg:  "missing or invalid dayMaxLoss",
+			errMsg:  "invalid dayMaxLoss",
 		},
 		{
 			name: "Invalid max drawdown",
@@ -234,7 +584,7 @@ func TestHandleSetRiskLimits(t *testing.T) {
 				"trailingStop":   float64(50.0),
 			},
 			wantErr: true,
-			errMsg:  "missing or invalid maxDrawdown",
+			errMsg:  "invalid maxDrawdown",
 		},
 		{
 			name: "Invalid max position quantity",
@@ -246,7 +596,7 @@ func TestHandleSetRiskLimits(t *testing.T) {
 				"trailingStop":   float64(50.0),
 			},
 			wantErr: true,
-			errMsg:  "missing or invalid maxPositionQty",
+			errMsg:  "invalid maxPositionQty",
 		},
 		{
 			name: "Invalid trailing stop",
@@ -258,7 +608,7 @@ func TestHandleSetRiskLimits(t *testing.T) {
 				"trailingStop":   float64(-50.0),
 			},
 			wantErr: true,
-			errMsg:  "missing or invalid trailingStop",
+			errMsg:  "invalid trailingStop",
 		},
 	}
 
@@ -272,9 +622,11 @@ func TestHandleSetRiskLimits(t *testing.T) {
 			handlers := NewHandlers(mockClient)
 			setRiskLimitsHandler := handlers["setRiskLimits"]
 
-			_, err := setRiskLimitsHandler.Handler(tt.params)
+			_, err := setRiskLimitsHandler.Handler(context.Background(), tt.params)
 
-			if tt.wantErr {
+			if tt.errMsg != "" {
+				assert.EqualError(t, err, tt.errMsg)
+			} else if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
@@ -307,6 +659,57 @@ func TestHandlePlaceOrder(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Client order ID",
+			params: map[string]interface{}{
+				"accountId":     float64(12345),
+				"contractId":    float64(54321),
+				"orderType":     "Market",
+				"quantity":      float64(1),
+				"timeInForce":   "Day",
+				"clientOrderId": "entry-1",
+			},
+			mockFn: func(order models.Order) (*models.Order, error) {
+				if order.ClientOrderID != "entry-1" {
+					return nil, errors.New("client order ID not passed on")
+				}
+				order.ID = 67890
+				return &order, nil
+			},
+			wantErr: false,
+		},
+		{
+			name: "Activation time",
+			params: map[string]interface{}{
+				"accountId":      float64(12345),
+				"contractId":     float64(54321),
+				"orderType":      "Market",
+				"quantity":       float64(1),
+				"timeInForce":    "Day",
+				"activationTime": "2030-06-03T09:30:00-04:00",
+			},
+			mockFn: func(order models.Order) (*models.Order, error) {
+				if order.ActivationTime != "2030-06-03T09:30:00-04:00" {
+					return nil, errors.New("activation time not passed on")
+				}
+				order.ID = 67890
+				return &order, nil
+			},
+			wantErr: false,
+		},
+		{
+			name: "Invalid client order ID",
+			params: map[string]interface{}{
+				"accountId":     float64(12345),
+				"contractId":    float64(54321),
+				"orderType":     "Market",
+				"quantity":      float64(1),
+				"timeInForce":   "Day",
+				"clientOrderId": float64(1),
+			},
+			wantErr: true,
+			errMsg:  "invalid type assertion for clientOrderId",
+		},
 		{
 			name: "Missing required fields",
 			params: map[string]interface{}{
@@ -317,12 +720,12 @@ func TestHandlePlaceOrder(t *testing.T) {
 				return nil, errors.New("missing required fields")
 			},
 			wantErr: true,
-			errMsg:  "missing required field: contractId",
+			errMsg:  "missing required field: orderType",
 		},
 		{
 			name: "Invalid field type",
 			params: map[string]interface{}{
-				"accountId":   "12345", // String instead of float64
+				"accountId":   "account-1", // Not a number
 				"contractId":  float64(54321),
 				"orderType":   "Limit",
 				"price":       float64(100.50),
@@ -348,7 +751,22 @@ func TestHandlePlaceOrder(t *testing.T) {
 				return nil, errors.New("price required for limit order")
 			},
 			wantErr: true,
-			errMsg:  "price is required for Limit orders",
+			errMsg:  "price is required when orderType is Limit",
+		},
+		{
+			name: "Zero quantity",
+			params: map[string]interface{}{
+				"accountId":   float64(12345),
+				"contractId":  float64(54321),
+				"orderType":   "Market",
+				"quantity":    float64(0),
+				"timeInForce": "Day",
+			},
+			mockFn: func(order models.Order) (*models.Order, error) {
+				return nil, errors.New("zero quantity")
+			},
+			wantErr: true,
+			errMsg:  "invalid quantity",
 		},
 	}
 
@@ -360,7 +778,7 @@ func TestHandlePlaceOrder(t *testing.T) {
 			handlers := NewHandlers(mockClient)
 			placeOrderHandler := handlers["placeOrder"]
 
-			result, err := placeOrderHandler.Handler(tt.params)
+			result, err := placeOrderHandler.Handler(context.Background(), tt.params)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -414,7 +832,7 @@ func TestHandleCancelOrder(t *testing.T) {
 			handlers := NewHandlers(mockClient)
 			cancelOrderHandler := handlers["cancelOrder"]
 
-			result, err := cancelOrderHandler.Handler(tt.params)
+			result, err := cancelOrderHandler.Handler(context.Background(), tt.params)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -473,7 +891,7 @@ func TestHandleGetFills(t *testing.T) {
 			handlers := NewHandlers(mockClient)
 			getFillsHandler := handlers["getFills"]
 
-			result, err := getFillsHandler.Handler(tt.params)
+			result, err := getFillsHandler.Handler(context.Background(), tt.params)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -495,16 +913,61 @@ func TestNewHandlers(t *testing.T) {
 	// Test all handler registrations
 	expectedHandlers := []string{
 		"authenticate",
+		"getAuthStatus",
+		"getServerTime",
+		"getServerStatus",
+		"getRateLimitStatus",
+		"selectAccount",
 		"getAccounts",
 		"getPositions",
+		"getExpiringExposure",
 		"placeOrder",
+		"placeOcoOrder",
+		"placeBracketOrder",
+		"riskCheck",
+		"marginPreview",
+		"modifyOrder",
+		"liquidatePosition",
+		"closePosition",
+		"reversePosition",
 		"cancelOrder",
+		"getOrder",
+		"listOrders",
+		"getExecutionReports",
+		"annotateTrade",
+		"getJournal",
+		"getCommandHistory",
 		"getFills",
+		"getFillsByAccount",
 		"getContracts",
+		"getProducts",
+		"resolveFrontMonth",
+		"searchContracts",
 		"getMarketData",
+		"getQuotes",
+		"subscribeMarketData",
+		"readMarketData",
+		"unsubscribeMarketData",
+		"listSubscriptions",
+		"createPriceAlert",
+		"listAlerts",
+		"deleteAlert",
 		"getHistoricalData",
+		"exportHistoricalData",
+		"resampleHistoricalData",
 		"setRiskLimits",
+		"getAccountPermissions",
+		"getCashBalance",
+		"getMarginSnapshot",
+		"getAccountSummary",
+		"aggregatedPnL",
+		"portfolioSummary",
+		"getAccountAlerts",
 		"getRiskLimits",
+		"getAutoLiquidation",
+		"setAutoLiquidation",
+		"initializeReplayClock",
+		"changeReplaySpeed",
 	}
 
 	for _, name := range expectedHandlers {
@@ -529,7 +992,7 @@ func TestGetAccountsHandler(t *testing.T) {
 	}
 
 	handlers := NewHandlers(mockClient)
-	result, err := handlers["getAccounts"].Handler(nil)
+	result, err := handlers["getAccounts"].Handler(context.Background(), nil)
 	assert.NoError(t, err)
 	assert.Equal(t, mockAccounts, result)
 }
@@ -546,11 +1009,192 @@ func TestGetPositionsHandler(t *testing.T) {
 	}
 
 	handlers := NewHandlers(mockClient)
-	result, err := handlers["getPositions"].Handler(nil)
+	result, err := handlers["getPositions"].Handler(context.Background(), nil)
 	assert.NoError(t, err)
 	assert.Equal(t, mockPositions, result)
 }
 
+func TestGetPositionsHandlerByAccount(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		getPositionsFunc: func() ([]models.Position, error) {
+			t.Error("all positions fetched for a single account")
+			return nil, nil
+		},
+		getPositionsByAccountFunc: func(accountID int) ([]models.Position, error) {
+			return []models.Position{{ID: 1, AccountID: accountID}}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getPositions"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"accountId": float64(123)})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Position{{ID: 1, AccountID: 123}}, result)
+
+	result, err = handler(context.Background(), map[string]interface{}{"accountId": "123"})
+	require.NoError(t, err, "numeric strings are accepted")
+	assert.Equal(t, []models.Position{{ID: 1, AccountID: 123}}, result)
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": "account-123"})
+	assert.Error(t, err)
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(0)})
+	assert.EqualError(t, err, "invalid accountId")
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": 123.5})
+	assert.EqualError(t, err, "invalid type assertion for accountId")
+}
+
+func TestGetPositionsHandlerBySymbol(t *testing.T) {
+	lookups := 0
+	mockClient := &MockTradovateClient{
+		getPositionsFunc: func() ([]models.Position, error) {
+			return []models.Position{
+				{ID: 1, AccountID: 123, ContractID: 1234, NetPos: 2},
+				{ID: 2, AccountID: 456, ContractID: 5678, NetPos: -1},
+				{ID: 3, AccountID: 456, ContractID: 1234, NetPos: 1},
+				{ID: 4, AccountID: 456, ContractID: 9999, NetPos: 1},
+			}, nil
+		},
+		getContractFunc: func(contractID int) (*models.Contract, error) {
+			lookups++
+			switch contractID {
+			case 1234:
+				return &models.Contract{ID: contractID, Name: "MNQZ4"}, nil
+			case 5678:
+				return &models.Contract{ID: contractID, Name: "MESZ4"}, nil
+			}
+			return nil, errors.New("contract not found")
+		},
+	}
+	handler := NewHandlers(mockClient)["getPositions"].Handler
+
+	result, err := handler(context.Background(), nil)
+	require.NoError(t, err)
+	positions := result.([]models.Position)
+	require.Len(t, positions, 4)
+	assert.Equal(t, "MNQZ4", positions[0].Symbol)
+	assert.Equal(t, "MESZ4", positions[1].Symbol)
+	assert.Empty(t, positions[3].Symbol, "an unknown contract is left unnamed")
+	assert.Equal(t, 3, lookups, "each contract is looked up once")
+
+	result, err = handler(context.Background(), map[string]interface{}{"symbol": "mnqz4"})
+	require.NoError(t, err)
+	positions = result.([]models.Position)
+	require.Len(t, positions, 2)
+	assert.Equal(t, 1, positions[0].ID)
+	assert.Equal(t, 3, positions[1].ID)
+
+	result, err = handler(context.Background(), map[string]interface{}{"symbol": "ESZ4"})
+	require.NoError(t, err)
+	assert.Empty(t, result)
+
+	mockClient.resolveFrontMonthFunc = func(product string) (*models.FrontMonth, error) {
+		return &models.FrontMonth{Product: product, Contract: models.Contract{ID: 5678, Name: "MESZ4"}}, nil
+	}
+	result, err = handler(context.Background(), map[string]interface{}{"symbol": "MES front month"})
+	require.NoError(t, err)
+	positions = result.([]models.Position)
+	require.Len(t, positions, 1)
+	assert.Equal(t, 2, positions[0].ID)
+	assert.Equal(t, "MESZ4", positions[0].Symbol)
+
+	_, err = handler(context.Background(), map[string]interface{}{"symbol": " "})
+	assert.EqualError(t, err, "invalid symbol")
+}
+
+func TestGetPositionsHandlerFilterAndSort(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		getPositionsFunc: func() ([]models.Position, error) {
+			return []models.Position{
+				{ID: 1, ContractID: 1, NetPos: 0, RealizedPL: 300},
+				{ID: 2, ContractID: 2, NetPos: 3, UnrealizedPL: -250},
+				{ID: 3, ContractID: 3, NetPos: -1, UnrealizedPL: 120},
+				{ID: 4, ContractID: 4, NetPos: -5, UnrealizedPL: -900},
+			}, nil
+		},
+		getContractFunc: func(contractID int) (*models.Contract, error) {
+			return &models.Contract{ID: contractID, Name: map[int]string{1: "NQZ4", 2: "ESZ4", 3: "CLF5", 4: "GCG5"}[contractID]}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getPositions"].Handler
+	ids := func(params map[string]interface{}) []int {
+		result, err := handler(context.Background(), params)
+		require.NoError(t, err)
+		var ids []int
+		for _, position := range result.([]models.Position) {
+			ids = append(ids, position.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []int{2, 3, 4}, ids(map[string]interface{}{"onlyOpen": true}))
+	assert.Equal(t, []int{2, 4}, ids(map[string]interface{}{"minAbsNetPos": float64(2)}))
+	assert.Equal(t, []int{4, 2}, ids(map[string]interface{}{"onlyLosing": true, "sortBy": "unrealizedPL"}))
+	assert.Equal(t, []int{3, 1, 2, 4}, ids(map[string]interface{}{"sortBy": "unrealizedPL", "descending": true}))
+	assert.Equal(t, []int{3, 2, 4, 1}, ids(map[string]interface{}{"sortBy": "symbol"}))
+	assert.Equal(t, []int{4, 3, 1, 2}, ids(map[string]interface{}{"sortBy": "netPos"}))
+
+	_, err := handler(context.Background(), map[string]interface{}{"sortBy": "pnl"})
+	assert.EqualError(t, err, "invalid sortBy: must be one of unrealizedPL, realizedPL, netPos, symbol")
+	_, err = handler(context.Background(), map[string]interface{}{"minAbsNetPos": float64(-1)})
+	assert.EqualError(t, err, "invalid minAbsNetPos")
+}
+
+func TestContractBySymbol(t *testing.T) {
+	var placed models.Order
+	var quoted int
+	mockClient := &MockTradovateClient{
+		findContractFunc: func(name string) (*models.Contract, error) {
+			if name == "MESZ4" {
+				return &models.Contract{ID: 5678, Name: name}, nil
+			}
+			return nil, errors.New("unknown contract " + name)
+		},
+		resolveFrontMonthFunc: func(product string) (*models.FrontMonth, error) {
+			if !strings.EqualFold(product, "ES") {
+				return nil, errors.New("unknown product " + product)
+			}
+			return &models.FrontMonth{Product: product, Contract: models.Contract{ID: 1234, Name: "ESZ4"}}, nil
+		},
+		placeOrderFunc: func(order models.Order) (*models.Order, error) {
+			placed = order
+			return &order, nil
+		},
+		getMarketDataFunc: func(contractID int) (*models.MarketData, error) {
+			quoted = contractID
+			return &models.MarketData{ContractID: contractID}, nil
+		},
+	}
+	h := NewHandlers(mockClient)
+
+	order := map[string]interface{}{"accountId": float64(1), "symbol": "MESZ4", "orderType": "Market", "quantity": float64(1), "timeInForce": "Day"}
+	_, err := h["placeOrder"].Handler(context.Background(), order)
+	require.NoError(t, err)
+	assert.Equal(t, 5678, placed.ContractID, "a contract name is found directly")
+
+	for _, symbol := range []string{"ES", "es Front Month"} {
+		_, err = h["getMarketData"].Handler(context.Background(), map[string]interface{}{"symbol": symbol})
+		require.NoError(t, err, symbol)
+		assert.Equal(t, 1234, quoted, "%s resolves to the front month", symbol)
+	}
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Unknown symbol", map[string]interface{}{"symbol": "XYZ"}, "no contract or product found for symbol XYZ"},
+		{"Blank symbol", map[string]interface{}{"symbol": " "}, "invalid symbol"},
+		{"Both given", map[string]interface{}{"symbol": "ES", "contractId": float64(1234)}, "only one of contractId and symbol may be given"},
+		{"Neither given", map[string]interface{}{}, "missing required field: contractId or symbol"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h["getMarketData"].Handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
 func TestGetContractsHandler(t *testing.T) {
 	mockContracts := []models.Contract{
 		{ID: 1, Name: "Test Contract"},
@@ -563,7 +1207,7 @@ func TestGetContractsHandler(t *testing.T) {
 	}
 
 	handlers := NewHandlers(mockClient)
-	result, err := handlers["getContracts"].Handler(nil)
+	result, err := handlers["getContracts"].Handler(context.Background(), nil)
 	assert.NoError(t, err)
 	assert.Equal(t, mockContracts, result)
 }
@@ -583,7 +1227,7 @@ func TestGetMarketDataHandler(t *testing.T) {
 	}
 
 	handlers := NewHandlers(mockClient)
-	result, err := handlers["getMarketData"].Handler(map[string]interface{}{
+	result, err := handlers["getMarketData"].Handler(context.Background(), map[string]interface{}{
 		"contractId": float64(1),
 	})
 	assert.NoError(t, err)
@@ -595,7 +1239,7 @@ func TestGetHistoricalDataHandler(t *testing.T) {
 	endTime := time.Now()
 
 	handlers := NewHandlers(&MockTradovateClient{})
-	result, err := handlers["getHistoricalData"].Handler(map[string]interface{}{
+	result, err := handlers["getHistoricalData"].Handler(context.Background(), map[string]interface{}{
 		"contractId": float64(1),
 		"startTime":  startTime.Format(time.RFC3339),
 		"endTime":    endTime.Format(time.RFC3339),
@@ -627,13 +1271,64 @@ func TestGetRiskLimitsHandler(t *testing.T) {
 	}
 
 	handlers := NewHandlers(mockClient)
-	result, err := handlers["getRiskLimits"].Handler(map[string]interface{}{
+	result, err := handlers["getRiskLimits"].Handler(context.Background(), map[string]interface{}{
 		"accountId": float64(1),
 	})
 	assert.NoError(t, err)
 	assert.Equal(t, expectedLimits, result)
 }
 
+func TestGetQuotes(t *testing.T) {
+	var requested []int
+	mockClient := &MockTradovateClient{
+		getMarketDataBatchFunc: func(contractIDs []int) ([]models.MarketDataResult, error) {
+			requested = contractIDs
+			return []models.MarketDataResult{{ContractID: 1, Data: &models.MarketData{ContractID: 1}}, {ContractID: 2, Error: "status 404"}}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getQuotes"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"contractIds": []interface{}{float64(1), "2"}})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, requested)
+	quotes := result.(map[string]models.MarketDataResult)
+	assert.Len(t, quotes, 2)
+	assert.Equal(t, "status 404", quotes["2"].Error)
+
+	mockClient.findContractFunc = func(name string) (*models.Contract, error) {
+		if name == "ESZ4" {
+			return &models.Contract{ID: 2, Name: name}, nil
+		}
+		return nil, errors.New("unknown contract " + name)
+	}
+	result, err = handler(context.Background(), map[string]interface{}{
+		"contractIds": []interface{}{float64(1)},
+		"symbols":     []interface{}{"ESZ4", "XXZ4"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, requested)
+	assert.Equal(t, map[string]models.MarketDataResult{
+		"1":    {ContractID: 1, Data: &models.MarketData{ContractID: 1}},
+		"ESZ4": {ContractID: 2, Error: "status 404"},
+		"XXZ4": {Error: "unknown contract XXZ4"},
+	}, result)
+
+	_, err = handler(context.Background(), map[string]interface{}{})
+	assert.EqualError(t, err, "missing required field: contractIds or symbols")
+	_, err = handler(context.Background(), map[string]interface{}{"contractIds": []interface{}{}})
+	assert.EqualError(t, err, "no contracts requested")
+	_, err = handler(context.Background(), map[string]interface{}{"contractIds": float64(1)})
+	assert.EqualError(t, err, "invalid type assertion for contractIds")
+	_, err = handler(context.Background(), map[string]interface{}{"contractIds": []interface{}{float64(1), "ES"}})
+	assert.EqualError(t, err, "invalid contractId in contractIds: ES")
+	_, err = handler(context.Background(), map[string]interface{}{"contractIds": []interface{}{1.5}})
+	assert.EqualError(t, err, "invalid contractId in contractIds: 1.5")
+	_, err = handler(context.Background(), map[string]interface{}{"contractIds": []interface{}{float64(0)}})
+	assert.EqualError(t, err, "invalid contractId in contractIds: 0")
+	_, err = handler(context.Background(), map[string]interface{}{"symbols": []interface{}{"ESZ4", 5.0}})
+	assert.EqualError(t, err, "invalid symbol in symbols: 5")
+}
+
 func TestHandleGetMarketDataInvalidParams(t *testing.T) {
 	mockClient := &MockTradovateClient{}
 	handlers := NewHandlers(mockClient)
@@ -648,7 +1343,7 @@ func TestHandleGetMarketDataInvalidParams(t *testing.T) {
 			name:    "Missing contract ID",
 			params:  map[string]interface{}{},
 			wantErr: true,
-			errMsg:  "missing contractId",
+			errMsg:  "missing required field: contractId or symbol",
 		},
 		{
 			name: "Invalid contract ID type",
@@ -670,7 +1365,7 @@ func TestHandleGetMarketDataInvalidParams(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := handlers["getMarketData"].Handler(tt.params)
+			_, err := handlers["getMarketData"].Handler(context.Background(), tt.params)
 			if tt.wantErr {
 				assert.Error(t, err)
 				if err != nil {
@@ -697,7 +1392,7 @@ func TestHandleGetHistoricalDataInvalidParams(t *testing.T) {
 			name:    "Missing all parameters",
 			params:  map[string]interface{}{},
 			wantErr: true,
-			errMsg:  "missing contractId",
+			errMsg:  "missing required field: startTime",
 		},
 		{
 			name: "Invalid contract ID type",
@@ -740,7 +1435,7 @@ func TestHandleGetHistoricalDataInvalidParams(t *testing.T) {
 				"endTime":    time.Now().Format(time.RFC3339),
 			},
 			wantErr: true,
-			errMsg:  "missing interval",
+			errMsg:  "missing required field: interval",
 		},
 		{
 			name: "End time before start time",
@@ -757,7 +1452,7 @@ func TestHandleGetHistoricalDataInvalidParams(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := handlers["getHistoricalData"].Handler(tt.params)
+			_, err := handlers["getHistoricalData"].Handler(context.Background(), tt.params)
 			if tt.wantErr {
 				assert.Error(t, err)
 				if err != nil {
@@ -784,7 +1479,7 @@ func TestHandleGetRiskLimitsInvalidParams(t *testing.T) {
 			name:    "Missing account ID",
 			params:  map[string]interface{}{},
 			wantErr: true,
-			errMsg:  "missing accountId",
+			errMsg:  "missing required field: accountId",
 		},
 		{
 			name: "Invalid account ID type",
@@ -806,7 +1501,7 @@ func TestHandleGetRiskLimitsInvalidParams(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := handlers["getRiskLimits"].Handler(tt.params)
+			_, err := handlers["getRiskLimits"].Handler(context.Background(), tt.params)
 			if tt.wantErr {
 				assert.Error(t, err)
 				if err != nil {
@@ -819,160 +1514,28 @@ func TestHandleGetRiskLimitsInvalidParams(t *testing.T) {
 	}
 }
 
-func TestValidateRequiredParams(t *testing.T) {
-	tests := []struct {
-		name     string
-		params   map[string]interface{}
-		required []string
-		wantErr  bool
+func TestHandleInvalidParams(t *testing.T) {
+	mockClient := &MockClient{}
+	handlers := NewHandlers(mockClient)
+
+	testCases := []struct {
+		name       string
+		handlerKey string
+		params     map[string]interface{}
 	}{
 		{
-			name: "all required params present",
+			name:       "missing account ID",
+			handlerKey: "placeOrder",
 			params: map[string]interface{}{
-				"param1": "value1",
-				"param2": 123,
+				"contractId":  float64(12345),
+				"orderType":   "Limit",
+				"quantity":    float64(1),
+				"timeInForce": "Day",
 			},
-			required: []string{"param1", "param2"},
-			wantErr:  false,
 		},
 		{
-			name: "missing required param",
-			params: map[string]interface{}{
-				"param1": "value1",
-			},
-			required: []string{"param1", "param2"},
-			wantErr:  true,
-		},
-		{
-			name:     "empty params",
-			params:   map[string]interface{}{},
-			required: []string{"param1"},
-			wantErr:  true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			err := validateRequiredParams(tt.params, tt.required)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("validateRequiredParams() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
-func TestAssertFloat64(t *testing.T) {
-	tests := []struct {
-		name      string
-		value     interface{}
-		paramName string
-		want      float64
-		wantErr   bool
-	}{
-		{
-			name:      "valid float64",
-			value:     float64(123.45),
-			paramName: "testParam",
-			want:      123.45,
-			wantErr:   false,
-		},
-		{
-			name:      "invalid type - string",
-			value:     "123.45",
-			paramName: "testParam",
-			want:      0,
-			wantErr:   true,
-		},
-		{
-			name:      "invalid type - int",
-			value:     123,
-			paramName: "testParam",
-			want:      0,
-			wantErr:   true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := assertFloat64(tt.value, tt.paramName)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("assertFloat64() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if got != tt.want {
-				t.Errorf("assertFloat64() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestAssertString(t *testing.T) {
-	tests := []struct {
-		name      string
-		value     interface{}
-		paramName string
-		want      string
-		wantErr   bool
-	}{
-		{
-			name:      "valid string",
-			value:     "test string",
-			paramName: "testParam",
-			want:      "test string",
-			wantErr:   false,
-		},
-		{
-			name:      "invalid type - float64",
-			value:     float64(123.45),
-			paramName: "testParam",
-			want:      "",
-			wantErr:   true,
-		},
-		{
-			name:      "invalid type - int",
-			value:     123,
-			paramName: "testParam",
-			want:      "",
-			wantErr:   true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := assertString(tt.value, tt.paramName)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("assertString() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if got != tt.want {
-				t.Errorf("assertString() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestHandleInvalidParams(t *testing.T) {
-	mockClient := &MockClient{}
-	handlers := NewHandlers(mockClient)
-
-	testCases := []struct {
-		name       string
-		handlerKey string
-		params     map[string]interface{}
-	}{
-		{
-			name:       "missing account ID",
-			handlerKey: "placeOrder",
-			params: map[string]interface{}{
-				"contractId":  float64(12345),
-				"orderType":   "Limit",
-				"quantity":    float64(1),
-				"timeInForce": "Day",
-			},
-		},
-		{
-			name:       "invalid order type",
-			handlerKey: "placeOrder",
+			name:       "invalid order type",
+			handlerKey: "placeOrder",
 			params: map[string]interface{}{
 				"accountId":   float64(12345),
 				"contractId":  float64(12345),
@@ -1010,7 +1573,7 @@ func TestHandleInvalidParams(t *testing.T) {
 			handler, exists := handlers[tc.handlerKey]
 			assert.True(t, exists)
 
-			_, err := handler.Handler(tc.params)
+			_, err := handler.Handler(context.Background(), tc.params)
 			assert.Error(t, err)
 		})
 	}
@@ -1087,7 +1650,7 @@ func TestHandleClientErrors(t *testing.T) {
 			handler, exists := handlers[tc.handlerKey]
 			assert.True(t, exists)
 
-			_, err := handler.Handler(tc.params)
+			_, err := handler.Handler(context.Background(), tc.params)
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "client error")
 		})
@@ -1158,7 +1721,7 @@ func TestHandleSuccess(t *testing.T) {
 			handler, exists := handlers[tc.handlerKey]
 			assert.True(t, exists)
 
-			_, err := handler.Handler(tc.params)
+			_, err := handler.Handler(context.Background(), tc.params)
 			assert.NoError(t, err)
 		})
 	}
@@ -1174,64 +1737,1530 @@ type MockClient struct {
 	getFillsError      error
 }
 
-func (m *MockClient) Authenticate() (*client.AuthResponse, error) {
+func (m *MockClient) Authenticate(ctx context.Context) (*client.AuthResponse, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (m *MockClient) GetAccounts() ([]models.Account, error) {
+func (m *MockClient) GetAccounts(ctx context.Context) ([]models.Account, error) {
 	if m.getAccountsError != nil {
 		return nil, m.getAccountsError
 	}
 	return []models.Account{}, nil
 }
 
-func (m *MockClient) SetRiskLimits(limits models.RiskLimit) error {
+func (m *MockClient) SetRiskLimits(ctx context.Context, limits models.RiskLimit) error {
 	if m.setRiskLimitsError != nil {
 		return m.setRiskLimitsError
 	}
 	return nil
 }
 
-func (m *MockClient) GetRiskLimits(accountID int) (*models.RiskLimit, error) {
+func (m *MockClient) GetRiskLimits(ctx context.Context, accountID int) (*models.RiskLimit, error) {
 	if m.getRiskLimitsError != nil {
 		return nil, m.getRiskLimitsError
 	}
 	return &models.RiskLimit{}, nil
 }
 
-func (m *MockClient) PlaceOrder(order models.Order) (*models.Order, error) {
+func (m *MockClient) PlaceOrder(ctx context.Context, order models.Order) (*models.Order, error) {
 	if m.placeOrderError != nil {
 		return nil, m.placeOrderError
 	}
 	return &models.Order{}, nil
 }
 
-func (m *MockClient) CancelOrder(orderID int) error {
+func (m *MockClient) CancelOrder(ctx context.Context, orderID int) error {
 	if m.cancelOrderError != nil {
 		return m.cancelOrderError
 	}
 	return nil
 }
 
-func (m *MockClient) GetFills(orderID int) ([]models.Fill, error) {
+func (m *MockClient) GetFills(ctx context.Context, orderID int) ([]models.Fill, error) {
 	if m.getFillsError != nil {
 		return nil, m.getFillsError
 	}
 	return []models.Fill{}, nil
 }
 
-func (m *MockClient) GetPositions() ([]models.Position, error) {
+func (m *MockClient) GetPositions(ctx context.Context) ([]models.Position, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetContracts(ctx context.Context) ([]models.Contract, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetContract(ctx context.Context, contractID int) (*models.Contract, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetContractMaturity(ctx context.Context, maturityID int) (*models.ContractMaturity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetMarketData(ctx context.Context, contractID int) (*models.MarketData, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetHistoricalData(ctx context.Context, contractID int, startTime, endTime time.Time, interval string) ([]models.HistoricalData, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) InitializeReplayClock(ctx context.Context, startTime time.Time, speed int, initialBalance float64) (*client.ReplayClock, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) ChangeReplaySpeed(ctx context.Context, speed int) error {
+	return errors.New("not implemented")
+}
+
+func (m *MockClient) ModifyOrder(ctx context.Context, orderID int, changes models.OrderChanges) error {
+	return errors.New("not implemented")
+}
+
+func (m *MockClient) PlaceOCO(ctx context.Context, oco models.OCOOrder) (*models.OCOOrder, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) PlaceBracketOrder(ctx context.Context, bracket models.BracketOrder) (*models.OrderStrategy, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) LiquidatePosition(ctx context.Context, accountID, contractID int) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (m *MockClient) GetOrders(ctx context.Context, accountID int, status string) ([]models.Order, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetOrder(ctx context.Context, orderID int) (*models.Order, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetExecutionReports(ctx context.Context, orderID, accountID int) ([]models.ExecutionReport, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetCommandReports(ctx context.Context, orderID int) ([]models.CommandReport, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestPlaceOrderTypes(t *testing.T) {
+	tests := []struct {
+		name      string
+		orderType string
+		prices    map[string]interface{}
+		want      models.Order
+		errMsg    string
+	}{
+		{"Stop", "Stop", map[string]interface{}{"stopPrice": 5080.0}, models.Order{StopPrice: 5080}, ""},
+		{"StopLimit", "StopLimit", map[string]interface{}{"stopPrice": 5080.0, "price": 5079.5}, models.Order{Price: 5079.5, StopPrice: 5080}, ""},
+		{"MIT", "MIT", map[string]interface{}{"stopPrice": 5120.0}, models.Order{StopPrice: 5120}, ""},
+		{"Stop without stopPrice", "Stop", map[string]interface{}{}, models.Order{}, "stopPrice is required when orderType is Stop"},
+		{"StopLimit without price", "StopLimit", map[string]interface{}{"stopPrice": 5080.0}, models.Order{}, "price is required when orderType is StopLimit"},
+		{"MIT without stopPrice", "MIT", map[string]interface{}{"price": 5120.0}, models.Order{}, "stopPrice is required when orderType is MIT"},
+		{"Stop with price", "Stop", map[string]interface{}{"stopPrice": 5080.0, "price": 5079.5}, models.Order{}, "price is not used when orderType is Stop"},
+		{"Market with stopPrice", "Market", map[string]interface{}{"stopPrice": 5080.0}, models.Order{}, "stopPrice is not used when orderType is Market"},
+		{"Negative stopPrice", "Stop", map[string]interface{}{"stopPrice": -1.0}, models.Order{}, "invalid stopPrice"},
+		{"Unknown type", "Iceberg", map[string]interface{}{}, models.Order{}, "invalid orderType: must be one of Market, Limit, Stop, StopLimit, MIT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var placed models.Order
+			mockClient := &MockTradovateClient{
+				placeOrderFunc: func(order models.Order) (*models.Order, error) {
+					placed = order
+					return &order, nil
+				},
+			}
+			params := map[string]interface{}{
+				"accountId":   float64(1),
+				"contractId":  float64(1234),
+				"orderType":   tt.orderType,
+				"quantity":    float64(1),
+				"timeInForce": "GTC",
+			}
+			for k, v := range tt.prices {
+				params[k] = v
+			}
+
+			_, err := NewHandlers(mockClient)["placeOrder"].Handler(context.Background(), params)
+			if tt.errMsg != "" {
+				assert.EqualError(t, err, tt.errMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.orderType, placed.OrderType)
+			assert.Equal(t, tt.want.Price, placed.Price)
+			assert.Equal(t, tt.want.StopPrice, placed.StopPrice)
+		})
+	}
+}
+
+func TestHandleGetAccountAlerts(t *testing.T) {
+	var gotAccount int
+	var gotUnhandled bool
+	mockClient := &MockTradovateClient{
+		getAccountAlertsFunc: func(accountID int, unhandledOnly bool) ([]models.AccountAlert, error) {
+			gotAccount, gotUnhandled = accountID, unhandledOnly
+			return []models.AccountAlert{{ID: 1, Source: "broker", AccountID: accountID, Text: "Margin call"}}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getAccountAlerts"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "unhandledOnly": true})
+	require.NoError(t, err)
+	assert.Equal(t, []models.AccountAlert{{ID: 1, Source: "broker", AccountID: 12345, Text: "Margin call"}}, result)
+	assert.Equal(t, 12345, gotAccount)
+	assert.True(t, gotUnhandled)
+
+	// Without an account every account's notices are included.
+	_, err = handler(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, gotAccount)
+	assert.False(t, gotUnhandled)
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(-1)})
+	assert.EqualError(t, err, "invalid accountId")
+	_, err = handler(context.Background(), map[string]interface{}{"unhandledOnly": "yes"})
+	assert.EqualError(t, err, "invalid type assertion for unhandledOnly")
+}
+
+func TestPlaceOrderRejected(t *testing.T) {
+	var reports []models.CommandReport
+	mockClient := &MockTradovateClient{
+		placeOrderFunc: func(order models.Order) (*models.Order, error) {
+			order.ID = 67890
+			return &order, nil
+		},
+		getCommandReportsFunc: func(orderID int) ([]models.CommandReport, error) {
+			assert.Equal(t, 67890, orderID)
+			return reports, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["placeOrder"].Handler
+	params := map[string]interface{}{
+		"accountId":   float64(1),
+		"contractId":  float64(1),
+		"orderType":   "Market",
+		"quantity":    float64(1),
+		"timeInForce": "Day",
+	}
+
+	reports = []models.CommandReport{{CommandID: 1, CommandStatus: "RiskPassed"}}
+	_, err := handler(context.Background(), params)
+	require.NoError(t, err)
+
+	reports = []models.CommandReport{{CommandID: 1, CommandStatus: "RiskRejected", RejectReason: "RiskCheck", Text: "Insufficient margin"}}
+	_, err = handler(context.Background(), params)
+	assert.EqualError(t, err, "order 67890 rejected: RiskCheck: Insufficient margin")
+}
+
+func (m *MockClient) GetProducts(ctx context.Context) ([]models.Product, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetContractMaturities(ctx context.Context, productID int) ([]models.ContractMaturity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetMarginSnapshot(ctx context.Context, accountID int) (*models.MarginSnapshot, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetProductFees(ctx context.Context, productIDs []int) ([]models.ProductFees, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetAccountPermissions(ctx context.Context, accountID int) (*models.AccountPermissions, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetUserProperties(ctx context.Context) ([]models.UserProperty, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetUserPlugins(ctx context.Context) ([]models.UserPlugin, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetPositionsByAccount(ctx context.Context, accountID int) ([]models.Position, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) IsAuthenticated() bool {
+	return false
+}
+
+func (m *MockClient) TokenExpiresAt() time.Time {
+	return time.Time{}
+}
+
+func (m *MockClient) ResolveAccount(ctx context.Context, account string) (*models.Account, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) AuthenticateWithCredentials(ctx context.Context, creds models.Credentials) (*client.AuthResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetServerTime(ctx context.Context) (time.Time, error) {
+	return time.Time{}, errors.New("not implemented")
+}
+
+func (m *MockClient) ClockSkew() time.Duration {
+	return 0
+}
+
+func (m *MockClient) GetMarketDataBatch(ctx context.Context, contractIDs []int) ([]models.MarketDataResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetCashBalanceSnapshot(ctx context.Context, accountID int) (*models.CashBalanceSnapshot, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetAccountSummary(ctx context.Context, accountID int) (*models.AccountSummary, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetAutoLiquidation(ctx context.Context, accountID int) (*models.AutoLiquidation, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) SetAutoLiquidation(ctx context.Context, settings models.AutoLiquidation) (*models.AutoLiquidation, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) ResolveFrontMonth(ctx context.Context, productSymbol string) (*models.FrontMonth, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetAccountAlerts(ctx context.Context, accountID int, unhandledOnly bool) ([]models.AccountAlert, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) SearchContracts(ctx context.Context, text string, limit int) ([]models.ContractMatch, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) FindContract(ctx context.Context, name string) (*models.Contract, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) SubscribeQuotes(ctx context.Context, contractID int) (<-chan models.MarketData, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetProductSessions(ctx context.Context) ([]models.ProductSession, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockClient) GetCommandHistory(ctx context.Context, orderID, accountID int) ([]models.Command, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (m *MockClient) GetContracts() ([]models.Contract, error) {
+func (m *MockClient) GetAccountFills(ctx context.Context, accountID int, start, end time.Time) ([]models.Fill, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (m *MockClient) GetMarketData(contractID int) (*models.MarketData, error) {
+func (m *MockClient) GetPnLSummary(ctx context.Context, accountID int, period string) (*models.PnLSummary, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (m *MockClient) GetHistoricalData(contractID int, startTime, endTime time.Time, interval string) ([]models.HistoricalData, error) {
+func (m *MockClient) GetContractMargin(ctx context.Context, contractID int) (*models.ProductMargin, error) {
 	return nil, errors.New("not implemented")
 }
+
+func (m *MockClient) RateLimitStatus() models.RateLimitStatus {
+	return models.RateLimitStatus{}
+}
+
+func (m *MockClient) ConnectionStatus() models.ConnectionStatus {
+	return models.ConnectionStatus{}
+}
+
+func TestPlaceOrderConfigLimits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"riskLimits": {"maxOrderQuantity": 2}, "allowedSymbols": ["ES"]}`), 0600))
+	store, err := config.NewStore(path)
+	require.NoError(t, err)
+
+	placed := 0
+	mockClient := &MockTradovateClient{
+		placeOrderFunc: func(order models.Order) (*models.Order, error) {
+			placed++
+			return &order, nil
+		},
+		getContractFunc: func(contractID int) (*models.Contract, error) {
+			names := map[int]string{1: "ESZ4", 2: "CLF5"}
+			return &models.Contract{ID: contractID, Name: names[contractID]}, nil
+		},
+	}
+	handler := NewHandlers(mockClient, WithConfig(store))["placeOrder"].Handler
+
+	order := func(contractID, quantity float64) map[string]interface{} {
+		return map[string]interface{}{
+			"accountId":   float64(1),
+			"contractId":  contractID,
+			"orderType":   "Market",
+			"quantity":    quantity,
+			"timeInForce": "Day",
+		}
+	}
+
+	_, err = handler(context.Background(), order(1, 2))
+	require.NoError(t, err)
+
+	_, err = handler(context.Background(), order(1, 3))
+	assert.EqualError(t, err, "order quantity 3 exceeds configured maximum of 2")
+
+	_, err = handler(context.Background(), order(2, 1))
+	assert.EqualError(t, err, "contract CLF5 is not in the configured symbol whitelist")
+	assert.Equal(t, 1, placed)
+
+	// Limits loosened by a reload apply to the next call.
+	require.NoError(t, os.WriteFile(path, []byte(`{"riskLimits": {"maxOrderQuantity": 5}}`), 0600))
+	_, err = store.Reload()
+	require.NoError(t, err)
+
+	_, err = handler(context.Background(), order(2, 3))
+	require.NoError(t, err)
+	assert.Equal(t, 2, placed)
+}
+
+func TestReplayClockHandlers(t *testing.T) {
+	var gotStart time.Time
+	var gotSpeed int
+	var gotBalance float64
+	mockClient := &MockTradovateClient{
+		initializeReplayClockFunc: func(startTime time.Time, speed int, initialBalance float64) (*client.ReplayClock, error) {
+			gotStart, gotSpeed, gotBalance = startTime, speed, initialBalance
+			return &client.ReplayClock{StartTimestamp: startTime, Speed: speed, InitialBalance: initialBalance}, nil
+		},
+		changeReplaySpeedFunc: func(speed int) error {
+			gotSpeed = speed
+			return nil
+		},
+	}
+	handlers := NewHandlers(mockClient)
+
+	result, err := handlers["initializeReplayClock"].Handler(context.Background(), map[string]interface{}{
+		"startTimestamp": "2024-03-15T13:30:00Z",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 15, 13, 30, 0, 0, time.UTC), gotStart)
+	assert.Equal(t, 100, gotSpeed)
+	assert.Zero(t, gotBalance)
+	assert.Equal(t, 100, result.(*client.ReplayClock).Speed)
+
+	_, err = handlers["initializeReplayClock"].Handler(context.Background(), map[string]interface{}{
+		"startTimestamp": "2024-03-15T13:30:00Z",
+		"speed":          float64(400),
+		"initialBalance": float64(50000),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 400, gotSpeed)
+	assert.Equal(t, 50000.0, gotBalance)
+
+	result, err = handlers["changeReplaySpeed"].Handler(context.Background(), map[string]interface{}{
+		"speed": float64(25),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 25, gotSpeed)
+	assert.Equal(t, map[string]interface{}{"success": true, "speed": 25}, result)
+}
+
+func TestReplayClockHandlersInvalidParams(t *testing.T) {
+	handlers := NewHandlers(&MockTradovateClient{})
+
+	tests := []struct {
+		name    string
+		handler string
+		params  map[string]interface{}
+		errMsg  string
+	}{
+		{
+			name:    "Missing start timestamp",
+			handler: "initializeReplayClock",
+			params:  map[string]interface{}{},
+			errMsg:  "missing required field: startTimestamp",
+		},
+		{
+			name:    "Invalid start timestamp",
+			handler: "initializeReplayClock",
+			params:  map[string]interface{}{"startTimestamp": "yesterday"},
+			errMsg:  "invalid startTimestamp format",
+		},
+		{
+			name:    "Negative initial balance",
+			handler: "initializeReplayClock",
+			params:  map[string]interface{}{"startTimestamp": "2024-03-15T13:30:00Z", "initialBalance": float64(-1)},
+			errMsg:  "invalid initialBalance",
+		},
+		{
+			name:    "Missing speed",
+			handler: "changeReplaySpeed",
+			params:  map[string]interface{}{},
+			errMsg:  "missing required field: speed",
+		},
+		{
+			name:    "Zero speed",
+			handler: "changeReplaySpeed",
+			params:  map[string]interface{}{"speed": float64(0)},
+			errMsg:  "invalid speed",
+		},
+		{
+			name:    "Fractional speed",
+			handler: "changeReplaySpeed",
+			params:  map[string]interface{}{"speed": 1.5},
+			errMsg:  "invalid type assertion for speed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handlers[tt.handler].Handler(context.Background(), tt.params)
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestHandleGetOrder(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		getOrderFunc: func(orderID int) (*models.Order, error) {
+			assert.Equal(t, 67890, orderID)
+			return &models.Order{ID: orderID, Status: "Working", FilledQty: 1, AveragePrice: 5100.25}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getOrder"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"orderId": float64(67890)})
+	require.NoError(t, err)
+	assert.Equal(t, &models.Order{ID: 67890, Status: "Working", FilledQty: 1, AveragePrice: 5100.25}, result)
+
+	_, err = handler(context.Background(), map[string]interface{}{})
+	assert.EqualError(t, err, "missing required field: orderId")
+
+	_, err = handler(context.Background(), map[string]interface{}{"orderId": "latest"})
+	assert.Error(t, err)
+}
+
+func TestHandleGetExecutionReports(t *testing.T) {
+	var gotOrder, gotAccount int
+	mockClient := &MockTradovateClient{
+		getExecutionReportsFunc: func(orderID, accountID int) ([]models.ExecutionReport, error) {
+			gotOrder, gotAccount = orderID, accountID
+			return []models.ExecutionReport{{ID: 1, OrderID: 67890, ExecType: "Rejected", Text: "Insufficient margin"}}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getExecutionReports"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"orderId": float64(67890)})
+	require.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 67890, gotOrder)
+	assert.Equal(t, 0, gotAccount)
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345)})
+	require.NoError(t, err)
+	assert.Equal(t, 0, gotOrder)
+	assert.Equal(t, 12345, gotAccount)
+
+	_, err = handler(context.Background(), map[string]interface{}{})
+	assert.EqualError(t, err, "missing required field: orderId or accountId")
+
+	_, err = handler(context.Background(), map[string]interface{}{"orderId": "latest"})
+	assert.Error(t, err)
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "startTime": "the other day"})
+	assert.ErrorContains(t, err, "invalid startTime format")
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "startTime": "2024-03-15T14:00:00Z", "endTime": "2024-03-15T13:00:00Z"})
+	assert.EqualError(t, err, "endTime must be after startTime")
+}
+
+func TestHandleGetExecutionReportsRejectsAndRange(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		getExecutionReportsFunc: func(orderID, accountID int) ([]models.ExecutionReport, error) {
+			return []models.ExecutionReport{
+				{ID: 1, OrderID: 67890, CommandID: 1, Timestamp: "2024-03-15T13:30:00Z", ExecType: "New"},
+				{ID: 2, OrderID: 67890, CommandID: 3, Timestamp: "2024-03-15T13:32:00Z", ExecType: "Rejected"},
+			}, nil
+		},
+		getCommandReportsFunc: func(orderID int) ([]models.CommandReport, error) {
+			return []models.CommandReport{
+				{CommandID: 1, Timestamp: "2024-03-15T13:30:00Z", CommandStatus: "RiskPassed"},
+				{CommandID: 2, Timestamp: "2024-03-15T13:31:00Z", CommandStatus: "RiskRejected", RejectReason: "AccountClosed", Text: "Trading is disabled"},
+				{CommandID: 3, Timestamp: "2024-03-15T13:32:00Z", CommandStatus: "ExecutionRejected", RejectReason: "UnknownOrder", Text: "Too late to modify"},
+			}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getExecutionReports"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"orderId": float64(67890)})
+	require.NoError(t, err)
+	reports := result.([]models.ExecutionReport)
+	require.Len(t, reports, 3)
+	assert.Equal(t, models.ExecutionReport{OrderID: 67890, CommandID: 2, Timestamp: "2024-03-15T13:31:00Z", ExecType: "Rejected", OrdStatus: "Rejected", RejectReason: "AccountClosed", Text: "Trading is disabled"}, reports[1])
+	assert.Equal(t, "UnknownOrder", reports[2].RejectReason, "the exchange's reject gets its reason")
+	assert.Equal(t, "Too late to modify", reports[2].Text)
+
+	result, err = handler(context.Background(), map[string]interface{}{"orderId": float64(67890), "startTime": "2024-03-15T13:31:00Z", "endTime": "2024-03-15T13:32:00Z"})
+	require.NoError(t, err)
+	reports = result.([]models.ExecutionReport)
+	require.Len(t, reports, 1)
+	assert.Equal(t, 2, reports[0].CommandID)
+}
+
+func TestHandleGetCommandHistory(t *testing.T) {
+	var gotOrder, gotAccount int
+	mockClient := &MockTradovateClient{
+		getCommandHistoryFunc: func(orderID, accountID int) ([]models.Command, error) {
+			gotOrder, gotAccount = orderID, accountID
+			return []models.Command{
+				{ID: 1, OrderID: 41, CommandType: "New", CommandStatus: "RiskPassed"},
+				{ID: 2, OrderID: 41, CommandType: "Modify", CommandStatus: "RiskRejected", Rejected: true, Text: "Exceeds position limit"},
+			}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getCommandHistory"].Handler
+	ctx := sessionWithAccount(12345)
+
+	result, err := handler(ctx, map[string]interface{}{"orderId": float64(41)})
+	require.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, 41, gotOrder)
+	assert.Equal(t, 0, gotAccount, "an order is not narrowed to the active account")
+
+	result, err = handler(ctx, map[string]interface{}{"rejectedOnly": true})
+	require.NoError(t, err)
+	assert.Equal(t, 12345, gotAccount)
+	commands := result.([]models.Command)
+	require.Len(t, commands, 1)
+	assert.Equal(t, "Exceeds position limit", commands[0].Text)
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Missing order and account", map[string]interface{}{}, "missing required field: orderId or accountId"},
+		{"Invalid order", map[string]interface{}{"orderId": float64(0)}, "invalid orderId"},
+		{"Invalid account", map[string]interface{}{"accountId": float64(-1)}, "invalid accountId"},
+		{"Invalid rejectedOnly", map[string]interface{}{"orderId": float64(41), "rejectedOnly": "yes"}, "invalid type assertion for rejectedOnly"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestHandleGetFillsByAccount(t *testing.T) {
+	var gotAccount int
+	var gotStart, gotEnd time.Time
+	mockClient := &MockTradovateClient{
+		getAccountFillsFunc: func(accountID int, start, end time.Time) ([]models.Fill, error) {
+			gotAccount, gotStart, gotEnd = accountID, start, end
+			return []models.Fill{{ID: 501, OrderID: 41, ContractID: 1234, Symbol: "MESM4", Action: "Buy", Price: 5100.25, Quantity: 1}}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getFillsByAccount"].Handler
+
+	result, err := handler(sessionWithAccount(12345), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "MESM4", result.([]models.Fill)[0].Symbol)
+	assert.Equal(t, 12345, gotAccount)
+	assert.True(t, gotStart.IsZero(), "the client defaults to today")
+	assert.True(t, gotEnd.IsZero())
+
+	_, err = handler(context.Background(), map[string]interface{}{
+		"accountId": float64(678),
+		"startTime": "2024-03-15T13:30:00Z",
+		"endTime":   "2024-03-15T20:00:00Z",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 678, gotAccount)
+	assert.Equal(t, time.Date(2024, 3, 15, 13, 30, 0, 0, time.UTC), gotStart)
+	assert.Equal(t, time.Date(2024, 3, 15, 20, 0, 0, 0, time.UTC), gotEnd)
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Missing account", map[string]interface{}{}, "missing required field: accountId"},
+		{"Invalid account", map[string]interface{}{"accountId": float64(0)}, "invalid accountId"},
+		{"Invalid startTime", map[string]interface{}{"accountId": float64(1), "startTime": "someday"}, `invalid startTime format: "someday" is neither an RFC 3339 time nor a relative time such as -24h, today or last 5 trading days`},
+		{"Reversed range", map[string]interface{}{"accountId": float64(1), "startTime": "2024-03-15T20:00:00Z", "endTime": "2024-03-15T13:30:00Z"}, "endTime must be after startTime"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestHandleGetAccountPermissions(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		getAccountPermissionsFunc: func(accountID int) (*models.AccountPermissions, error) {
+			return &models.AccountPermissions{AccountID: accountID, Restrictions: []string{"view-only: trading permission is Requested"}}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getAccountPermissions"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"accountId": float64(12345)})
+	require.NoError(t, err)
+	assert.False(t, result.(*models.AccountPermissions).CanTrade)
+
+	_, err = handler(context.Background(), map[string]interface{}{})
+	assert.EqualError(t, err, "missing required field: accountId")
+}
+
+func TestHandleGetCashBalance(t *testing.T) {
+	var requested int
+	mockClient := &MockTradovateClient{
+		getCashBalanceSnapshotFunc: func(accountID int) (*models.CashBalanceSnapshot, error) {
+			requested = accountID
+			return &models.CashBalanceSnapshot{TotalCashValue: 50000, NetLiq: 50250.5, OpenPnL: 250.5, RealizedPnL: -120}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getCashBalance"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"accountId": float64(12345)})
+	require.NoError(t, err)
+	assert.Equal(t, 12345, requested)
+	balance := result.(*models.CashBalanceSnapshot)
+	assert.Equal(t, 50000.0, balance.TotalCashValue)
+	assert.Equal(t, 250.5, balance.OpenPnL)
+	assert.Equal(t, -120.0, balance.RealizedPnL)
+
+	_, err = handler(context.Background(), map[string]interface{}{})
+	assert.EqualError(t, err, "missing required field: accountId")
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(0)})
+	assert.EqualError(t, err, "invalid accountId")
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": 1.5})
+	assert.EqualError(t, err, "invalid type assertion for accountId")
+}
+
+func TestHandleGetMarginSnapshot(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		getMarginSnapshotFunc: func(accountID int) (*models.MarginSnapshot, error) {
+			return &models.MarginSnapshot{AccountID: accountID, NetLiq: 50000, TotalUsedMargin: 14500, AvailableMargin: 35500}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getMarginSnapshot"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"accountId": float64(12345)})
+	require.NoError(t, err)
+	assert.Equal(t, 35500.0, result.(*models.MarginSnapshot).AvailableMargin)
+
+	_, err = handler(context.Background(), map[string]interface{}{})
+	assert.EqualError(t, err, "missing required field: accountId")
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(-1)})
+	assert.EqualError(t, err, "invalid accountId")
+}
+
+func TestHandleGetAccountSummary(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		getAccountSummaryFunc: func(accountID int) (*models.AccountSummary, error) {
+			return &models.AccountSummary{
+				Account:       models.Account{ID: accountID},
+				CashBalance:   models.CashBalanceSnapshot{NetLiq: 50250.5, OpenPnL: 250.5},
+				WorkingOrders: []models.Order{{ID: 67890}},
+			}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getAccountSummary"].Handler
+
+	result, err := handler(sessionWithAccount(12345), map[string]interface{}{})
+	require.NoError(t, err)
+	summary := result.(*models.AccountSummary)
+	assert.Equal(t, 12345, summary.Account.ID)
+	assert.Equal(t, 250.5, summary.CashBalance.OpenPnL)
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(-1)})
+	assert.EqualError(t, err, "invalid accountId")
+}
+
+func TestHandleAggregatedPnL(t *testing.T) {
+	var periods []string
+	mockClient := &MockTradovateClient{
+		getAccountsFunc: func() ([]models.Account, error) {
+			return []models.Account{{ID: 1, Name: "Demo"}, {ID: 2, Name: "Funded"}}, nil
+		},
+		getPnLSummaryFunc: func(accountID int, period string) (*models.PnLSummary, error) {
+			periods = append(periods, period)
+			if accountID == 1 {
+				return &models.PnLSummary{AccountID: 1, Period: period, RealizedPnL: 300, UnrealizedPnL: -50, TotalPnL: 250}, nil
+			}
+			return &models.PnLSummary{AccountID: accountID, Period: period, RealizedPnL: -100, TotalPnL: -100}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["aggregatedPnL"].Handler
+
+	result, err := handler(context.Background(), nil)
+	require.NoError(t, err)
+	report := result.(models.PnLReport)
+	assert.Equal(t, "day", report.Period)
+	assert.Equal(t, 200.0, report.RealizedPnL)
+	assert.Equal(t, -50.0, report.UnrealizedPnL)
+	assert.Equal(t, 150.0, report.TotalPnL)
+	require.Len(t, report.Accounts, 2)
+	assert.Equal(t, "Demo", report.Accounts[0].AccountName)
+	assert.Equal(t, "Funded", report.Accounts[1].AccountName)
+
+	// Naming an account, or selecting one, totals only that account.
+	result, err = handler(sessionWithAccount(2), map[string]interface{}{"period": "week"})
+	require.NoError(t, err)
+	report = result.(models.PnLReport)
+	require.Len(t, report.Accounts, 1)
+	assert.Equal(t, 2, report.Accounts[0].AccountID)
+	assert.Equal(t, -100.0, report.TotalPnL)
+	assert.Equal(t, []string{"day", "day", "week"}, periods)
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Invalid account", map[string]interface{}{"accountId": float64(0)}, "invalid accountId"},
+		{"Invalid period", map[string]interface{}{"period": "month"}, "invalid period: must be one of day, week"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestHandleSetAutoLiquidation(t *testing.T) {
+	dailyLoss, alert := 1000.0, 80.0
+	var stored models.AutoLiquidation
+	mockClient := &MockTradovateClient{
+		getAutoLiquidationFunc: func(accountID int) (*models.AutoLiquidation, error) {
+			return &models.AutoLiquidation{AccountID: accountID, DailyLossAutoLiq: &dailyLoss, MarginPercentageAlert: &alert}, nil
+		},
+		setAutoLiquidationFunc: func(settings models.AutoLiquidation) (*models.AutoLiquidation, error) {
+			stored = settings
+			return &settings, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["setAutoLiquidation"].Handler
+
+	_, err := handler(context.Background(), map[string]interface{}{
+		"accountId":           float64(12345),
+		"weeklyLossAutoLiq":   float64(2500),
+		"dailyLossAutoLiq":    float64(0),
+		"trailingMaxDrawdown": float64(1500),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 12345, stored.AccountID)
+	assert.Nil(t, stored.DailyLossAutoLiq, "0 removes the threshold")
+	require.NotNil(t, stored.WeeklyLossAutoLiq)
+	assert.Equal(t, 2500.0, *stored.WeeklyLossAutoLiq)
+	assert.Equal(t, 1500.0, *stored.TrailingMaxDrawdown)
+	assert.Equal(t, &alert, stored.MarginPercentageAlert, "thresholds not given are kept")
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345)})
+	assert.EqualError(t, err, "no auto-liquidation threshold to change")
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "dailyLossAutoLiq": float64(-5)})
+	assert.EqualError(t, err, "invalid dailyLossAutoLiq")
+
+	mockClient.getAutoLiquidationFunc = func(accountID int) (*models.AutoLiquidation, error) {
+		return &models.AutoLiquidation{AccountID: accountID, ChangesLocked: true}, nil
+	}
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "dailyLossAutoLiq": float64(500)})
+	assert.EqualError(t, err, "auto-liquidation settings of account 12345 are locked")
+}
+
+func TestResolveFrontMonth(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		resolveFrontMonthFunc: func(symbol string) (*models.FrontMonth, error) {
+			return &models.FrontMonth{Product: symbol, Contract: models.Contract{ID: 3570, Name: "ESZ4"}}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["resolveFrontMonth"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"symbol": "ES"})
+	require.NoError(t, err)
+	assert.Equal(t, "ESZ4", result.(*models.FrontMonth).Contract.Name)
+
+	_, err = handler(context.Background(), map[string]interface{}{})
+	assert.EqualError(t, err, "missing required field: symbol")
+
+	_, err = handler(context.Background(), map[string]interface{}{"symbol": 5})
+	assert.EqualError(t, err, "invalid type assertion for symbol")
+}
+
+func TestHandleGetProducts(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		getProductsFunc: func() ([]models.Product, error) {
+			return []models.Product{
+				{ID: 1, Name: "ES", Description: "E-mini S&P 500", TickSize: 0.25, ValuePerPoint: 50, Months: "HMUZ"},
+				{ID: 2, Name: "NQ", Description: "E-mini Nasdaq-100", TickSize: 0.25, ValuePerPoint: 20, Months: "HMUZ"},
+			}, nil
+		},
+		getProductSessionsFunc: func() ([]models.ProductSession, error) {
+			return []models.ProductSession{{ID: 10, ProductID: 1, OpenTime: "17:00", StartTime: "17:00", StopTime: "16:00", CloseTime: "16:00"}}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["getProducts"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	products := result.([]models.Product)
+	require.Len(t, products, 2)
+	require.NotNil(t, products[0].TradingHours)
+	assert.Equal(t, "16:00", products[0].TradingHours.StopTime)
+	assert.Nil(t, products[1].TradingHours)
+
+	result, err = handler(context.Background(), map[string]interface{}{"symbol": "nq"})
+	require.NoError(t, err)
+	products = result.([]models.Product)
+	require.Len(t, products, 1)
+	assert.Equal(t, 20.0, products[0].ValuePerPoint)
+
+	_, err = handler(context.Background(), map[string]interface{}{"symbol": "ZZ"})
+	assert.EqualError(t, err, `unknown product "ZZ"`)
+
+	_, err = handler(context.Background(), map[string]interface{}{"symbol": 5.0})
+	assert.EqualError(t, err, "invalid type assertion for symbol")
+}
+
+func TestSearchContracts(t *testing.T) {
+	var gotText string
+	var gotLimit int
+	mockClient := &MockTradovateClient{
+		searchContractsFunc: func(text string, limit int) ([]models.ContractMatch, error) {
+			gotText, gotLimit = text, limit
+			return []models.ContractMatch{{Contract: models.Contract{ID: 4100, Name: "MNQZ4"}, Product: "MNQ"}}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["searchContracts"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"text": "micro nasdaq"})
+	require.NoError(t, err)
+	assert.Equal(t, "MNQZ4", result.([]models.ContractMatch)[0].Contract.Name)
+	assert.Equal(t, "micro nasdaq", gotText)
+	assert.Equal(t, 0, gotLimit)
+
+	_, err = handler(context.Background(), map[string]interface{}{"text": "MNQ", "maxResults": float64(3)})
+	require.NoError(t, err)
+	assert.Equal(t, 3, gotLimit)
+
+	_, err = handler(context.Background(), map[string]interface{}{})
+	assert.EqualError(t, err, "missing required field: text")
+
+	_, err = handler(context.Background(), map[string]interface{}{"text": "MNQ", "maxResults": float64(0)})
+	assert.EqualError(t, err, "invalid maxResults")
+}
+
+func TestAuthStatus(t *testing.T) {
+	now := time.Date(2024, 3, 15, 13, 30, 0, 0, time.UTC)
+	expiresAt := now.Add(75 * time.Minute)
+	mockClient := &MockTradovateClient{
+		tokenExpiresAtFunc: func() time.Time { return expiresAt },
+	}
+
+	status := authStatus(mockClient, now)
+	assert.True(t, status.Authenticated)
+	assert.Equal(t, &expiresAt, status.ExpiresAt)
+	assert.Equal(t, 4500, *status.ExpiresInSeconds)
+
+	mockClient = &MockTradovateClient{isAuthenticatedFunc: func() bool { return false }}
+	status = authStatus(mockClient, now)
+	assert.Equal(t, AuthStatus{Authenticated: false}, status)
+
+	result, err := NewHandlers(mockClient)["getAuthStatus"].Handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.False(t, result.(AuthStatus).Authenticated)
+}
+
+func TestGetServerTime(t *testing.T) {
+	serverTime := time.Date(2024, 3, 15, 13, 30, 0, 0, time.UTC)
+	mockClient := &MockTradovateClient{
+		getServerTimeFunc: func() (time.Time, error) { return serverTime, nil },
+		clockSkewFunc:     func() time.Duration { return -7 * time.Second },
+	}
+
+	result, err := NewHandlers(mockClient)["getServerTime"].Handler(context.Background(), nil)
+	require.NoError(t, err)
+	st := result.(ServerTime)
+	assert.Equal(t, serverTime, st.ServerTime)
+	assert.Equal(t, int64(-7000), st.SkewMs)
+	assert.False(t, st.LocalTime.IsZero())
+
+	mockClient.getServerTimeFunc = func() (time.Time, error) { return time.Time{}, errors.New("status 503") }
+	_, err = NewHandlers(mockClient)["getServerTime"].Handler(context.Background(), nil)
+	assert.EqualError(t, err, "status 503")
+}
+
+func TestGetRateLimitStatus(t *testing.T) {
+	status := models.RateLimitStatus{
+		Endpoints:          []models.EndpointUsage{{Class: "historical", LastMinute: 3, LastHour: 40, ResetInSeconds: 1200}},
+		Penalized:          true,
+		PenaltyRemainingMs: 4000,
+		Penalties:          1,
+	}
+	mockClient := &MockTradovateClient{
+		rateLimitStatusFunc: func() models.RateLimitStatus { return status },
+	}
+
+	result, err := NewHandlers(mockClient)["getRateLimitStatus"].Handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, status, result)
+}
+
+// sessionWithAccount returns the context of a request on a session that has
+// selected accountID.
+func sessionWithAccount(accountID int) context.Context {
+	ctx := WithNotifier(context.Background(), func(string, interface{}) {})
+	sessionOf(ctx).accountID.Store(int64(accountID))
+	return ctx
+}
+
+func TestSelectAccount(t *testing.T) {
+	var resolved string
+	mockClient := &MockTradovateClient{
+		resolveAccountFunc: func(account string) (*models.Account, error) {
+			resolved = account
+			return &models.Account{ID: 202, Name: "DEMO202", Active: true}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["selectAccount"].Handler
+	ctx := WithNotifier(context.Background(), func(string, interface{}) {})
+
+	result, err := handler(ctx, map[string]interface{}{"account": "DEMO202"})
+	require.NoError(t, err)
+	assert.Equal(t, 202, result.(*models.Account).ID)
+	assert.Equal(t, "DEMO202", resolved)
+	assert.Equal(t, 202, activeAccountID(ctx))
+
+	_, err = handler(ctx, map[string]interface{}{"account": float64(202)})
+	require.NoError(t, err)
+	assert.Equal(t, "202", resolved)
+
+	_, err = handler(ctx, map[string]interface{}{})
+	assert.EqualError(t, err, "missing required field: account")
+	_, err = handler(ctx, map[string]interface{}{"account": true})
+	assert.Error(t, err)
+
+	_, err = handler(context.Background(), map[string]interface{}{"account": "DEMO202"})
+	assert.EqualError(t, err, "selectAccount needs a session, which this transport does not keep: give accountId with each call instead")
+}
+
+func TestActiveAccountDefault(t *testing.T) {
+	var orderAccount, limitsAccount int
+	mockClient := &MockTradovateClient{
+		placeOrderFunc: func(order models.Order) (*models.Order, error) {
+			orderAccount = order.AccountID
+			return &order, nil
+		},
+		getRiskLimitsFunc: func(accountID int) (*models.RiskLimit, error) {
+			limitsAccount = accountID
+			return &models.RiskLimit{AccountID: accountID}, nil
+		},
+		resolveAccountFunc: func(account string) (*models.Account, error) {
+			return &models.Account{ID: 202, Name: "DEMO202", Active: true}, nil
+		},
+	}
+	handlers := NewHandlers(mockClient)
+	ctx := WithNotifier(context.Background(), func(string, interface{}) {})
+	_, err := handlers["selectAccount"].Handler(ctx, map[string]interface{}{"account": "202"})
+	require.NoError(t, err)
+
+	params := map[string]interface{}{
+		"contractId":  float64(1),
+		"orderType":   "Market",
+		"quantity":    float64(1),
+		"timeInForce": "Day",
+	}
+	_, err = handlers["placeOrder"].Handler(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, 202, orderAccount)
+	assert.NotContains(t, params, "accountId", "the caller's params are not modified")
+
+	// An explicit account still wins.
+	params["accountId"] = float64(101)
+	_, err = handlers["placeOrder"].Handler(ctx, params)
+	require.NoError(t, err)
+	assert.Equal(t, 101, orderAccount)
+
+	_, err = handlers["getRiskLimits"].Handler(ctx, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, 202, limitsAccount)
+
+	// Other sessions sharing the server keep their own selection.
+	other := WithNotifier(context.Background(), func(string, interface{}) {})
+	_, err = handlers["getRiskLimits"].Handler(other, map[string]interface{}{})
+	assert.EqualError(t, err, "missing required field: accountId")
+}
+
+func TestHandleModifyOrder(t *testing.T) {
+	var gotID int
+	var gotChanges models.OrderChanges
+	mockClient := &MockTradovateClient{
+		modifyOrderFunc: func(orderID int, changes models.OrderChanges) error {
+			gotID, gotChanges = orderID, changes
+			return nil
+		},
+	}
+	handler := NewHandlers(mockClient)["modifyOrder"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{
+		"orderId":   float64(67890),
+		"quantity":  float64(3),
+		"stopPrice": 5090.5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"success": true}, result)
+	assert.Equal(t, 67890, gotID)
+	require.NotNil(t, gotChanges.Quantity)
+	assert.Equal(t, 3, *gotChanges.Quantity)
+	require.NotNil(t, gotChanges.StopPrice)
+	assert.Equal(t, 5090.5, *gotChanges.StopPrice)
+	assert.Nil(t, gotChanges.Price)
+	assert.Empty(t, gotChanges.OrderType)
+
+	// Converting a stop to a stop-limit adds its limit price.
+	_, err = handler(context.Background(), map[string]interface{}{
+		"orderId":   float64(67890),
+		"orderType": "StopLimit",
+		"stopPrice": 5090.5,
+		"price":     5090.0,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "StopLimit", gotChanges.OrderType)
+	require.NotNil(t, gotChanges.Price)
+	assert.Equal(t, 5090.0, *gotChanges.Price)
+}
+
+func TestHandleListOrders(t *testing.T) {
+	var gotAccount int
+	var gotStatus string
+	mockClient := &MockTradovateClient{
+		getOrdersFunc: func(accountID int, status string) ([]models.Order, error) {
+			gotAccount, gotStatus = accountID, status
+			return []models.Order{
+				{ID: 1, ContractID: 10, Status: "Working", Timestamp: "2024-06-03T13:00:00Z"},
+				{ID: 2, ContractID: 20, Status: "Working", Timestamp: "2024-06-03T14:30:00Z"},
+				{ID: 3, ContractID: 10, Status: "Working", Timestamp: "2024-06-03T15:00:00Z"},
+				{ID: 4, ContractID: 10, Status: "Working"},
+			}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["listOrders"].Handler
+
+	ids := func(result interface{}) []int {
+		var ids []int
+		for _, order := range result.([]models.Order) {
+			ids = append(ids, order.ID)
+		}
+		return ids
+	}
+
+	result, err := handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "status": "Working"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, ids(result))
+	assert.Equal(t, 12345, gotAccount)
+	assert.Equal(t, "Working", gotStatus)
+
+	result, err = handler(context.Background(), map[string]interface{}{"contractId": float64(10), "since": "2024-06-03T10:00:00-04:00"})
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, ids(result))
+	assert.Equal(t, 0, gotAccount)
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Invalid account", map[string]interface{}{"accountId": float64(0)}, "invalid accountId"},
+		{"Invalid status", map[string]interface{}{"status": 1.0}, "invalid type assertion for status"},
+		{"Invalid contract", map[string]interface{}{"contractId": float64(-2)}, "invalid contractId"},
+		{"Invalid since", map[string]interface{}{"since": "today"}, `invalid since format: parsing time "today" as "2006-01-02T15:04:05Z07:00": cannot parse "today" as "2006"`},
+		{"Invalid sortBy", map[string]interface{}{"sortBy": "side"}, "invalid sortBy: must be one of time, quantity, price"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestHandleListOrdersFilterAndSort(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		getOrdersFunc: func(accountID int, status string) ([]models.Order, error) {
+			return []models.Order{
+				{ID: 1, Status: "Filled", Quantity: 2, Price: 5100, Timestamp: "2024-06-03T13:00:00Z"},
+				{ID: 2, Status: "Working", Quantity: 1, Price: 5080, Timestamp: "2024-06-03T15:00:00Z"},
+				{ID: 3, Status: "PendingNew", Quantity: 3, Price: 5090, CreatedAt: time.Date(2024, 6, 3, 14, 0, 0, 0, time.UTC).UnixMilli()},
+				{ID: 4, Status: "Canceled", Quantity: 1, Price: 5120},
+			}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["listOrders"].Handler
+	ids := func(params map[string]interface{}) []int {
+		result, err := handler(context.Background(), params)
+		require.NoError(t, err)
+		var ids []int
+		for _, order := range result.([]models.Order) {
+			ids = append(ids, order.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []int{2, 3}, ids(map[string]interface{}{"onlyOpen": true}))
+	assert.Equal(t, []int{4, 1, 3, 2}, ids(map[string]interface{}{"sortBy": "time"}), "orders placed at an unknown time sort first")
+	assert.Equal(t, []int{3, 1, 2, 4}, ids(map[string]interface{}{"sortBy": "quantity", "descending": true}))
+	assert.Equal(t, []int{2, 3}, ids(map[string]interface{}{"sortBy": "price", "onlyOpen": true}))
+}
+
+func TestHandleLiquidatePosition(t *testing.T) {
+	var liquidated [2]int
+	mockClient := &MockTradovateClient{
+		getPositionsByAccountFunc: func(accountID int) ([]models.Position, error) {
+			return []models.Position{
+				{AccountID: accountID, ContractID: 1, NetPos: 0},
+				{AccountID: accountID, ContractID: 2, NetPos: -3},
+			}, nil
+		},
+		getContractFunc: func(contractID int) (*models.Contract, error) {
+			return &models.Contract{ID: contractID, Name: map[int]string{1: "NQZ4", 2: "ESZ4"}[contractID]}, nil
+		},
+		liquidatePositionFunc: func(accountID, contractID int) (int, error) {
+			liquidated = [2]int{accountID, contractID}
+			return 900, nil
+		},
+		getOrderFunc: func(orderID int) (*models.Order, error) {
+			return &models.Order{ID: orderID, ContractID: 2, Status: "Filled", FilledQty: 3, AveragePrice: 5101.25}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["liquidatePosition"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "symbol": "esz4"})
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{12345, 2}, liquidated)
+	assert.Equal(t, &models.Order{ID: 900, ContractID: 2, Status: "Filled", FilledQty: 3, AveragePrice: 5101.25}, result)
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "contractId": float64(7)})
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{12345, 7}, liquidated)
+
+	// A flat contract has nothing to liquidate.
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "symbol": "NQZ4"})
+	assert.EqualError(t, err, "account 12345 has no open position in NQZ4")
+
+	// A symbol other than a held contract's name is resolved to a contract.
+	mockClient.resolveFrontMonthFunc = func(product string) (*models.FrontMonth, error) {
+		return &models.FrontMonth{Product: product, Contract: models.Contract{ID: 2, Name: "ESZ4"}}, nil
+	}
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "symbol": "ES front month"})
+	require.NoError(t, err)
+	assert.Equal(t, [2]int{12345, 2}, liquidated)
+
+	// The order ID is returned even if the order cannot be fetched.
+	mockClient.getOrderFunc = func(orderID int) (*models.Order, error) { return nil, errors.New("unavailable") }
+	result, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "contractId": float64(7)})
+	require.NoError(t, err)
+	assert.Equal(t, &models.Order{ID: 900, AccountID: 12345, ContractID: 7}, result)
+}
+
+func TestHandleLiquidatePositionErrors(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		liquidatePositionFunc: func(accountID, contractID int) (int, error) {
+			return 0, errors.New("liquidation rejected: RiskCheck: Account is locked")
+		},
+	}
+	handler := NewHandlers(mockClient)["liquidatePosition"].Handler
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Missing account", map[string]interface{}{"contractId": float64(1)}, "missing required field: accountId"},
+		{"Missing contract", map[string]interface{}{"accountId": float64(1)}, "missing required field: contractId or symbol"},
+		{"Invalid contract", map[string]interface{}{"accountId": float64(1), "contractId": float64(0)}, "invalid contractId"},
+		{"Invalid symbol", map[string]interface{}{"accountId": float64(1), "symbol": 5.0}, "invalid type assertion for symbol"},
+		{"Rejected", map[string]interface{}{"accountId": float64(1), "contractId": float64(1)}, "liquidation rejected: RiskCheck: Account is locked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestHandleModifyOrderInvalidParams(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"riskLimits": {"maxOrderQuantity": 2}}`), 0600))
+	store, err := config.NewStore(path)
+	require.NoError(t, err)
+	handler := NewHandlers(&MockTradovateClient{}, WithConfig(store))["modifyOrder"].Handler
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Missing order ID", map[string]interface{}{"price": 1.0}, "missing required field: orderId"},
+		{"Invalid order ID", map[string]interface{}{"orderId": "abc", "price": 1.0}, "invalid type assertion for orderId"},
+		{"No changes", map[string]interface{}{"orderId": float64(1)}, "nothing to modify: set quantity, price, stopPrice, orderType or timeInForce"},
+		{"Zero quantity", map[string]interface{}{"orderId": float64(1), "quantity": float64(0)}, "invalid quantity"},
+		{"Quantity over limit", map[string]interface{}{"orderId": float64(1), "quantity": float64(3)}, "order quantity 3 exceeds configured maximum of 2"},
+		{"Negative price", map[string]interface{}{"orderId": float64(1), "price": -1.0}, "invalid price"},
+		{"Invalid order type", map[string]interface{}{"orderId": float64(1), "orderType": 1.0}, "invalid type assertion for orderType"},
+		{"Unknown order type", map[string]interface{}{"orderId": float64(1), "orderType": "Iceberg"}, "invalid orderType: must be one of Market, Limit, Stop, StopLimit, MIT"},
+		{"Order type without its price", map[string]interface{}{"orderId": float64(1), "orderType": "StopLimit", "stopPrice": 5080.0}, "price is required when orderType is StopLimit"},
+		{"Order type with unused price", map[string]interface{}{"orderId": float64(1), "orderType": "Market", "price": 5080.0}, "price is not used when orderType is Market"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestHandlePlaceOcoOrder(t *testing.T) {
+	var got models.OCOOrder
+	mockClient := &MockTradovateClient{
+		getPositionsByAccountFunc: func(accountID int) ([]models.Position, error) {
+			return []models.Position{{AccountID: accountID, ContractID: 1234, NetPos: 3}}, nil
+		},
+		placeOCOFunc: func(oco models.OCOOrder) (*models.OCOOrder, error) {
+			got = oco
+			return &oco, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["placeOcoOrder"].Handler
+
+	params := map[string]interface{}{
+		"accountId":       float64(1),
+		"contractId":      float64(1234),
+		"side":            "Sell",
+		"quantity":        float64(2),
+		"takeProfitPrice": 5120.0,
+		"stopLossPrice":   5080.0,
+	}
+	_, err := handler(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, models.Order{AccountID: 1, ContractID: 1234, Side: "Sell", Quantity: 2, TimeInForce: "GTC", OrderType: "Limit", Price: 5120}, got.First)
+	assert.Equal(t, models.Order{AccountID: 1, ContractID: 1234, Side: "Sell", Quantity: 2, TimeInForce: "GTC", OrderType: "Stop", StopPrice: 5080}, got.Second)
+
+	params["side"] = "Buy"
+	_, err = handler(context.Background(), params)
+	assert.EqualError(t, err, "takeProfitPrice must be below stopLossPrice for a Buy OCO")
+
+	params["side"] = "Short"
+	_, err = handler(context.Background(), params)
+	assert.EqualError(t, err, "invalid side: must be one of Buy, Sell")
+
+	delete(params, "stopLossPrice")
+	_, err = handler(context.Background(), params)
+	assert.EqualError(t, err, "missing required field: stopLossPrice")
+
+	params["side"] = "Sell"
+	params["stopLossPrice"] = 5080.0
+	params["accountId"] = 1.5
+	_, err = handler(context.Background(), params)
+	assert.EqualError(t, err, "invalid type assertion for accountId")
+
+	params["accountId"] = float64(1)
+	for _, contractID := range []float64{0, -1234} {
+		params["contractId"] = contractID
+		_, err = handler(context.Background(), params)
+		assert.EqualError(t, err, "invalid contractId")
+	}
+}
+
+func TestHandlePlaceOcoOrderPosition(t *testing.T) {
+	netPos := 0
+	mockClient := &MockTradovateClient{
+		getPositionsByAccountFunc: func(accountID int) ([]models.Position, error) {
+			return []models.Position{
+				{AccountID: accountID, ContractID: 5678, NetPos: 4},
+				{AccountID: accountID, ContractID: 1234, NetPos: netPos},
+			}, nil
+		},
+		placeOCOFunc: func(oco models.OCOOrder) (*models.OCOOrder, error) {
+			return &oco, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["placeOcoOrder"].Handler
+	exitShort := map[string]interface{}{
+		"accountId":       float64(1),
+		"contractId":      float64(1234),
+		"side":            "Buy",
+		"quantity":        float64(2),
+		"takeProfitPrice": 5080.0,
+		"stopLossPrice":   5120.0,
+	}
+
+	tests := []struct {
+		name   string
+		netPos int
+		errMsg string
+	}{
+		{"Flat", 0, "account 1 has no open position in contract 1234 to exit"},
+		{"Long", 2, "a Buy OCO exits a short position, but account 1 is long 2 of contract 1234"},
+		{"Smaller short", -1, "quantity 2 exceeds the 1 contracts held in contract 1234"},
+		{"Short", -2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			netPos = tt.netPos
+			_, err := handler(context.Background(), exitShort)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestHandlePlaceBracketOrder(t *testing.T) {
+	var got models.BracketOrder
+	mockClient := &MockTradovateClient{
+		placeBracketOrderFunc: func(bracket models.BracketOrder) (*models.OrderStrategy, error) {
+			got = bracket
+			return &models.OrderStrategy{ID: 55}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["placeBracketOrder"].Handler
+
+	params := map[string]interface{}{
+		"accountId":    float64(1),
+		"contractId":   float64(1234),
+		"side":         "Buy",
+		"quantity":     float64(2),
+		"targetOffset": 10.0,
+		"stopOffset":   5.0,
+	}
+	result, err := handler(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, &models.OrderStrategy{ID: 55}, result)
+	assert.Equal(t, models.BracketOrder{
+		AccountID:    1,
+		ContractID:   1234,
+		Side:         "Buy",
+		Quantity:     2,
+		OrderType:    "Market",
+		TimeInForce:  "Day",
+		TargetOffset: 10,
+		StopOffset:   5,
+	}, got)
+
+	params["orderType"] = "Limit"
+	_, err = handler(context.Background(), params)
+	assert.EqualError(t, err, "price is required when orderType is Limit")
+
+	params["price"] = 5100.0
+	_, err = handler(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, 5100.0, got.Price)
+
+	params["orderType"] = "Stop"
+	_, err = handler(context.Background(), params)
+	assert.EqualError(t, err, "invalid orderType: must be one of Market, Limit")
+
+	params["orderType"] = "Market"
+	params["stopOffset"] = 0.0
+	_, err = handler(context.Background(), params)
+	assert.EqualError(t, err, "invalid stopOffset")
+
+	params["stopOffset"] = 5.0
+	for _, contractID := range []float64{0, -1234} {
+		params["contractId"] = contractID
+		_, err = handler(context.Background(), params)
+		assert.EqualError(t, err, "invalid contractId")
+	}
+	params["contractId"] = float64(1234)
+	params["accountId"] = 1.5
+	_, err = handler(context.Background(), params)
+	assert.EqualError(t, err, "invalid type assertion for accountId")
+}
+
+func TestHandlePlaceBracketOrderExits(t *testing.T) {
+	var got models.BracketOrder
+	lookups := 0
+	mockClient := &MockTradovateClient{
+		getContractFunc: func(contractID int) (*models.Contract, error) {
+			lookups++
+			return &models.Contract{ID: contractID, Name: "MNQZ4", TickSize: 0.25}, nil
+		},
+		placeBracketOrderFunc: func(bracket models.BracketOrder) (*models.OrderStrategy, error) {
+			got = bracket
+			return &models.OrderStrategy{ID: 55}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["placeBracketOrder"].Handler
+	base := func(side string, exits map[string]interface{}) map[string]interface{} {
+		params := map[string]interface{}{"accountId": float64(1), "contractId": float64(1234), "side": side, "quantity": float64(1)}
+		for k, v := range exits {
+			params[k] = v
+		}
+		return params
+	}
+
+	_, err := handler(context.Background(), base("Buy", map[string]interface{}{"takeProfitTicks": float64(40), "stopLossTicks": float64(20)}))
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, got.TargetOffset)
+	assert.Equal(t, 5.0, got.StopOffset)
+	assert.Equal(t, 1, lookups, "the tick size is looked up once")
+
+	limit := map[string]interface{}{"orderType": "Limit", "price": 5100.0, "takeProfitPrice": 5110.0, "stopLossPrice": 5095.0}
+	_, err = handler(context.Background(), base("Buy", limit))
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, got.TargetOffset)
+	assert.Equal(t, 5.0, got.StopOffset)
+
+	limit = map[string]interface{}{"orderType": "Limit", "price": 5100.0, "takeProfitPrice": 5090.0, "stopLossTicks": float64(8)}
+	_, err = handler(context.Background(), base("Sell", limit))
+	require.NoError(t, err)
+	assert.Equal(t, 10.0, got.TargetOffset)
+	assert.Equal(t, 2.0, got.StopOffset)
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Missing take-profit", base("Buy", map[string]interface{}{"stopOffset": 5.0}), "missing required field: targetOffset, takeProfitTicks or takeProfitPrice"},
+		{"Two stop-losses", base("Buy", map[string]interface{}{"targetOffset": 10.0, "stopOffset": 5.0, "stopLossTicks": float64(20)}), "only one of stopOffset, stopLossTicks and stopLossPrice may be given"},
+		{"Fractional ticks", base("Buy", map[string]interface{}{"takeProfitTicks": 2.5, "stopOffset": 5.0}), "invalid type assertion for takeProfitTicks"},
+		{"Price on a Market entry", base("Buy", map[string]interface{}{"takeProfitPrice": 5110.0, "stopOffset": 5.0}), "takeProfitPrice needs a Limit entry, whose fill price is known: give targetOffset or takeProfitTicks instead"},
+		{"Take-profit below a Buy", base("Buy", map[string]interface{}{"orderType": "Limit", "price": 5100.0, "takeProfitPrice": 5090.0, "stopOffset": 5.0}), "takeProfitPrice must be above the entry price of a Buy order"},
+		{"Stop-loss below a Sell", base("Sell", map[string]interface{}{"orderType": "Limit", "price": 5100.0, "targetOffset": 10.0, "stopLossPrice": 5095.0}), "stopLossPrice must be above the entry price of a Sell order"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+
+	mockClient.getContractFunc = func(contractID int) (*models.Contract, error) {
+		return &models.Contract{ID: contractID, Name: "MNQZ4"}, nil
+	}
+	_, err = handler(context.Background(), base("Buy", map[string]interface{}{"takeProfitTicks": float64(40), "stopOffset": 5.0}))
+	assert.EqualError(t, err, "tick size of MNQZ4 is unknown: give targetOffset instead")
+}
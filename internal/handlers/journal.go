@@ -0,0 +1,347 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// JournalNote is a note attached to an order or fill.
+type JournalNote struct {
+	Text string    `json:"text"` // The note
+	At   time.Time `json:"at"`   // When it was written
+}
+
+// TradeAnnotation is what the journal records about one order or fill.
+type TradeAnnotation struct {
+	OrderID   int           `json:"orderId,omitempty"`  // Order annotated, or
+	FillID    int           `json:"fillId,omitempty"`   // Fill annotated
+	Notes     []JournalNote `json:"notes,omitempty"`    // Notes, oldest first
+	Tags      []string      `json:"tags,omitempty"`     // Tags, sorted
+	Strategy  string        `json:"strategy,omitempty"` // Strategy the trade was made under
+	UpdatedAt time.Time     `json:"updatedAt"`          // When the annotation last changed
+}
+
+// Journal keeps annotations of orders and fills. Annotations are saved to
+// a file, when it has one, on every change.
+type Journal struct {
+	mu     sync.Mutex
+	path   string
+	orders map[int]TradeAnnotation
+	fills  map[int]TradeAnnotation
+}
+
+// journalFile is the on-disk form of a Journal.
+type journalFile struct {
+	Annotations []TradeAnnotation `json:"annotations"`
+}
+
+// DefaultJournalPath returns the journal location under the user's config
+// directory, or an empty string if it cannot be determined.
+func DefaultJournalPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "mcp-tradovate", "journal.json")
+}
+
+// OpenJournal loads the journal saved at path, or starts an empty one if
+// there is no file yet. An empty path keeps the journal in memory only.
+func OpenJournal(path string) (*Journal, error) {
+	j := newJournal(path)
+	if path == "" {
+		return j, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	var file journalFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode journal %s: %w", path, err)
+	}
+	for _, annotation := range file.Annotations {
+		if annotation.FillID != 0 {
+			j.fills[annotation.FillID] = annotation
+		} else {
+			j.orders[annotation.OrderID] = annotation
+		}
+	}
+	return j, nil
+}
+
+// newJournal returns an empty journal saved to path.
+func newJournal(path string) *Journal {
+	return &Journal{path: path, orders: make(map[int]TradeAnnotation), fills: make(map[int]TradeAnnotation)}
+}
+
+// WithJournal keeps trade annotations in journal. Without it, they are
+// kept in memory and lost on exit.
+func WithJournal(journal *Journal) Option {
+	return func(o *options) {
+		o.journal = journal
+	}
+}
+
+// annotationChange is an edit to a TradeAnnotation.
+type annotationChange struct {
+	note       string
+	tags       []string
+	removeTags []string
+	strategy   *string
+}
+
+// annotate applies change to the annotation of the order or fill key
+// names, saves the journal and returns the result. The journal is left as
+// it was if it cannot be saved.
+func (j *Journal) annotate(key TradeAnnotation, change annotationChange, now time.Time) (TradeAnnotation, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	annotations, id := j.orders, key.OrderID
+	if key.FillID != 0 {
+		annotations, id = j.fills, key.FillID
+	}
+	previous, existed := annotations[id]
+
+	annotation := key
+	if existed {
+		annotation = previous
+		annotation.Notes = append([]JournalNote(nil), previous.Notes...)
+	}
+	if change.note != "" {
+		annotation.Notes = append(annotation.Notes, JournalNote{Text: change.note, At: now})
+	}
+	tags := make(map[string]bool)
+	for _, tag := range annotation.Tags {
+		tags[tag] = true
+	}
+	for _, tag := range change.tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags[tag] = true
+		}
+	}
+	for _, tag := range change.removeTags {
+		delete(tags, strings.TrimSpace(tag))
+	}
+	annotation.Tags = sortedKeys(tags)
+	if change.strategy != nil {
+		annotation.Strategy = *change.strategy
+	}
+	annotation.UpdatedAt = now
+
+	annotations[id] = annotation
+	if err := j.save(); err != nil {
+		if existed {
+			annotations[id] = previous
+		} else {
+			delete(annotations, id)
+		}
+		return TradeAnnotation{}, err
+	}
+	return annotation, nil
+}
+
+// lookup returns the annotations of a fill and of the order it filled.
+func (j *Journal) lookup(fill models.Fill) (order, own TradeAnnotation) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.orders[fill.OrderID], j.fills[fill.ID]
+}
+
+// save writes the journal to its file, readable only by the current user.
+// It is written to a temporary file and renamed into place so that a crash
+// never leaves a truncated journal behind.
+func (j *Journal) save() error {
+	if j.path == "" {
+		return nil
+	}
+	file := journalFile{Annotations: make([]TradeAnnotation, 0, len(j.orders)+len(j.fills))}
+	for _, annotation := range j.orders {
+		file.Annotations = append(file.Annotations, annotation)
+	}
+	for _, annotation := range j.fills {
+		file.Annotations = append(file.Annotations, annotation)
+	}
+	sort.Slice(file.Annotations, func(i, k int) bool {
+		a, b := file.Annotations[i], file.Annotations[k]
+		if a.OrderID != b.OrderID {
+			return a.OrderID < b.OrderID
+		}
+		return a.FillID < b.FillID
+	})
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode journal: %w", err)
+	}
+
+	dir := filepath.Dir(j.path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".journal-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), j.path); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of set in order, or nil if it is empty.
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// annotateTradeRequest holds the parameters of annotateTrade.
+type annotateTradeRequest struct {
+	OrderID    int      `json:"orderId" validate:"gt=0" desc:"Order to annotate"`
+	FillID     int      `json:"fillId" validate:"gt=0" desc:"Fill to annotate"`
+	Note       string   `json:"note" desc:"Note to add"`
+	Tags       []string `json:"tags" desc:"Tags to add, e.g. [\"breakout\", \"mistake\"]"`
+	RemoveTags []string `json:"removeTags" desc:"Tags to remove"`
+	Strategy   *string  `json:"strategy" desc:"Strategy label to set; empty to clear it"`
+}
+
+// handleAnnotateTrade processes requests to attach a note, tags or a
+// strategy label to an order or a fill. Notes are added to those already
+// attached, tags are added to or removed from the set, and the strategy
+// replaces the one set before.
+// Parameters (exactly one of orderId and fillId is required, and at least one change):
+// - orderId: (float64) The order to annotate
+// - fillId: (float64) The fill to annotate
+// - note: (string) A note to add
+// - tags: ([]string) Tags to add
+// - removeTags: ([]string) Tags to remove
+// - strategy: (string) The strategy label to set, or "" to clear it
+func handleAnnotateTrade(journal *Journal) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req annotateTradeRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		switch {
+		case req.OrderID == 0 && req.FillID == 0:
+			return nil, paramErrorf("missing required field: orderId or fillId")
+		case req.OrderID != 0 && req.FillID != 0:
+			return nil, fmt.Errorf("only one of orderId and fillId may be given")
+		}
+		if strings.TrimSpace(req.Note) == "" && len(req.Tags) == 0 && len(req.RemoveTags) == 0 && req.Strategy == nil {
+			return nil, fmt.Errorf("nothing to annotate: give a note, tags, removeTags or a strategy")
+		}
+		return journal.annotate(TradeAnnotation{OrderID: req.OrderID, FillID: req.FillID}, annotationChange{
+			note:       strings.TrimSpace(req.Note),
+			tags:       req.Tags,
+			removeTags: req.RemoveTags,
+			strategy:   req.Strategy,
+		}, timeNow())
+	}
+}
+
+// JournalEntry is a fill with the annotations of it and of its order.
+type JournalEntry struct {
+	models.Fill
+	Notes    []JournalNote `json:"notes,omitempty"`    // Notes on the order and the fill, oldest first
+	Tags     []string      `json:"tags,omitempty"`     // Tags of the order and the fill, sorted
+	Strategy string        `json:"strategy,omitempty"` // Strategy of the fill, or else of its order
+}
+
+// getJournalRequest holds the parameters of getJournal.
+type getJournalRequest struct {
+	AccountID     int    `json:"accountId" validate:"required,gt=0" desc:"Account whose trades to return"`
+	StartTime     string `json:"startTime" desc:"Only return fills at or after this time, RFC 3339 or relative, e.g. last 5 trading days (default: the start of the trading day, 17:00 Chicago time)"`
+	EndTime       string `json:"endTime" desc:"Only return fills before this time, RFC 3339 or relative"`
+	Tag           string `json:"tag" desc:"Only return trades with this tag"`
+	Strategy      string `json:"strategy" desc:"Only return trades under this strategy"`
+	AnnotatedOnly bool   `json:"annotatedOnly" desc:"Only return trades with a note, tag or strategy"`
+}
+
+// handleGetJournal processes requests for an account's trade history with
+// the notes, tags and strategies attached to its orders and fills.
+// Required parameters:
+// - accountId: (float64) The account whose trades to return (default: the active account, if set)
+// Optional parameters:
+// - startTime: (string) Only return fills at or after this time (default: the start of the trading day, 17:00 Chicago time)
+// - endTime: (string) Only return fills before this time
+// - tag: (string) Only return trades with this tag
+// - strategy: (string) Only return trades under this strategy
+// - annotatedOnly: (bool) Only return annotated trades
+func handleGetJournal(client client.TradovateClientInterface, journal *Journal) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req getJournalRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		start, end, err := timeRange(req.StartTime, req.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		fills, err := client.GetAccountFills(ctx, req.AccountID, start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		entries := make([]JournalEntry, 0, len(fills))
+		for _, fill := range fills {
+			order, own := journal.lookup(fill)
+			entry := JournalEntry{Fill: fill, Strategy: own.Strategy}
+			if entry.Strategy == "" {
+				entry.Strategy = order.Strategy
+			}
+			entry.Notes = append(append(entry.Notes, order.Notes...), own.Notes...)
+			sort.SliceStable(entry.Notes, func(i, k int) bool { return entry.Notes[i].At.Before(entry.Notes[k].At) })
+			tags := make(map[string]bool)
+			for _, tag := range append(append([]string(nil), order.Tags...), own.Tags...) {
+				tags[tag] = true
+			}
+			entry.Tags = sortedKeys(tags)
+
+			if req.AnnotatedOnly && len(entry.Notes) == 0 && len(entry.Tags) == 0 && entry.Strategy == "" {
+				continue
+			}
+			if req.Tag != "" && !tags[req.Tag] {
+				continue
+			}
+			if req.Strategy != "" && entry.Strategy != req.Strategy {
+				continue
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	}
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTradeJournal(t *testing.T) {
+	now := time.Date(2024, 3, 19, 15, 30, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	path := filepath.Join(t.TempDir(), "journal", "journal.json")
+	journal, err := OpenJournal(path)
+	require.NoError(t, err)
+	mockClient := &MockTradovateClient{
+		getAccountFillsFunc: func(accountID int, start, end time.Time) ([]models.Fill, error) {
+			return []models.Fill{
+				{ID: 501, OrderID: 41, ContractID: 1234, Symbol: "ESM4", Action: "Buy", Price: 5100, Quantity: 1},
+				{ID: 502, OrderID: 41, ContractID: 1234, Symbol: "ESM4", Action: "Buy", Price: 5100.25, Quantity: 1},
+				{ID: 503, OrderID: 42, ContractID: 1234, Symbol: "ESM4", Action: "Sell", Price: 5110, Quantity: 2},
+			}, nil
+		},
+	}
+	h := NewHandlers(mockClient, WithJournal(journal))
+	annotate := func(params map[string]interface{}) TradeAnnotation {
+		result, err := h["annotateTrade"].Handler(context.Background(), params)
+		require.NoError(t, err)
+		return result.(TradeAnnotation)
+	}
+
+	annotation := annotate(map[string]interface{}{
+		"orderId":  float64(41),
+		"note":     "Entered on the opening range breakout",
+		"tags":     []interface{}{"breakout", "open"},
+		"strategy": "ORB",
+	})
+	assert.Equal(t, TradeAnnotation{
+		OrderID:   41,
+		Notes:     []JournalNote{{Text: "Entered on the opening range breakout", At: now}},
+		Tags:      []string{"breakout", "open"},
+		Strategy:  "ORB",
+		UpdatedAt: now,
+	}, annotation)
+
+	now = now.Add(time.Hour)
+	annotation = annotate(map[string]interface{}{"orderId": float64(41), "tags": []interface{}{"chased"}, "removeTags": []interface{}{"open"}})
+	assert.Equal(t, []string{"breakout", "chased"}, annotation.Tags)
+	assert.Len(t, annotation.Notes, 1)
+	assert.Equal(t, "ORB", annotation.Strategy, "a strategy not given is kept")
+	annotate(map[string]interface{}{"fillId": float64(502), "note": "Slipped a tick", "tags": []interface{}{"slippage"}})
+
+	journalOf := func(params map[string]interface{}) []JournalEntry {
+		params["accountId"] = float64(12345)
+		result, err := h["getJournal"].Handler(context.Background(), params)
+		require.NoError(t, err)
+		return result.([]JournalEntry)
+	}
+	entries := journalOf(map[string]interface{}{})
+	require.Len(t, entries, 3)
+	assert.Equal(t, []string{"breakout", "chased"}, entries[0].Tags)
+	assert.Equal(t, []string{"breakout", "chased", "slippage"}, entries[1].Tags)
+	assert.Equal(t, []string{"Entered on the opening range breakout", "Slipped a tick"}, []string{entries[1].Notes[0].Text, entries[1].Notes[1].Text})
+	assert.Equal(t, "ORB", entries[1].Strategy)
+	assert.Empty(t, entries[2].Tags)
+
+	assert.Len(t, journalOf(map[string]interface{}{"annotatedOnly": true}), 2)
+	assert.Len(t, journalOf(map[string]interface{}{"tag": "slippage"}), 1)
+	assert.Len(t, journalOf(map[string]interface{}{"strategy": "ORB"}), 2)
+	assert.Empty(t, journalOf(map[string]interface{}{"strategy": "fade"}))
+
+	// The journal survives a restart.
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	reopened, err := OpenJournal(path)
+	require.NoError(t, err)
+	order, own := reopened.lookup(models.Fill{ID: 502, OrderID: 41})
+	assert.Equal(t, annotation.Tags, order.Tags)
+	assert.Equal(t, []string{"slippage"}, own.Tags)
+	assert.True(t, now.Equal(own.UpdatedAt))
+}
+
+func TestAnnotateTradeInvalidParams(t *testing.T) {
+	handler := NewHandlers(&MockTradovateClient{})["annotateTrade"].Handler
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Missing trade", map[string]interface{}{"note": "x"}, "missing required field: orderId or fillId"},
+		{"Both trades", map[string]interface{}{"orderId": float64(1), "fillId": float64(2), "note": "x"}, "only one of orderId and fillId may be given"},
+		{"Nothing to annotate", map[string]interface{}{"orderId": float64(1), "note": "  "}, "nothing to annotate: give a note, tags, removeTags or a strategy"},
+		{"Invalid tags", map[string]interface{}{"orderId": float64(1), "tags": "breakout"}, "invalid type assertion for tags"},
+		{"Invalid order", map[string]interface{}{"orderId": float64(-1), "note": "x"}, "invalid orderId"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestOpenJournalInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0600))
+	_, err := OpenJournal(path)
+	assert.ErrorContains(t, err, "failed to decode journal")
+}
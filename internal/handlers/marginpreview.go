@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+)
+
+// MarginPreview estimates the margin a prospective order would take from,
+// or give back to, an account once it fills in full.
+type MarginPreview struct {
+	ContractID                   int     `json:"contractId"`                   // Contract traded
+	Symbol                       string  `json:"symbol,omitempty"`             // Name of the contract, e.g. "MNQZ4", when resolved
+	NetPos                       int     `json:"netPos"`                       // Net position now
+	PostTradeNetPos              int     `json:"postTradeNetPos"`              // Net position once the order fills
+	InitialMarginPerContract     float64 `json:"initialMarginPerContract"`     // Margin needed to open one contract
+	MaintenanceMarginPerContract float64 `json:"maintenanceMarginPerContract"` // Margin needed to keep one contract open
+	MarginChange                 float64 `json:"marginChange"`                 // Initial margin the order takes; negative when closing contracts frees margin
+	AvailableMargin              float64 `json:"availableMargin"`              // Buying power left before the order
+	PostTradeAvailableMargin     float64 `json:"postTradeAvailableMargin"`     // Buying power left once the order fills
+	BuyingPowerUsed              float64 `json:"buyingPowerUsed"`              // MarginChange as a percentage of AvailableMargin; 0 when the order takes none
+	MaxQuantity                  int     `json:"maxQuantity"`                  // Largest order on this side the available margin covers
+	Sufficient                   bool    `json:"sufficient"`                   // Whether the available margin covers the order
+}
+
+// marginPreviewRequest holds the parameters of marginPreview.
+type marginPreviewRequest struct {
+	AccountID int `json:"accountId" validate:"required,gt=0" desc:"Account ID the order would be placed for"`
+	contractRef
+	Side     string `json:"side" validate:"required,oneof=Buy Sell" desc:"Order side"`
+	Quantity int    `json:"quantity" validate:"required,gt=0" desc:"Number of contracts"`
+}
+
+// handleMarginPreview processes requests to estimate the margin impact of
+// an order before placing it. Nothing is placed. The order is netted
+// against the account's position in the contract: contracts it closes free
+// their initial margin and contracts it opens take it, at the exchange
+// margin of the contract's product.
+// Required parameters:
+// - accountId: (float64) The account the order would be placed for
+// - contractId: (float64) The contract to trade, or
+// - symbol: (string) The contract to trade, e.g. "MESZ4", "ES" or "ES front month"
+// - side: (string) Buy or Sell
+// - quantity: (float64) The number of contracts
+func handleMarginPreview(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req marginPreviewRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		contractID, err := req.resolve(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		preview := MarginPreview{ContractID: contractID}
+		positions, err := client.GetPositionsByAccount(ctx, req.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching positions of account %d: %w", req.AccountID, err)
+		}
+		for _, position := range positions {
+			if position.ContractID == contractID {
+				preview.NetPos += position.NetPos
+			}
+		}
+		direction := 1
+		if req.Side == "Sell" {
+			direction = -1
+		}
+		preview.PostTradeNetPos = preview.NetPos + direction*req.Quantity
+
+		margin, err := client.GetContractMargin(ctx, contractID)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching margin of contract %d: %w", contractID, err)
+		}
+		snapshot, err := client.GetMarginSnapshot(ctx, req.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching margin of account %d: %w", req.AccountID, err)
+		}
+		if contract, err := client.GetContract(ctx, contractID); err == nil && contract != nil {
+			preview.Symbol = contract.Name
+		}
+
+		perContract := margin.InitialMargin
+		preview.InitialMarginPerContract = perContract
+		preview.MaintenanceMarginPerContract = margin.MaintenanceMargin
+		preview.MarginChange = float64(abs(preview.PostTradeNetPos)-abs(preview.NetPos)) * perContract
+		preview.AvailableMargin = snapshot.AvailableMargin
+		preview.PostTradeAvailableMargin = snapshot.AvailableMargin - preview.MarginChange
+		if preview.MarginChange > 0 && snapshot.AvailableMargin > 0 {
+			preview.BuyingPowerUsed = preview.MarginChange / snapshot.AvailableMargin * 100
+		}
+		preview.Sufficient = preview.MarginChange <= 0 || preview.MarginChange <= snapshot.AvailableMargin
+
+		// Contracts the order closes free the margin to open as many again
+		// on the other side.
+		closable := 0
+		if preview.NetPos*direction < 0 {
+			closable = abs(preview.NetPos)
+		}
+		preview.MaxQuantity = closable
+		if perContract > 0 {
+			if fits := 2*closable + int(math.Floor(snapshot.AvailableMargin/perContract)); fits > closable {
+				preview.MaxQuantity = fits
+			}
+		}
+		return preview, nil
+	}
+}
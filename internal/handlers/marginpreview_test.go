@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleMarginPreview(t *testing.T) {
+	mockClient := &MockTradovateClient{
+		getPositionsByAccountFunc: func(accountID int) ([]models.Position, error) {
+			return []models.Position{{AccountID: accountID, ContractID: 1234, NetPos: 2}, {AccountID: accountID, ContractID: 4321, NetPos: -1}}, nil
+		},
+		getContractFunc: func(contractID int) (*models.Contract, error) {
+			return &models.Contract{ID: contractID, Name: "ESZ4"}, nil
+		},
+		getContractMarginFunc: func(contractID int) (*models.ProductMargin, error) {
+			return &models.ProductMargin{ProductID: 9, InitialMargin: 10000, MaintenanceMargin: 9000}, nil
+		},
+		getMarginSnapshotFunc: func(accountID int) (*models.MarginSnapshot, error) {
+			return &models.MarginSnapshot{AccountID: accountID, AvailableMargin: 25000}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["marginPreview"].Handler
+	preview := func(side string, quantity int) MarginPreview {
+		result, err := handler(context.Background(), map[string]interface{}{
+			"accountId":  float64(12345),
+			"contractId": float64(1234),
+			"side":       side,
+			"quantity":   float64(quantity),
+		})
+		require.NoError(t, err)
+		return result.(MarginPreview)
+	}
+
+	assert.Equal(t, MarginPreview{
+		ContractID:                   1234,
+		Symbol:                       "ESZ4",
+		NetPos:                       2,
+		PostTradeNetPos:              4,
+		InitialMarginPerContract:     10000,
+		MaintenanceMarginPerContract: 9000,
+		MarginChange:                 20000,
+		AvailableMargin:              25000,
+		PostTradeAvailableMargin:     5000,
+		BuyingPowerUsed:              80,
+		MaxQuantity:                  2,
+		Sufficient:                   true,
+	}, preview("Buy", 2))
+
+	result := preview("Buy", 3)
+	assert.False(t, result.Sufficient)
+	assert.Equal(t, -5000.0, result.PostTradeAvailableMargin)
+
+	// Closing contracts frees their margin for the other side.
+	result = preview("Sell", 2)
+	assert.Equal(t, 0, result.PostTradeNetPos)
+	assert.Equal(t, -20000.0, result.MarginChange)
+	assert.Equal(t, 45000.0, result.PostTradeAvailableMargin)
+	assert.Zero(t, result.BuyingPowerUsed)
+	assert.Equal(t, 6, result.MaxQuantity)
+	assert.True(t, result.Sufficient)
+
+	result = preview("Sell", 5)
+	assert.Equal(t, -3, result.PostTradeNetPos)
+	assert.Equal(t, 10000.0, result.MarginChange)
+	assert.InDelta(t, 40, result.BuyingPowerUsed, 1e-9)
+
+	mockClient.getContractMarginFunc = nil
+	_, err := handler(context.Background(), map[string]interface{}{
+		"accountId": float64(12345), "contractId": float64(1234), "side": "Buy", "quantity": float64(1),
+	})
+	assert.EqualError(t, err, "error fetching margin of contract 1234: not implemented")
+
+	mockClient.getContractMarginFunc = func(contractID int) (*models.ProductMargin, error) {
+		return &models.ProductMargin{InitialMargin: 10000}, nil
+	}
+	mockClient.getMarginSnapshotFunc = func(accountID int) (*models.MarginSnapshot, error) { return nil, errors.New("unavailable") }
+	_, err = handler(context.Background(), map[string]interface{}{
+		"accountId": float64(12345), "contractId": float64(1234), "side": "Buy", "quantity": float64(1),
+	})
+	assert.EqualError(t, err, "error fetching margin of account 12345: unavailable")
+
+	_, err = handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234), "side": "Buy"})
+	assert.EqualError(t, err, "missing required field: quantity")
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+const (
+	// DefaultPageSize is the number of items returned per page when a list
+	// result is too large to return whole and no limit was requested.
+	DefaultPageSize = 100
+	// MaxPageSize is the largest page a caller may request.
+	MaxPageSize = 1000
+)
+
+// Page is a window onto a list result that was too large to return whole.
+type Page struct {
+	Items      interface{} `json:"items"`                // Items in this page
+	TotalCount int         `json:"totalCount"`           // Total number of items in the full result
+	NextCursor string      `json:"nextCursor,omitempty"` // Cursor for the next page; empty on the last page
+}
+
+// PageRequest holds the pagination parameters of a tool call.
+type PageRequest struct {
+	Offset int  // Index of the first item to return
+	Limit  int  // Maximum number of items to return
+	paged  bool // Whether the caller asked for pagination explicitly
+}
+
+// ExtractPageRequest removes the reserved "cursor" and "limit" parameters
+// from params and returns them as a PageRequest.
+func ExtractPageRequest(params map[string]interface{}) (PageRequest, error) {
+	req := PageRequest{Limit: DefaultPageSize}
+
+	if raw, ok := params["cursor"]; ok {
+		delete(params, "cursor")
+		cursor, ok := raw.(string)
+		if !ok {
+			return req, fmt.Errorf("invalid type assertion for cursor")
+		}
+		offset, err := decodeCursor(cursor)
+		if err != nil {
+			return req, err
+		}
+		req.Offset = offset
+		req.paged = true
+	}
+
+	if raw, ok := params["limit"]; ok {
+		delete(params, "limit")
+		limit, ok := toNumber(raw)
+		if !ok {
+			return req, fmt.Errorf("invalid type assertion for limit")
+		}
+		if limit < 1 || limit > MaxPageSize || limit != float64(int(limit)) {
+			return req, fmt.Errorf("limit must be a whole number between 1 and %d", MaxPageSize)
+		}
+		req.Limit = int(limit)
+		req.paged = true
+	}
+
+	return req, nil
+}
+
+// Paginate applies req to result. Results that are not lists are returned
+// unchanged, as are lists that fit in a single default page when the caller
+// did not ask for pagination. Any other list is returned as a Page.
+func Paginate(result interface{}, req PageRequest) interface{} {
+	value := reflect.ValueOf(result)
+	if value.Kind() != reflect.Slice {
+		return result
+	}
+
+	total := value.Len()
+	if !req.paged && total <= req.Limit {
+		return result
+	}
+
+	start := req.Offset
+	if start > total {
+		start = total
+	}
+	end := start + req.Limit
+	if end > total {
+		end = total
+	}
+
+	page := Page{
+		Items:      value.Slice(start, end).Interface(),
+		TotalCount: total,
+	}
+	if end < total {
+		page.NextCursor = encodeCursor(end)
+	}
+	return page
+}
+
+// withPageParams returns a copy of s that also describes the limit and
+// cursor parameters, which ExtractPageRequest takes before s is checked.
+func (s *Schema) withPageParams() *Schema {
+	paged := *s
+	paged.Properties = make(map[string]*Property, len(s.Properties)+2)
+	for name, prop := range s.Properties {
+		paged.Properties[name] = prop
+	}
+	minLimit, maxLimit := 1.0, float64(MaxPageSize)
+	paged.Properties["limit"] = &Property{
+		Type:        "integer",
+		Description: fmt.Sprintf("Most items to return; longer results are paged %d at a time without it", DefaultPageSize),
+		Minimum:     &minLimit,
+		Maximum:     &maxLimit,
+	}
+	paged.Properties["cursor"] = &Property{Type: "string", Description: "nextCursor of the previous page"}
+	return &paged
+}
+
+// encodeCursor returns the opaque cursor for the page starting at offset.
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+	return offset, nil
+}
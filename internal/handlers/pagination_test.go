@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractPageRequest(t *testing.T) {
+	params := map[string]interface{}{"contractId": float64(1), "limit": float64(10), "cursor": encodeCursor(20)}
+	req, err := ExtractPageRequest(params)
+	require.NoError(t, err)
+	assert.Equal(t, 20, req.Offset)
+	assert.Equal(t, 10, req.Limit)
+	assert.Equal(t, map[string]interface{}{"contractId": float64(1)}, params)
+
+	req, err = ExtractPageRequest(nil)
+	require.NoError(t, err)
+	assert.Equal(t, PageRequest{Limit: DefaultPageSize}, req)
+
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"cursor not a string", map[string]interface{}{"cursor": float64(1)}, "invalid type assertion for cursor"},
+		{"garbled cursor", map[string]interface{}{"cursor": "!!"}, "invalid cursor"},
+		{"negative cursor", map[string]interface{}{"cursor": encodeCursor(-1)}, "invalid cursor"},
+		{"limit not a number", map[string]interface{}{"limit": "ten"}, "invalid type assertion for limit"},
+		{"limit too small", map[string]interface{}{"limit": float64(0)}, "limit must be a whole number"},
+		{"limit too large", map[string]interface{}{"limit": float64(MaxPageSize + 1)}, "limit must be a whole number"},
+		{"fractional limit", map[string]interface{}{"limit": 2.5}, "limit must be a whole number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ExtractPageRequest(tt.params)
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	contracts := make([]models.Contract, 250)
+	for i := range contracts {
+		contracts[i].ID = i
+	}
+
+	// Non-list results and small lists pass through untouched.
+	order := &models.Order{ID: 1}
+	assert.Same(t, order, Paginate(order, PageRequest{Limit: DefaultPageSize}))
+	assert.Equal(t, contracts[:5], Paginate(contracts[:5], PageRequest{Limit: DefaultPageSize}))
+
+	// Oversized lists are truncated to the default page size.
+	page, ok := Paginate(contracts, PageRequest{Limit: DefaultPageSize}).(Page)
+	require.True(t, ok)
+	assert.Equal(t, 250, page.TotalCount)
+	assert.Equal(t, contracts[:100], page.Items)
+	require.NotEmpty(t, page.NextCursor)
+
+	// Following the cursor walks the remaining items.
+	req, err := ExtractPageRequest(map[string]interface{}{"cursor": page.NextCursor, "limit": float64(120)})
+	require.NoError(t, err)
+	page = Paginate(contracts, req).(Page)
+	assert.Equal(t, contracts[100:220], page.Items)
+
+	req, err = ExtractPageRequest(map[string]interface{}{"cursor": page.NextCursor, "limit": float64(120)})
+	require.NoError(t, err)
+	page = Paginate(contracts, req).(Page)
+	assert.Equal(t, contracts[220:], page.Items)
+	assert.Empty(t, page.NextCursor)
+
+	// An explicit limit pages even small lists, and past-the-end cursors are empty.
+	page = Paginate(contracts[:5], PageRequest{Limit: 2, paged: true}).(Page)
+	assert.Equal(t, contracts[:2], page.Items)
+	assert.Equal(t, 5, page.TotalCount)
+	page = Paginate(contracts[:5], PageRequest{Offset: 50, Limit: 2, paged: true}).(Page)
+	assert.Empty(t, page.Items)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestInputSchemaPaged(t *testing.T) {
+	h := NewHandlers(&MockTradovateClient{})
+	for _, name := range []string{"getContracts", "getFills", "listOrders", "getHistoricalData"} {
+		schema := h[name].InputSchema()
+		require.Contains(t, schema.Properties, "limit", name)
+		assert.Equal(t, "integer", schema.Properties["limit"].Type)
+		assert.Equal(t, float64(MaxPageSize), *schema.Properties["limit"].Maximum)
+		assert.Equal(t, "string", schema.Properties["cursor"].Type)
+		assert.NotContains(t, schema.Required, "limit", name)
+	}
+
+	// The schema checked by the handler is left without them.
+	assert.NotContains(t, schemaOf(getHistoricalDataRequest{}).Properties, "limit")
+	assert.NotContains(t, h["getOrder"].InputSchema().Properties, "limit")
+}
@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Schema describes a tool's parameters as a JSON Schema object. It is built
+// from the tool's request struct by schemaOf, so the parameters tools/list
+// publishes are exactly the ones decodeParams checks.
+type Schema struct {
+	Type       string               `json:"type"`
+	Properties map[string]*Property `json:"properties"`
+	Required   []string             `json:"required,omitempty"`
+	AllOf      []Conditional        `json:"allOf,omitempty"`
+
+	params []string    // Property names in declaration order
+	rules  []crossRule // Rules between parameters, also published in AllOf
+}
+
+// Property describes a single parameter.
+type Property struct {
+	Type             string   `json:"type,omitempty"` // Unset if any JSON value is accepted
+	Description      string   `json:"description,omitempty"`
+	Enum             []string `json:"enum,omitempty"`
+	Minimum          *float64 `json:"minimum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+}
+
+// Conditional is a JSON Schema if/then clause: when the parameters match
+// If, they must also match Then.
+type Conditional struct {
+	If   map[string]interface{} `json:"if"`
+	Then map[string]interface{} `json:"then"`
+}
+
+// crossRule makes param required, or rules it out, depending on the value
+// of another parameter. Rules only apply when that parameter is given.
+type crossRule struct {
+	param  string
+	on     string   // Parameter whose value decides
+	values []string // Values of on the rule applies to
+	// required means param is required when on is one of values;
+	// otherwise param may only be given when on is one of values.
+	required bool
+}
+
+// schemas caches the schema of each request struct type.
+var schemas sync.Map
+
+// schemaOf returns the schema of the request struct req, or of the struct
+// req points to. Parameters are named by the fields' json tags and
+// described by their desc tags. A validate tag is a comma-separated list of:
+// - required: the parameter must be given
+// - gt=N: a number must be greater than N
+// - gte=N: a number must be at least N
+// - oneof=A B: a string must be one of the space-separated values
+// - required_if=P A B: the parameter must be given when parameter P is A or B
+// - excluded_unless=P A B: the parameter may only be given when parameter P is A or B
+// A malformed tag is a programming error and panics.
+func schemaOf(req interface{}) *Schema {
+	t := reflect.TypeOf(req)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if cached, ok := schemas.Load(t); ok {
+		return cached.(*Schema)
+	}
+
+	schema := &Schema{Type: "object", Properties: make(map[string]*Property)}
+	for _, field := range paramFields(t) {
+		name := paramName(field)
+		if name == "" {
+			continue
+		}
+		prop := &Property{Type: jsonType(field.Type), Description: field.Tag.Get("desc")}
+		for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+			key, arg, _ := strings.Cut(rule, "=")
+			switch key {
+			case "":
+			case "required":
+				schema.Required = append(schema.Required, name)
+			case "gt", "gte":
+				bound, err := strconv.ParseFloat(arg, 64)
+				if err != nil {
+					panic(fmt.Sprintf("handlers: bad %s bound %q for %s", key, arg, name))
+				}
+				if key == "gt" {
+					prop.ExclusiveMinimum = &bound
+				} else {
+					prop.Minimum = &bound
+				}
+			case "oneof":
+				prop.Enum = strings.Fields(arg)
+			case "required_if", "excluded_unless":
+				args := strings.Fields(arg)
+				if len(args) < 2 {
+					panic(fmt.Sprintf("handlers: %s for %s needs a parameter and values", key, name))
+				}
+				schema.rules = append(schema.rules, crossRule{param: name, on: args[0], values: args[1:], required: key == "required_if"})
+			default:
+				panic(fmt.Sprintf("handlers: unknown validate rule %q for %s", rule, name))
+			}
+		}
+		schema.Properties[name] = prop
+		schema.params = append(schema.params, name)
+	}
+	for _, rule := range schema.rules {
+		if _, ok := schema.Properties[rule.on]; !ok {
+			panic(fmt.Sprintf("handlers: rule for %s refers to unknown parameter %s", rule.param, rule.on))
+		}
+		schema.AllOf = append(schema.AllOf, rule.conditional())
+	}
+
+	cached, _ := schemas.LoadOrStore(t, schema)
+	return cached.(*Schema)
+}
+
+// paramFields returns the fields of the struct type t, with those of
+// embedded structs in their place, as encoding/json sees them.
+func paramFields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			fields = append(fields, paramFields(field.Type)...)
+			continue
+		}
+		if field.IsExported() {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// paramName returns the parameter a struct field is decoded from, or "" if
+// it has none.
+func paramName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// jsonType returns the JSON Schema type of parameters decoded into t, or ""
+// for an interface, which takes any value.
+func jsonType(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Interface:
+		return ""
+	}
+	panic(fmt.Sprintf("handlers: no parameter type for %s", t))
+}
+
+// conditional returns the rule as a JSON Schema if/then clause.
+func (r crossRule) conditional() Conditional {
+	var match interface{} = map[string]interface{}{"enum": r.values}
+	then := map[string]interface{}{"required": []string{r.param}}
+	if !r.required {
+		match = map[string]interface{}{"not": match}
+		then = map[string]interface{}{"not": then}
+	}
+	return Conditional{
+		If:   map[string]interface{}{"properties": map[string]interface{}{r.on: match}, "required": []string{r.on}},
+		Then: then,
+	}
+}
+
+// validate checks params against the schema and returns them with numbers
+// given in other forms, such as "67890", as float64s. Errors name the
+// parameter the way the rest of the handlers do, such as "missing required
+// field: orderId" or "invalid type assertion for orderId". A null parameter
+// counts as not given.
+func (s *Schema) validate(params map[string]interface{}) (map[string]interface{}, error) {
+	given := func(name string) bool { return params[name] != nil }
+	for _, name := range s.Required {
+		if !given(name) {
+			return nil, fmt.Errorf("missing required field: %s", name)
+		}
+	}
+	checked := make(map[string]interface{}, len(params))
+	for name, value := range params {
+		checked[name] = value
+	}
+	for _, name := range s.params {
+		if !given(name) {
+			continue
+		}
+		value, err := s.Properties[name].check(name, params[name])
+		if err != nil {
+			return nil, err
+		}
+		checked[name] = value
+	}
+	// Missing parameters are reported before unused ones.
+	for _, required := range []bool{true, false} {
+		for _, rule := range s.rules {
+			if rule.required != required || !given(rule.on) {
+				continue
+			}
+			on, _ := params[rule.on].(string)
+			applies := false
+			for _, value := range rule.values {
+				applies = applies || on == value
+			}
+			switch {
+			case required && applies && !given(rule.param):
+				return nil, fmt.Errorf("%s is required when %s is %s", rule.param, rule.on, on)
+			case !required && !applies && given(rule.param):
+				return nil, fmt.Errorf("%s is not used when %s is %s", rule.param, rule.on, on)
+			}
+		}
+	}
+	return checked, nil
+}
+
+// check checks the value given for the parameter name, returning it as a
+// float64 if it is a number.
+func (p *Property) check(name string, value interface{}) (interface{}, error) {
+	invalidType := fmt.Errorf("invalid type assertion for %s", name)
+	switch p.Type {
+	case "integer", "number":
+		n, ok := toNumber(value)
+		if !ok || (p.Type == "integer" && n != math.Trunc(n)) {
+			return nil, invalidType
+		}
+		if (p.Type == "integer" && math.Abs(n) > maxSafeInteger) ||
+			(p.Minimum != nil && n < *p.Minimum) || (p.ExclusiveMinimum != nil && n <= *p.ExclusiveMinimum) ||
+			(p.Maximum != nil && n > *p.Maximum) {
+			return nil, fmt.Errorf("invalid %s", name)
+		}
+		return n, nil
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, invalidType
+		}
+		if len(p.Enum) > 0 {
+			for _, allowed := range p.Enum {
+				if s == allowed {
+					return value, nil
+				}
+			}
+			return nil, fmt.Errorf("invalid %s: must be one of %s", name, strings.Join(p.Enum, ", "))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return nil, invalidType
+		}
+	case "array":
+		if _, ok := value.([]interface{}); !ok {
+			return nil, invalidType
+		}
+	}
+	return value, nil
+}
+
+// maxSafeInteger is the largest whole number a float64 holds exactly, and so
+// the largest accepted for an integer parameter.
+const maxSafeInteger = 1<<53 - 1
+
+// toNumber returns value as a float64 if it is a finite number, whether
+// JSON decoding made it a float64, a Go caller an int, or a client sent it
+// as a string such as "67890", as some serialize IDs.
+func toNumber(value interface{}) (float64, bool) {
+	var n float64
+	switch v := value.(type) {
+	case float64:
+		n = v
+	case float32:
+		n = float64(v)
+	case int:
+		n = float64(v)
+	case int32:
+		n = float64(v)
+	case int64:
+		n = float64(v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		n = f
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, false
+		}
+		n = f
+	default:
+		return 0, false
+	}
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, false
+	}
+	return n, true
+}
+
+// ParamError is returned by a tool whose parameters are missing or invalid,
+// as opposed to one that failed to carry out a valid call.
+type ParamError struct {
+	err error
+}
+
+func (e *ParamError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.err
+}
+
+// paramErrorf returns a ParamError formatted as fmt.Errorf would.
+func paramErrorf(format string, args ...interface{}) error {
+	return &ParamError{err: fmt.Errorf(format, args...)}
+}
+
+// decodeParams checks a tool's parameters against the schema of the request
+// struct req points to, then decodes them into it. Fields whose parameter is
+// not given keep their value, so defaults can be set on req beforehand;
+// pointer fields are left nil. Parameters that fail the checks are reported
+// as a ParamError.
+func decodeParams(params map[string]interface{}, req interface{}) error {
+	params, err := schemaOf(req).validate(params)
+	if err != nil {
+		return &ParamError{err: err}
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return paramErrorf("invalid parameters: %w", err)
+	}
+	if err := json.Unmarshal(data, req); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return paramErrorf("invalid type assertion for %s", typeErr.Field)
+		}
+		return paramErrorf("invalid parameters: %w", err)
+	}
+	return nil
+}
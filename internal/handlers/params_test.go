@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRequest struct {
+	OrderID  int      `json:"orderId" validate:"required,gt=0"`
+	Side     string   `json:"side" validate:"required,oneof=Buy Sell"`
+	Price    *float64 `json:"price" validate:"gte=0"`
+	Note     string   `json:"note"`
+	Internal string   `json:"-"`
+}
+
+func TestDecodeParams(t *testing.T) {
+	req := testRequest{Note: "default"}
+	err := decodeParams(map[string]interface{}{"orderId": float64(42), "side": "Sell", "price": 5100.25, "extra": true}, &req)
+	require.NoError(t, err)
+	require.NotNil(t, req.Price)
+	assert.Equal(t, 42, req.OrderID)
+	assert.Equal(t, "Sell", req.Side)
+	assert.Equal(t, 5100.25, *req.Price)
+	assert.Equal(t, "default", req.Note, "parameters not given keep their value")
+
+	req = testRequest{}
+	require.NoError(t, decodeParams(map[string]interface{}{"orderId": float64(42), "side": "Buy"}, &req))
+	assert.Nil(t, req.Price)
+}
+
+func TestDecodeParamsCoercesNumbers(t *testing.T) {
+	params := map[string]interface{}{"orderId": "67890", "side": "Buy", "price": " 5100.25 "}
+	var req testRequest
+	require.NoError(t, decodeParams(params, &req))
+	assert.Equal(t, 67890, req.OrderID)
+	assert.Equal(t, 5100.25, *req.Price)
+	assert.Equal(t, "67890", params["orderId"], "the caller's parameters are left as given")
+
+	require.NoError(t, decodeParams(map[string]interface{}{"orderId": 67890, "side": "Buy"}, &req))
+	assert.Equal(t, 67890, req.OrderID)
+
+	tests := []struct {
+		name   string
+		value  interface{}
+		errMsg string
+	}{
+		{"Fractional string", "4.5", "invalid type assertion for orderId"},
+		{"Not finite", "Inf", "invalid type assertion for orderId"},
+		{"Beyond exact integers", float64(1 << 60), "invalid orderId"},
+		{"Below range", "-3", "invalid orderId"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := decodeParams(map[string]interface{}{"orderId": tt.value, "side": "Buy"}, &req)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestDecodeParamsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Nil params", nil, "missing required field: orderId"},
+		{"Missing side", map[string]interface{}{"orderId": float64(42)}, "missing required field: side"},
+		{"Wrong type", map[string]interface{}{"orderId": "forty-two", "side": "Buy"}, "invalid type assertion for orderId"},
+		{"Fractional integer", map[string]interface{}{"orderId": 4.5, "side": "Buy"}, "invalid type assertion for orderId"},
+		{"Not greater than", map[string]interface{}{"orderId": float64(0), "side": "Buy"}, "invalid orderId"},
+		{"Not one of", map[string]interface{}{"orderId": float64(42), "side": "Short"}, "invalid side: must be one of Buy, Sell"},
+		{"Negative pointer", map[string]interface{}{"orderId": float64(42), "side": "Buy", "price": -1.0}, "invalid price"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req testRequest
+			err := decodeParams(tt.params, &req)
+			assert.EqualError(t, err, tt.errMsg)
+			var paramErr *ParamError
+			assert.ErrorAs(t, err, &paramErr)
+		})
+	}
+}
+
+type orderTypeRequest struct {
+	OrderType string `json:"orderType" validate:"oneof=Market Limit"`
+	orderPrices
+}
+
+func TestDecodeParamsCrossField(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Required", map[string]interface{}{"orderType": "Limit"}, "price is required when orderType is Limit"},
+		{"Missing before unused", map[string]interface{}{"orderType": "Limit", "stopPrice": 5080.0}, "price is required when orderType is Limit"},
+		{"Unused", map[string]interface{}{"orderType": "Market", "price": 5080.0}, "price is not used when orderType is Market"},
+		{"Deciding parameter not given", map[string]interface{}{"price": 5080.0}, ""},
+		{"Taken", map[string]interface{}{"orderType": "Limit", "price": 5080.0}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req orderTypeRequest
+			err := decodeParams(tt.params, &req)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestSchemaOf(t *testing.T) {
+	schema := schemaOf(&orderTypeRequest{})
+	assert.Same(t, schema, schemaOf(orderTypeRequest{}), "schemas are built once per type")
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"orderType": {"type": "string", "enum": ["Market", "Limit"]},
+			"price": {"type": "number", "description": "Limit price", "exclusiveMinimum": 0},
+			"stopPrice": {"type": "number", "description": "Trigger price", "exclusiveMinimum": 0}
+		},
+		"allOf": [
+			{"if": {"properties": {"orderType": {"enum": ["Limit", "StopLimit"]}}, "required": ["orderType"]}, "then": {"required": ["price"]}},
+			{"if": {"properties": {"orderType": {"not": {"enum": ["Limit", "StopLimit"]}}}, "required": ["orderType"]}, "then": {"not": {"required": ["price"]}}},
+			{"if": {"properties": {"orderType": {"enum": ["Stop", "StopLimit", "MIT"]}}, "required": ["orderType"]}, "then": {"required": ["stopPrice"]}},
+			{"if": {"properties": {"orderType": {"not": {"enum": ["Stop", "StopLimit", "MIT"]}}}, "required": ["orderType"]}, "then": {"not": {"required": ["stopPrice"]}}}
+		]
+	}`, string(data))
+}
+
+func TestNewHandlersSchemas(t *testing.T) {
+	// Building every declared schema catches malformed tags.
+	for name, handler := range NewHandlers(&MockTradovateClient{}) {
+		require.NotNil(t, handler.Params, "%s declares no parameters", name)
+		schema := handler.InputSchema()
+		assert.Equal(t, "object", schema.Type, name)
+		for _, required := range schema.Required {
+			assert.Contains(t, schema.Properties, required, name)
+		}
+	}
+}
+
+func TestSchemaOfAnyValue(t *testing.T) {
+	schema := schemaOf(selectAccountRequest{})
+	data, err := json.Marshal(schema.Properties["account"])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"description": "Account ID (number) or name (string) to select"}`, string(data))
+
+	for _, account := range []interface{}{"DEMO202", float64(202)} {
+		var req selectAccountRequest
+		require.NoError(t, decodeParams(map[string]interface{}{"account": account}, &req))
+		assert.Equal(t, account, req.Account)
+	}
+}
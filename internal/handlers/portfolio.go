@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// PortfolioSummary rolls up every account of the user into one overview.
+type PortfolioSummary struct {
+	PortfolioRisk
+	Concentration []ContractExposure `json:"concentration"` // Exposure by contract across accounts, largest first
+	Accounts      []AccountRisk      `json:"accounts"`      // Each account's figures
+}
+
+// PortfolioRisk is the equity, P&L, risk and margin of one account or of
+// several together.
+type PortfolioRisk struct {
+	Equity               float64 `json:"equity"`               // Net liquidation value
+	DayPnL               float64 `json:"dayPnL"`               // Realized today plus open P&L
+	OpenRisk             float64 `json:"openRisk"`             // Loss from the last price if every protective stop filled
+	UnprotectedPositions int     `json:"unprotectedPositions"` // Open positions not fully covered by stops, or whose point value is unknown; their risk is not in OpenRisk
+	MarginUsed           float64 `json:"marginUsed"`           // Margin held by positions and working orders
+	MarginUtilization    float64 `json:"marginUtilization"`    // MarginUsed as a percentage of Equity
+	OpenPositions        int     `json:"openPositions"`        // Number of open positions
+}
+
+// AccountRisk is one account's part of a PortfolioSummary.
+type AccountRisk struct {
+	AccountID   int    `json:"accountId"`   // Account the figures are for
+	AccountName string `json:"accountName"` // Name of the account
+	PortfolioRisk
+}
+
+// ContractExposure is the size of the positions held in one contract across
+// accounts.
+type ContractExposure struct {
+	ContractID int     `json:"contractId"`       // Contract held
+	Symbol     string  `json:"symbol,omitempty"` // Name of the contract, e.g. "MNQZ4", when resolved
+	GrossPos   int     `json:"grossPos"`         // Contracts held, long and short together
+	Notional   float64 `json:"notional"`         // Value of GrossPos at the last price; 0 if the point value is unknown
+	Share      float64 `json:"share"`            // Notional as a percentage of the portfolio's
+}
+
+// handlePortfolioSummary processes requests for an overview of all
+// accounts: equity, the day's P&L, open risk, margin utilization and
+// position concentration, in total and by account.
+func handlePortfolioSummary(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		accounts, err := client.GetAccounts(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		contracts := make(map[int]*models.Contract)
+		contract := func(contractID int) *models.Contract {
+			if _, ok := contracts[contractID]; !ok {
+				found, err := client.GetContract(ctx, contractID)
+				if err != nil {
+					found = nil
+				}
+				contracts[contractID] = found
+			}
+			return contracts[contractID]
+		}
+
+		summary := PortfolioSummary{Accounts: make([]AccountRisk, 0, len(accounts))}
+		exposures := make(map[int]*ContractExposure)
+		for _, account := range accounts {
+			accountSummary, err := client.GetAccountSummary(ctx, account.ID)
+			if err != nil {
+				return nil, fmt.Errorf("error summarizing account %d: %w", account.ID, err)
+			}
+			risk := AccountRisk{AccountID: account.ID, AccountName: account.Name}
+			risk.Equity = accountSummary.CashBalance.NetLiq
+			risk.DayPnL = accountSummary.CashBalance.RealizedPnL + accountSummary.CashBalance.OpenPnL
+			risk.MarginUsed = accountSummary.Margin.TotalUsedMargin
+
+			for _, position := range accountSummary.Positions {
+				if position.NetPos == 0 {
+					continue
+				}
+				risk.OpenPositions++
+				held := contract(position.ContractID)
+				var pointValue float64
+				if held != nil {
+					pointValue = held.ValuePerPoint
+				}
+
+				exposure := exposures[position.ContractID]
+				if exposure == nil {
+					exposure = &ContractExposure{ContractID: position.ContractID}
+					if held != nil {
+						exposure.Symbol = held.Name
+					}
+					exposures[position.ContractID] = exposure
+				}
+				size := abs(position.NetPos)
+				exposure.GrossPos += size
+				exposure.Notional += float64(size) * markOf(position) * pointValue
+
+				stopRisk, covered := stopLoss(position, accountSummary.WorkingOrders)
+				if pointValue == 0 || !covered {
+					risk.UnprotectedPositions++
+				}
+				risk.OpenRisk += stopRisk * pointValue
+			}
+			risk.MarginUtilization = utilization(risk.MarginUsed, risk.Equity)
+
+			summary.Equity += risk.Equity
+			summary.DayPnL += risk.DayPnL
+			summary.OpenRisk += risk.OpenRisk
+			summary.UnprotectedPositions += risk.UnprotectedPositions
+			summary.MarginUsed += risk.MarginUsed
+			summary.OpenPositions += risk.OpenPositions
+			summary.Accounts = append(summary.Accounts, risk)
+		}
+		summary.MarginUtilization = utilization(summary.MarginUsed, summary.Equity)
+
+		var notional float64
+		for _, exposure := range exposures {
+			notional += exposure.Notional
+		}
+		summary.Concentration = make([]ContractExposure, 0, len(exposures))
+		for _, exposure := range exposures {
+			if notional > 0 {
+				exposure.Share = exposure.Notional / notional * 100
+			}
+			summary.Concentration = append(summary.Concentration, *exposure)
+		}
+		sort.Slice(summary.Concentration, func(i, j int) bool {
+			a, b := summary.Concentration[i], summary.Concentration[j]
+			if a.Notional != b.Notional {
+				return a.Notional > b.Notional
+			}
+			return a.ContractID < b.ContractID
+		})
+		return summary, nil
+	}
+}
+
+// stopLoss returns the points lost, times contracts, if the working stop
+// orders protecting position filled, and whether they cover all of it. A
+// stop protects a position when it is in the same contract and on the
+// other side.
+func stopLoss(position models.Position, orders []models.Order) (loss float64, covered bool) {
+	exitSide, direction := "Sell", 1.0
+	if position.NetPos < 0 {
+		exitSide, direction = "Buy", -1.0
+	}
+	mark := markOf(position)
+	remaining := abs(position.NetPos)
+	for _, order := range orders {
+		if remaining == 0 {
+			break
+		}
+		if order.ContractID != position.ContractID || order.Side != exitSide ||
+			(order.OrderType != "Stop" && order.OrderType != "StopLimit") {
+			continue
+		}
+		quantity := order.Quantity - order.FilledQty
+		if quantity > remaining {
+			quantity = remaining
+		}
+		remaining -= quantity
+		loss += math.Max(0, (mark-order.StopPrice)*direction) * float64(quantity)
+	}
+	return loss, remaining == 0
+}
+
+// markOf returns the last price position was marked at, or its average
+// price if it was not marked.
+func markOf(position models.Position) float64 {
+	if position.MarkPrice != 0 {
+		return position.MarkPrice
+	}
+	return position.AvgPrice
+}
+
+// utilization returns used as a percentage of equity, or 0 without equity.
+func utilization(used, equity float64) float64 {
+	if equity <= 0 {
+		return 0
+	}
+	return used / equity * 100
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortfolioSummary(t *testing.T) {
+	summaries := map[int]*models.AccountSummary{
+		1: {
+			CashBalance: models.CashBalanceSnapshot{NetLiq: 50000, RealizedPnL: 300, OpenPnL: -100},
+			Margin:      models.MarginSnapshot{TotalUsedMargin: 10000},
+			Positions: []models.Position{
+				{ContractID: 10, NetPos: 2, AvgPrice: 5100, MarkPrice: 5098},
+				{ContractID: 20, NetPos: 0},
+			},
+			WorkingOrders: []models.Order{
+				{ContractID: 10, Side: "Sell", OrderType: "Stop", Quantity: 2, StopPrice: 5090},
+				{ContractID: 10, Side: "Sell", OrderType: "Limit", Quantity: 2, Price: 5120},
+			},
+		},
+		2: {
+			CashBalance: models.CashBalanceSnapshot{NetLiq: 25000, RealizedPnL: -50},
+			Margin:      models.MarginSnapshot{TotalUsedMargin: 5000},
+			Positions: []models.Position{
+				{ContractID: 10, NetPos: -1, AvgPrice: 5095},
+				{ContractID: 30, NetPos: 3, AvgPrice: 18000},
+			},
+			WorkingOrders: []models.Order{
+				{ContractID: 10, Side: "Buy", OrderType: "StopLimit", Quantity: 2, FilledQty: 1, StopPrice: 5105, Price: 5106},
+			},
+		},
+	}
+	mockClient := &MockTradovateClient{
+		getAccountsFunc: func() ([]models.Account, error) {
+			return []models.Account{{ID: 1, Name: "Demo"}, {ID: 2, Name: "Funded"}}, nil
+		},
+		getAccountSummaryFunc: func(accountID int) (*models.AccountSummary, error) {
+			return summaries[accountID], nil
+		},
+		getContractFunc: func(contractID int) (*models.Contract, error) {
+			if contractID == 30 {
+				return nil, errors.New("not found")
+			}
+			return &models.Contract{ID: contractID, Name: "ESZ4", ValuePerPoint: 50}, nil
+		},
+	}
+
+	result, err := NewHandlers(mockClient)["portfolioSummary"].Handler(context.Background(), nil)
+	require.NoError(t, err)
+	summary := result.(PortfolioSummary)
+
+	require.Len(t, summary.Accounts, 2)
+	demo := summary.Accounts[0]
+	assert.Equal(t, "Demo", demo.AccountName)
+	assert.Equal(t, 200.0, demo.DayPnL)
+	assert.Equal(t, 800.0, demo.OpenRisk, "2 contracts from 5098 to a 5090 stop at $50 a point")
+	assert.Equal(t, 20.0, demo.MarginUtilization)
+	assert.Equal(t, 1, demo.OpenPositions)
+	assert.Zero(t, demo.UnprotectedPositions)
+
+	funded := summary.Accounts[1]
+	assert.Equal(t, 500.0, funded.OpenRisk, "short 1 from 5095 to the 5105 stop's unfilled contract")
+	assert.Equal(t, 1, funded.UnprotectedPositions, "contract 30 has no stop or point value")
+
+	assert.Equal(t, 75000.0, summary.Equity)
+	assert.Equal(t, 150.0, summary.DayPnL)
+	assert.Equal(t, 1300.0, summary.OpenRisk)
+	assert.Equal(t, 15000.0, summary.MarginUsed)
+	assert.Equal(t, 20.0, summary.MarginUtilization)
+	assert.Equal(t, 3, summary.OpenPositions)
+
+	require.Len(t, summary.Concentration, 2)
+	assert.Equal(t, ContractExposure{ContractID: 10, Symbol: "ESZ4", GrossPos: 3, Notional: 764550, Share: 100}, summary.Concentration[0])
+	assert.Equal(t, ContractExposure{ContractID: 30, GrossPos: 3}, summary.Concentration[1])
+
+	mockClient.getAccountSummaryFunc = func(accountID int) (*models.AccountSummary, error) {
+		return nil, errors.New("unavailable")
+	}
+	_, err = NewHandlers(mockClient)["portfolioSummary"].Handler(context.Background(), nil)
+	assert.EqualError(t, err, "error summarizing account 1: unavailable")
+}
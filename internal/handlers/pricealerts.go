@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+const (
+	// maxPriceAlerts caps the price alerts active at once.
+	maxPriceAlerts = 50
+	// PriceAlertNotification is the method of the notifications sent when a
+	// price alert triggers.
+	PriceAlertNotification = "notifications/priceAlert"
+)
+
+// webhookClient posts triggered alerts to their webhooks.
+var webhookClient = &http.Client{Timeout: 5 * time.Second}
+
+// PriceAlert describes a price alert and whether it has triggered.
+type PriceAlert struct {
+	AlertID       int        `json:"alertId"`                // Identifies the alert to deleteAlert
+	ContractID    int        `json:"contractId"`             // Contract watched
+	Direction     string     `json:"direction"`              // "above" to trigger at or above Price, "below" at or below it
+	Price         float64    `json:"price"`                  // Threshold the last trade price is compared with
+	Note          string     `json:"note,omitempty"`         // Caller's reminder of what the alert is for
+	Webhook       string     `json:"webhook,omitempty"`      // URL the triggered alert is also posted to
+	Notifications bool       `json:"notifications"`          // Whether the triggered alert is pushed as a notification
+	CreatedAt     time.Time  `json:"createdAt"`              // When the alert was created
+	Status        string     `json:"status"`                 // "active" until the threshold is reached, then "triggered"
+	TriggeredAt   *time.Time `json:"triggeredAt,omitempty"`  // When the threshold was reached
+	TriggerPrice  float64    `json:"triggerPrice,omitempty"` // Last trade price that reached it
+}
+
+// priceAlerts tracks the alerts created through createPriceAlert.
+type priceAlerts struct {
+	mu     sync.Mutex
+	nextID int
+	alerts map[int]*priceAlert
+}
+
+// priceAlert watches one contract's quotes for a threshold.
+type priceAlert struct {
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	alert PriceAlert
+}
+
+// create starts watching alert.ContractID. Like a market data subscription,
+// an alert outlives the request creating it: it lasts until deleted or, for
+// a session that can be notified, until the session ends. Triggered alerts
+// stop watching and stay listed until deleted.
+func (a *priceAlerts) create(ctx context.Context, client client.TradovateClientInterface, alert PriceAlert) (*PriceAlert, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	active := 0
+	for _, existing := range a.alerts {
+		if existing.snapshot().Status == "active" {
+			active++
+		}
+	}
+	if active >= maxPriceAlerts {
+		return nil, fmt.Errorf("too many price alerts: at most %d may be active", maxPriceAlerts)
+	}
+
+	alertCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := func() bool { return false }
+	var notify Notifier
+	if sess := sessionOf(ctx); sess != nil {
+		notify = sess.notify
+		stop = context.AfterFunc(sess.ctx, cancel)
+	}
+	quotes, err := client.SubscribeQuotes(alertCtx, alert.ContractID)
+	if err != nil {
+		stop()
+		cancel()
+		return nil, err
+	}
+
+	a.nextID++
+	alert.AlertID = a.nextID
+	alert.Notifications = notify != nil
+	alert.CreatedAt = time.Now()
+	alert.Status = "active"
+	pa := &priceAlert{cancel: cancel, alert: alert}
+	if a.alerts == nil {
+		a.alerts = make(map[int]*priceAlert)
+	}
+	a.alerts[alert.AlertID] = pa
+
+	go func() {
+		triggered := pa.watch(quotes, notify)
+		stop()
+		cancel()
+		if !triggered {
+			// The session ended or the alert was deleted.
+			a.mu.Lock()
+			delete(a.alerts, alert.AlertID)
+			a.mu.Unlock()
+		}
+	}()
+	return &alert, nil
+}
+
+// list returns the alerts, oldest first.
+func (a *priceAlerts) list() []PriceAlert {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	list := make([]PriceAlert, 0, len(a.alerts))
+	for _, pa := range a.alerts {
+		list = append(list, pa.snapshot())
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].AlertID < list[j].AlertID })
+	return list
+}
+
+// delete stops and forgets the alert with id.
+func (a *priceAlerts) delete(id int) error {
+	a.mu.Lock()
+	pa, ok := a.alerts[id]
+	delete(a.alerts, id)
+	a.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no price alert %d", id)
+	}
+	pa.cancel()
+	return nil
+}
+
+// snapshot returns a copy of the alert's current state.
+func (pa *priceAlert) snapshot() PriceAlert {
+	pa.mu.Lock()
+	defer pa.mu.Unlock()
+	return pa.alert
+}
+
+// watch compares each last trade price from quotes with the threshold,
+// and delivers the alert once it is reached. It reports whether the alert
+// triggered before quotes was closed.
+func (pa *priceAlert) watch(quotes <-chan models.MarketData, notify Notifier) bool {
+	for md := range quotes {
+		if md.Last == 0 || !pa.reached(md.Last) {
+			continue
+		}
+		pa.mu.Lock()
+		now := time.Now()
+		pa.alert.Status = "triggered"
+		pa.alert.TriggeredAt = &now
+		pa.alert.TriggerPrice = md.Last
+		alert := pa.alert
+		pa.mu.Unlock()
+
+		if notify != nil {
+			notify(PriceAlertNotification, alert)
+		}
+		if alert.Webhook != "" {
+			postWebhook(alert)
+		}
+		return true
+	}
+	return false
+}
+
+// reached reports whether last is at or beyond the alert's threshold.
+func (pa *priceAlert) reached(last float64) bool {
+	if pa.alert.Direction == "above" {
+		return last >= pa.alert.Price
+	}
+	return last <= pa.alert.Price
+}
+
+// postWebhook posts a triggered alert as JSON to its webhook. Failures are
+// logged; the alert is not retried.
+func postWebhook(alert PriceAlert) {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		slog.Warn("could not encode price alert", "alertId", alert.AlertID, "error", err)
+		return
+	}
+	resp, err := webhookClient.Post(alert.Webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		slog.Warn("price alert webhook failed", "alertId", alert.AlertID, "error", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		slog.Warn("price alert webhook failed", "alertId", alert.AlertID, "status", resp.StatusCode)
+	}
+}
+
+// createPriceAlertRequest holds the parameters of createPriceAlert.
+type createPriceAlertRequest struct {
+	contractRef
+	Direction string  `json:"direction" validate:"required,oneof=above below" desc:"Trigger when the last price reaches price from below (above) or from above (below)"`
+	Price     float64 `json:"price" validate:"required,gt=0" desc:"Price threshold"`
+	Webhook   string  `json:"webhook" desc:"http or https URL to also post the triggered alert to"`
+	Note      string  `json:"note" desc:"Reminder of what the alert is for, returned with it"`
+}
+
+// handleCreatePriceAlert processes requests to be alerted when a contract's
+// last trade price reaches a threshold. The server watches the contract's
+// quotes and, once, pushes the triggered alert as a notification on
+// sessions that support it and posts it to the webhook, if any.
+// Required parameters:
+// - contractId: (float64) The contract to watch, or
+// - symbol: (string) The contract to watch, e.g. "MESZ4", "ES" or "ES front month"
+// - direction: (string) "above" or "below"
+// - price: (float64) The threshold
+// Optional parameters:
+// - webhook: (string) An http or https URL to post the triggered alert to
+// - note: (string) A reminder returned with the alert
+func handleCreatePriceAlert(client client.TradovateClientInterface, alerts *priceAlerts) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req createPriceAlertRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		if req.Webhook != "" {
+			u, err := url.Parse(req.Webhook)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("invalid webhook: must be an http or https URL")
+			}
+		}
+		contractID, err := req.resolve(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		return alerts.create(ctx, client, PriceAlert{
+			ContractID: contractID,
+			Direction:  req.Direction,
+			Price:      req.Price,
+			Note:       req.Note,
+			Webhook:    req.Webhook,
+		})
+	}
+}
+
+// deleteAlertRequest holds the parameters of deleteAlert.
+type deleteAlertRequest struct {
+	AlertID int `json:"alertId" validate:"required,gt=0" desc:"Price alert to delete"`
+}
+
+// handleDeleteAlert processes requests to stop and forget a price alert.
+// Required parameters:
+// - alertId: (float64) The alert to delete
+func handleDeleteAlert(alerts *priceAlerts) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req deleteAlertRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		if err := alerts.delete(req.AlertID); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"alertId": req.AlertID, "status": "deleted"}, nil
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceAlert(t *testing.T) {
+	posted := make(chan PriceAlert, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert PriceAlert
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&alert))
+		posted <- alert
+	}))
+	defer webhook.Close()
+
+	quotes := make(chan models.MarketData)
+	h := NewHandlers(newQuoteStreamMock(quotes))
+
+	var mu sync.Mutex
+	var notified []PriceAlert
+	sessionCtx, endSession := context.WithCancel(context.Background())
+	defer endSession()
+	ctx := WithNotifier(sessionCtx, func(method string, params interface{}) {
+		assert.Equal(t, PriceAlertNotification, method)
+		mu.Lock()
+		notified = append(notified, params.(PriceAlert))
+		mu.Unlock()
+	})
+
+	result, err := h["createPriceAlert"].Handler(ctx, map[string]interface{}{
+		"contractId": float64(1234),
+		"direction":  "above",
+		"price":      5110.0,
+		"webhook":    webhook.URL,
+		"note":       "breakout",
+	})
+	require.NoError(t, err)
+	alert := result.(*PriceAlert)
+	assert.Equal(t, 1, alert.AlertID)
+	assert.Equal(t, "active", alert.Status)
+	assert.True(t, alert.Notifications)
+
+	quotes <- models.MarketData{ContractID: 1234, Last: 5105}
+	quotes <- models.MarketData{ContractID: 1234}
+	quotes <- models.MarketData{ContractID: 1234, Last: 5110.25}
+
+	select {
+	case got := <-posted:
+		assert.Equal(t, "triggered", got.Status)
+		assert.Equal(t, 5110.25, got.TriggerPrice)
+		assert.Equal(t, "breakout", got.Note)
+	case <-time.After(time.Second):
+		t.Fatal("webhook not called")
+	}
+	mu.Lock()
+	require.Len(t, notified, 1)
+	assert.Equal(t, 5110.25, notified[0].TriggerPrice)
+	mu.Unlock()
+
+	// Triggered alerts stay listed until deleted.
+	list := func() []PriceAlert {
+		result, err := h["listAlerts"].Handler(context.Background(), nil)
+		require.NoError(t, err)
+		return result.([]PriceAlert)
+	}
+	listed := list()
+	require.Len(t, listed, 1)
+	assert.Equal(t, "triggered", listed[0].Status)
+	require.NotNil(t, listed[0].TriggeredAt)
+
+	result, err = h["deleteAlert"].Handler(context.Background(), map[string]interface{}{"alertId": float64(1)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"alertId": 1, "status": "deleted"}, result)
+	assert.Empty(t, list())
+	_, err = h["deleteAlert"].Handler(context.Background(), map[string]interface{}{"alertId": float64(1)})
+	assert.EqualError(t, err, "no price alert 1")
+
+	// An alert that has not triggered is dropped when its session ends.
+	_, err = h["createPriceAlert"].Handler(ctx, map[string]interface{}{"contractId": float64(1234), "direction": "below", "price": 5000.0})
+	require.NoError(t, err)
+	quotes <- models.MarketData{ContractID: 1234, Last: 5001}
+	assert.Equal(t, "active", list()[0].Status)
+	endSession()
+	require.Eventually(t, func() bool { return len(list()) == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestPriceAlertReached(t *testing.T) {
+	above := &priceAlert{alert: PriceAlert{Direction: "above", Price: 100}}
+	below := &priceAlert{alert: PriceAlert{Direction: "below", Price: 100}}
+	assert.True(t, above.reached(100))
+	assert.False(t, above.reached(99.75))
+	assert.True(t, below.reached(100))
+	assert.False(t, below.reached(100.25))
+}
+
+func TestCreatePriceAlertInvalidParams(t *testing.T) {
+	handler := NewHandlers(newQuoteStreamMock(make(chan models.MarketData)))["createPriceAlert"].Handler
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Missing direction", map[string]interface{}{"contractId": float64(1234), "price": 5100.0}, "missing required field: direction"},
+		{"Invalid direction", map[string]interface{}{"contractId": float64(1234), "direction": "sideways", "price": 5100.0}, "invalid direction: must be one of above, below"},
+		{"Invalid price", map[string]interface{}{"contractId": float64(1234), "direction": "above", "price": -1.0}, "invalid price"},
+		{"Invalid webhook", map[string]interface{}{"contractId": float64(1234), "direction": "above", "price": 5100.0, "webhook": "ftp://example.com"}, "invalid webhook: must be an http or https URL"},
+		{"Missing contract", map[string]interface{}{"direction": "above", "price": 5100.0}, "missing required field: contractId or symbol"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
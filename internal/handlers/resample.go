@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// defaultSessionStart is when CME Globex sessions open, in exchange time,
+// for products whose trading hours cannot be looked up.
+const defaultSessionStart = 17 * time.Hour
+
+// ResampledBar is a bar built from several finer ones.
+type ResampledBar struct {
+	models.HistoricalData
+	Time string `json:"time"` // Start of the bar in the requested time zone, in RFC 3339 format
+}
+
+// resampleHistoricalDataRequest holds the parameters of resampleHistoricalData.
+type resampleHistoricalDataRequest struct {
+	contractRef
+	StartTime      string `json:"startTime" validate:"required" desc:"Start time, in RFC 3339 format or relative, e.g. -24h, today or last 5 trading days"`
+	EndTime        string `json:"endTime" validate:"required" desc:"End time, in RFC 3339 format or relative, e.g. now"`
+	Interval       string `json:"interval" validate:"required" desc:"Interval to resample to, e.g. 15m, 4h or 1d"`
+	SourceInterval string `json:"sourceInterval" desc:"Interval of the bars fetched and combined, which must divide interval (default 1m)"`
+	TimeZone       string `json:"timeZone" desc:"IANA time zone bars are aligned to, e.g. America/New_York (default America/Chicago)"`
+}
+
+// handleResampleHistoricalData processes requests for historical bars at
+// an interval the API may not serve. Bars are fetched at the source
+// interval and combined. Intraday bars are aligned to midnight in the
+// requested time zone, and a bar never spans the start of a trading
+// session: the bar the session opens in is cut short there. Bars of one or
+// more days span whole trading sessions instead, and start at the open of
+// their first session.
+// Required parameters:
+// - contractId: (float64) The contract ID to get data for, or
+// - symbol: (string) The contract to get data for, e.g. "MESZ4", "ES" or "ES front month"
+// - startTime: (string) Start time in RFC3339 format, or relative as parseTime reads it
+// - endTime: (string) End time in RFC3339 format, or relative
+// - interval: (string) The interval to resample to, e.g. "15m", "4h" or "1d"
+// Optional parameters:
+// - sourceInterval: (string) The interval fetched, which must divide interval (default "1m")
+// - timeZone: (string) The IANA time zone bars are aligned and timed in (default "America/Chicago")
+func handleResampleHistoricalData(client client.TradovateClientInterface) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		req := resampleHistoricalDataRequest{SourceInterval: "1m", TimeZone: exchangeLocation.String()}
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+
+		target, ok := barDuration(req.Interval)
+		if !ok {
+			return nil, fmt.Errorf("invalid interval: must be a number and a unit of s, m, h or d, e.g. 15m")
+		}
+		source, ok := barDuration(req.SourceInterval)
+		if !ok {
+			return nil, fmt.Errorf("invalid sourceInterval: must be a number and a unit of s, m, h or d, e.g. 1m")
+		}
+		if target%source != 0 {
+			return nil, fmt.Errorf("interval %s is not a multiple of the source interval %s", req.Interval, req.SourceInterval)
+		}
+		loc, err := time.LoadLocation(req.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown time zone %q", req.TimeZone)
+		}
+
+		now := timeNow()
+		startTime, err := parseTime(req.StartTime, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start time")
+		}
+		endTime, err := parseTime(req.EndTime, now)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end time")
+		}
+		if endTime.Before(startTime) {
+			return nil, fmt.Errorf("end time must be after start time")
+		}
+
+		contractID, err := req.resolve(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		bars, err := client.GetHistoricalData(ctx, contractID, startTime, endTime, req.SourceInterval)
+		if err != nil {
+			return nil, err
+		}
+		return resample(bars, target, loc, sessionStart(ctx, client, contractID)), nil
+	}
+}
+
+// barDuration returns the duration of a bar interval such as "15s", "5m",
+// "4h" or "1d", or zero and false if it is not of that form.
+func barDuration(interval string) (time.Duration, bool) {
+	if len(interval) < 2 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || n <= 0 {
+		return 0, false
+	}
+	units := map[byte]time.Duration{'s': time.Second, 'm': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour}
+	unit, ok := units[interval[len(interval)-1]]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// sessionStart returns when the trading sessions of a contract's product
+// open, as a time of day in exchange time. It falls back to the CME Globex
+// open when the product's trading hours cannot be looked up.
+func sessionStart(ctx context.Context, client client.TradovateClientInterface, contractID int) time.Duration {
+	contract, err := client.GetContract(ctx, contractID)
+	if err != nil || contract == nil || contract.ContractMaturityID == 0 {
+		return defaultSessionStart
+	}
+	maturity, err := client.GetContractMaturity(ctx, contract.ContractMaturityID)
+	if err != nil || maturity == nil {
+		return defaultSessionStart
+	}
+	sessions, err := client.GetProductSessions(ctx)
+	if err != nil {
+		return defaultSessionStart
+	}
+	for _, session := range sessions {
+		if session.ProductID != maturity.ProductID {
+			continue
+		}
+		start, err := time.Parse("15:04", session.StartTime)
+		if err != nil {
+			break
+		}
+		return time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute
+	}
+	return defaultSessionStart
+}
+
+// resample combines bars into bars of interval. Bars are taken to be
+// timestamped in Unix seconds at their start.
+func resample(bars []models.HistoricalData, interval time.Duration, loc *time.Location, sessionStart time.Duration) []ResampledBar {
+	bars = append([]models.HistoricalData(nil), bars...)
+	sort.SliceStable(bars, func(i, j int) bool { return bars[i].Timestamp < bars[j].Timestamp })
+
+	days := int(interval / (24 * time.Hour))
+	multiDay := interval%(24*time.Hour) == 0
+
+	result := make([]ResampledBar, 0)
+	var key, lastOpen time.Time
+	sessions := 0
+	for _, bar := range bars {
+		at := time.Unix(bar.Timestamp, 0)
+		open := sessionOpen(at, sessionStart)
+		newSession := !open.Equal(lastOpen)
+		if newSession {
+			sessions++
+			lastOpen = open
+		}
+
+		var start time.Time
+		if multiDay {
+			// Sessions are grouped days at a time, counting from the first.
+			start = key
+			if newSession && (sessions-1)%days == 0 {
+				start = open
+			}
+		} else {
+			local := at.In(loc)
+			midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+			start = midnight.Add(local.Sub(midnight) / interval * interval)
+			if open.After(start) {
+				start = open
+			}
+		}
+
+		if len(result) > 0 && start.Equal(key) {
+			last := &result[len(result)-1]
+			last.High = max(last.High, bar.High)
+			last.Low = min(last.Low, bar.Low)
+			last.Close = bar.Close
+			last.Volume += bar.Volume
+			continue
+		}
+		key = start
+		resampled := ResampledBar{HistoricalData: bar, Time: start.In(loc).Format(time.RFC3339)}
+		resampled.Timestamp = start.Unix()
+		result = append(result, resampled)
+	}
+	return result
+}
+
+// sessionOpen returns when the trading session that at falls in opened,
+// given that sessions open daily at sessionStart in exchange time.
+func sessionOpen(at time.Time, sessionStart time.Duration) time.Time {
+	local := at.In(exchangeLocation)
+	hour, minute := int(sessionStart/time.Hour), int(sessionStart%time.Hour/time.Minute)
+	open := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, exchangeLocation)
+	if open.After(local) {
+		open = open.AddDate(0, 0, -1)
+	}
+	return open
+}
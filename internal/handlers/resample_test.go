@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hourlyBars returns hourly bars at the given hours of March 19, 2024 in
+// Chicago, each a point higher than the one before.
+func hourlyBars(hours ...int) []models.HistoricalData {
+	bars := make([]models.HistoricalData, len(hours))
+	for i, hour := range hours {
+		price := 100 + float64(i)
+		bars[i] = models.HistoricalData{
+			ContractID: 1234,
+			Timestamp:  time.Date(2024, 3, 19, hour, 0, 0, 0, exchangeLocation).Unix(),
+			Open:       price,
+			High:       price + 1,
+			Low:        price - 1,
+			Close:      price + 0.5,
+			Volume:     10,
+		}
+	}
+	return bars
+}
+
+func TestResample(t *testing.T) {
+	// The session opening at 17:00 follows the one traded at 14:00 and 15:00.
+	bars := hourlyBars(14, 15, 17, 18, 19)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	times := func(result []ResampledBar) []string {
+		var times []string
+		for _, bar := range result {
+			times = append(times, bar.Time)
+		}
+		return times
+	}
+
+	result := resample(bars, 4*time.Hour, exchangeLocation, defaultSessionStart)
+	assert.Equal(t, []string{"2024-03-19T12:00:00-05:00", "2024-03-19T17:00:00-05:00"}, times(result), "the 16:00 bar is cut at the session open")
+	assert.Equal(t, ResampledBar{
+		HistoricalData: models.HistoricalData{
+			ContractID: 1234,
+			Timestamp:  time.Date(2024, 3, 19, 17, 0, 0, 0, exchangeLocation).Unix(),
+			Open:       102,
+			High:       105,
+			Low:        101,
+			Close:      104.5,
+			Volume:     30,
+		},
+		Time: "2024-03-19T17:00:00-05:00",
+	}, result[1])
+	assert.Equal(t, 100.5+1, result[0].Close)
+
+	result = resample(bars, 4*time.Hour, newYork, defaultSessionStart)
+	assert.Equal(t, []string{"2024-03-19T12:00:00-04:00", "2024-03-19T16:00:00-04:00", "2024-03-19T18:00:00-04:00", "2024-03-19T20:00:00-04:00"}, times(result))
+
+	result = resample(bars, 2*time.Hour, exchangeLocation, defaultSessionStart)
+	assert.Equal(t, []string{"2024-03-19T14:00:00-05:00", "2024-03-19T17:00:00-05:00", "2024-03-19T18:00:00-05:00"}, times(result))
+
+	result = resample(bars, 24*time.Hour, exchangeLocation, defaultSessionStart)
+	assert.Equal(t, []string{"2024-03-18T17:00:00-05:00", "2024-03-19T17:00:00-05:00"}, times(result))
+	assert.Equal(t, 20, result[0].Volume)
+
+	result = resample(bars, 48*time.Hour, exchangeLocation, defaultSessionStart)
+	assert.Equal(t, []string{"2024-03-18T17:00:00-05:00"}, times(result))
+	assert.Equal(t, 50, result[0].Volume)
+	assert.Equal(t, 100.0, result[0].Open)
+	assert.Equal(t, 104.5, result[0].Close)
+
+	// A later session start moves the cut.
+	result = resample(bars, 4*time.Hour, exchangeLocation, 18*time.Hour)
+	assert.Equal(t, []string{"2024-03-19T12:00:00-05:00", "2024-03-19T16:00:00-05:00", "2024-03-19T18:00:00-05:00"}, times(result))
+
+	assert.Empty(t, resample(nil, time.Hour, exchangeLocation, defaultSessionStart))
+}
+
+func TestHandleResampleHistoricalData(t *testing.T) {
+	var interval string
+	mockClient := &MockTradovateClient{
+		getHistoricalDataFunc: func(contractID int, startTime, endTime time.Time, i string) ([]models.HistoricalData, error) {
+			interval = i
+			return hourlyBars(15, 14, 17, 18), nil
+		},
+		getContractFunc: func(contractID int) (*models.Contract, error) {
+			return &models.Contract{ID: contractID, Name: "ESM4", ContractMaturityID: 77}, nil
+		},
+		getMaturityFunc: func(maturityID int) (*models.ContractMaturity, error) {
+			return &models.ContractMaturity{ID: maturityID, ProductID: 9}, nil
+		},
+		getProductSessionsFunc: func() ([]models.ProductSession, error) {
+			return []models.ProductSession{{ProductID: 8, StartTime: "08:30"}, {ProductID: 9, StartTime: "18:00"}}, nil
+		},
+	}
+	handler := NewHandlers(mockClient)["resampleHistoricalData"].Handler
+	params := func(extra map[string]interface{}) map[string]interface{} {
+		params := map[string]interface{}{
+			"contractId": float64(1234),
+			"startTime":  "2024-03-19T00:00:00Z",
+			"endTime":    "2024-03-20T00:00:00Z",
+			"interval":   "4h",
+		}
+		for k, v := range extra {
+			params[k] = v
+		}
+		return params
+	}
+
+	result, err := handler(context.Background(), params(map[string]interface{}{"sourceInterval": "1h", "timeZone": "UTC"}))
+	require.NoError(t, err)
+	assert.Equal(t, "1h", interval)
+	bars := result.([]ResampledBar)
+	require.Len(t, bars, 3)
+	assert.Equal(t, "2024-03-19T16:00:00Z", bars[0].Time)
+	assert.Equal(t, "2024-03-19T20:00:00Z", bars[1].Time)
+	assert.Equal(t, 20, bars[1].Volume, "the product's 18:00 session start joins the 17:00 bar to the one before")
+	assert.Equal(t, "2024-03-19T23:00:00Z", bars[2].Time)
+
+	_, err = handler(context.Background(), params(nil))
+	require.NoError(t, err)
+	assert.Equal(t, "1m", interval)
+
+	tests := []struct {
+		name   string
+		extra  map[string]interface{}
+		errMsg string
+	}{
+		{"Invalid interval", map[string]interface{}{"interval": "4x"}, "invalid interval: must be a number and a unit of s, m, h or d, e.g. 15m"},
+		{"Invalid source interval", map[string]interface{}{"sourceInterval": "tick"}, "invalid sourceInterval: must be a number and a unit of s, m, h or d, e.g. 1m"},
+		{"Not a multiple", map[string]interface{}{"interval": "90m", "sourceInterval": "1h"}, "interval 90m is not a multiple of the source interval 1h"},
+		{"Unknown time zone", map[string]interface{}{"timeZone": "Mars/Olympus"}, `unknown time zone "Mars/Olympus"`},
+		{"Invalid start time", map[string]interface{}{"startTime": "someday"}, "invalid start time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(context.Background(), params(tt.extra))
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// ReversedPosition describes a position flipped by reversePosition.
+type ReversedPosition struct {
+	AccountID      int            `json:"accountId"`                // Account holding the position
+	ContractID     int            `json:"contractId"`               // Contract of the position
+	PreviousNetPos int            `json:"previousNetPos"`           // Net position before the reversal; positive if long
+	NetPos         int            `json:"netPos"`                   // Net position once the reversal fills
+	CanceledOrders []int          `json:"canceledOrders,omitempty"` // Working orders in the contract cancelled first
+	Order          *models.Order  `json:"order"`                    // Market order reversing the position
+	StopOffset     float64        `json:"stopOffset,omitempty"`     // Distance of the previous stop from the previous entry, carried over
+	TargetOffset   float64        `json:"targetOffset,omitempty"`   // Distance of the previous target from the previous entry, carried over
+	Protection     []models.Order `json:"protection,omitempty"`     // Stop and target placed for the new position
+	Warning        string         `json:"warning,omitempty"`        // Why protection was not carried over, when it was asked for
+}
+
+// reversePositionRequest holds the parameters of reversePosition.
+type reversePositionRequest struct {
+	AccountID int `json:"accountId" validate:"required,gt=0" desc:"Account holding the position"`
+	contractRef
+	KeepProtection bool `json:"keepProtection" desc:"Place a stop and target for the new position at the same distances from its entry as the current ones"`
+}
+
+// handleReversePosition processes requests to flip a position, e.g. from
+// long 2 to short 2, with a single market order for twice the position.
+// The working orders in the contract are cancelled first, since exits of
+// the old position would add to the new one; if the reversal then cannot
+// be placed, the position is left without them, and the error lists them.
+// With keepProtection, the distances of the old stop and target from the
+// position's average entry are measured before they are cancelled, and a
+// stop and target at the same distances from the reversal's fill are
+// placed for the new position, one-cancels-other when there are both. They
+// can only be placed once the reversal has filled; if it has not filled by
+// the time it is looked up, the result carries a warning instead.
+// Required parameters:
+// - accountId: (float64) The account holding the position
+// - contractId: (float64) The contract of the position, or
+// - symbol: (string) The contract, e.g. "ESZ4" or "ES front month", of one of the account's positions
+// Optional parameters:
+// - keepProtection: (bool) Carry the stop and target offsets over to the new position
+func handleReversePosition(client client.TradovateClientInterface, o options) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		var req reversePositionRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		position, err := openPosition(ctx, client, req.AccountID, req.contractRef)
+		if err != nil {
+			return nil, err
+		}
+		contractID := position.ContractID
+		reversed := ReversedPosition{
+			AccountID:      req.AccountID,
+			ContractID:     contractID,
+			PreviousNetPos: position.NetPos,
+			NetPos:         -position.NetPos,
+		}
+		// Long positions are reversed, and later exited, by selling.
+		side, exitSide := "Sell", "Buy"
+		if position.NetPos < 0 {
+			side, exitSide = "Buy", "Sell"
+		}
+		order := models.Order{
+			AccountID:   req.AccountID,
+			ContractID:  contractID,
+			OrderType:   "Market",
+			Side:        side,
+			Quantity:    2 * abs(position.NetPos),
+			TimeInForce: "Day",
+		}
+		if err := checkOrderLimits(ctx, client, o.config.Current(), order); err != nil {
+			return nil, err
+		}
+
+		working, err := workingOrders(ctx, client, req.AccountID, contractID)
+		if err != nil {
+			return nil, err
+		}
+		if req.KeepProtection {
+			reversed.StopOffset, reversed.TargetOffset = protectionOffsets(position, side, working)
+			if reversed.StopOffset == 0 && reversed.TargetOffset == 0 {
+				return nil, fmt.Errorf("no working stop or target in contract %d to carry over", contractID)
+			}
+		}
+		if reversed.CanceledOrders, err = cancelOrders(ctx, client, working); err != nil {
+			return reversed, unprotected(reversed.CanceledOrders, err)
+		}
+
+		placed, err := client.PlaceOrder(ctx, order)
+		if err != nil {
+			return reversed, unprotected(reversed.CanceledOrders, err)
+		}
+		if err := checkRejected(ctx, client, placed); err != nil {
+			return reversed, unprotected(reversed.CanceledOrders, err)
+		}
+		reversed.Order = placed
+		if current, err := client.GetOrder(ctx, placed.ID); err == nil {
+			reversed.Order = current
+		}
+		if !req.KeepProtection {
+			return reversed, nil
+		}
+
+		if reversed.Order.FilledQty < order.Quantity || reversed.Order.AveragePrice == 0 {
+			reversed.Warning = fmt.Sprintf("order %d has not filled yet, so no stop or target was placed for the new position", placed.ID)
+			return reversed, nil
+		}
+		protection, err := placeProtection(ctx, client, reversed, exitSide)
+		if err != nil {
+			reversed.Warning = fmt.Sprintf("the position was reversed but its stop and target could not be placed: %v", err)
+			return reversed, nil
+		}
+		reversed.Protection = protection
+		return reversed, nil
+	}
+}
+
+// protectionOffsets returns how far the working stop and target exiting
+// position on side sit from its average entry price, or zero for either
+// that is missing.
+func protectionOffsets(position models.Position, side string, working []models.Order) (stopOffset, targetOffset float64) {
+	if position.AvgPrice == 0 {
+		return 0, 0
+	}
+	for _, order := range working {
+		if order.Side != side {
+			continue
+		}
+		switch order.OrderType {
+		case "Stop", "StopLimit", "TrailingStop":
+			if stopOffset == 0 && order.StopPrice != 0 {
+				stopOffset = math.Abs(position.AvgPrice - order.StopPrice)
+			}
+		case "Limit":
+			if targetOffset == 0 && order.Price != 0 {
+				targetOffset = math.Abs(order.Price - position.AvgPrice)
+			}
+		}
+	}
+	return stopOffset, targetOffset
+}
+
+// placeProtection places the stop and target of a reversed position at
+// its offsets from the reversal's fill, as a one-cancels-other pair when
+// there are both.
+func placeProtection(ctx context.Context, client client.TradovateClientInterface, reversed ReversedPosition, exitSide string) ([]models.Order, error) {
+	entry := reversed.Order.AveragePrice
+	// A new long exits by selling, with its stop below the entry.
+	direction := 1.0
+	if reversed.NetPos < 0 {
+		direction = -1
+	}
+	leg := models.Order{
+		AccountID:   reversed.AccountID,
+		ContractID:  reversed.ContractID,
+		Side:        exitSide,
+		Quantity:    abs(reversed.NetPos),
+		TimeInForce: "GTC",
+	}
+	stop, target := leg, leg
+	stop.OrderType = "Stop"
+	stop.StopPrice = entry - direction*reversed.StopOffset
+	target.OrderType = "Limit"
+	target.Price = entry + direction*reversed.TargetOffset
+
+	switch {
+	case reversed.StopOffset != 0 && reversed.TargetOffset != 0:
+		oco, err := client.PlaceOCO(ctx, models.OCOOrder{First: target, Second: stop})
+		if err != nil {
+			return nil, err
+		}
+		return []models.Order{oco.First, oco.Second}, nil
+	case reversed.StopOffset != 0:
+		placed, err := client.PlaceOrder(ctx, stop)
+		if err != nil {
+			return nil, err
+		}
+		return []models.Order{*placed}, nil
+	default:
+		placed, err := client.PlaceOrder(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		return []models.Order{*placed}, nil
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReverseMock returns a mock client holding long 2 ESM4 at 5100 with a
+// stop at 5090 and a target at 5120, whose market orders fill at fillPrice.
+func newReverseMock(fillPrice float64) (*MockTradovateClient, *[]models.Order) {
+	var placed []models.Order
+	mockClient := &MockTradovateClient{
+		getPositionsByAccountFunc: func(accountID int) ([]models.Position, error) {
+			return []models.Position{{AccountID: accountID, ContractID: 1234, NetPos: 2, AvgPrice: 5100}}, nil
+		},
+		getOrdersFunc: func(accountID int, status string) ([]models.Order, error) {
+			return []models.Order{
+				{ID: 41, ContractID: 1234, OrderType: "Stop", Side: "Sell", Quantity: 2, StopPrice: 5090},
+				{ID: 42, ContractID: 1234, OrderType: "Limit", Side: "Sell", Quantity: 2, Price: 5120},
+			}, nil
+		},
+		placeOrderFunc: func(order models.Order) (*models.Order, error) {
+			placed = append(placed, order)
+			order.ID = 90 + len(placed)
+			return &order, nil
+		},
+		getOrderFunc: func(orderID int) (*models.Order, error) {
+			order := placed[0]
+			order.ID = orderID
+			if fillPrice != 0 {
+				order.Status, order.FilledQty, order.AveragePrice = "Filled", order.Quantity, fillPrice
+			}
+			return &order, nil
+		},
+	}
+	return mockClient, &placed
+}
+
+func TestHandleReversePosition(t *testing.T) {
+	mockClient, placed := newReverseMock(5099)
+	var canceled []int
+	mockClient.cancelOrderFunc = func(orderID int) error {
+		assert.Empty(t, *placed, "exits are cancelled before reversing")
+		canceled = append(canceled, orderID)
+		return nil
+	}
+	var oco models.OCOOrder
+	mockClient.placeOCOFunc = func(o models.OCOOrder) (*models.OCOOrder, error) {
+		oco = o
+		return &o, nil
+	}
+	handler := NewHandlers(mockClient)["reversePosition"].Handler
+
+	result, err := handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234), "keepProtection": true})
+	require.NoError(t, err)
+	reversed := result.(ReversedPosition)
+	assert.Equal(t, []int{41, 42}, canceled)
+	require.Len(t, *placed, 1, "a single order reverses the position")
+	assert.Equal(t, models.Order{AccountID: 12345, ContractID: 1234, OrderType: "Market", Side: "Sell", Quantity: 4, TimeInForce: "Day"}, (*placed)[0])
+	assert.Equal(t, 2, reversed.PreviousNetPos)
+	assert.Equal(t, -2, reversed.NetPos)
+	assert.Equal(t, 10.0, reversed.StopOffset)
+	assert.Equal(t, 20.0, reversed.TargetOffset)
+
+	// Short from 5099: the stop is above the entry and the target below.
+	assert.Equal(t, models.Order{AccountID: 12345, ContractID: 1234, OrderType: "Limit", Side: "Buy", Quantity: 2, Price: 5079, TimeInForce: "GTC"}, oco.First)
+	assert.Equal(t, models.Order{AccountID: 12345, ContractID: 1234, OrderType: "Stop", Side: "Buy", Quantity: 2, StopPrice: 5109, TimeInForce: "GTC"}, oco.Second)
+	assert.Equal(t, []models.Order{oco.First, oco.Second}, reversed.Protection)
+	assert.Empty(t, reversed.Warning)
+}
+
+func TestHandleReversePositionWithoutProtection(t *testing.T) {
+	mockClient, placed := newReverseMock(5099)
+	mockClient.placeOCOFunc = func(o models.OCOOrder) (*models.OCOOrder, error) {
+		t.Fatal("no protection was asked for")
+		return nil, nil
+	}
+	result, err := NewHandlers(mockClient)["reversePosition"].Handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234)})
+	require.NoError(t, err)
+	assert.Len(t, *placed, 1)
+	assert.Equal(t, []int{41, 42}, result.(ReversedPosition).CanceledOrders)
+}
+
+func TestHandleReversePositionPendingExits(t *testing.T) {
+	mockClient, placed := newReverseMock(5099)
+	mockClient.getOrdersFunc = func(accountID int, status string) ([]models.Order, error) {
+		return []models.Order{
+			{ID: 40, ContractID: 1234, OrderType: "Limit", Side: "Sell", Quantity: 2, Price: 5110, Status: "Canceled"},
+			{ID: 41, ContractID: 1234, OrderType: "Stop", Side: "Sell", Quantity: 2, StopPrice: 5090, Status: "PendingReplace"},
+			{ID: 42, ContractID: 1234, OrderType: "Limit", Side: "Sell", Quantity: 2, Price: 5120, Status: "Suspended"},
+		}, nil
+	}
+	var canceled []int
+	mockClient.cancelOrderFunc = func(orderID int) error {
+		canceled = append(canceled, orderID)
+		return nil
+	}
+	result, err := NewHandlers(mockClient)["reversePosition"].Handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234), "keepProtection": true})
+	require.NoError(t, err)
+	reversed := result.(ReversedPosition)
+	assert.Equal(t, []int{41, 42}, canceled, "exits not yet working are cancelled, finished ones are not")
+	assert.Equal(t, []int{41, 42}, reversed.CanceledOrders)
+	assert.Equal(t, 10.0, reversed.StopOffset)
+	assert.Equal(t, 20.0, reversed.TargetOffset)
+	assert.Len(t, *placed, 1)
+}
+
+func TestHandleReversePositionUnfilled(t *testing.T) {
+	mockClient, _ := newReverseMock(0)
+	result, err := NewHandlers(mockClient)["reversePosition"].Handler(context.Background(), map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234), "keepProtection": true})
+	require.NoError(t, err)
+	reversed := result.(ReversedPosition)
+	assert.Empty(t, reversed.Protection)
+	assert.Equal(t, "order 91 has not filled yet, so no stop or target was placed for the new position", reversed.Warning)
+}
+
+func TestReversePositionErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(*MockTradovateClient)
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Missing contract", nil, map[string]interface{}{"accountId": float64(12345)}, "missing required field: contractId or symbol"},
+		{"Flat", nil, map[string]interface{}{"accountId": float64(12345), "contractId": float64(5678)}, "account 12345 has no open position in contract 5678"},
+		{"No protection", func(m *MockTradovateClient) {
+			m.getOrdersFunc = func(int, string) ([]models.Order, error) { return nil, nil }
+		}, map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234), "keepProtection": true}, "no working stop or target in contract 1234 to carry over"},
+		{"Cancel fails", func(m *MockTradovateClient) {
+			m.cancelOrderFunc = func(int) error { return errors.New("status 500") }
+		}, map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234)}, "error cancelling order 41, position left open: status 500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient, placed := newReverseMock(5099)
+			if tt.setup != nil {
+				tt.setup(mockClient)
+			}
+			_, err := NewHandlers(mockClient)["reversePosition"].Handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+			assert.Empty(t, *placed)
+		})
+	}
+}
+
+func TestReversePositionRejected(t *testing.T) {
+	mockClient, placed := newReverseMock(5099)
+	mockClient.getCommandReportsFunc = func(commandID int) ([]models.CommandReport, error) {
+		return []models.CommandReport{{CommandID: commandID, CommandStatus: "RiskRejected", RejectReason: "RiskCheck", Text: "Exceeds margin"}}, nil
+	}
+	handler := NewHandlers(mockClient)["reversePosition"].Handler
+	params := map[string]interface{}{"accountId": float64(12345), "contractId": float64(1234), "keepProtection": true}
+
+	result, err := handler(context.Background(), params)
+	assert.EqualError(t, err, "order 91 rejected: RiskCheck: Exceeds margin; working orders 41, 42 were already cancelled and have not been placed again")
+	reversed := result.(ReversedPosition)
+	assert.Equal(t, []int{41, 42}, reversed.CanceledOrders)
+	assert.Empty(t, reversed.Protection)
+	assert.Len(t, *placed, 1, "no protection is placed for a rejected reversal")
+
+	mockClient.placeOrderFunc = func(order models.Order) (*models.Order, error) {
+		return nil, errors.New("status 500")
+	}
+	result, err = handler(context.Background(), params)
+	assert.EqualError(t, err, "status 500; working orders 41, 42 were already cancelled and have not been placed again")
+	assert.Equal(t, []int{41, 42}, result.(ReversedPosition).CanceledOrders)
+}
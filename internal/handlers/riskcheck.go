@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// RiskCheck is the outcome of checking a prospective order against the
+// local and broker risk rules without placing it.
+type RiskCheck struct {
+	Allowed    bool          `json:"allowed"`            // Whether the order breaks none of the rules checked
+	Violations []string      `json:"violations"`         // Rules the order would break
+	Warnings   []string      `json:"warnings,omitempty"` // Checks that could not be made, and why
+	Exposure   TradeExposure `json:"exposure"`           // The account's position in the contract if the order filled
+}
+
+// TradeExposure is an account's position in a contract before and after a
+// prospective order fills in full.
+type TradeExposure struct {
+	ContractID      int     `json:"contractId"`               // Contract traded
+	Symbol          string  `json:"symbol,omitempty"`         // Name of the contract, e.g. "MNQZ4", when resolved
+	NetPos          int     `json:"netPos"`                   // Net position now
+	PostTradeNetPos int     `json:"postTradeNetPos"`          // Net position once the order fills
+	Price           float64 `json:"price,omitempty"`          // Price the order is expected to fill at: its limit or stop price, or else the last trade
+	Notional        float64 `json:"notional,omitempty"`       // Value of the post-trade position at Price, when the point value is known
+	DayPnL          float64 `json:"dayPnL"`                   // Realized today plus open P&L of the account
+	AvailableMargin float64 `json:"availableMargin"`          // Buying power left before the order
+	MaxPositionQty  int     `json:"maxPositionQty,omitempty"` // Broker's maximum position size, if set
+	DayMaxLoss      float64 `json:"dayMaxLoss,omitempty"`     // Broker's maximum daily loss, if set
+}
+
+// riskCheckRequest holds the parameters of riskCheck.
+type riskCheckRequest struct {
+	AccountID int `json:"accountId" validate:"required,gt=0" desc:"Account ID the order would be placed for"`
+	contractRef
+	Side      string `json:"side" validate:"required,oneof=Buy Sell" desc:"Order side"`
+	Quantity  int    `json:"quantity" validate:"required,gt=0" desc:"Number of contracts"`
+	OrderType string `json:"orderType" validate:"oneof=Market Limit Stop StopLimit MIT" desc:"Order type (default Market)"`
+	orderPrices
+}
+
+// handleRiskCheck processes requests to check an order against the risk
+// rules before placing it. Nothing is placed. An order is reported as
+// breaking a rule when:
+// - it breaks the configured limits or symbol whitelist, as placeOrder would reject it
+// - its filled position would exceed the broker's maximum position size
+// - it adds to the position while the account is at its broker day loss limit, or has no margin left
+// Checks whose data cannot be fetched are reported as warnings.
+// Required parameters:
+// - accountId: (float64) The account the order would be placed for
+// - contractId: (float64) The contract to trade, or
+// - symbol: (string) The contract to trade, e.g. "MESZ4", "ES" or "ES front month"
+// - side: (string) Buy or Sell
+// - quantity: (float64) The number of contracts
+// Optional parameters:
+// - orderType: (string) Market (default), Limit, Stop, StopLimit or MIT
+// - price: (float64) The limit price, for Limit and StopLimit orders
+// - stopPrice: (float64) The trigger price, for Stop, StopLimit and MIT orders
+func handleRiskCheck(client client.TradovateClientInterface, o options) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		params = withActiveAccount(ctx, params)
+		req := riskCheckRequest{OrderType: "Market"}
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		contractID, err := req.resolve(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		order := models.Order{
+			AccountID:  req.AccountID,
+			ContractID: contractID,
+			OrderType:  req.OrderType,
+			Side:       req.Side,
+			Quantity:   req.Quantity,
+		}
+		if req.Price != nil {
+			order.Price = *req.Price
+		}
+		if req.StopPrice != nil {
+			order.StopPrice = *req.StopPrice
+		}
+
+		check := RiskCheck{Violations: []string{}}
+		violate := func(format string, args ...interface{}) {
+			check.Violations = append(check.Violations, fmt.Sprintf(format, args...))
+		}
+		warn := func(format string, args ...interface{}) {
+			check.Warnings = append(check.Warnings, fmt.Sprintf(format, args...))
+		}
+
+		if err := checkOrderLimits(ctx, client, o.config.Current(), order); err != nil {
+			violate("%v", err)
+		}
+
+		exposure := &check.Exposure
+		exposure.ContractID = contractID
+		positions, err := client.GetPositionsByAccount(ctx, req.AccountID)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching positions of account %d: %w", req.AccountID, err)
+		}
+		for _, position := range positions {
+			if position.ContractID == contractID {
+				exposure.NetPos += position.NetPos
+			}
+		}
+		exposure.PostTradeNetPos = exposure.NetPos + req.Quantity
+		if req.Side == "Sell" {
+			exposure.PostTradeNetPos = exposure.NetPos - req.Quantity
+		}
+		adds := abs(exposure.PostTradeNetPos) > abs(exposure.NetPos)
+
+		switch {
+		case order.Price != 0:
+			exposure.Price = order.Price
+		case order.StopPrice != 0:
+			exposure.Price = order.StopPrice
+		default:
+			if md, err := client.GetMarketData(ctx, contractID); err != nil || md == nil || md.Last == 0 {
+				warn("no last trade price for contract %d, so the post-trade notional is unknown", contractID)
+			} else {
+				exposure.Price = md.Last
+			}
+		}
+		if contract, err := client.GetContract(ctx, contractID); err == nil && contract != nil {
+			exposure.Symbol = contract.Name
+			exposure.Notional = math.Abs(float64(exposure.PostTradeNetPos)) * exposure.Price * contract.ValuePerPoint
+		}
+
+		if limits, err := client.GetRiskLimits(ctx, req.AccountID); err != nil {
+			warn("broker risk limits of account %d are unavailable: %v", req.AccountID, err)
+		} else if limits != nil {
+			exposure.MaxPositionQty = limits.MaxPositionQty
+			exposure.DayMaxLoss = limits.DayMaxLoss
+		}
+		if balance, err := client.GetCashBalanceSnapshot(ctx, req.AccountID); err != nil {
+			warn("day P&L of account %d is unavailable: %v", req.AccountID, err)
+		} else if balance != nil {
+			exposure.DayPnL = balance.RealizedPnL + balance.OpenPnL
+			if adds && exposure.DayMaxLoss > 0 && exposure.DayPnL <= -exposure.DayMaxLoss {
+				violate("account %d has lost %.2f today, reaching its day loss limit of %.2f", req.AccountID, -exposure.DayPnL, exposure.DayMaxLoss)
+			}
+		}
+		if limit := exposure.MaxPositionQty; limit > 0 && adds && abs(exposure.PostTradeNetPos) > limit {
+			violate("position of %d contracts would exceed the account's maximum position size of %d", abs(exposure.PostTradeNetPos), limit)
+		}
+		if margin, err := client.GetMarginSnapshot(ctx, req.AccountID); err != nil {
+			warn("margin of account %d is unavailable: %v", req.AccountID, err)
+		} else if margin != nil {
+			exposure.AvailableMargin = margin.AvailableMargin
+			if adds && margin.AvailableMargin <= 0 {
+				violate("account %d has no margin available for a larger position", req.AccountID)
+			}
+		}
+
+		check.Allowed = len(check.Violations) == 0
+		return check, nil
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRiskCheckMock() *MockTradovateClient {
+	return &MockTradovateClient{
+		getPositionsByAccountFunc: func(accountID int) ([]models.Position, error) {
+			return []models.Position{{AccountID: accountID, ContractID: 1234, NetPos: 3}}, nil
+		},
+		getMarketDataFunc: func(contractID int) (*models.MarketData, error) {
+			return &models.MarketData{ContractID: contractID, Last: 5100}, nil
+		},
+		getContractFunc: func(contractID int) (*models.Contract, error) {
+			return &models.Contract{ID: contractID, Name: "ESZ4", ValuePerPoint: 50}, nil
+		},
+		getRiskLimitsFunc: func(accountID int) (*models.RiskLimit, error) {
+			return &models.RiskLimit{AccountID: accountID, MaxPositionQty: 4, DayMaxLoss: 1000}, nil
+		},
+		getCashBalanceSnapshotFunc: func(accountID int) (*models.CashBalanceSnapshot, error) {
+			return &models.CashBalanceSnapshot{RealizedPnL: -400, OpenPnL: -100}, nil
+		},
+		getMarginSnapshotFunc: func(accountID int) (*models.MarginSnapshot, error) {
+			return &models.MarginSnapshot{AccountID: accountID, AvailableMargin: 20000}, nil
+		},
+	}
+}
+
+func TestHandleRiskCheck(t *testing.T) {
+	var placed bool
+	mockClient := newRiskCheckMock()
+	mockClient.placeOrderFunc = func(order models.Order) (*models.Order, error) {
+		placed = true
+		return &order, nil
+	}
+	handler := NewHandlers(mockClient)["riskCheck"].Handler
+	check := func(params map[string]interface{}) RiskCheck {
+		params["accountId"] = float64(12345)
+		params["contractId"] = float64(1234)
+		result, err := handler(context.Background(), params)
+		require.NoError(t, err)
+		return result.(RiskCheck)
+	}
+
+	result := check(map[string]interface{}{"side": "Buy", "quantity": float64(1)})
+	assert.True(t, result.Allowed)
+	assert.Empty(t, result.Violations)
+	assert.Empty(t, result.Warnings)
+	assert.Equal(t, TradeExposure{
+		ContractID:      1234,
+		Symbol:          "ESZ4",
+		NetPos:          3,
+		PostTradeNetPos: 4,
+		Price:           5100,
+		Notional:        1020000,
+		DayPnL:          -500,
+		AvailableMargin: 20000,
+		MaxPositionQty:  4,
+		DayMaxLoss:      1000,
+	}, result.Exposure)
+	assert.False(t, placed, "a risk check places nothing")
+
+	result = check(map[string]interface{}{"side": "Buy", "quantity": float64(2), "orderType": "Limit", "price": 5090.0})
+	assert.False(t, result.Allowed)
+	assert.Equal(t, []string{"position of 5 contracts would exceed the account's maximum position size of 4"}, result.Violations)
+	assert.Equal(t, 5090.0, result.Exposure.Price)
+
+	// Orders that reduce the position pass limits on adding to it.
+	mockClient.getCashBalanceSnapshotFunc = func(accountID int) (*models.CashBalanceSnapshot, error) {
+		return &models.CashBalanceSnapshot{RealizedPnL: -1200}, nil
+	}
+	mockClient.getMarginSnapshotFunc = func(accountID int) (*models.MarginSnapshot, error) {
+		return &models.MarginSnapshot{AccountID: accountID, AvailableMargin: -50}, nil
+	}
+	result = check(map[string]interface{}{"side": "Sell", "quantity": float64(2)})
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Exposure.PostTradeNetPos)
+
+	result = check(map[string]interface{}{"side": "Sell", "quantity": float64(8)})
+	assert.Equal(t, []string{
+		"account 12345 has lost 1200.00 today, reaching its day loss limit of 1000.00",
+		"position of 5 contracts would exceed the account's maximum position size of 4",
+		"account 12345 has no margin available for a larger position",
+	}, result.Violations)
+	assert.Equal(t, -5, result.Exposure.PostTradeNetPos)
+
+	// Data that cannot be fetched is reported rather than failing the check.
+	mockClient.getRiskLimitsFunc = func(accountID int) (*models.RiskLimit, error) { return nil, errors.New("unavailable") }
+	mockClient.getMarketDataFunc = func(contractID int) (*models.MarketData, error) { return nil, errors.New("no subscription") }
+	mockClient.getMarginSnapshotFunc = nil
+	result = check(map[string]interface{}{"side": "Sell", "quantity": float64(2)})
+	assert.True(t, result.Allowed)
+	assert.Equal(t, []string{
+		"no last trade price for contract 1234, so the post-trade notional is unknown",
+		"broker risk limits of account 12345 are unavailable: unavailable",
+	}, result.Warnings)
+}
+
+func TestHandleRiskCheckInvalidParams(t *testing.T) {
+	handler := NewHandlers(newRiskCheckMock())["riskCheck"].Handler
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Missing side", map[string]interface{}{"accountId": float64(1), "contractId": float64(1234), "quantity": float64(1)}, "missing required field: side"},
+		{"Invalid side", map[string]interface{}{"accountId": float64(1), "contractId": float64(1234), "side": "Long", "quantity": float64(1)}, "invalid side: must be one of Buy, Sell"},
+		{"Invalid quantity", map[string]interface{}{"accountId": float64(1), "contractId": float64(1234), "side": "Buy", "quantity": float64(0)}, "invalid quantity"},
+		{"Missing contract", map[string]interface{}{"accountId": float64(1), "side": "Buy", "quantity": float64(1)}, "missing required field: contractId or symbol"},
+		{"Missing price", map[string]interface{}{"accountId": float64(1), "contractId": float64(1234), "side": "Buy", "quantity": float64(1), "orderType": "Limit"}, "price is required when orderType is Limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+// ServerStatus gives a snapshot of the server's state.
+type ServerStatus struct {
+	StartedAt       time.Time             `json:"startedAt"`                 // When the handlers were created
+	UptimeSeconds   int                   `json:"uptimeSeconds"`             // Time since StartedAt
+	Transport       string                `json:"transport,omitempty"`       // MCP transport served: stdio, http, tcp or unix
+	Environment     string                `json:"environment"`               // Tradovate environment: live, demo or replay
+	Auth            AuthStatus            `json:"auth"`                      // Whether the server holds a valid token
+	ActiveAccountID int                   `json:"activeAccountId,omitempty"` // Account this session's calls use when they omit one
+	Sockets         []models.SocketStatus `json:"sockets"`                   // Tradovate WebSocket connections
+	Subscriptions   int                   `json:"subscriptions"`             // Streaming subscriptions this session has open, see listSubscriptions
+	PriceAlerts     int                   `json:"priceAlerts"`               // Price alerts still watching, see listAlerts
+	Risk            RiskStatus            `json:"risk"`                      // Checks applied to orders before they are sent
+	DryRun          bool                  `json:"dryRun"`                    // Whether orders are simulated instead of sent; the server has no such mode yet
+	ReadOnly        bool                  `json:"readOnly"`                  // Whether tools that trade are refused; the server has no such mode yet
+}
+
+// RiskStatus describes the server-side checks orders must pass.
+type RiskStatus struct {
+	Configured       bool     `json:"configured"`                 // Whether a configuration file supplies the limits below
+	MaxOrderQuantity int      `json:"maxOrderQuantity,omitempty"` // Largest quantity an order may carry; unset if unlimited
+	AllowedSymbols   []string `json:"allowedSymbols,omitempty"`   // Contracts and products orders may trade; unset if any
+	TickPrices       string   `json:"tickPrices"`                 // Handling of off-tick prices: reject, snap or off
+}
+
+// serverStatus reports the state of the server at now. It is answered
+// locally, without contacting Tradovate.
+func serverStatus(ctx context.Context, client client.TradovateClientInterface, o options, subs *quoteSubscriptions, alerts *priceAlerts, now time.Time) ServerStatus {
+	conns := client.ConnectionStatus()
+	cfg := o.config.Current()
+	status := ServerStatus{
+		StartedAt:       o.startedAt,
+		UptimeSeconds:   int(now.Sub(o.startedAt).Seconds()),
+		Transport:       o.transport,
+		Environment:     conns.Environment,
+		Auth:            authStatus(client, now.Add(client.ClockSkew())),
+		ActiveAccountID: activeAccountID(ctx),
+		Sockets:         conns.Sockets,
+		Subscriptions:   len(subs.list(ctx, now)),
+		Risk: RiskStatus{
+			Configured:       o.config != nil,
+			MaxOrderQuantity: cfg.RiskLimits.MaxOrderQuantity,
+			AllowedSymbols:   cfg.AllowedSymbols,
+			TickPrices:       cfg.TickPrices,
+		},
+	}
+	if status.Risk.TickPrices == "" {
+		status.Risk.TickPrices = "reject"
+	}
+	for _, alert := range alerts.list() {
+		if alert.Status == "active" {
+			status.PriceAlerts++
+		}
+	}
+	return status
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/config"
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetServerStatus(t *testing.T) {
+	now := time.Date(2024, 3, 19, 15, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	expiresAt := now.Add(50 * time.Minute)
+	sockets := []models.SocketStatus{{Name: "marketData", Connected: true, Subscriptions: 1}, {Name: "userSync"}}
+	quotes := make(chan models.MarketData)
+	mockClient := newQuoteStreamMock(quotes)
+	mockClient.isAuthenticatedFunc = func() bool { return true }
+	mockClient.tokenExpiresAtFunc = func() time.Time { return expiresAt }
+	mockClient.connectionStatusFunc = func() models.ConnectionStatus {
+		return models.ConnectionStatus{Environment: "demo", Sockets: sockets}
+	}
+	h := NewHandlers(mockClient, WithTransport("http"))
+
+	ctx := sessionWithAccount(12345)
+	_, err := h["subscribeMarketData"].Handler(ctx, map[string]interface{}{"contractId": float64(1234)})
+	require.NoError(t, err)
+	_, err = h["createPriceAlert"].Handler(ctx, map[string]interface{}{"contractId": float64(1234), "direction": "above", "price": 5110.0})
+	require.NoError(t, err)
+
+	now = now.Add(90 * time.Second)
+	result, err := h["getServerStatus"].Handler(ctx, nil)
+	require.NoError(t, err)
+	expiresIn := 48*60 + 30
+	assert.Equal(t, ServerStatus{
+		StartedAt:       now.Add(-90 * time.Second),
+		UptimeSeconds:   90,
+		Transport:       "http",
+		Environment:     "demo",
+		Auth:            AuthStatus{Authenticated: true, ExpiresAt: &expiresAt, ExpiresInSeconds: &expiresIn},
+		ActiveAccountID: 12345,
+		Sockets:         sockets,
+		Subscriptions:   1,
+		PriceAlerts:     1,
+		Risk:            RiskStatus{TickPrices: "reject"},
+	}, result)
+
+	// The modes are reported even while they are off.
+	encoded, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"dryRun":false`)
+	assert.Contains(t, string(encoded), `"readOnly":false`)
+}
+
+func TestGetServerStatusRiskConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"riskLimits": {"maxOrderQuantity": 2}, "allowedSymbols": ["ES"], "tickPrices": "snap"}`), 0600))
+	store, err := config.NewStore(path)
+	require.NoError(t, err)
+
+	result, err := NewHandlers(&MockTradovateClient{}, WithConfig(store))["getServerStatus"].Handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, RiskStatus{Configured: true, MaxOrderQuantity: 2, AllowedSymbols: []string{"ES"}, TickPrices: "snap"}, result.(ServerStatus).Risk)
+}
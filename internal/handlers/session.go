@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Notifier delivers a message to the MCP client of a session without it
+// being asked for.
+type Notifier func(method string, params interface{})
+
+// session is the connection a request arrived on. Several sessions may
+// share the server, and with it the Tradovate client, so state chosen by
+// one client, such as its account, is kept here rather than on the client.
+type session struct {
+	ctx       context.Context // Done when the session ends
+	notify    Notifier
+	accountID atomic.Int64 // Account selected with selectAccount; zero if none
+}
+
+type sessionKey struct{}
+
+// WithNotifier returns a copy of ctx for requests arriving on a session that
+// can be sent notifications with notify. Subscriptions opened by those
+// requests push their updates to it and end when ctx is done, and the
+// account selected by one of them is the default for the rest.
+func WithNotifier(ctx context.Context, notify Notifier) context.Context {
+	return context.WithValue(ctx, sessionKey{}, &session{ctx: ctx, notify: notify})
+}
+
+// sessionOf returns the session a request arrived on, or nil for requests
+// that do not belong to one, such as those over HTTP.
+func sessionOf(ctx context.Context) *session {
+	sess, _ := ctx.Value(sessionKey{}).(*session)
+	return sess
+}
+
+// activeAccountID returns the account selected on the request's session, or
+// zero if none is.
+func activeAccountID(ctx context.Context) int {
+	if sess := sessionOf(ctx); sess != nil {
+		return int(sess.accountID.Load())
+	}
+	return 0
+}
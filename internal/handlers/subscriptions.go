@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+)
+
+const (
+	// defaultQuoteThrottle is how often a market data subscription delivers
+	// updates when no throttle is given.
+	defaultQuoteThrottle = time.Second
+	// maxQuoteSubscriptions caps the subscriptions open at once.
+	maxQuoteSubscriptions = 20
+	// maxBufferedQuotes is how many updates a subscription keeps for
+	// readMarketData; older ones are dropped.
+	maxBufferedQuotes = 100
+	// MarketDataNotification is the method of the notifications carrying
+	// market data updates.
+	MarketDataNotification = "notifications/marketData"
+)
+
+// QuoteUpdate is one update delivered by a market data subscription.
+type QuoteUpdate struct {
+	SubscriptionID int               `json:"subscriptionId"` // Subscription that delivered it
+	Seq            int64             `json:"seq"`            // Position in the subscription's updates, from 1
+	Data           models.MarketData `json:"data"`           // The contract's top of book
+}
+
+// QuoteUpdates is the result of reading a market data subscription.
+type QuoteUpdates struct {
+	SubscriptionID int           `json:"subscriptionId"` // Subscription read
+	ContractID     int           `json:"contractId"`     // Contract it streams
+	Updates        []QuoteUpdate `json:"updates"`        // Updates after the one asked for, oldest first
+	LastSeq        int64         `json:"lastSeq"`        // Seq of the newest update, to read from next time
+	Missed         int64         `json:"missed"`         // Updates dropped from the buffer before they were read
+}
+
+// SubscriptionStatus reports how long a subscription has been open and
+// how many updates it has delivered.
+type SubscriptionStatus struct {
+	QuoteSubscription
+	Type         string     `json:"type"`                   // What is streamed: "marketData"
+	OpenedAt     time.Time  `json:"openedAt"`               // When the subscription was opened
+	AgeSeconds   int64      `json:"ageSeconds"`             // How long it has been open
+	Updates      int64      `json:"updates"`                // Updates delivered so far
+	LastUpdateAt *time.Time `json:"lastUpdateAt,omitempty"` // When the newest update was delivered, if any
+}
+
+// QuoteSubscription describes an open market data subscription.
+type QuoteSubscription struct {
+	SubscriptionID int   `json:"subscriptionId"` // Identifies the subscription to readMarketData and unsubscribeMarketData
+	ContractID     int   `json:"contractId"`     // Contract it streams
+	ThrottleMs     int64 `json:"throttleMs"`     // Updates are delivered at most this often
+	Notifications  bool  `json:"notifications"`  // Whether updates are also pushed as notifications
+}
+
+// quoteSubscriptions tracks the market data subscriptions opened through
+// subscribeMarketData. Each belongs to the session that opened it, and only
+// that session can see, read or close it; requests without a session, such
+// as those over HTTP, share theirs.
+type quoteSubscriptions struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[int]*quoteSubscription
+}
+
+// quoteSubscription buffers the throttled updates of one subscription.
+type quoteSubscription struct {
+	QuoteSubscription
+	owner    *session // Session that opened it; nil if none
+	cancel   context.CancelFunc
+	openedAt time.Time
+
+	mu           sync.Mutex
+	updates      []QuoteUpdate
+	lastSeq      int64
+	lastUpdateAt time.Time
+}
+
+// open subscribes to contractID, delivering updates at most once per
+// throttle. The subscription outlives the request opening it: it lasts
+// until closed or, for a session that can be notified, until the session
+// ends.
+func (s *quoteSubscriptions) open(ctx context.Context, client client.TradovateClientInterface, contractID int, throttle time.Duration) (*QuoteSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.subs) >= maxQuoteSubscriptions {
+		return nil, fmt.Errorf("too many market data subscriptions: at most %d allowed", maxQuoteSubscriptions)
+	}
+
+	subCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := func() bool { return false }
+	var notify Notifier
+	sess := sessionOf(ctx)
+	if sess != nil {
+		notify = sess.notify
+		stop = context.AfterFunc(sess.ctx, cancel)
+	}
+	quotes, err := client.SubscribeQuotes(subCtx, contractID)
+	if err != nil {
+		stop()
+		cancel()
+		return nil, err
+	}
+
+	s.nextID++
+	sub := &quoteSubscription{
+		QuoteSubscription: QuoteSubscription{
+			SubscriptionID: s.nextID,
+			ContractID:     contractID,
+			ThrottleMs:     throttle.Milliseconds(),
+			Notifications:  notify != nil,
+		},
+		owner:    sess,
+		cancel:   cancel,
+		openedAt: time.Now(),
+	}
+	if s.subs == nil {
+		s.subs = make(map[int]*quoteSubscription)
+	}
+	s.subs[sub.SubscriptionID] = sub
+
+	go func() {
+		sub.run(quotes, throttle, notify)
+		stop()
+		s.mu.Lock()
+		delete(s.subs, sub.SubscriptionID)
+		s.mu.Unlock()
+	}()
+	info := sub.QuoteSubscription
+	return &info, nil
+}
+
+// get returns the open subscription with id, if the request's session
+// opened it.
+func (s *quoteSubscriptions) get(ctx context.Context, id int) (*quoteSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub, ok := s.subs[id]
+	if !ok || sub.owner != sessionOf(ctx) {
+		return nil, fmt.Errorf("no market data subscription %d", id)
+	}
+	return sub, nil
+}
+
+// list returns the status at now of the open subscriptions of the
+// request's session, oldest first.
+func (s *quoteSubscriptions) list(ctx context.Context, now time.Time) []SubscriptionStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	owner := sessionOf(ctx)
+	list := make([]SubscriptionStatus, 0, len(s.subs))
+	for _, sub := range s.subs {
+		if sub.owner == owner {
+			list = append(list, sub.status(now))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].SubscriptionID < list[j].SubscriptionID })
+	return list
+}
+
+// close cancels the subscription with id, if the request's session opened
+// it.
+func (s *quoteSubscriptions) close(ctx context.Context, id int) error {
+	s.mu.Lock()
+	sub, ok := s.subs[id]
+	if ok && sub.owner == sessionOf(ctx) {
+		delete(s.subs, id)
+	}
+	s.mu.Unlock()
+	if !ok || sub.owner != sessionOf(ctx) {
+		return fmt.Errorf("no market data subscription %d", id)
+	}
+	sub.cancel()
+	return nil
+}
+
+// run delivers the newest quote received in each throttle interval until
+// quotes is closed.
+func (sub *quoteSubscription) run(quotes <-chan models.MarketData, throttle time.Duration, notify Notifier) {
+	ticker := time.NewTicker(throttle)
+	defer ticker.Stop()
+
+	var pending *models.MarketData
+	for {
+		select {
+		case md, ok := <-quotes:
+			if !ok {
+				return
+			}
+			pending = &md
+		case <-ticker.C:
+			if pending == nil {
+				continue
+			}
+			update := sub.add(*pending)
+			pending = nil
+			if notify != nil {
+				notify(MarketDataNotification, update)
+			}
+		}
+	}
+}
+
+// status reports the subscription's age and update count at now.
+func (sub *quoteSubscription) status(now time.Time) SubscriptionStatus {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	status := SubscriptionStatus{
+		QuoteSubscription: sub.QuoteSubscription,
+		Type:              "marketData",
+		OpenedAt:          sub.openedAt,
+		AgeSeconds:        int64(now.Sub(sub.openedAt).Seconds()),
+		Updates:           sub.lastSeq,
+	}
+	if !sub.lastUpdateAt.IsZero() {
+		lastUpdateAt := sub.lastUpdateAt
+		status.LastUpdateAt = &lastUpdateAt
+	}
+	return status
+}
+
+// add buffers md as the subscription's next update.
+func (sub *quoteSubscription) add(md models.MarketData) QuoteUpdate {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	sub.lastSeq++
+	sub.lastUpdateAt = time.Now()
+	update := QuoteUpdate{SubscriptionID: sub.SubscriptionID, Seq: sub.lastSeq, Data: md}
+	sub.updates = append(sub.updates, update)
+	if len(sub.updates) > maxBufferedQuotes {
+		sub.updates = sub.updates[len(sub.updates)-maxBufferedQuotes:]
+	}
+	return update
+}
+
+// read returns the buffered updates after seq since.
+func (sub *quoteSubscription) read(since int64) QuoteUpdates {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	result := QuoteUpdates{
+		SubscriptionID: sub.SubscriptionID,
+		ContractID:     sub.ContractID,
+		Updates:        []QuoteUpdate{},
+		LastSeq:        sub.lastSeq,
+	}
+	for _, update := range sub.updates {
+		if update.Seq > since {
+			result.Updates = append(result.Updates, update)
+		}
+	}
+	if len(sub.updates) > 0 && sub.updates[0].Seq > since+1 {
+		result.Missed = sub.updates[0].Seq - since - 1
+	}
+	return result
+}
+
+// subscribeMarketDataRequest holds the parameters of subscribeMarketData.
+type subscribeMarketDataRequest struct {
+	ContractID int `json:"contractId" validate:"required,gt=0" desc:"Contract to stream"`
+	ThrottleMs int `json:"throttleMs" validate:"gte=100" desc:"Deliver updates at most this often, in milliseconds (default 1000, at least 100)"`
+}
+
+// subscriptionRequest holds the parameters of tools that act on a single
+// market data subscription.
+type subscriptionRequest struct {
+	SubscriptionID int `json:"subscriptionId" validate:"required,gt=0" desc:"Subscription ID, as returned by subscribeMarketData"`
+}
+
+// readMarketDataRequest holds the parameters of readMarketData.
+type readMarketDataRequest struct {
+	subscriptionRequest
+	Since int `json:"since" validate:"gte=0" desc:"Only return updates after this seq (default: every buffered update)"`
+}
+
+// handleSubscribeMarketData processes requests to stream a contract's
+// quotes. Updates are buffered for readMarketData and, on sessions that
+// support it, pushed as notifications.
+// Required parameters:
+// - contractId: (float64) The contract to stream
+// Optional parameters:
+// - throttleMs: (float64) Deliver updates at most this often, in milliseconds (default: 1000, minimum: 100)
+func handleSubscribeMarketData(client client.TradovateClientInterface, subs *quoteSubscriptions) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		req := subscribeMarketDataRequest{ThrottleMs: int(defaultQuoteThrottle.Milliseconds())}
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		return subs.open(ctx, client, req.ContractID, time.Duration(req.ThrottleMs)*time.Millisecond)
+	}
+}
+
+// handleReadMarketData processes requests for the updates buffered by a
+// market data subscription.
+// Required parameters:
+// - subscriptionId: (float64) The subscription to read
+// Optional parameters:
+// - since: (float64) Only return updates after this seq (default: 0, every buffered update)
+func handleReadMarketData(subs *quoteSubscriptions) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req readMarketDataRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		sub, err := subs.get(ctx, req.SubscriptionID)
+		if err != nil {
+			return nil, err
+		}
+		return sub.read(int64(req.Since)), nil
+	}
+}
+
+// handleUnsubscribeMarketData processes requests to stop streaming quotes.
+// Required parameters:
+// - subscriptionId: (float64) The subscription to cancel
+func handleUnsubscribeMarketData(subs *quoteSubscriptions) interface{} {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		var req subscriptionRequest
+		if err := decodeParams(params, &req); err != nil {
+			return nil, err
+		}
+		if err := subs.close(ctx, req.SubscriptionID); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"subscriptionId": req.SubscriptionID, "status": "unsubscribed"}, nil
+	}
+}
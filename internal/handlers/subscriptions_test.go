@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newQuoteStreamMock returns a mock client whose quote subscriptions read
+// from quotes, closing it once their context is done.
+func newQuoteStreamMock(quotes chan models.MarketData) *MockTradovateClient {
+	return &MockTradovateClient{
+		subscribeQuotesFunc: func(ctx context.Context, contractID int) (<-chan models.MarketData, error) {
+			out := make(chan models.MarketData)
+			go func() {
+				defer close(out)
+				for {
+					select {
+					case md := <-quotes:
+						out <- md
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+			return out, nil
+		},
+	}
+}
+
+func TestMarketDataSubscription(t *testing.T) {
+	quotes := make(chan models.MarketData)
+	h := NewHandlers(newQuoteStreamMock(quotes))
+
+	var mu sync.Mutex
+	var notified []QuoteUpdate
+	sessionCtx, endSession := context.WithCancel(context.Background())
+	ctx := WithNotifier(sessionCtx, func(method string, params interface{}) {
+		assert.Equal(t, MarketDataNotification, method)
+		mu.Lock()
+		notified = append(notified, params.(QuoteUpdate))
+		mu.Unlock()
+	})
+
+	result, err := h["subscribeMarketData"].Handler(ctx, map[string]interface{}{"contractId": float64(1234), "throttleMs": float64(100)})
+	require.NoError(t, err)
+	sub := result.(*QuoteSubscription)
+	assert.Equal(t, &QuoteSubscription{SubscriptionID: 1, ContractID: 1234, ThrottleMs: 100, Notifications: true}, sub)
+
+	// Quotes arriving within one interval are delivered as the newest.
+	quotes <- models.MarketData{ContractID: 1234, Last: 5100}
+	quotes <- models.MarketData{ContractID: 1234, Last: 5100.25}
+	read := func(since float64) QuoteUpdates {
+		result, err := h["readMarketData"].Handler(ctx, map[string]interface{}{"subscriptionId": float64(1), "since": since})
+		require.NoError(t, err)
+		return result.(QuoteUpdates)
+	}
+	require.Eventually(t, func() bool { return read(0).LastSeq == 1 }, time.Second, 10*time.Millisecond)
+	updates := read(0)
+	require.Len(t, updates.Updates, 1)
+	assert.Equal(t, 5100.25, updates.Updates[0].Data.Last)
+	mu.Lock()
+	assert.Equal(t, updates.Updates, notified)
+	mu.Unlock()
+
+	quotes <- models.MarketData{ContractID: 1234, Last: 5100.5}
+	require.Eventually(t, func() bool { return read(0).LastSeq == 2 }, time.Second, 10*time.Millisecond)
+	updates = read(1)
+	require.Len(t, updates.Updates, 1)
+	assert.Equal(t, 5100.5, updates.Updates[0].Data.Last)
+
+	listed, err := h["listSubscriptions"].Handler(ctx, nil)
+	require.NoError(t, err)
+	statuses := listed.([]SubscriptionStatus)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, *sub, statuses[0].QuoteSubscription)
+	assert.Equal(t, "marketData", statuses[0].Type)
+	assert.Equal(t, int64(2), statuses[0].Updates)
+	require.NotNil(t, statuses[0].LastUpdateAt)
+	assert.False(t, statuses[0].LastUpdateAt.Before(statuses[0].OpenedAt))
+
+	// Other sessions, and requests without one, can neither see the
+	// subscription nor read or stop it.
+	for _, other := range []context.Context{WithNotifier(context.Background(), func(string, interface{}) {}), context.Background()} {
+		listed, err := h["listSubscriptions"].Handler(other, nil)
+		require.NoError(t, err)
+		assert.Empty(t, listed)
+		_, err = h["readMarketData"].Handler(other, map[string]interface{}{"subscriptionId": float64(1)})
+		assert.EqualError(t, err, "no market data subscription 1")
+		_, err = h["unsubscribeMarketData"].Handler(other, map[string]interface{}{"subscriptionId": float64(1)})
+		assert.EqualError(t, err, "no market data subscription 1")
+	}
+
+	// The subscription ends with the session it was opened on.
+	endSession()
+	require.Eventually(t, func() bool {
+		listed, _ := h["listSubscriptions"].Handler(ctx, nil)
+		return len(listed.([]SubscriptionStatus)) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestUnsubscribeMarketData(t *testing.T) {
+	quotes := make(chan models.MarketData)
+	h := NewHandlers(newQuoteStreamMock(quotes))
+
+	result, err := h["subscribeMarketData"].Handler(context.Background(), map[string]interface{}{"contractId": float64(1234)})
+	require.NoError(t, err)
+	sub := result.(*QuoteSubscription)
+	assert.Equal(t, int64(1000), sub.ThrottleMs)
+	assert.False(t, sub.Notifications, "requests without a session are not notified")
+
+	listed, err := h["listSubscriptions"].Handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Len(t, listed, 1)
+	assert.Nil(t, listed.([]SubscriptionStatus)[0].LastUpdateAt)
+
+	_, err = h["unsubscribeMarketData"].Handler(context.Background(), map[string]interface{}{"subscriptionId": float64(sub.SubscriptionID)})
+	require.NoError(t, err)
+
+	listed, err = h["listSubscriptions"].Handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+
+	_, err = h["readMarketData"].Handler(context.Background(), map[string]interface{}{"subscriptionId": float64(sub.SubscriptionID)})
+	assert.EqualError(t, err, "no market data subscription 1")
+	_, err = h["unsubscribeMarketData"].Handler(context.Background(), map[string]interface{}{"subscriptionId": float64(sub.SubscriptionID)})
+	assert.EqualError(t, err, "no market data subscription 1")
+}
+
+func TestSubscribeMarketDataInvalidParams(t *testing.T) {
+	h := NewHandlers(&MockTradovateClient{})
+	tests := []struct {
+		name   string
+		params map[string]interface{}
+		errMsg string
+	}{
+		{"Missing contract", map[string]interface{}{}, "missing required field: contractId"},
+		{"Invalid contract", map[string]interface{}{"contractId": float64(0)}, "invalid contractId"},
+		{"Throttle too short", map[string]interface{}{"contractId": float64(1), "throttleMs": float64(10)}, "invalid throttleMs"},
+		{"Subscription failed", map[string]interface{}{"contractId": float64(1)}, "not implemented"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := h["subscribeMarketData"].Handler(context.Background(), tt.params)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestQuoteSubscriptionStatus(t *testing.T) {
+	openedAt := time.Date(2024, 3, 15, 13, 30, 0, 0, time.UTC)
+	sub := &quoteSubscription{QuoteSubscription: QuoteSubscription{SubscriptionID: 7, ContractID: 1234}, openedAt: openedAt}
+
+	status := sub.status(openedAt.Add(90 * time.Second))
+	assert.Equal(t, int64(90), status.AgeSeconds)
+	assert.Zero(t, status.Updates)
+}
+
+func TestQuoteSubscriptionBuffer(t *testing.T) {
+	sub := &quoteSubscription{QuoteSubscription: QuoteSubscription{SubscriptionID: 7, ContractID: 1234}}
+	for i := 0; i < maxBufferedQuotes+5; i++ {
+		sub.add(models.MarketData{ContractID: 1234, Last: float64(i)})
+	}
+
+	updates := sub.read(2)
+	assert.Len(t, updates.Updates, maxBufferedQuotes)
+	assert.Equal(t, int64(6), updates.Updates[0].Seq)
+	assert.Equal(t, int64(3), updates.Missed, "updates 3 to 5 were dropped")
+	assert.Equal(t, int64(maxBufferedQuotes+5), updates.LastSeq)
+
+	updates = sub.read(updates.LastSeq)
+	assert.Empty(t, updates.Updates)
+	assert.Zero(t, updates.Missed)
+}
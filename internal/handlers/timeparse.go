@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/client"
+)
+
+// exchangeLocation is the time zone of the CME Group exchanges Tradovate
+// trades on. Relative times such as "today" count days in it.
+var exchangeLocation = client.ExchangeLocation
+
+var (
+	// lastPattern matches times such as "last 5 trading days" or "last 2 hours".
+	lastPattern = regexp.MustCompile(`^last (\d+) (trading days?|days?|hours?|minutes?)$`)
+	// dayOffsetPattern matches offsets in days, such as "-3d", which
+	// time.ParseDuration does not accept.
+	dayOffsetPattern = regexp.MustCompile(`^([-+])(\d+)d$`)
+)
+
+// parseTime parses a time parameter, given either as an RFC 3339 time or
+// relative to now in one of these forms:
+// - now
+// - today or yesterday: the start of the day in exchange time
+// - a signed duration, such as -24h, -90m or -3d
+// - last N hours or last N minutes: that long before now
+// - last N days: the start of the day N-1 days before today, in exchange time
+// - last N trading days: the start of the Nth weekday counting back from today, in exchange time
+// Exchange holidays count as trading days. Relative times are returned in
+// exchange time.
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	now = now.In(exchangeLocation)
+	y, m, d := now.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, exchangeLocation)
+	s := strings.ToLower(strings.Join(strings.Fields(value), " "))
+	switch s {
+	case "now":
+		return now, nil
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if match := dayOffsetPattern.FindStringSubmatch(s); match != nil {
+		days, err := strconv.Atoi(match[2])
+		if err == nil {
+			if match[1] == "-" {
+				days = -days
+			}
+			return now.AddDate(0, 0, days), nil
+		}
+	}
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		if offset, err := time.ParseDuration(s); err == nil {
+			return now.Add(offset), nil
+		}
+	}
+
+	if match := lastPattern.FindStringSubmatch(s); match != nil {
+		n, err := strconv.Atoi(match[1])
+		if err == nil && n > 0 {
+			switch strings.TrimSuffix(match[2], "s") {
+			case "minute":
+				return now.Add(-time.Duration(n) * time.Minute), nil
+			case "hour":
+				return now.Add(-time.Duration(n) * time.Hour), nil
+			case "day":
+				return today.AddDate(0, 0, 1-n), nil
+			case "trading day":
+				return tradingDaysBack(today, n), nil
+			}
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 time nor a relative time such as -24h, today or last 5 trading days", value)
+}
+
+// tradingDaysBack returns the nth weekday counting back from today, which
+// counts itself if it is a weekday.
+func tradingDaysBack(today time.Time, n int) time.Time {
+	day := today
+	for {
+		if weekday := day.Weekday(); weekday != time.Saturday && weekday != time.Sunday {
+			if n--; n == 0 {
+				return day
+			}
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+}
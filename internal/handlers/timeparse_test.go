@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/0xjmp/mcp-tradovate/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTime(t *testing.T) {
+	// Tuesday 10:30 in Chicago.
+	now := time.Date(2024, 3, 19, 15, 30, 0, 0, time.UTC)
+	chicago := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2024, month, day, hour, min, 0, 0, exchangeLocation)
+	}
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-03-15T14:00:00Z", time.Date(2024, 3, 15, 14, 0, 0, 0, time.UTC)},
+		{"now", chicago(3, 19, 10, 30)},
+		{"Today", chicago(3, 19, 0, 0)},
+		{"yesterday", chicago(3, 18, 0, 0)},
+		{"-24h", chicago(3, 18, 10, 30)},
+		{"-1h30m", chicago(3, 19, 9, 0)},
+		{"-3d", chicago(3, 16, 10, 30)},
+		{"last 2 hours", chicago(3, 19, 8, 30)},
+		{"last 15 minutes", chicago(3, 19, 10, 15)},
+		{"last 1 day", chicago(3, 19, 0, 0)},
+		{"last 3 days", chicago(3, 17, 0, 0)},
+		{"last  5 trading days", chicago(3, 13, 0, 0)},
+		{"last 2 trading days", chicago(3, 18, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseTime(tt.value, now)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want, got)
+		})
+	}
+
+	// A weekend day is not a trading day.
+	sunday := time.Date(2024, 3, 17, 18, 0, 0, 0, time.UTC)
+	got, err := parseTime("last 1 trading day", sunday)
+	require.NoError(t, err)
+	assert.True(t, chicago(3, 15, 0, 0).Equal(got), got)
+
+	for _, value := range []string{"", "24h", "last 0 days", "last week", "someday"} {
+		_, err := parseTime(value, now)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestHandleGetHistoricalDataRelativeTimes(t *testing.T) {
+	now := time.Date(2024, 3, 19, 15, 30, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	var start, end time.Time
+	mockClient := &MockTradovateClient{
+		getHistoricalDataFunc: func(contractID int, startTime, endTime time.Time, interval string) ([]models.HistoricalData, error) {
+			start, end = startTime, endTime
+			return nil, nil
+		},
+	}
+	_, err := NewHandlers(mockClient)["getHistoricalData"].Handler(context.Background(), map[string]interface{}{
+		"contractId": float64(1234),
+		"startTime":  "-24h",
+		"endTime":    "now",
+		"interval":   "1h",
+	})
+	require.NoError(t, err)
+	assert.True(t, now.Add(-24*time.Hour).Equal(start), start)
+	assert.True(t, now.Equal(end), end)
+}
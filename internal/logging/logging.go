@@ -0,0 +1,88 @@
+// Package logging provides request-scoped structured logging for the MCP Tradovate server.
+// It carries the MCP request ID through context.Context so that every log line and
+// audit record produced while serving a request can be correlated back to it.
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// requestIDKey is the context key under which the MCP request ID is stored.
+type requestIDKey struct{}
+
+// sessionIDKey is the context key under which the client session ID is stored.
+type sessionIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the given MCP request ID.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the MCP request ID carried by ctx, or an empty string.
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// WithSessionID returns a copy of ctx carrying the ID of the client session
+// (connection) a request arrived on.
+func WithSessionID(ctx context.Context, sessionID string) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, sessionID)
+}
+
+// SessionID returns the client session ID carried by ctx, or an empty string.
+func SessionID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	sessionID, _ := ctx.Value(sessionIDKey{}).(string)
+	return sessionID
+}
+
+// Handler is a slog.Handler that adds the session and request IDs carried by
+// the record's context to every log line as the "sessionId" and "requestId"
+// attributes.
+type Handler struct {
+	next slog.Handler
+}
+
+// NewHandler wraps next so that records logged with a request-scoped context
+// include the request ID.
+func NewHandler(next slog.Handler) *Handler {
+	return &Handler{next: next}
+}
+
+// Enabled reports whether the wrapped handler handles records at level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the session and request IDs to r, if present, and passes it to
+// the wrapped handler.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	sessionID, requestID := SessionID(ctx), RequestID(ctx)
+	if sessionID != "" || requestID != "" {
+		r = r.Clone()
+	}
+	if sessionID != "" {
+		r.AddAttrs(slog.String("sessionId", sessionID))
+	}
+	if requestID != "" {
+		r.AddAttrs(slog.String("requestId", requestID))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a Handler whose wrapped handler includes attrs.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup returns a Handler whose wrapped handler nests attributes under name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name)}
+}
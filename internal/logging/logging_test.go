@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	assert.Equal(t, "", RequestID(context.Background()))
+	assert.Equal(t, "", RequestID(nil))
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	assert.Equal(t, "req-1", RequestID(ctx))
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewTextHandler(&buf, nil)))
+
+	logger.InfoContext(WithRequestID(context.Background(), "req-42"), "placed order", "orderId", 7)
+	assert.Contains(t, buf.String(), "requestId=req-42")
+	assert.Contains(t, buf.String(), "orderId=7")
+
+	buf.Reset()
+	logger.With("component", "client").WithGroup("http").InfoContext(context.Background(), "no request")
+	assert.NotContains(t, buf.String(), "requestId")
+	assert.Contains(t, buf.String(), "component=client")
+
+	assert.False(t, logger.Enabled(context.Background(), slog.LevelDebug))
+}
+
+func TestSessionID(t *testing.T) {
+	assert.Equal(t, "", SessionID(context.Background()))
+	assert.Equal(t, "", SessionID(nil))
+
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewTextHandler(&buf, nil)))
+
+	ctx := WithRequestID(WithSessionID(context.Background(), "sess-1"), "req-1")
+	assert.Equal(t, "sess-1", SessionID(ctx))
+	logger.InfoContext(ctx, "request completed")
+	assert.Contains(t, buf.String(), "sessionId=sess-1 requestId=req-1")
+}
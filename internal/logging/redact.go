@@ -0,0 +1,24 @@
+package logging
+
+import "strings"
+
+// secretMarkers are the words that mark a name as holding a credential
+// wherever they appear in it.
+var secretMarkers = []string{"password", "secret", "token", "ticket"}
+
+// IsSecretKey reports whether a field, parameter or environment variable
+// name looks like it holds a credential, such as a password, client secret,
+// access token or rate limit ticket. The client, the audit log and support
+// bundles all redact what it matches, so they agree on what is secret.
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	if key == "sec" || key == "cid" {
+		return true
+	}
+	for _, marker := range secretMarkers {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
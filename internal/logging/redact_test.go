@@ -0,0 +1,16 @@
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSecretKey(t *testing.T) {
+	for _, key := range []string{"password", "accessToken", "mdAccessToken", "clientSecret", "p-ticket", "sec", "cid", "MCP_AUTH_TOKEN"} {
+		assert.True(t, IsSecretKey(key), key)
+	}
+	for _, key := range []string{"orderId", "security", "name", "TRADOVATE_USERNAME"} {
+		assert.False(t, IsSecretKey(key), key)
+	}
+}
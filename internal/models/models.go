@@ -3,6 +3,26 @@
 // that are used for communication with the Tradovate API.
 package models
 
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Credentials are the secrets used to request a Tradovate access token.
+// Applications embedding the client can supply them from a vault, a prompt
+// or a per-tenant store instead of the TRADOVATE_* environment variables.
+type Credentials struct {
+	Username     string `json:"username"`     // Tradovate account username
+	Password     string `json:"password"`     // Tradovate account password
+	AppID        string `json:"appId"`        // Application ID provided by Tradovate
+	AppVersion   string `json:"appVersion"`   // Application version string
+	ClientID     string `json:"cid"`          // OAuth client ID
+	ClientSecret string `json:"clientSecret"` // OAuth client secret
+}
+
 // Account represents a trading account in Tradovate.
 type Account struct {
 	ID            int     `json:"id"`            // Unique identifier for the account
@@ -29,27 +49,135 @@ type Order struct {
 	FilledQty    int     `json:"filledQty"`           // Number of contracts filled
 	AveragePrice float64 `json:"averagePrice"`        // Average fill price
 	CreatedAt    int64   `json:"createdAt"`           // Order creation timestamp
+	Timestamp    string  `json:"timestamp,omitempty"` // When Tradovate received the order, in RFC 3339
 	UpdatedAt    int64   `json:"updatedAt"`           // Last update timestamp
+
+	ClientOrderID  string `json:"clOrdId,omitempty"`        // Caller-chosen ID that makes placement safe to retry
+	ActivationTime string `json:"activationTime,omitempty"` // When a staged order starts working, in RFC 3339; empty for immediately
+
+	ExpiresInDays *int `json:"expiresInDays,omitempty"` // Days until the contract expires, set when expiry is near
+}
+
+// OCOOrder links the two legs of a one-cancels-other order: when either
+// leg fills, Tradovate cancels the other.
+type OCOOrder struct {
+	First  Order `json:"first"`  // Leg submitted first, e.g. the take-profit
+	Second Order `json:"second"` // Leg cancelled when First fills, and vice versa
+}
+
+// BracketOrder is an entry order with an attached take-profit and
+// stop-loss, placed as a single Tradovate order strategy. Offsets are in
+// price points from the entry fill and are always positive; their direction
+// follows from Side.
+type BracketOrder struct {
+	AccountID    int     `json:"accountId"`       // Account to place the orders for
+	ContractID   int     `json:"contractId"`      // Contract to trade
+	Side         string  `json:"side"`            // Entry side (Buy, Sell)
+	Quantity     int     `json:"quantity"`        // Number of contracts
+	OrderType    string  `json:"orderType"`       // Entry order type (Market, Limit)
+	Price        float64 `json:"price,omitempty"` // Entry limit price
+	TimeInForce  string  `json:"timeInForce"`     // Time in force of the entry order
+	TargetOffset float64 `json:"targetOffset"`    // Distance from entry to the take-profit
+	StopOffset   float64 `json:"stopOffset"`      // Distance from entry to the stop-loss
+}
+
+// OrderStrategy represents a running Tradovate order strategy, such as a
+// bracket, which manages a group of related orders.
+type OrderStrategy struct {
+	ID         int    `json:"id"`         // Unique identifier for the strategy
+	AccountID  int    `json:"accountId"`  // Account the strategy trades
+	ContractID int    `json:"contractId"` // Contract the strategy trades
+	Status     string `json:"status"`     // Strategy status, e.g. "ExecutionStarted"
+}
+
+// OrderChanges describes a modification to a working order. Fields left nil
+// or empty keep their current value.
+type OrderChanges struct {
+	Quantity    *int     `json:"orderQty,omitempty"`    // New number of contracts
+	Price       *float64 `json:"price,omitempty"`       // New limit price
+	StopPrice   *float64 `json:"stopPrice,omitempty"`   // New stop price
+	OrderType   string   `json:"orderType,omitempty"`   // New order type, e.g. "Limit" or "Stop"
+	TimeInForce string   `json:"timeInForce,omitempty"` // New time in force
 }
 
 // Fill represents an order fill in Tradovate.
 type Fill struct {
-	ID        int     `json:"id"`        // Unique identifier for the fill
-	OrderID   int     `json:"orderId"`   // Order that was filled
-	Price     float64 `json:"price"`     // Fill price
-	Quantity  int     `json:"quantity"`  // Fill quantity
-	Timestamp int64   `json:"timestamp"` // Fill timestamp
+	ID         int     `json:"id"`                   // Unique identifier for the fill
+	OrderID    int     `json:"orderId"`              // Order that was filled
+	ContractID int     `json:"contractId,omitempty"` // Contract that was traded
+	Symbol     string  `json:"symbol,omitempty"`     // Name of the contract, e.g. "MNQZ4"
+	Action     string  `json:"action,omitempty"`     // Side of the fill (Buy, Sell)
+	Price      float64 `json:"price"`                // Fill price
+	Quantity   int     `json:"quantity"`             // Fill quantity
+	Timestamp  int64   `json:"timestamp"`            // Fill time in milliseconds since the epoch
+}
+
+// ExecutionReport represents an exchange-level event on an order: an
+// acknowledgement, a fill, a cancel or a reject.
+type ExecutionReport struct {
+	ID           int     `json:"id"`                     // Unique identifier for the report
+	OrderID      int     `json:"orderId"`                // Order the report is for
+	AccountID    int     `json:"accountId"`              // Account that placed the order
+	ContractID   int     `json:"contractId"`             // Contract being traded
+	CommandID    int     `json:"commandId"`              // Command that produced the report
+	Timestamp    string  `json:"timestamp"`              // When the event occurred
+	ExecType     string  `json:"execType"`               // Kind of event (New, Trade, Canceled, Rejected, etc.)
+	OrdStatus    string  `json:"ordStatus"`              // Order status after the event
+	Action       string  `json:"action"`                 // Order side (Buy, Sell)
+	CumQty       int     `json:"cumQty"`                 // Total quantity filled so far
+	AvgPx        float64 `json:"avgPx"`                  // Average price of the filled quantity
+	LastQty      int     `json:"lastQty,omitempty"`      // Quantity filled by this event
+	LastPx       float64 `json:"lastPx,omitempty"`       // Price of this event's fill
+	RejectReason string  `json:"rejectReason,omitempty"` // Why the order was rejected
+	Text         string  `json:"text,omitempty"`         // Free-form detail from the exchange
+}
+
+// CommandReport represents Tradovate's outcome for a command sent on an
+// order, such as placing or modifying it. Orders rejected by risk checks or
+// the exchange carry their reason here rather than in the order itself.
+type CommandReport struct {
+	ID            int    `json:"id"`                     // Unique identifier for the report
+	CommandID     int    `json:"commandId"`              // Command the report is for
+	Timestamp     string `json:"timestamp"`              // When the command was processed
+	CommandStatus string `json:"commandStatus"`          // Outcome, e.g. RiskPassed or RiskRejected
+	RejectReason  string `json:"rejectReason,omitempty"` // Category of the rejection
+	Text          string `json:"text,omitempty"`         // Human-readable rejection detail
+}
+
+// Rejected reports whether the command was rejected by risk checks or the
+// exchange.
+func (r CommandReport) Rejected() bool {
+	return r.CommandStatus == "RiskRejected" || r.CommandStatus == "ExecutionRejected"
+}
+
+// Command is an instruction sent on an order, such as placing, modifying or
+// cancelling it, together with Tradovate's reports of its outcome.
+type Command struct {
+	ID            int             `json:"id"`                     // Unique identifier for the command
+	OrderID       int             `json:"orderId"`                // Order the command was sent on
+	Timestamp     string          `json:"timestamp"`              // When the command was sent
+	ClOrdID       string          `json:"clOrdId,omitempty"`      // Client order ID it carried, if any
+	CommandType   string          `json:"commandType"`            // Kind of command (New, Modify, Cancel, etc.)
+	CommandStatus string          `json:"commandStatus"`          // Latest outcome, e.g. RiskPassed or RiskRejected
+	Rejected      bool            `json:"rejected"`               // Whether risk checks or the exchange rejected it
+	RejectReason  string          `json:"rejectReason,omitempty"` // Category of the rejection
+	Text          string          `json:"text,omitempty"`         // Human-readable rejection detail
+	Reports       []CommandReport `json:"reports"`                // Reports of its outcome, oldest first
 }
 
 // Position represents a trading position in Tradovate.
 type Position struct {
-	ID           int     `json:"id"`           // Unique identifier for the position
-	AccountID    int     `json:"accountId"`    // Account holding the position
-	ContractID   int     `json:"contractId"`   // Contract being held
-	NetPos       int     `json:"netPos"`       // Net position size
-	AvgPrice     float64 `json:"avgPrice"`     // Average entry price
-	RealizedPL   float64 `json:"realizedPL"`   // Realized profit/loss
-	UnrealizedPL float64 `json:"unrealizedPL"` // Unrealized profit/loss
+	ID           int     `json:"id"`                  // Unique identifier for the position
+	AccountID    int     `json:"accountId"`           // Account holding the position
+	ContractID   int     `json:"contractId"`          // Contract being held
+	Symbol       string  `json:"symbol,omitempty"`    // Name of the contract, e.g. "MNQZ4", when resolved
+	NetPos       int     `json:"netPos"`              // Net position size
+	AvgPrice     float64 `json:"avgPrice"`            // Average entry price
+	RealizedPL   float64 `json:"realizedPL"`          // Realized profit/loss
+	UnrealizedPL float64 `json:"unrealizedPL"`        // Unrealized profit/loss
+	MarkPrice    float64 `json:"markPrice,omitempty"` // Last trade price UnrealizedPL was worked out at, when marked live
+
+	ExpiresInDays *int `json:"expiresInDays,omitempty"` // Days until the contract expires, set when expiry is near
 }
 
 // Contract represents a tradable contract in Tradovate.
@@ -59,6 +187,132 @@ type Contract struct {
 	ContractType string `json:"contractType"` // Type of contract (Future, Option, etc.)
 	Exchange     string `json:"exchange"`     // Exchange where contract is traded
 	Symbol       string `json:"symbol"`       // Trading symbol
+
+	ContractMaturityID int `json:"contractMaturityId,omitempty"` // Maturity this contract belongs to
+
+	TickSize      float64 `json:"tickSize,omitempty"`      // Minimum price increment, from the product; zero if unknown
+	ValuePerPoint float64 `json:"valuePerPoint,omitempty"` // Currency value of a one point move, from the product
+}
+
+// OnTick reports whether price is a whole number of ticks. Any price is
+// accepted while the tick size is unknown.
+func (c Contract) OnTick(price float64) bool {
+	if c.TickSize <= 0 {
+		return true
+	}
+	ticks := price / c.TickSize
+	return math.Abs(ticks-math.Round(ticks)) < 1e-6
+}
+
+// RoundToTick returns price rounded to the nearest tick, or price unchanged
+// while the tick size is unknown.
+func (c Contract) RoundToTick(price float64) float64 {
+	if c.TickSize <= 0 {
+		return price
+	}
+	rounded := math.Round(price/c.TickSize) * c.TickSize
+	// Trim the binary noise left by ticks such as 0.01, so 70.12 is not
+	// sent as 70.12000000000001.
+	scale := math.Pow10(tickDecimals(c.TickSize))
+	return math.Round(rounded*scale) / scale
+}
+
+// tickDecimals returns the number of decimal places in tick.
+func tickDecimals(tick float64) int {
+	s := strconv.FormatFloat(tick, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+// Product represents a futures product in Tradovate, such as ES, from which
+// a contract is listed for each maturity.
+type Product struct {
+	ID            int     `json:"id"`            // Unique identifier for the product
+	Name          string  `json:"name"`          // Product root symbol, e.g. ES
+	Description   string  `json:"description"`   // Full product name
+	ProductType   string  `json:"productType"`   // Type of product (Futures, Options, etc.)
+	ExchangeID    int     `json:"exchangeId"`    // Exchange where the product is traded
+	CurrencyID    int     `json:"currencyId"`    // Currency the product is quoted in
+	Status        string  `json:"status"`        // Listing status (Verified, Locked, etc.)
+	Months        string  `json:"months"`        // Listed month codes, e.g. HMUZ
+	TickSize      float64 `json:"tickSize"`      // Minimum price increment
+	ValuePerPoint float64 `json:"valuePerPoint"` // Currency value of a one point move per contract
+
+	TradingHours *ProductSession `json:"tradingHours,omitempty"` // Daily trading session, when looked up
+}
+
+// ProductSession is the daily trading session of a product. Times are
+// clock times, e.g. "17:00", in the exchange's time zone.
+type ProductSession struct {
+	ID         int    `json:"id"`         // Unique identifier for the session
+	ProductID  int    `json:"productId"`  // Product the session applies to
+	OpenTime   string `json:"openTime"`   // When the pre-open begins
+	StartTime  string `json:"startTime"`  // When trading starts
+	StopTime   string `json:"stopTime"`   // When trading stops
+	CloseTime  string `json:"closeTime"`  // When the session closes
+	SundayOpen string `json:"sundayOpen"` // When the week's first session opens on Sunday, if it does
+}
+
+// ProductFees represents the per-contract, per-side fees charged for
+// trading a product.
+type ProductFees struct {
+	ProductID       int     `json:"productId"`       // Product the fees apply to
+	ClearingFee     float64 `json:"clearingFee"`     // Clearing house fee
+	ExchangeFee     float64 `json:"exchangeFee"`     // Exchange fee
+	NFAFee          float64 `json:"nfaFee"`          // National Futures Association fee
+	BrokerageFee    float64 `json:"brokerageFee"`    // Brokerage fee
+	IPFee           float64 `json:"ipFee"`           // Intellectual property fee
+	Commission      float64 `json:"commission"`      // Commission
+	OrderRoutingFee float64 `json:"orderRoutingFee"` // Order routing fee
+	RoundTripCost   float64 `json:"roundTripCost"`   // Total cost of opening and closing one contract
+}
+
+// PerSide returns the total fees charged for a single buy or sell of one
+// contract.
+func (f ProductFees) PerSide() float64 {
+	return f.ClearingFee + f.ExchangeFee + f.NFAFee + f.BrokerageFee + f.IPFee + f.Commission + f.OrderRoutingFee
+}
+
+// ProductMargin is the exchange margin required to hold one contract of a
+// product.
+type ProductMargin struct {
+	ProductID         int     `json:"id"`                // Product the margin applies to
+	InitialMargin     float64 `json:"initialMargin"`     // Margin needed to open a contract
+	MaintenanceMargin float64 `json:"maintenanceMargin"` // Margin needed to keep a contract open
+	Timestamp         string  `json:"timestamp"`         // When the requirement was last changed
+}
+
+// ContractMaturity represents the expiration details of a contract in Tradovate.
+type ContractMaturity struct {
+	ID              int    `json:"id"`              // Unique identifier for the maturity
+	ProductID       int    `json:"productId"`       // Product this maturity belongs to
+	ExpirationMonth int    `json:"expirationMonth"` // Expiration month in YYYYMM format
+	ExpirationDate  string `json:"expirationDate"`  // Expiration timestamp in ISO format
+	IsFront         bool   `json:"isFront"`         // Whether this is the front month
+}
+
+// FrontMonth is the contract of a product that is currently most actively
+// traded, as resolved from its maturities and trading volume.
+type FrontMonth struct {
+	Product        string    `json:"product"`                // Product root symbol, e.g. ES
+	Contract       Contract  `json:"contract"`               // The active contract, e.g. ESZ4
+	ExpirationDate string    `json:"expirationDate"`         // When the active contract expires
+	Volume         int       `json:"volume"`                 // Today's volume of the active contract, if known
+	NextContract   *Contract `json:"nextContract,omitempty"` // The contract that expires after it, if listed
+	NextVolume     int       `json:"nextVolume"`             // Today's volume of the next contract, if known
+	Rolled         bool      `json:"rolled"`                 // Whether volume has moved to the next contract ahead of expiry
+}
+
+// ContractMatch is a contract found by a search, with the product it
+// belongs to and how closely it matched.
+type ContractMatch struct {
+	Contract       Contract `json:"contract"`                 // The matching contract
+	Product        string   `json:"product"`                  // Product root symbol, e.g. MNQ
+	Description    string   `json:"description"`              // Full product name, e.g. Micro E-mini Nasdaq-100
+	ExpirationDate string   `json:"expirationDate,omitempty"` // When the contract expires, if known
+	Relevance      int      `json:"relevance"`                // How closely the contract matched, higher first
 }
 
 // MarketData represents real-time market data for a contract.
@@ -71,6 +325,109 @@ type MarketData struct {
 	Timestamp  int64   `json:"timestamp"`  // Data timestamp
 }
 
+// MarketDataResult is the outcome of fetching one contract's market data as
+// part of a batch. Exactly one of Data and Error is set.
+type MarketDataResult struct {
+	ContractID int         `json:"contractId"`      // Contract that was requested
+	Data       *MarketData `json:"data,omitempty"`  // Its market data, if it was fetched
+	Error      string      `json:"error,omitempty"` // Why it could not be fetched
+}
+
+// QuoteEntry is one field of a streamed quote, such as the best bid or the
+// last trade. Entries that carry only a size, like total volume, omit Price.
+type QuoteEntry struct {
+	Price float64 `json:"price,omitempty"` // Price of the entry
+	Size  float64 `json:"size,omitempty"`  // Size or volume of the entry
+}
+
+// Quote represents a real-time quote update streamed for a contract.
+// Entries is keyed by Tradovate's entry names: Bid, Offer, Trade,
+// TotalTradeVolume, OpenInterest, OpeningPrice, HighPrice, LowPrice and
+// SettlementPrice.
+type Quote struct {
+	ContractID int                   `json:"contractId"` // Contract this quote is for
+	Timestamp  string                `json:"timestamp"`  // Quote timestamp in ISO format
+	Entries    map[string]QuoteEntry `json:"entries"`    // Quote entries by name
+}
+
+// DOMLevel is one price level of a depth-of-market ladder.
+type DOMLevel struct {
+	Price float64 `json:"price"` // Price of the level
+	Size  float64 `json:"size"`  // Resting quantity at the price
+}
+
+// DOM represents a depth-of-market update for a contract: the full bid and
+// ask ladders, best price first.
+type DOM struct {
+	ContractID int        `json:"contractId"` // Contract this ladder is for
+	Timestamp  string     `json:"timestamp"`  // Update timestamp in ISO format
+	Bids       []DOMLevel `json:"bids"`       // Bid levels, highest price first
+	Offers     []DOMLevel `json:"offers"`     // Ask levels, lowest price first
+}
+
+// ChartBar represents one OHLCV bar of a streamed chart. In-progress bars
+// are re-sent as they update; a bar with Complete set is final.
+type ChartBar struct {
+	ContractID  int     `json:"contractId"`  // Contract this bar is for
+	Timestamp   string  `json:"timestamp"`   // Bar start time in ISO format
+	Open        float64 `json:"open"`        // Opening price
+	High        float64 `json:"high"`        // Highest price
+	Low         float64 `json:"low"`         // Lowest price
+	Close       float64 `json:"close"`       // Closing (or latest) price
+	UpVolume    float64 `json:"upVolume"`    // Volume traded on upticks
+	DownVolume  float64 `json:"downVolume"`  // Volume traded on downticks
+	UpTicks     float64 `json:"upTicks"`     // Number of upticks
+	DownTicks   float64 `json:"downTicks"`   // Number of downticks
+	BidVolume   float64 `json:"bidVolume"`   // Volume traded at the bid
+	OfferVolume float64 `json:"offerVolume"` // Volume traded at the offer
+	Complete    bool    `json:"complete"`    // Whether the bar has closed
+}
+
+// VolumeProfile represents the volume traded at each price of a contract
+// during the current trading session, as streamed by the histogram feed.
+type VolumeProfile struct {
+	ContractID int             `json:"contractId"` // Contract this profile is for
+	Timestamp  string          `json:"timestamp"`  // Update timestamp in ISO format
+	Base       float64         `json:"base"`       // Reference price the item offsets are relative to
+	Items      map[int]float64 `json:"items"`      // Volume traded by offset from Base in ticks
+}
+
+// VolumeLevel is the volume traded at one price of a VolumeProfile.
+type VolumeLevel struct {
+	Price  float64 `json:"price"`  // Price of the level
+	Volume float64 `json:"volume"` // Volume traded at the price
+}
+
+// Levels converts the profile into price levels, lowest price first, using
+// the contract's minimum price increment.
+func (p VolumeProfile) Levels(tickSize float64) []VolumeLevel {
+	levels := make([]VolumeLevel, 0, len(p.Items))
+	for offset, volume := range p.Items {
+		price := p.Base + float64(offset)*tickSize
+		if tickSize > 0 {
+			// Round away floating point error from the tick arithmetic.
+			price = math.Round(price/tickSize) * tickSize
+		}
+		levels = append(levels, VolumeLevel{Price: price, Volume: volume})
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Price < levels[j].Price })
+	return levels
+}
+
+// PointOfControl returns the level with the most volume, the profile's
+// highest-volume node. It returns false if the profile is empty.
+func (p VolumeProfile) PointOfControl(tickSize float64) (VolumeLevel, bool) {
+	var poc VolumeLevel
+	found := false
+	for _, level := range p.Levels(tickSize) {
+		if !found || level.Volume > poc.Volume {
+			poc = level
+			found = true
+		}
+	}
+	return poc, found
+}
+
 // HistoricalData represents historical price data for a contract.
 type HistoricalData struct {
 	ContractID int     `json:"contractId"` // Contract this data is for
@@ -82,6 +439,147 @@ type HistoricalData struct {
 	Volume     int     `json:"volume"`     // Trading volume
 }
 
+// MarginSnapshot represents an account's current margin usage and the
+// buying power left for new positions.
+type MarginSnapshot struct {
+	AccountID         int     `json:"accountId"`         // Account the snapshot is for
+	Timestamp         string  `json:"timestamp"`         // When the snapshot was taken
+	NetLiq            float64 `json:"netLiq"`            // Net liquidation value: cash plus open P&L
+	InitialMargin     float64 `json:"initialMargin"`     // Initial margin required by open positions
+	MaintenanceMargin float64 `json:"maintenanceMargin"` // Maintenance margin required by open positions
+	TotalUsedMargin   float64 `json:"totalUsedMargin"`   // Margin held by positions and working orders
+	AutoLiqLevel      float64 `json:"autoLiqLevel"`      // Net liquidation value that triggers auto-liquidation
+	AvailableMargin   float64 `json:"availableMargin"`   // Buying power left: NetLiq less TotalUsedMargin
+}
+
+// CashBalanceSnapshot is an account's current balance, including the profit
+// and loss of positions that are still open.
+type CashBalanceSnapshot struct {
+	TotalCashValue    float64 `json:"totalCashValue"`    // Cash on the account
+	TotalPnL          float64 `json:"totalPnL"`          // Realized plus open profit and loss
+	NetLiq            float64 `json:"netLiq"`            // Net liquidation value: cash plus open P&L
+	OpenPnL           float64 `json:"openPnL"`           // Profit and loss of open positions
+	RealizedPnL       float64 `json:"realizedPnL"`       // Profit and loss realized today
+	WeekRealizedPnL   float64 `json:"weekRealizedPnL"`   // Profit and loss realized this week
+	InitialMargin     float64 `json:"initialMargin"`     // Initial margin required by open positions
+	MaintenanceMargin float64 `json:"maintenanceMargin"` // Maintenance margin required by open positions
+}
+
+// AccountSummary gathers everything about an account's current state that
+// is needed to answer "how am I doing?".
+type AccountSummary struct {
+	Account       Account             `json:"account"`       // The account itself
+	CashBalance   CashBalanceSnapshot `json:"cashBalance"`   // Current balance and P&L
+	Margin        MarginSnapshot      `json:"margin"`        // Margin usage and buying power
+	Positions     []Position          `json:"positions"`     // Open positions
+	WorkingOrders []Order             `json:"workingOrders"` // Orders still working
+}
+
+// PnLSummary is an account's profit and loss for a period.
+type PnLSummary struct {
+	AccountID     int           `json:"accountId"`             // Account the P&L belongs to
+	AccountName   string        `json:"accountName,omitempty"` // Name of the account, when known
+	Period        string        `json:"period"`                // day or week
+	RealizedPnL   float64       `json:"realizedPnL"`           // Profit and loss realized in the period
+	UnrealizedPnL float64       `json:"unrealizedPnL"`         // Profit and loss of open positions
+	TotalPnL      float64       `json:"totalPnL"`              // Realized plus unrealized
+	Contracts     []ContractPnL `json:"contracts,omitempty"`   // The day's P&L by contract
+}
+
+// ContractPnL is the profit and loss of an account's trading in one
+// contract today.
+type ContractPnL struct {
+	ContractID    int     `json:"contractId"`       // Contract traded or held
+	Symbol        string  `json:"symbol,omitempty"` // Name of the contract, e.g. "MNQZ4", when resolved
+	NetPos        int     `json:"netPos"`           // Net position held now
+	RealizedPnL   float64 `json:"realizedPnL"`      // Profit and loss realized, as Tradovate reports it on the position
+	UnrealizedPnL float64 `json:"unrealizedPnL"`    // Profit and loss of the open position, marked at the last trade when possible
+	TotalPnL      float64 `json:"totalPnL"`         // Realized plus unrealized
+	Fills         int     `json:"fills"`            // Number of fills today
+	Volume        int     `json:"volume"`           // Contracts bought and sold today
+}
+
+// PnLReport totals the profit and loss of one or more accounts.
+type PnLReport struct {
+	Period        string       `json:"period"`        // day or week
+	RealizedPnL   float64      `json:"realizedPnL"`   // Realized across the accounts
+	UnrealizedPnL float64      `json:"unrealizedPnL"` // Unrealized across the accounts
+	TotalPnL      float64      `json:"totalPnL"`      // Realized plus unrealized across the accounts
+	Accounts      []PnLSummary `json:"accounts"`      // Each account's P&L
+}
+
+// TradingPermission represents access granted to a user on an account they
+// do not own, such as a managed or shared account.
+type TradingPermission struct {
+	ID        int    `json:"id"`        // Unique identifier for the permission
+	UserID    int    `json:"userId"`    // User the permission is granted to
+	AccountID int    `json:"accountId"` // Account the permission applies to
+	Status    string `json:"status"`    // Requested, Approved, Accepted, Declined or Revoked
+}
+
+// AccountPermissions summarizes what the authenticated user may do on an
+// account, so restricted accounts can be detected before placing orders.
+type AccountPermissions struct {
+	AccountID       int                 `json:"accountId"`              // Account the summary is for
+	CanTrade        bool                `json:"canTrade"`               // Whether new orders may be placed
+	LiquidationOnly bool                `json:"liquidationOnly"`        // Whether orders may only reduce positions
+	Restrictions    []string            `json:"restrictions,omitempty"` // Why trading is limited, if it is
+	Permissions     []TradingPermission `json:"permissions,omitempty"`  // Permissions granted on the account
+}
+
+// UserProperty represents a setting stored on the user's profile.
+type UserProperty struct {
+	ID         int    `json:"id"`         // Unique identifier for the property value
+	UserID     int    `json:"userId"`     // User the property belongs to
+	PropertyID int    `json:"propertyId"` // Which property this is
+	Value      string `json:"value"`      // Property value
+}
+
+// PluginDate is a calendar date as Tradovate returns it for plugins.
+type PluginDate struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Day   int `json:"day"`
+}
+
+// IsZero reports whether the date is unset.
+func (d PluginDate) IsZero() bool {
+	return d.Year == 0
+}
+
+// Time returns the start of the date in UTC.
+func (d PluginDate) Time() time.Time {
+	return time.Date(d.Year, time.Month(d.Month), d.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// UserPlugin represents an add-on the user has subscribed to, such as a
+// market data entitlement for an exchange.
+type UserPlugin struct {
+	ID             int        `json:"id"`                      // Unique identifier for the subscription
+	UserID         int        `json:"userId"`                  // User subscribed to the plugin
+	AccountID      int        `json:"accountId,omitempty"`     // Account billed for the plugin
+	PluginName     string     `json:"pluginName"`              // Plugin name, e.g. CME_Bundle
+	Approval       bool       `json:"approval"`                // Whether the subscription has been approved
+	EntitlementID  int        `json:"entitlementId,omitempty"` // Entitlement the plugin grants
+	StartDate      PluginDate `json:"startDate"`               // First day the plugin is active
+	ExpirationDate PluginDate `json:"expirationDate"`          // Last day the plugin is active; unset if it does not expire
+	Autorenewal    bool       `json:"autorenewal"`             // Whether the subscription renews automatically
+}
+
+// Active reports whether the plugin is approved and in effect at now.
+func (p UserPlugin) Active(now time.Time) bool {
+	if !p.Approval {
+		return false
+	}
+	if !p.StartDate.IsZero() && now.Before(p.StartDate.Time()) {
+		return false
+	}
+	if !p.ExpirationDate.IsZero() && !now.Before(p.ExpirationDate.Time().AddDate(0, 0, 1)) {
+		return false
+	}
+	return true
+}
+
 // RiskLimit represents risk management limits for an account.
 type RiskLimit struct {
 	AccountID      int     `json:"accountId"`      // Account these limits apply to
@@ -90,3 +588,66 @@ type RiskLimit struct {
 	MaxPositionQty int     `json:"maxPositionQty"` // Maximum position size allowed
 	TrailingStop   float64 `json:"trailingStop"`   // Trailing stop percentage
 }
+
+// AccountAlert is a notice raised for the user: a margin call, trade desk
+// message or system notice from the broker, or one of the user's own alerts
+// that has triggered.
+type AccountAlert struct {
+	ID        int    `json:"id"`                  // Unique identifier of the alert signal
+	Source    string `json:"source"`              // "broker" for broker notices, "user" for the user's own alerts
+	AccountID int    `json:"accountId,omitempty"` // Account the notice concerns; zero if it concerns the user
+	Timestamp string `json:"timestamp"`           // When the alert was raised
+	Text      string `json:"text"`                // What the alert says
+	Handled   bool   `json:"handled"`             // Whether the alert has been read or dealt with
+}
+
+// AutoLiquidation holds the broker-side thresholds at which Tradovate warns,
+// restricts an account to liquidating trades, or flattens it. Unset
+// thresholds are not enforced.
+type AutoLiquidation struct {
+	AccountID               int      `json:"id"`                                // Account these settings apply to
+	ChangesLocked           bool     `json:"changesLocked,omitempty"`           // Whether the settings can no longer be changed today
+	DailyLossAutoLiq        *float64 `json:"dailyLossAutoLiq,omitempty"`        // Daily loss that flattens the account
+	WeeklyLossAutoLiq       *float64 `json:"weeklyLossAutoLiq,omitempty"`       // Weekly loss that flattens the account
+	DailyProfitAutoLiq      *float64 `json:"dailyProfitAutoLiq,omitempty"`      // Daily profit that flattens the account
+	WeeklyProfitAutoLiq     *float64 `json:"weeklyProfitAutoLiq,omitempty"`     // Weekly profit that flattens the account
+	MarginPercentageAlert   *float64 `json:"marginPercentageAlert,omitempty"`   // Margin usage percentage that triggers an alert
+	MarginPercentageLiqOnly *float64 `json:"marginPercentageLiqOnly,omitempty"` // Margin usage percentage that restricts the account to liquidating trades
+	MarginPercentageAutoLiq *float64 `json:"marginPercentageAutoLiq,omitempty"` // Margin usage percentage that flattens the account
+	TrailingMaxDrawdown     *float64 `json:"trailingMaxDrawdown,omitempty"`     // Trailing drawdown from the peak balance that flattens the account
+	FlattenTimestamp        string   `json:"flattenTimestamp,omitempty"`        // Time of day positions are flattened
+}
+
+// RateLimitStatus reports how hard the client has been using the Tradovate
+// API, so a caller can pace itself before being penalized.
+type RateLimitStatus struct {
+	Endpoints          []EndpointUsage `json:"endpoints"`                    // Recent requests per endpoint class
+	Penalized          bool            `json:"penalized"`                    // Whether requests are held back by a time penalty
+	PenaltyUntil       *time.Time      `json:"penaltyUntil,omitempty"`       // When the penalty ends
+	PenaltyRemainingMs int64           `json:"penaltyRemainingMs,omitempty"` // Time left until the penalty ends
+	Penalties          int             `json:"penalties"`                    // Time penalties imposed since the server started
+	LastPenaltyAt      *time.Time      `json:"lastPenaltyAt,omitempty"`      // When the latest penalty was imposed
+}
+
+// EndpointUsage counts the requests recently sent to one class of endpoint:
+// auth, marketData, historical, trading or other.
+type EndpointUsage struct {
+	Class          string `json:"class"`                    // Endpoint class
+	LastMinute     int    `json:"lastMinute"`               // Requests sent in the last minute
+	LastHour       int    `json:"lastHour"`                 // Requests sent in the last hour
+	ResetInSeconds int    `json:"resetInSeconds,omitempty"` // Seconds until the oldest request counted in LastHour drops out of it
+}
+
+// ConnectionStatus describes the Tradovate environment the client trades in
+// and its WebSocket connections.
+type ConnectionStatus struct {
+	Environment string         `json:"environment"` // "live", "demo" or "replay"
+	Sockets     []SocketStatus `json:"sockets"`     // State of each WebSocket
+}
+
+// SocketStatus describes one of the client's WebSockets.
+type SocketStatus struct {
+	Name          string `json:"name"`          // "marketData", "userSync" or "replay"
+	Connected     bool   `json:"connected"`     // Whether the socket is open; false before first use or while reconnecting
+	Subscriptions int    `json:"subscriptions"` // Subscriptions carried by the socket
+}
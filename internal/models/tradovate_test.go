@@ -215,3 +215,56 @@ func TestHistoricalDataMarshaling(t *testing.T) {
 		t.Errorf("Expected Volume %d, got %d", historicalData.Volume, decoded.Volume)
 	}
 }
+
+func TestVolumeProfileLevels(t *testing.T) {
+	var profile VolumeProfile
+	data := []byte(`{"contractId":1234,"base":5100.25,"items":{"-2":150,"0":900,"3":420}}`)
+	if err := json.Unmarshal(data, &profile); err != nil {
+		t.Fatalf("Failed to unmarshal VolumeProfile: %v", err)
+	}
+
+	levels := profile.Levels(0.25)
+	expected := []VolumeLevel{{Price: 5099.75, Volume: 150}, {Price: 5100.25, Volume: 900}, {Price: 5101, Volume: 420}}
+	if len(levels) != len(expected) {
+		t.Fatalf("Expected %d levels, got %d", len(expected), len(levels))
+	}
+	for i, level := range levels {
+		if level != expected[i] {
+			t.Errorf("Expected level %d to be %+v, got %+v", i, expected[i], level)
+		}
+	}
+
+	poc, ok := profile.PointOfControl(0.25)
+	if !ok || poc.Price != 5100.25 {
+		t.Errorf("Expected point of control at 5100.25, got %+v", poc)
+	}
+	if _, ok := (VolumeProfile{}).PointOfControl(0.25); ok {
+		t.Error("Expected no point of control for an empty profile")
+	}
+}
+
+func TestContractTicks(t *testing.T) {
+	es := Contract{Name: "ESZ4", TickSize: 0.25}
+	if es.OnTick(100.37) {
+		t.Error("Expected 100.37 to be off the 0.25 tick")
+	}
+	if !es.OnTick(5100.75) {
+		t.Error("Expected 5100.75 to be on the 0.25 tick")
+	}
+	if got := es.RoundToTick(100.37); got != 100.25 {
+		t.Errorf("Expected 100.37 to round to 100.25, got %v", got)
+	}
+
+	cl := Contract{Name: "CLZ4", TickSize: 0.01}
+	if got := cl.RoundToTick(70.123); got != 70.12 {
+		t.Errorf("Expected 70.123 to round to 70.12, got %v", got)
+	}
+	if !cl.OnTick(70.12) {
+		t.Error("Expected 70.12 to be on the 0.01 tick")
+	}
+
+	var unknown Contract
+	if !unknown.OnTick(100.37) || unknown.RoundToTick(100.37) != 100.37 {
+		t.Error("Expected prices to pass unchanged without a tick size")
+	}
+}
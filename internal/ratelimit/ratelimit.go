@@ -0,0 +1,59 @@
+// Package ratelimit provides keyed token-bucket rate limiting for inbound
+// MCP tool calls, protecting the Tradovate API from runaway clients.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter tracks an independent token bucket per key.
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+// bucket holds up to limit tokens, refilled evenly over window.
+type bucket struct {
+	limit  int
+	window time.Duration
+	tokens float64
+	last   time.Time
+}
+
+// New creates a Limiter with no buckets.
+func New() *Limiter {
+	return &Limiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow takes a token from the bucket for key, which allows limit calls per
+// window with bursts of up to limit calls. If no token is available it
+// returns false and how long to wait before the next call will be allowed.
+// Changing the limit or window of a key starts it with a full bucket.
+func (l *Limiter) Allow(key string, limit int, window time.Duration) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[key]
+	if !ok || b.limit != limit || b.window != window {
+		b = &bucket{limit: limit, window: window, tokens: float64(limit), last: now}
+		l.buckets[key] = b
+	}
+
+	rate := float64(limit) / window.Seconds()
+	b.tokens = math.Min(float64(limit), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	return false, wait
+}
@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllow(t *testing.T) {
+	now := time.Date(2024, 12, 17, 12, 0, 0, 0, time.UTC)
+	limiter := New()
+	limiter.now = func() time.Time { return now }
+
+	// A full bucket allows a burst of limit calls.
+	for i := 0; i < 5; i++ {
+		ok, _ := limiter.Allow("placeOrder", 5, time.Minute)
+		assert.True(t, ok, "call %d", i)
+	}
+	ok, wait := limiter.Allow("placeOrder", 5, time.Minute)
+	assert.False(t, ok)
+	assert.Equal(t, 12*time.Second, wait)
+
+	// Other keys have their own buckets.
+	ok, _ = limiter.Allow("getPositions", 60, time.Minute)
+	assert.True(t, ok)
+
+	// Tokens refill evenly over the window.
+	now = now.Add(6 * time.Second)
+	ok, wait = limiter.Allow("placeOrder", 5, time.Minute)
+	assert.False(t, ok)
+	assert.Equal(t, 6*time.Second, wait)
+
+	now = now.Add(6 * time.Second)
+	ok, _ = limiter.Allow("placeOrder", 5, time.Minute)
+	assert.True(t, ok)
+
+	// A changed limit starts over with a full bucket.
+	ok, _ = limiter.Allow("placeOrder", 10, time.Minute)
+	assert.True(t, ok)
+}